@@ -0,0 +1,111 @@
+// migrate 使用 db/migration 下的 SQL 文件管理数据库结构
+//
+// 用法:
+//
+//	migrate [-config file] [-path dir] up        执行所有未执行的迁移
+//	migrate [-config file] [-path dir] down N    回滚最近 N 个迁移
+//	migrate [-config file] [-path dir] version   显示当前版本
+//
+// 数据库连接信息与服务端共用 config.LoadConfig
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	configFile := flag.String("config", "", "optional YAML/JSON config file merged over .env")
+	path := flag.String("path", "db/migration", "directory containing migration files")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] up | down N | version\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		return errors.New("missing command")
+	}
+
+	var files []string
+	if *configFile != "" {
+		files = append(files, *configFile)
+	}
+
+	// 加载配置 (只需要数据库部分)
+	cfg, err := config.LoadConfig(".", files...)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.ValidateDatabase(); err != nil {
+		return err
+	}
+
+	// 迁移文件中一个文件可能包含多条语句
+	m, err := migrate.New("file://"+*path, "mysql://"+cfg.DBSource()+"&multiStatements=true")
+	if err != nil {
+		return fmt.Errorf("create migrate: %w", err)
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		// 必须显式指定步数，避免误回滚全部迁移
+		if len(args) != 2 {
+			return errors.New("usage: down N")
+		}
+		n, convErr := strconv.Atoi(args[1])
+		if convErr != nil || n <= 0 {
+			return fmt.Errorf("invalid step count %q", args[1])
+		}
+		err = m.Steps(-n)
+	case "version":
+		return printVersion(m)
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return printVersion(m)
+}
+
+// printVersion 输出当前迁移版本，dirty 表示上次迁移中途失败需要人工处理
+func printVersion(m *migrate.Migrate) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Println("version: none")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+	fmt.Printf("version: %d (dirty: %t)\n", version, dirty)
+	return nil
+}
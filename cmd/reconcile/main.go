@@ -0,0 +1,97 @@
+// reconcile 检查所有账户的余额是否等于账目之和
+//
+// 用法:
+//
+//	reconcile [-config file] [-batch N]         只读检查，发现不一致时以非零状态退出
+//	reconcile [-config file] [-batch N] -fix    把不一致的余额修正为账目之和
+//
+// 每个不一致的账户输出一条警告日志；数据库连接信息与服务端共用 config.LoadConfig
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+	"github.com/proyuen/simple-bank-v2/internal/repository"
+	"github.com/proyuen/simple-bank-v2/internal/server"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	configFile := flag.String("config", "", "optional YAML/JSON config file merged over .env")
+	fix := flag.Bool("fix", false, "correct stored balances that differ from the sum of entries")
+	batch := flag.Int("batch", 500, "number of accounts to check per query")
+	flag.Parse()
+
+	var files []string
+	if *configFile != "" {
+		files = append(files, *configFile)
+	}
+
+	// 加载配置 (只需要数据库部分)
+	cfg, err := config.LoadConfig(".", files...)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.ValidateDatabase(); err != nil {
+		return err
+	}
+
+	db, err := server.OpenDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("get underlying sql.DB: %w", err)
+	}
+	defer sqlDB.Close()
+
+	// 收到中断信号时停止，已修正的账户各自在独立事务中提交
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	reconciler := service.NewReconcileService(
+		repository.NewTxManager(db),
+		repository.NewAccountRepository(db),
+		repository.NewEntryRepository(db),
+	).WithBatchSize(*batch)
+
+	report, err := reconciler.ReconcileAll(ctx, *fix, func(d *service.Discrepancy) {
+		slog.Warn("balance discrepancy",
+			"account_id", d.AccountID,
+			"public_id", d.PublicID,
+			"owner", d.Owner,
+			"currency", d.Currency,
+			"stored", d.Stored,
+			"computed", d.Computed,
+			"fixed", d.Fixed,
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("reconcile (checked %d accounts): %w", report.Checked, err)
+	}
+
+	slog.Info("reconcile finished",
+		"checked", report.Checked,
+		"discrepancies", report.Discrepancies,
+		"fixed", report.Fixed,
+	)
+	if unfixed := report.Discrepancies - report.Fixed; unfixed > 0 {
+		return fmt.Errorf("%d accounts have balance discrepancies, rerun with -fix to correct them", unfixed)
+	}
+	return nil
+}
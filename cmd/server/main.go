@@ -2,14 +2,17 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/proyuen/simple-bank-v2/internal/config"
 	"github.com/proyuen/simple-bank-v2/internal/server"
+	"github.com/proyuen/simple-bank-v2/internal/version"
 )
 
 func main() {
@@ -20,14 +23,33 @@ func main() {
 }
 
 func run() error {
+	// 可选的结构化配置文件 (YAML/JSON)，环境变量仍然优先
+	configFile := flag.String("config", "", "optional YAML/JSON config file merged over .env")
+	flag.Parse()
+
+	var files []string
+	if *configFile != "" {
+		files = append(files, *configFile)
+	}
+
 	// 加载配置
-	cfg, err := config.LoadConfig(".")
+	cfg, err := config.LoadConfig(".", files...)
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
 
 	// 设置日志
-	setupLogger(cfg.IsProduction())
+	for _, warning := range setupLogger(&cfg) {
+		slog.Warn("logger", "warning", warning)
+	}
+	for _, warning := range cfg.Warnings() {
+		slog.Warn("config", "warning", warning)
+	}
+	build := version.Get()
+	slog.Info("build info", "version", build.Version, "commit", build.Commit, "build_time", build.BuildTime, "go_version", build.GoVersion)
 
 	// 创建应用
 	app, err := server.NewApp(cfg)
@@ -54,17 +76,55 @@ func run() error {
 	return nil
 }
 
-func setupLogger(production bool) {
-	var handler slog.Handler
+// setupLogger 根据 LOG_LEVEL 和 LOG_FORMAT 设置默认 Logger
+// 无法识别的取值使用默认值，返回的警告在 Logger 设置完成后输出
+func setupLogger(cfg *config.Config) []string {
+	var warnings []string
+
+	level, ok := parseLogLevel(cfg.LogLevel)
+	if !ok {
+		warnings = append(warnings, fmt.Sprintf("LOG_LEVEL %q is invalid, using info", cfg.LogLevel))
+	}
 	opts := &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+		Level: level,
 	}
 
-	if production {
+	jsonFormat := cfg.IsProduction()
+	switch strings.ToLower(strings.TrimSpace(cfg.LogFormat)) {
+	case "":
+	case "json":
+		jsonFormat = true
+	case "text":
+		jsonFormat = false
+	default:
+		warnings = append(warnings, fmt.Sprintf("LOG_FORMAT %q is invalid, must be json or text", cfg.LogFormat))
+	}
+
+	var handler slog.Handler
+	if jsonFormat {
 		handler = slog.NewJSONHandler(os.Stdout, opts)
 	} else {
 		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
 	slog.SetDefault(slog.New(handler))
+	return warnings
+}
+
+// parseLogLevel 解析日志级别 (不区分大小写)，支持 slog 的 "warn+2" 这样的偏移写法
+// 为空时返回 info；无法识别时返回 info 和 false
+func parseLogLevel(s string) (slog.Level, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return slog.LevelInfo, true
+	}
+	if strings.EqualFold(s, "warning") {
+		return slog.LevelWarn, true
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo, false
+	}
+	return level, true
 }
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   slog.Level
+		wantOK bool
+	}{
+		{"", slog.LevelInfo, true},
+		{"debug", slog.LevelDebug, true},
+		{"INFO", slog.LevelInfo, true},
+		{" warn ", slog.LevelWarn, true},
+		{"warning", slog.LevelWarn, true},
+		{"error", slog.LevelError, true},
+		{"warn+2", slog.LevelWarn + 2, true},
+		{"verbose", slog.LevelInfo, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseLogLevel(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseLogLevel(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
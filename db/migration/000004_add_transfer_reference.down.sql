@@ -0,0 +1,9 @@
+-- =====================================================
+-- Migration: 000004_add_transfer_reference (DOWN)
+-- Description: Rollback - remove transfer reference
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP INDEX `idx_transfers_reference` ON `transfers`;
+
+ALTER TABLE `transfers` DROP COLUMN `reference`;
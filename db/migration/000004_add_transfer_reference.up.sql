@@ -0,0 +1,17 @@
+-- =====================================================
+-- Migration: 000004_add_transfer_reference
+-- Description: Add human-readable reference to transfers
+-- Database: MySQL 8.0+
+-- =====================================================
+
+-- 先以可空列添加，便于回填已有数据
+ALTER TABLE `transfers`
+    ADD COLUMN `reference` VARCHAR(32) NULL COMMENT '转账参考号(用户可见)' AFTER `id`;
+
+-- 回填已有转账: TR + 16 位补零的ID
+UPDATE `transfers` SET `reference` = CONCAT('TR', LPAD(`id`, 16, '0')) WHERE `reference` IS NULL;
+
+ALTER TABLE `transfers` MODIFY COLUMN `reference` VARCHAR(32) NOT NULL COMMENT '转账参考号(用户可见)';
+
+-- 唯一索引: 按参考号查询，同时保证参考号不重复
+CREATE UNIQUE INDEX `idx_transfers_reference` ON `transfers` (`reference`);
@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000005_add_session_last_used_at (DOWN)
+-- Description: Rollback - remove session last_used_at
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `sessions` DROP COLUMN `last_used_at`;
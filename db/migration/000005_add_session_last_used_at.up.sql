@@ -0,0 +1,8 @@
+-- =====================================================
+-- Migration: 000005_add_session_last_used_at
+-- Description: Track session last use for idle timeout
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `sessions`
+    ADD COLUMN `last_used_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最后使用时间(用于空闲超时)' AFTER `expires_at`;
@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000006_add_user_role (DOWN)
+-- Description: Rollback - remove user role
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `users` DROP COLUMN `role`;
@@ -0,0 +1,8 @@
+-- =====================================================
+-- Migration: 000006_add_user_role
+-- Description: Add role to users for authorization
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `users`
+    ADD COLUMN `role` VARCHAR(32) NOT NULL DEFAULT 'user' COMMENT '用户角色(user/admin)' AFTER `email`;
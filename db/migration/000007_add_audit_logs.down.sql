@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000007_add_audit_logs (DOWN)
+-- Description: Rollback - drop audit log table
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP TABLE IF EXISTS `audit_logs`;
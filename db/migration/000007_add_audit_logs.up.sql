@@ -0,0 +1,20 @@
+-- =====================================================
+-- Migration: 000007_add_audit_logs
+-- Description: Create append-only audit log table
+-- Database: MySQL 8.0+
+-- =====================================================
+
+-- audit_logs: 审计日志表
+-- 记录登录、转账、会话封禁等敏感操作，只追加不修改
+CREATE TABLE `audit_logs` (
+    `id`         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `actor`      VARCHAR(255) NOT NULL COMMENT '操作者(用户名)',
+    `action`     VARCHAR(64) NOT NULL COMMENT '操作类型',
+    `target`     VARCHAR(255) NOT NULL DEFAULT '' COMMENT '操作对象',
+    `client_ip`  VARCHAR(45) NOT NULL DEFAULT '' COMMENT '客户端 IP (支持 IPv6)',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='审计日志表';
+
+-- 索引: 按操作者/操作类型过滤
+CREATE INDEX `idx_audit_logs_actor` ON `audit_logs` (`actor`);
+CREATE INDEX `idx_audit_logs_action` ON `audit_logs` (`action`);
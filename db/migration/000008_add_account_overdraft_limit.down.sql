@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000008_add_account_overdraft_limit (DOWN)
+-- Description: Rollback - remove account overdraft limit
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts` DROP COLUMN `overdraft_limit`;
@@ -0,0 +1,8 @@
+-- =====================================================
+-- Migration: 000008_add_account_overdraft_limit
+-- Description: Add per-account overdraft (credit) limit
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts`
+    ADD COLUMN `overdraft_limit` BIGINT NOT NULL DEFAULT 0 COMMENT '透支额度(单位:分)，余额最低可到 -overdraft_limit' AFTER `balance`;
@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000009_add_account_name (DOWN)
+-- Description: Rollback - remove account display name
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts` DROP COLUMN `name`;
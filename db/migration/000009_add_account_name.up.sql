@@ -0,0 +1,8 @@
+-- =====================================================
+-- Migration: 000009_add_account_name
+-- Description: Add optional display name (nickname) to accounts
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts`
+    ADD COLUMN `name` VARCHAR(64) NULL DEFAULT NULL COMMENT '账户名称(仅用于展示，不要求唯一)' AFTER `owner`;
@@ -0,0 +1,15 @@
+-- =====================================================
+-- Migration: 000010_add_account_soft_delete (DOWN)
+-- Description: Rollback - restore owner/currency uniqueness
+--              and remove deleted_at from accounts
+-- Database: MySQL 8.0+
+-- =====================================================
+
+-- 注意: 如果存在同一用户同一货币的已删除账户，需先清理，否则唯一索引创建失败
+CREATE UNIQUE INDEX `idx_accounts_owner_currency` ON `accounts` (`owner`, `currency`);
+DROP INDEX `idx_accounts_owner_currency_active` ON `accounts`;
+
+ALTER TABLE `accounts` DROP COLUMN `active`;
+
+DROP INDEX `idx_accounts_deleted_at` ON `accounts`;
+ALTER TABLE `accounts` DROP COLUMN `deleted_at`;
@@ -0,0 +1,22 @@
+-- =====================================================
+-- Migration: 000010_add_account_soft_delete
+-- Description: Add deleted_at to accounts and make the
+--              owner/currency uniqueness ignore soft-deleted rows
+-- Database: MySQL 8.0+
+-- =====================================================
+
+-- 软删除时间 (模型 Account.DeletedAt)
+ALTER TABLE `accounts`
+    ADD COLUMN `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT '软删除时间' AFTER `updated_at`;
+
+CREATE INDEX `idx_accounts_deleted_at` ON `accounts` (`deleted_at`);
+
+-- 未删除时为 1，已删除时为 NULL
+-- MySQL 唯一索引允许多个 NULL，因此软删除的账户不参与唯一约束
+ALTER TABLE `accounts`
+    ADD COLUMN `active` TINYINT GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, 1, NULL)) STORED COMMENT '未删除标记(仅用于唯一索引)';
+
+-- 唯一约束: 同一用户同一货币只能有一个未删除的账户
+-- 先建新索引再删旧索引，避免中间状态没有唯一约束
+CREATE UNIQUE INDEX `idx_accounts_owner_currency_active` ON `accounts` (`owner`, `currency`, `active`);
+DROP INDEX `idx_accounts_owner_currency` ON `accounts`;
@@ -0,0 +1,11 @@
+-- =====================================================
+-- Migration: 000011_add_public_ids (DOWN)
+-- Description: Rollback - remove public identifiers
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP INDEX `idx_transfers_public_id` ON `transfers`;
+ALTER TABLE `transfers` DROP COLUMN `public_id`;
+
+DROP INDEX `idx_accounts_public_id` ON `accounts`;
+ALTER TABLE `accounts` DROP COLUMN `public_id`;
@@ -0,0 +1,19 @@
+-- =====================================================
+-- Migration: 000011_add_public_ids
+-- Description: Add UUID public identifiers to accounts and transfers
+--              (used in URLs instead of sequential ids)
+-- Database: MySQL 8.0+
+-- =====================================================
+
+-- 先允许 NULL，为已有数据生成 UUID 后再加 NOT NULL 约束
+ALTER TABLE `accounts`
+    ADD COLUMN `public_id` CHAR(36) NULL COMMENT '公开ID(UUID)，用于 URL' AFTER `id`;
+UPDATE `accounts` SET `public_id` = UUID() WHERE `public_id` IS NULL;
+ALTER TABLE `accounts` MODIFY COLUMN `public_id` CHAR(36) NOT NULL COMMENT '公开ID(UUID)，用于 URL';
+CREATE UNIQUE INDEX `idx_accounts_public_id` ON `accounts` (`public_id`);
+
+ALTER TABLE `transfers`
+    ADD COLUMN `public_id` CHAR(36) NULL COMMENT '公开ID(UUID)，用于 URL' AFTER `id`;
+UPDATE `transfers` SET `public_id` = UUID() WHERE `public_id` IS NULL;
+ALTER TABLE `transfers` MODIFY COLUMN `public_id` CHAR(36) NOT NULL COMMENT '公开ID(UUID)，用于 URL';
+CREATE UNIQUE INDEX `idx_transfers_public_id` ON `transfers` (`public_id`);
@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000012_add_account_min_balance (DOWN)
+-- Description: Rollback - remove minimum balance from accounts
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts` DROP COLUMN `min_balance`;
@@ -0,0 +1,8 @@
+-- =====================================================
+-- Migration: 000012_add_account_min_balance
+-- Description: Add minimum balance (reserve) requirement to accounts
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts`
+    ADD COLUMN `min_balance` BIGINT NOT NULL DEFAULT 0 COMMENT '最低余额(单位:分)，0 表示不要求' AFTER `overdraft_limit`;
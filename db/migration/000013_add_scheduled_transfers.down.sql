@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000013_add_scheduled_transfers (DOWN)
+-- Description: Rollback - drop scheduled transfers
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP TABLE IF EXISTS `scheduled_transfers`;
@@ -0,0 +1,55 @@
+-- =====================================================
+-- Migration: 000013_add_scheduled_transfers
+-- Description: Add scheduled (future-dated) transfers
+-- Database: MySQL 8.0+
+-- =====================================================
+
+CREATE TABLE `scheduled_transfers` (
+    `id`              BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `public_id`       CHAR(36) NOT NULL COMMENT '公开ID',
+    `owner`           VARCHAR(255) NOT NULL COMMENT '创建者(用户名)',
+    `from_account_id` BIGINT NOT NULL COMMENT '转出账户',
+    `to_account_id`   BIGINT NOT NULL COMMENT '转入账户',
+    `amount`          BIGINT NOT NULL COMMENT '转账金额(必须为正数)',
+    `currency`        VARCHAR(3) NOT NULL COMMENT '货币类型',
+    `execute_at`      TIMESTAMP NOT NULL COMMENT '计划执行时间',
+    `status`          VARCHAR(16) NOT NULL DEFAULT 'pending' COMMENT '状态: pending/processing/executed/failed/cancelled',
+    `transfer_id`     BIGINT NULL DEFAULT NULL COMMENT '执行成功后生成的转账',
+    `failure_reason`  VARCHAR(255) NOT NULL DEFAULT '' COMMENT '执行失败原因',
+    `executed_at`     TIMESTAMP NULL DEFAULT NULL COMMENT '实际执行时间',
+    `created_at`      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    CONSTRAINT `fk_scheduled_transfers_owner`
+        FOREIGN KEY (`owner`)
+        REFERENCES `users` (`username`),
+
+    CONSTRAINT `fk_scheduled_transfers_from_account`
+        FOREIGN KEY (`from_account_id`)
+        REFERENCES `accounts` (`id`),
+
+    CONSTRAINT `fk_scheduled_transfers_to_account`
+        FOREIGN KEY (`to_account_id`)
+        REFERENCES `accounts` (`id`),
+
+    CONSTRAINT `fk_scheduled_transfers_transfer`
+        FOREIGN KEY (`transfer_id`)
+        REFERENCES `transfers` (`id`),
+
+    -- 检查约束: 金额必须为正数
+    CONSTRAINT `chk_scheduled_transfers_amount_positive`
+        CHECK (`amount` > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='定时转账表';
+
+-- 唯一索引: 公开ID
+CREATE UNIQUE INDEX `idx_scheduled_transfers_public_id` ON `scheduled_transfers` (`public_id`);
+
+-- 索引: 按创建者查询
+CREATE INDEX `idx_scheduled_transfers_owner` ON `scheduled_transfers` (`owner`);
+
+-- 索引: 按转出账户查询
+CREATE INDEX `idx_scheduled_transfers_from_account_id` ON `scheduled_transfers` (`from_account_id`);
+
+-- 复合索引: 后台任务查询到期任务 (status = 'pending' AND execute_at <= NOW())
+CREATE INDEX `idx_scheduled_transfers_due` ON `scheduled_transfers` (`status`, `execute_at`);
@@ -0,0 +1,11 @@
+-- =====================================================
+-- Migration: 000014_add_recurring_transfers (DOWN)
+-- Description: Rollback - drop recurring transfer rules
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `scheduled_transfers` DROP FOREIGN KEY `fk_scheduled_transfers_recurring_transfer`;
+DROP INDEX `idx_scheduled_transfers_recurring_transfer_id` ON `scheduled_transfers`;
+ALTER TABLE `scheduled_transfers` DROP COLUMN `recurring_transfer_id`;
+
+DROP TABLE IF EXISTS `recurring_transfers`;
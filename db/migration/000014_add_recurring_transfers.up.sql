@@ -0,0 +1,57 @@
+-- =====================================================
+-- Migration: 000014_add_recurring_transfers
+-- Description: Add recurring transfer rules (standing orders)
+-- Database: MySQL 8.0+
+-- =====================================================
+
+CREATE TABLE `recurring_transfers` (
+    `id`               BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `public_id`        CHAR(36) NOT NULL COMMENT '公开ID',
+    `owner`            VARCHAR(255) NOT NULL COMMENT '创建者(用户名)',
+    `from_account_id`  BIGINT NOT NULL COMMENT '转出账户',
+    `to_account_id`    BIGINT NOT NULL COMMENT '转入账户',
+    `amount`           BIGINT NOT NULL COMMENT '每次转账金额(必须为正数)',
+    `currency`         VARCHAR(3) NOT NULL COMMENT '货币类型',
+    `cadence`          VARCHAR(16) NOT NULL COMMENT '频率: daily/weekly/monthly',
+    `start_at`         TIMESTAMP NOT NULL COMMENT '第一次执行时间',
+    `end_at`           TIMESTAMP NULL DEFAULT NULL COMMENT '结束时间(含)，NULL 表示不结束',
+    `occurrence_count` INT NOT NULL DEFAULT 0 COMMENT '已生成的次数',
+    `status`           VARCHAR(16) NOT NULL DEFAULT 'active' COMMENT '状态: active/completed/cancelled',
+    `created_at`       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    CONSTRAINT `fk_recurring_transfers_owner`
+        FOREIGN KEY (`owner`)
+        REFERENCES `users` (`username`),
+
+    CONSTRAINT `fk_recurring_transfers_from_account`
+        FOREIGN KEY (`from_account_id`)
+        REFERENCES `accounts` (`id`),
+
+    CONSTRAINT `fk_recurring_transfers_to_account`
+        FOREIGN KEY (`to_account_id`)
+        REFERENCES `accounts` (`id`),
+
+    -- 检查约束: 金额必须为正数
+    CONSTRAINT `chk_recurring_transfers_amount_positive`
+        CHECK (`amount` > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='周期转账规则表';
+
+-- 唯一索引: 公开ID
+CREATE UNIQUE INDEX `idx_recurring_transfers_public_id` ON `recurring_transfers` (`public_id`);
+
+-- 索引: 按创建者查询
+CREATE INDEX `idx_recurring_transfers_owner` ON `recurring_transfers` (`owner`);
+
+-- 索引: 按转出账户查询
+CREATE INDEX `idx_recurring_transfers_from_account_id` ON `recurring_transfers` (`from_account_id`);
+
+-- 定时转账关联到生成它的周期转账规则
+ALTER TABLE `scheduled_transfers`
+    ADD COLUMN `recurring_transfer_id` BIGINT NULL DEFAULT NULL COMMENT '由周期转账规则生成时指向规则' AFTER `status`,
+    ADD CONSTRAINT `fk_scheduled_transfers_recurring_transfer`
+        FOREIGN KEY (`recurring_transfer_id`)
+        REFERENCES `recurring_transfers` (`id`);
+
+CREATE INDEX `idx_scheduled_transfers_recurring_transfer_id` ON `scheduled_transfers` (`recurring_transfer_id`);
@@ -0,0 +1,9 @@
+-- =====================================================
+-- Migration: 000015_add_transfer_reversal (DOWN)
+-- Description: Rollback - remove reversal link from transfers
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `transfers` DROP FOREIGN KEY `fk_transfers_reversal_of`;
+DROP INDEX `idx_transfers_reversal_of` ON `transfers`;
+ALTER TABLE `transfers` DROP COLUMN `reversal_of`;
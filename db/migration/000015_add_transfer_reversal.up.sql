@@ -0,0 +1,15 @@
+-- =====================================================
+-- Migration: 000015_add_transfer_reversal
+-- Description: Link reversal transfers to the transfer they undo
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `transfers`
+    ADD COLUMN `reversal_of` BIGINT NULL COMMENT '被撤销的原转账ID，为空表示普通转账' AFTER `amount`;
+
+-- 每笔转账最多被撤销一次
+CREATE UNIQUE INDEX `idx_transfers_reversal_of` ON `transfers`(`reversal_of`);
+
+ALTER TABLE `transfers`
+    ADD CONSTRAINT `fk_transfers_reversal_of`
+    FOREIGN KEY (`reversal_of`) REFERENCES `transfers`(`id`);
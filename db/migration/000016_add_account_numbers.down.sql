@@ -0,0 +1,8 @@
+-- =====================================================
+-- Migration: 000016_add_account_numbers (DOWN)
+-- Description: Rollback - remove account numbers
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP INDEX `idx_accounts_number` ON `accounts`;
+ALTER TABLE `accounts` DROP COLUMN `number`;
@@ -0,0 +1,14 @@
+-- =====================================================
+-- Migration: 000016_add_account_numbers
+-- Description: Add account numbers that clients can share with payers
+--              (recipients can be addressed by number instead of id)
+-- Database: MySQL 8.0+
+-- =====================================================
+
+-- 先允许 NULL，为已有账户生成账号后再加 NOT NULL 约束
+-- 已有账户的账号为补零的 ID (以 0 开头)，新账户的账号由应用随机生成 (不以 0 开头)，两者不会冲突
+ALTER TABLE `accounts`
+    ADD COLUMN `number` CHAR(12) NULL COMMENT '账号(12 位数字)，用于收款' AFTER `public_id`;
+UPDATE `accounts` SET `number` = LPAD(`id`, 12, '0') WHERE `number` IS NULL;
+ALTER TABLE `accounts` MODIFY COLUMN `number` CHAR(12) NOT NULL COMMENT '账号(12 位数字)，用于收款';
+CREATE UNIQUE INDEX `idx_accounts_number` ON `accounts` (`number`);
@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000017_add_api_keys (DOWN)
+-- Description: Rollback - drop api_keys table
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP TABLE IF EXISTS `api_keys`;
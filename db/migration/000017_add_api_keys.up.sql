@@ -0,0 +1,33 @@
+-- =====================================================
+-- Migration: 000017_add_api_keys
+-- Description: Add long-lived API keys for server-to-server access
+-- Database: MySQL 8.0+
+-- =====================================================
+
+CREATE TABLE `api_keys` (
+    `id`           BIGINT AUTO_INCREMENT PRIMARY KEY,
+    `public_id`    CHAR(36) NOT NULL COMMENT '公开ID',
+    `owner`        VARCHAR(255) NOT NULL COMMENT '所有者(用户名)',
+    `name`         VARCHAR(64) NOT NULL COMMENT '名称(用于识别用途)',
+    `prefix`       VARCHAR(16) NOT NULL COMMENT 'Key 前缀(明文)，用于识别',
+    `secret_hash`  CHAR(64) NOT NULL COMMENT 'Key 的 SHA-256 (十六进制)，不保存明文',
+    `scopes`       VARCHAR(255) NOT NULL COMMENT '权限范围(逗号分隔)',
+    `revoked`      BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否已撤销',
+    `revoked_at`   TIMESTAMP NULL DEFAULT NULL COMMENT '撤销时间',
+    `last_used_at` TIMESTAMP NULL DEFAULT NULL COMMENT '最后使用时间',
+    `created_at`   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- 外键约束
+    CONSTRAINT `fk_api_keys_owner`
+        FOREIGN KEY (`owner`)
+        REFERENCES `users` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='API Key 表';
+
+-- 唯一索引: 公开ID
+CREATE UNIQUE INDEX `idx_api_keys_public_id` ON `api_keys` (`public_id`);
+
+-- 唯一索引: 认证时按 Key 的哈希查询
+CREATE UNIQUE INDEX `idx_api_keys_secret_hash` ON `api_keys` (`secret_hash`);
+
+-- 索引: 按所有者查询
+CREATE INDEX `idx_api_keys_owner` ON `api_keys` (`owner`);
@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000018_add_account_frozen (DOWN)
+-- Description: Rollback - remove frozen flag from accounts
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts` DROP COLUMN `is_frozen`;
@@ -0,0 +1,9 @@
+-- =====================================================
+-- Migration: 000018_add_account_frozen
+-- Description: Add frozen flag to accounts
+--              (frozen accounts stay queryable but reject transfers)
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `accounts`
+    ADD COLUMN `is_frozen` BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否冻结，冻结期间不能转入转出' AFTER `min_balance`;
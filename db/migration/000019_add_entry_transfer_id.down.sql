@@ -0,0 +1,9 @@
+-- =====================================================
+-- Migration: 000019_add_entry_transfer_id (DOWN)
+-- Description: Rollback - remove transfer link from entries
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `entries` DROP FOREIGN KEY `fk_entries_transfer`;
+DROP INDEX `idx_entries_transfer_id` ON `entries`;
+ALTER TABLE `entries` DROP COLUMN `transfer_id`;
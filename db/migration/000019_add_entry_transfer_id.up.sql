@@ -0,0 +1,47 @@
+-- =====================================================
+-- Migration: 000019_add_entry_transfer_id
+-- Description: Link entries to the transfer that created them
+--              (used by the unified account history)
+-- Database: MySQL 8.0+
+-- =====================================================
+
+ALTER TABLE `entries`
+    ADD COLUMN `transfer_id` BIGINT NULL COMMENT '产生该账目的转账ID，为空表示非转账资金变动' AFTER `amount`;
+
+CREATE INDEX `idx_entries_transfer_id` ON `entries`(`transfer_id`);
+
+ALTER TABLE `entries`
+    ADD CONSTRAINT `fk_entries_transfer`
+    FOREIGN KEY (`transfer_id`) REFERENCES `transfers`(`id`);
+
+-- 回填已有账目: 转账与双方账目在同一事务中创建，账户、金额和创建时间都相同
+-- created_at 只精确到秒，同一秒内相同账户、相同金额的转账无法区分，因此:
+--   1. 一笔转账必须同时匹配到出账和入账两条账目
+--   2. 转账、出账账目、入账账目在所有候选中都只出现一次
+-- 不满足的 (有歧义的) 账目保持 transfer_id 为 NULL，在账户历史中显示为普通资金变动
+CREATE TABLE `entry_transfer_backfill` AS
+SELECT `transfer_id`, `debit_entry_id`, `credit_entry_id`
+FROM (
+    SELECT t.`id` AS `transfer_id`,
+           d.`id` AS `debit_entry_id`,
+           c.`id` AS `credit_entry_id`,
+           COUNT(*) OVER (PARTITION BY t.`id`) AS `transfer_matches`,
+           COUNT(*) OVER (PARTITION BY d.`id`) AS `debit_matches`,
+           COUNT(*) OVER (PARTITION BY c.`id`) AS `credit_matches`
+    FROM `transfers` t
+        JOIN `entries` d
+            ON d.`account_id` = t.`from_account_id` AND d.`amount` = -t.`amount` AND d.`created_at` = t.`created_at`
+        JOIN `entries` c
+            ON c.`account_id` = t.`to_account_id` AND c.`amount` = t.`amount` AND c.`created_at` = t.`created_at`
+) candidates
+WHERE `transfer_matches` = 1 AND `debit_matches` = 1 AND `credit_matches` = 1;
+
+UPDATE `entries` e
+    JOIN `entry_transfer_backfill` b ON e.`id` = b.`debit_entry_id`
+SET e.`transfer_id` = b.`transfer_id`;
+
+UPDATE `entries` e
+    JOIN `entry_transfer_backfill` b ON e.`id` = b.`credit_entry_id`
+SET e.`transfer_id` = b.`transfer_id`;
+
+DROP TABLE `entry_transfer_backfill`;
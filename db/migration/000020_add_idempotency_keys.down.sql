@@ -0,0 +1,7 @@
+-- =====================================================
+-- Migration: 000020_add_idempotency_keys (DOWN)
+-- Description: Rollback - drop idempotency_keys table
+-- Database: MySQL 8.0+
+-- =====================================================
+
+DROP TABLE IF EXISTS `idempotency_keys`;
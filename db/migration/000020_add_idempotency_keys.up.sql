@@ -0,0 +1,20 @@
+-- =====================================================
+-- Migration: 000020_add_idempotency_keys
+-- Description: Add shared storage for Idempotency-Key request state
+--              (used when IDEMPOTENCY_STORE=database)
+-- Database: MySQL 8.0+
+-- =====================================================
+
+CREATE TABLE `idempotency_keys` (
+    `idempotency_key` VARCHAR(255) NOT NULL PRIMARY KEY COMMENT '客户端提供的幂等键',
+    `fingerprint`     CHAR(64) NOT NULL COMMENT '请求指纹(方法、路径和请求体的 SHA-256)',
+    `done`            BOOLEAN NOT NULL DEFAULT FALSE COMMENT '首次请求是否已成功完成',
+    `status_code`     INT NOT NULL DEFAULT 0 COMMENT '首次成功响应的状态码',
+    `content_type`    VARCHAR(255) NOT NULL DEFAULT '' COMMENT '首次成功响应的 Content-Type',
+    `body`            MEDIUMBLOB NULL COMMENT '首次成功响应的响应体',
+    `expires_at`      TIMESTAMP NOT NULL COMMENT '过期时间，过期后键可以被重新占用',
+    `created_at`      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='幂等键表';
+
+-- 索引: 清理过期记录
+CREATE INDEX `idx_idempotency_keys_expires_at` ON `idempotency_keys` (`expires_at`);
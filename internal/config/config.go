@@ -1,11 +1,21 @@
 // Package config 负责加载和管理应用程序配置
-// 使用 Viper 库从环境变量和 .env 文件中读取配置
+// 使用 Viper 库从环境变量、.env 文件和可选的 YAML/JSON 配置文件中读取配置
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/proyuen/simple-bank-v2/pkg/currency"
 )
 
 // Config 存储应用程序的所有配置
@@ -14,6 +24,12 @@ type Config struct {
 	// 环境配置
 	Environment string `mapstructure:"ENVIRONMENT"` // 运行环境: development, production
 
+	// 日志配置
+	LogLevel  string `mapstructure:"LOG_LEVEL"`  // 日志级别: debug, info, warn, error，默认 info
+	LogFormat string `mapstructure:"LOG_FORMAT"` // 日志格式: json, text，默认生产环境 json，其他环境 text
+
+	AccessLogSkipPaths []string `mapstructure:"ACCESS_LOG_SKIP_PATHS"` // 不记录访问日志的路径，默认 /health 和 /ready
+
 	// 数据库配置
 	DBHost            string        `mapstructure:"DB_HOST"`
 	DBPort            string        `mapstructure:"DB_PORT"`
@@ -23,17 +39,108 @@ type Config struct {
 	DBMaxIdleConns    int           `mapstructure:"DB_MAX_IDLE_CONNS"`
 	DBMaxOpenConns    int           `mapstructure:"DB_MAX_OPEN_CONNS"`
 	DBConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	DBLogLevel        string        `mapstructure:"DB_LOG_LEVEL"`      // SQL 日志级别: silent, error, warn, info
+	DBSlowThreshold   time.Duration `mapstructure:"DB_SLOW_THRESHOLD"` // 慢查询阈值，超过时记录警告
+	DBAutoMigrate     bool          `mapstructure:"DB_AUTO_MIGRATE"`   // 启动时执行 AutoMigrate (仅用于本地开发)
 
 	// 服务器配置
-	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
+	ServerAddress         string        `mapstructure:"SERVER_ADDRESS"`
 	ServerShutdownTimeout time.Duration `mapstructure:"SERVER_SHUTDOWN_TIMEOUT"`
+	WorkerShutdownTimeout time.Duration `mapstructure:"WORKER_SHUTDOWN_TIMEOUT"` // 关闭时等待后台任务完成当前一轮的时间
+	ResponseEnvelope      bool          `mapstructure:"RESPONSE_ENVELOPE"`       // 成功响应是否包装为 {code, message, data}
+	JSONAmountsAsStrings  bool          `mapstructure:"JSON_AMOUNTS_AS_STRINGS"` // 响应中的金额输出为字符串，避免 JavaScript 丢失精度
+	MaxRequestBytes       int64         `mapstructure:"MAX_REQUEST_BYTES"`       // 请求体大小上限 (字节)
+	OpenAPIValidation     bool          `mapstructure:"OPENAPI_VALIDATION"`      // 按 OpenAPI 规范校验请求体
+	OpenAPISpecPath       string        `mapstructure:"OPENAPI_SPEC_PATH"`       // swag 生成的规范文件 (swagger.json)
+	StrictRequestFields   bool          `mapstructure:"STRICT_REQUEST_FIELDS"`   // JSON 请求体包含未定义的字段时返回 400
+
+	// TLS 配置 (两者都设置时启用 HTTPS，都不设置时使用 HTTP)
+	TLSCertFile string `mapstructure:"TLS_CERT_FILE"` // 证书文件路径 (PEM)
+	TLSKeyFile  string `mapstructure:"TLS_KEY_FILE"`  // 私钥文件路径 (PEM)
 
 	// JWT 配置
 	TokenSecretKey       string        `mapstructure:"TOKEN_SECRET_KEY"`
+	TokenPreviousKeys    []string      `mapstructure:"TOKEN_SECRET_KEY_PREVIOUS"` // 轮换前的旧密钥，仅用于验证
+	TokenIssuer          string        `mapstructure:"TOKEN_ISSUER"`              // Token 签发方 (iss)，为空时不校验
+	TokenAudience        string        `mapstructure:"TOKEN_AUDIENCE"`            // Token 接收方 (aud)，为空时不校验
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	RefreshTokenCookie   bool          `mapstructure:"REFRESH_TOKEN_COOKIE"` // 通过 HttpOnly Cookie 下发 Refresh Token
+
+	// 修改密码后使旧 Token 失效 (每个认证请求多一次用户查询)
+	TokenCheckPasswordChange    bool          `mapstructure:"TOKEN_CHECK_PASSWORD_CHANGE"`     // 拒绝修改密码前签发的 Token
+	TokenPasswordChangeCacheTTL time.Duration `mapstructure:"TOKEN_PASSWORD_CHANGE_CACHE_TTL"` // 修改密码时间的缓存有效期，0 表示不缓存
+
+	// 转账限额配置 (单位: 分，0 表示不限制)
+	TransferMaxAmount  int64 `mapstructure:"TRANSFER_MAX_AMOUNT"`  // 单笔转账上限
+	TransferDailyLimit int64 `mapstructure:"TRANSFER_DAILY_LIMIT"` // 单账户 24 小时累计转出上限
+	MinTransferAmount  int64 `mapstructure:"MIN_TRANSFER_AMOUNT"`  // 单笔转账下限 (最小货币单位)
+
+	// 账户配置
+	MaxAccountsPerUser int    `mapstructure:"MAX_ACCOUNTS_PER_USER"` // 每个用户最多拥有的未关闭账户数，0 表示不限制
+	DefaultCurrency    string `mapstructure:"DEFAULT_CURRENCY"`      // 创建账户时省略货币使用的默认货币，为空表示必须指定
+
+	// 转账撤销配置
+	TransferReversalWindow time.Duration `mapstructure:"TRANSFER_REVERSAL_WINDOW"` // 转账创建后允许转出方撤销的时间窗口
+
+	// 会话配置
+	SessionIdleTimeout time.Duration `mapstructure:"SESSION_IDLE_TIMEOUT"` // 会话空闲超时，0 表示不启用
+
+	// 敏感操作 step-up 配置 (要求最近登录)
+	StepUpMaxTokenAge    time.Duration `mapstructure:"STEP_UP_MAX_TOKEN_AGE"`   // 登录超过该时间后敏感操作需要重新登录，0 表示不启用
+	StepUpTransferAmount int64         `mapstructure:"STEP_UP_TRANSFER_AMOUNT"` // 不低于该金额 (单位: 分) 的转账视为敏感操作，0 表示不按金额要求
+
+	// 幂等请求配置
+	IdempotencyKeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"` // 用户注册的 Idempotency-Key 保留时间
+	IdempotencyStore  string        `mapstructure:"IDEMPOTENCY_STORE"`   // 幂等键的存储: memory (进程内)、database 或 redis (多实例共享)
+
+	// Redis 配置 (IDEMPOTENCY_STORE=redis 时使用)
+	RedisAddr     string `mapstructure:"REDIS_ADDR"`     // 地址 (host:port)
+	RedisPassword string `mapstructure:"REDIS_PASSWORD"` // 密码，为空表示不认证
+	RedisDB       int    `mapstructure:"REDIS_DB"`       // 数据库编号
+
+	// 登录限流配置
+	LoginRateLimit  int           `mapstructure:"LOGIN_RATE_LIMIT"`  // 每个 IP 在一个窗口内允许的登录请求数，0 表示不限流
+	LoginRateWindow time.Duration `mapstructure:"LOGIN_RATE_WINDOW"` // 登录限流的窗口长度
+
+	// 响应缓存配置
+	ResponseCacheTTL time.Duration `mapstructure:"RESPONSE_CACHE_TTL"` // 账户、转账列表等热点 GET 响应的缓存时间，0 表示不缓存
+
+	// 定时转账配置
+	ScheduledTransferInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_INTERVAL"` // 后台任务检查到期定时转账的间隔
+
+	// 汇率配置
+	FXRates        []string      `mapstructure:"FX_RATES"`          // 静态汇率表，格式 FROM/TO=RATE
+	FXProviderURL  string        `mapstructure:"FX_PROVIDER_URL"`   // 外部汇率服务地址，为空时使用静态汇率表
+	FXCacheTTL     time.Duration `mapstructure:"FX_CACHE_TTL"`      // 汇率缓存有效期
+	FXStaleOnError bool          `mapstructure:"FX_STALE_ON_ERROR"` // 外部汇率服务失败时返回过期的缓存值
+
+	// 分页配置
+	PageSizeDefault        int  `mapstructure:"PAGE_SIZE_DEFAULT"`         // 未携带 page_size 时的每页条数
+	PageSizeMax            int  `mapstructure:"PAGE_SIZE_MAX"`             // 每页条数上限
+	PageSizeRejectOversize bool `mapstructure:"PAGE_SIZE_REJECT_OVERSIZE"` // 超过上限时返回 400，默认截断到上限
+
+	// 管理配置
+	AdminAllowedIPs []string `mapstructure:"ADMIN_ALLOWED_IPS"` // 允许访问内部管理接口的 IP/CIDR 列表 (按 TCP 连接的对端地址判断)
+
+	// 反向代理配置
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"` // 信任其 X-Forwarded-For 的代理 IP/CIDR 列表，为空表示不信任任何代理
+
+	// 可热更新配置 (见 RuntimeConfig)
+	Runtime RuntimeConfig `mapstructure:",squash"`
+
+	// Path 和 Files 记录加载时使用的参数，由 LoadConfig 设置，用于重新加载
+	Path  string   `mapstructure:"-"`
+	Files []string `mapstructure:"-"`
 }
 
+// IDEMPOTENCY_STORE 的取值
+const (
+	IdempotencyStoreMemory   = "memory"   // 进程内，多实例部署时每个实例分别保存
+	IdempotencyStoreDatabase = "database" // idempotency_keys 表，多实例共享
+	IdempotencyStoreRedis    = "redis"    // Redis (REDIS_ADDR)，多实例共享，过期由 Redis 删除
+)
+
 // Defaults 设置配置的默认值
 func (c *Config) Defaults() {
 	if c.Environment == "" {
@@ -48,9 +155,51 @@ func (c *Config) Defaults() {
 	if c.DBConnMaxLifetime == 0 {
 		c.DBConnMaxLifetime = time.Hour
 	}
+	if c.DBSlowThreshold == 0 {
+		c.DBSlowThreshold = 200 * time.Millisecond
+	}
 	if c.ServerShutdownTimeout == 0 {
 		c.ServerShutdownTimeout = 10 * time.Second
 	}
+	if c.WorkerShutdownTimeout == 0 {
+		c.WorkerShutdownTimeout = c.ServerShutdownTimeout
+	}
+	if c.MaxRequestBytes == 0 {
+		c.MaxRequestBytes = 1 << 20 // 1 MiB
+	}
+	if c.OpenAPISpecPath == "" {
+		c.OpenAPISpecPath = "docs/swagger.json"
+	}
+	if c.FXCacheTTL == 0 {
+		c.FXCacheTTL = 5 * time.Minute
+	}
+	if c.ScheduledTransferInterval == 0 {
+		c.ScheduledTransferInterval = 30 * time.Second
+	}
+	if c.PageSizeDefault == 0 {
+		c.PageSizeDefault = 10
+	}
+	if c.PageSizeMax == 0 {
+		c.PageSizeMax = 100
+	}
+	if c.TransferReversalWindow == 0 {
+		c.TransferReversalWindow = 24 * time.Hour
+	}
+	if c.IdempotencyKeyTTL == 0 {
+		c.IdempotencyKeyTTL = 10 * time.Minute
+	}
+	if c.IdempotencyStore == "" {
+		c.IdempotencyStore = IdempotencyStoreMemory
+	}
+	if c.LoginRateWindow == 0 {
+		c.LoginRateWindow = time.Minute
+	}
+	if len(c.AccessLogSkipPaths) == 0 {
+		c.AccessLogSkipPaths = []string{"/health", "/ready"}
+	}
+	if len(c.AdminAllowedIPs) == 0 {
+		c.AdminAllowedIPs = []string{"127.0.0.1", "::1"}
+	}
 }
 
 // IsProduction 返回是否为生产环境
@@ -58,14 +207,221 @@ func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
 
+// minTokenSecretKeySize Token 签名密钥的最小长度 (与 token 包的要求一致)
+const minTokenSecretKeySize = 32
+
+// Validate 检查必填配置和取值范围
+//
+// 一次性返回所有问题 (用换行分隔)，便于一次修正全部配置
+// 应在 LoadConfig 之后、创建应用之前调用
+func (c *Config) Validate() error {
+	var problems []error
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	// 数据库配置
+	problems = append(problems, c.databaseProblems()...)
+	switch strings.ToLower(c.DBLogLevel) {
+	case "", "silent", "error", "warn", "info":
+	default:
+		addf("DB_LOG_LEVEL %q must be one of silent, error, warn, info", c.DBLogLevel)
+	}
+
+	// 服务器配置
+	if c.ServerAddress == "" {
+		addf("SERVER_ADDRESS is required")
+	} else if _, port, err := net.SplitHostPort(c.ServerAddress); err != nil {
+		addf("SERVER_ADDRESS %q is invalid: %v", c.ServerAddress, err)
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		addf("SERVER_ADDRESS %q has invalid port", c.ServerAddress)
+	}
+	if c.ServerShutdownTimeout <= 0 {
+		addf("SERVER_SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.WorkerShutdownTimeout <= 0 {
+		addf("WORKER_SHUTDOWN_TIMEOUT must be positive")
+	}
+
+	// JWT 配置
+	if len(c.TokenSecretKey) < minTokenSecretKeySize {
+		addf("TOKEN_SECRET_KEY must be at least %d characters", minTokenSecretKeySize)
+	}
+	for _, key := range c.TokenPreviousKeys {
+		if len(key) < minTokenSecretKeySize {
+			addf("TOKEN_SECRET_KEY_PREVIOUS keys must be at least %d characters", minTokenSecretKeySize)
+			break
+		}
+	}
+	if c.AccessTokenDuration <= 0 {
+		addf("ACCESS_TOKEN_DURATION must be positive")
+	}
+	if c.RefreshTokenDuration <= 0 {
+		addf("REFRESH_TOKEN_DURATION must be positive")
+	}
+	if c.AccessTokenDuration > 0 && c.RefreshTokenDuration > 0 && c.AccessTokenDuration > c.RefreshTokenDuration {
+		addf("ACCESS_TOKEN_DURATION (%s) must not exceed REFRESH_TOKEN_DURATION (%s)",
+			c.AccessTokenDuration, c.RefreshTokenDuration)
+	}
+
+	// 其他取值范围
+	if c.TransferMaxAmount < 0 || c.TransferDailyLimit < 0 {
+		addf("TRANSFER_MAX_AMOUNT and TRANSFER_DAILY_LIMIT must not be negative")
+	}
+	if c.MaxAccountsPerUser < 0 {
+		addf("MAX_ACCOUNTS_PER_USER must not be negative")
+	}
+	if c.DefaultCurrency != "" && !currency.IsSupported(c.DefaultCurrency) {
+		addf("DEFAULT_CURRENCY %q is not supported (supported: %s)",
+			c.DefaultCurrency, strings.Join(currency.Supported(), ", "))
+	}
+	if c.MinTransferAmount < 0 {
+		addf("MIN_TRANSFER_AMOUNT must not be negative")
+	}
+	if c.MinTransferAmount > 0 && c.TransferMaxAmount > 0 && c.MinTransferAmount > c.TransferMaxAmount {
+		addf("MIN_TRANSFER_AMOUNT (%d) must not exceed TRANSFER_MAX_AMOUNT (%d)", c.MinTransferAmount, c.TransferMaxAmount)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if !isIPOrCIDR(proxy) {
+			addf("TRUSTED_PROXIES entry %q must be an IP address or CIDR", proxy)
+		}
+	}
+	if c.PageSizeMax < 5 {
+		addf("PAGE_SIZE_MAX must be at least 5")
+	}
+	if c.PageSizeDefault < 5 || c.PageSizeDefault > c.PageSizeMax {
+		addf("PAGE_SIZE_DEFAULT must be between 5 and PAGE_SIZE_MAX (%d)", c.PageSizeMax)
+	}
+	if c.TokenPasswordChangeCacheTTL < 0 {
+		addf("TOKEN_PASSWORD_CHANGE_CACHE_TTL must not be negative")
+	}
+	if c.TransferReversalWindow < 0 {
+		addf("TRANSFER_REVERSAL_WINDOW must not be negative")
+	}
+	if c.SessionIdleTimeout < 0 {
+		addf("SESSION_IDLE_TIMEOUT must not be negative")
+	}
+	if c.StepUpMaxTokenAge < 0 {
+		addf("STEP_UP_MAX_TOKEN_AGE must not be negative")
+	}
+	if c.StepUpTransferAmount < 0 {
+		addf("STEP_UP_TRANSFER_AMOUNT must not be negative")
+	}
+	if c.IdempotencyKeyTTL < 0 {
+		addf("IDEMPOTENCY_KEY_TTL must not be negative")
+	}
+	switch c.IdempotencyStore {
+	case IdempotencyStoreMemory, IdempotencyStoreDatabase:
+	case IdempotencyStoreRedis:
+		if c.RedisAddr == "" {
+			addf("REDIS_ADDR is required when IDEMPOTENCY_STORE is %s", IdempotencyStoreRedis)
+		}
+	default:
+		addf("IDEMPOTENCY_STORE %q must be one of %s, %s, %s",
+			c.IdempotencyStore, IdempotencyStoreMemory, IdempotencyStoreDatabase, IdempotencyStoreRedis)
+	}
+	if c.RedisDB < 0 {
+		addf("REDIS_DB must not be negative")
+	}
+	if c.LoginRateLimit < 0 {
+		addf("LOGIN_RATE_LIMIT must not be negative")
+	}
+	if c.LoginRateWindow < 0 {
+		addf("LOGIN_RATE_WINDOW must not be negative")
+	}
+	if c.ResponseCacheTTL < 0 {
+		addf("RESPONSE_CACHE_TTL must not be negative")
+	}
+	if c.ScheduledTransferInterval < 0 {
+		addf("SCHEDULED_TRANSFER_INTERVAL must not be negative")
+	}
+	if err := c.ValidateTLS(); err != nil {
+		problems = append(problems, err)
+	}
+
+	return joinProblems(problems)
+}
+
+// maxAccessTokenDuration 建议的 Access Token 最长有效期
+// Access Token 无法单独撤销，有效期过长时泄露的 Token 长期可用
+const maxAccessTokenDuration = 24 * time.Hour
+
+// Warnings 返回不影响启动但可能是误配置的问题
+// 应在 Validate 通过之后调用，由调用方记录日志
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if c.AccessTokenDuration > maxAccessTokenDuration {
+		warnings = append(warnings, fmt.Sprintf(
+			"ACCESS_TOKEN_DURATION %s is longer than %s; consider a shorter lifetime and relying on refresh tokens",
+			c.AccessTokenDuration, maxAccessTokenDuration))
+	}
+	return warnings
+}
+
+// ValidateDatabase 只检查数据库连接配置
+// 用于只需要连接数据库的命令 (如 cmd/migrate、cmd/reconcile)
+func (c *Config) ValidateDatabase() error {
+	return joinProblems(c.databaseProblems())
+}
+
+// databaseProblems 返回数据库连接配置中缺失的必填项
+func (c *Config) databaseProblems() []error {
+	var problems []error
+	for name, value := range map[string]string{
+		"DB_HOST": c.DBHost,
+		"DB_PORT": c.DBPort,
+		"DB_USER": c.DBUser,
+		"DB_NAME": c.DBName,
+	} {
+		if value == "" {
+			problems = append(problems, fmt.Errorf("%s is required", name))
+		}
+	}
+	return problems
+}
+
+// joinProblems 把配置问题合并为一个错误，没有问题时返回 nil
+func joinProblems(problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	// map 遍历顺序不固定，排序后输出稳定的错误信息
+	slices.SortFunc(problems, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return fmt.Errorf("invalid config:\n%w", errors.Join(problems...))
+}
+
+// TLSEnabled 返回是否启用 HTTPS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// ValidateTLS 检查 TLS 配置
+// 证书和私钥必须同时设置或同时不设置，设置时文件必须存在
+func (c *Config) ValidateTLS() error {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" {
+		return nil
+	}
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	for _, file := range []string{c.TLSCertFile, c.TLSKeyFile} {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("tls file: %w", err)
+		}
+	}
+	return nil
+}
+
 // LoadConfig 从指定路径加载配置
 //
-// 加载顺序：
+// 加载顺序 (后者覆盖前者)：
 // 1. 读取 .env 文件（如果存在）
-// 2. 读取系统环境变量（会覆盖 .env 中的值）
+// 2. 依次合并 files 指定的结构化配置文件 (YAML/JSON/TOML，按扩展名识别)
+// 3. 读取系统环境变量（优先级最高）
 //
 // 参数:
 //   - path: 配置文件所在目录路径（例如 "." 表示当前目录）
+//   - files: 可选的结构化配置文件，相对路径相对于 path；文件必须存在
 //
 // 返回:
 //   - config: 加载完成的配置结构体
@@ -78,23 +434,29 @@ func (c *Config) IsProduction() bool {
 //	    log.Fatal("无法加载配置:", err)
 //	}
 //	fmt.Println("服务器地址:", cfg.ServerAddress)
-func LoadConfig(path string) (config Config, err error) {
+//
+//	// 额外合并 config.yaml (键名与环境变量相同，大小写不敏感)
+//	cfg, err := config.LoadConfig(".", "config.yaml")
+func LoadConfig(path string, files ...string) (config Config, err error) {
+	// 每次加载使用独立的 Viper 实例，重新加载时不会残留上一次的状态
+	v := viper.New()
+
 	// 告诉 Viper 在哪个目录查找配置文件
-	viper.AddConfigPath(path)
+	v.AddConfigPath(path)
 
 	// 设置配置文件名（不包含扩展名）
-	viper.SetConfigName(".env")
+	v.SetConfigName(".env")
 
 	// 设置配置文件类型为环境变量格式
-	viper.SetConfigType("env")
+	v.SetConfigType("env")
 
 	// 自动读取系统环境变量
 	// 这允许环境变量覆盖 .env 文件中的值
 	// 这在 Docker/Kubernetes 部署时非常有用
-	viper.AutomaticEnv()
+	v.AutomaticEnv()
 
 	// 尝试读取配置文件
-	err = viper.ReadInConfig()
+	err = v.ReadInConfig()
 	if err != nil {
 		// 如果是文件不存在错误，我们可以继续（依赖环境变量）
 		// 如果是其他错误，则返回
@@ -105,15 +467,30 @@ func LoadConfig(path string) (config Config, err error) {
 		err = nil
 	}
 
+	// 合并结构化配置文件，只覆盖文件中出现的键
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(path, file)
+		}
+		v.SetConfigFile(file)
+		v.SetConfigType(strings.TrimPrefix(filepath.Ext(file), "."))
+		if err = v.MergeInConfig(); err != nil {
+			err = fmt.Errorf("merge config file %s: %w", file, err)
+			return
+		}
+	}
+
 	// 将配置值解析到 Config 结构体
 	// mapstructure 标签指定了环境变量名与结构体字段的映射关系
-	err = viper.Unmarshal(&config)
+	err = v.Unmarshal(&config)
 	if err != nil {
 		return
 	}
 
 	// 应用默认值
 	config.Defaults()
+	config.Path = path
+	config.Files = files
 	return
 }
 
@@ -130,3 +507,12 @@ func (c *Config) DBSource() string {
 		"@tcp(" + c.DBHost + ":" + c.DBPort + ")/" +
 		c.DBName + "?charset=utf8mb4&parseTime=true&loc=Local"
 }
+
+// isIPOrCIDR 判断 s 是否为 IP 地址 (127.0.0.1) 或 CIDR (10.0.0.0/8)
+func isIPOrCIDR(s string) bool {
+	if net.ParseIP(s) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
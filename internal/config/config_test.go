@@ -0,0 +1,121 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig 返回能通过 Validate 的最小配置，测试在此基础上修改单个字段
+func validConfig() Config {
+	c := Config{
+		DBHost:               "localhost",
+		DBPort:               "3306",
+		DBUser:               "bank",
+		DBName:               "simple_bank",
+		ServerAddress:        "0.0.0.0:8080",
+		TokenSecretKey:       strings.Repeat("k", minTokenSecretKeySize),
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+	}
+	c.Defaults()
+	return c
+}
+
+func TestValidConfig(t *testing.T) {
+	c := validConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
+
+func TestValidateTokenDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		access  time.Duration
+		refresh time.Duration
+		want    string
+	}{
+		{"zero access", 0, time.Hour, "ACCESS_TOKEN_DURATION must be positive"},
+		{"negative access", -time.Minute, time.Hour, "ACCESS_TOKEN_DURATION must be positive"},
+		{"zero refresh", time.Minute, 0, "REFRESH_TOKEN_DURATION must be positive"},
+		{"access longer than refresh", 2 * time.Hour, time.Hour, "must not exceed REFRESH_TOKEN_DURATION"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			c.AccessTokenDuration, c.RefreshTokenDuration = tt.access, tt.refresh
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarnsOnLongAccessToken(t *testing.T) {
+	c := validConfig()
+	if warnings := c.Warnings(); len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none", warnings)
+	}
+
+	// 720h 合法 (不超过 Refresh Token)，但给出警告
+	c.AccessTokenDuration, c.RefreshTokenDuration = 720*time.Hour, 720*time.Hour
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if warnings := c.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "ACCESS_TOKEN_DURATION") {
+		t.Errorf("warnings = %v, want one ACCESS_TOKEN_DURATION warning", warnings)
+	}
+}
+
+func TestValidateMinTransferAmount(t *testing.T) {
+	c := validConfig()
+	c.MinTransferAmount = -1
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "MIN_TRANSFER_AMOUNT must not be negative") {
+		t.Errorf("negative minimum: Validate = %v", err)
+	}
+
+	c = validConfig()
+	c.MinTransferAmount, c.TransferMaxAmount = 500, 100
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "must not exceed TRANSFER_MAX_AMOUNT") {
+		t.Errorf("minimum above maximum: Validate = %v", err)
+	}
+}
+
+func TestValidatePageSizes(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultSize int
+		maxSize     int
+		want        string
+	}{
+		{"max below minimum page size", 4, 4, "PAGE_SIZE_MAX"},
+		{"default above max", 60, 50, "PAGE_SIZE_DEFAULT must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			c.PageSizeDefault, c.PageSizeMax = tt.defaultSize, tt.maxSize
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultCurrency(t *testing.T) {
+	for _, code := range []string{"", "USD", "CNY"} {
+		c := validConfig()
+		c.DefaultCurrency = code
+		if err := c.Validate(); err != nil {
+			t.Errorf("DEFAULT_CURRENCY=%q: Validate = %v", code, err)
+		}
+	}
+
+	c := validConfig()
+	c.DefaultCurrency = "JPY"
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), `DEFAULT_CURRENCY "JPY" is not supported`) {
+		t.Errorf("unsupported default: Validate = %v", err)
+	}
+}
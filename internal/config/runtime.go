@@ -0,0 +1,39 @@
+package config
+
+import "sync/atomic"
+
+// RuntimeConfig 可在运行时热更新的配置子集
+//
+// 只包含可以安全切换的开关类配置，数据库连接、Token 密钥等
+// 需要重启才能生效的配置不在此列
+type RuntimeConfig struct {
+	// MaintenanceMode 维护模式: 开启后业务接口返回 503
+	MaintenanceMode bool `mapstructure:"MAINTENANCE_MODE" json:"maintenance_mode"`
+
+	// FeatureFlags 已开启的功能开关列表 (逗号分隔)
+	FeatureFlags []string `mapstructure:"FEATURE_FLAGS" json:"feature_flags"`
+}
+
+// RuntimeStore 持有当前生效的 RuntimeConfig
+//
+// 使用原子指针整体替换，正在处理的请求始终看到一份完整一致的配置
+type RuntimeStore struct {
+	current atomic.Pointer[RuntimeConfig]
+}
+
+// NewRuntimeStore 创建 RuntimeStore 并设置初始配置
+func NewRuntimeStore(initial RuntimeConfig) *RuntimeStore {
+	store := &RuntimeStore{}
+	store.Store(initial)
+	return store
+}
+
+// Load 返回当前生效的配置 (只读，不要修改返回值)
+func (s *RuntimeStore) Load() *RuntimeConfig {
+	return s.current.Load()
+}
+
+// Store 原子替换当前配置
+func (s *RuntimeStore) Store(cfg RuntimeConfig) {
+	s.current.Store(&cfg)
+}
@@ -1,22 +1,111 @@
 package request
 
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
 // CreateAccountRequest 创建账户请求
 // 用于: POST /api/v1/accounts
 type CreateAccountRequest struct {
 	// Currency 货币类型
-	// 规则: 必填, 必须是支持的货币代码
-	Currency string `json:"currency" binding:"required,oneof=USD EUR CNY"`
+	// 规则: 必须是支持的货币代码; 配置了 DEFAULT_CURRENCY 时可省略，省略时使用默认货币
+	Currency string `json:"currency" binding:"omitempty,currency"`
+
+	// Name 账户名称 (如 "Savings")
+	// 规则: 可选, 最多 64 个字符
+	Name string `json:"name" binding:"max=64"`
+}
+
+// CreateAccountsBatchRequest 批量创建账户请求
+// 用于: POST /api/v1/accounts/batch
+type CreateAccountsBatchRequest struct {
+	// Currencies 要创建账户的货币类型列表
+	// 规则: 至少一个, 不能重复, 每个都必须是支持的货币代码
+	Currencies []string `json:"currencies" binding:"required,min=1,max=8,unique,dive,currency"`
+}
+
+// UpdateAccountRequest 修改账户请求
+// 用于: PATCH /api/v1/accounts/:id
+//
+// 不包含 Currency: 账户货币创建后不能修改 (见 model.Account)
+// 开启 STRICT_REQUEST_FIELDS 时，请求体中的 currency 作为未知字段返回 400 (CodeInvalidParams)
+type UpdateAccountRequest struct {
+	// Name 新的账户名称
+	// 规则: 必须传入, 最多 64 个字符, 空字符串表示清除名称
+	// 使用指针以区分 "未传" 和 "传了空字符串"
+	Name *string `json:"name" binding:"required,max=64"`
 }
 
 // GetAccountRequest 获取账户请求
-// 用于: GET /api/v1/accounts/:id
+// 用于: GET /api/v1/accounts/:id 及其他以账户公开ID为路径参数的路由
 type GetAccountRequest struct {
-	ID uint `uri:"id" binding:"required,min=1"`
+	// ID 账户公开ID (UUID)
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// PublicID 返回解析后的账户公开ID
+// 绑定时已经过 uuid 校验，解析不会失败
+func (r *GetAccountRequest) PublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.ID)
+	return id
 }
 
 // ListAccountsRequest 获取账户列表请求
 // 用于: GET /api/v1/accounts
 type ListAccountsRequest struct {
-	PageID   int `form:"page_id" binding:"required,min=1"`
-	PageSize int `form:"page_size" binding:"required,min=5,max=100"`
+	PaginationRequest
+	Currency string `form:"currency" binding:"omitempty,currency"` // 只返回该货币的账户，为空时返回全部
+}
+
+// SetOverdraftLimitRequest 设置透支额度请求 (管理员)
+// 用于: PUT /api/v1/admin/accounts/:id/overdraft-limit
+type SetOverdraftLimitRequest struct {
+	// OverdraftLimit 透支额度 (单位: 分)
+	// 规则: 必填, 0 表示不允许透支
+	// 使用指针以区分 "未传" 和 "传了 0"
+	OverdraftLimit *money.Amount `json:"overdraft_limit" binding:"required,min=0"`
+}
+
+// SetMinBalanceRequest 设置最低余额请求 (管理员)
+// 用于: PUT /api/v1/admin/accounts/:id/min-balance
+type SetMinBalanceRequest struct {
+	// MinBalance 最低余额 (单位: 分)
+	// 规则: 必填, 0 表示不要求最低余额
+	// 使用指针以区分 "未传" 和 "传了 0"
+	MinBalance *money.Amount `json:"min_balance" binding:"required,min=0"`
+}
+
+// GetStatementRequest 获取月度对账单请求
+// 用于: GET /api/v1/accounts/:id/statement
+type GetStatementRequest struct {
+	// Year 年份，例如 2024
+	Year int `form:"year" binding:"required,min=1970,max=9999"`
+
+	// Month 月份 (1-12)
+	Month int `form:"month" binding:"required,min=1,max=12"`
+}
+
+// maxStatsPeriodDays 转账统计窗口的最大天数
+const maxStatsPeriodDays = 366
+
+// GetAccountStatsRequest 获取账户转账统计请求
+// 用于: GET /api/v1/accounts/:id/stats
+type GetAccountStatsRequest struct {
+	// Period 统计窗口，格式为天数 + d，例如 7d、30d (默认 30d，最大 366d)
+	Period string `form:"period,default=30d" binding:"max=4"`
+}
+
+// Days 解析统计窗口的天数
+func (r *GetAccountStatsRequest) Days() (int, error) {
+	days, err := strconv.Atoi(strings.TrimSuffix(r.Period, "d"))
+	if err != nil || !strings.HasSuffix(r.Period, "d") || days < 1 || days > maxStatsPeriodDays {
+		return 0, errors.New("period must be between 1d and 366d, e.g. 30d")
+	}
+	return days, nil
 }
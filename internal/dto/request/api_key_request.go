@@ -0,0 +1,25 @@
+package request
+
+import "github.com/google/uuid"
+
+// CreateAPIKeyRequest 创建 API Key 请求
+// 用于: POST /api/v1/api-keys
+type CreateAPIKeyRequest struct {
+	// Name 名称，用于识别 Key 的用途 (如 "billing-sync")
+	Name string `json:"name" binding:"required,max=64"`
+
+	// Scopes 授予的权限范围 (如 ["*:read"])，不传时授予全部权限范围
+	Scopes []string `json:"scopes" binding:"omitempty,max=8,dive,scope"`
+}
+
+// GetAPIKeyRequest API Key URL 参数
+// 用于: DELETE /api/v1/api-keys/:id
+type GetAPIKeyRequest struct {
+	ID string `uri:"id" binding:"required,uuid"` // API Key 公开ID
+}
+
+// PublicID 返回解析后的 API Key 公开ID
+func (r *GetAPIKeyRequest) PublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.ID)
+	return id
+}
@@ -0,0 +1,24 @@
+package request
+
+// ListAuditLogsRequest 查询审计日志请求
+// 用于: GET /api/v1/admin/audit-logs
+type ListAuditLogsRequest struct {
+	// Actor 按操作者过滤 (可选)
+	Actor string `form:"actor" binding:"omitempty,max=255"`
+
+	// Action 按操作类型过滤 (可选)
+	Action string `form:"action" binding:"omitempty,max=64"`
+
+	PageID   int `form:"page_id,default=1" binding:"min=1"`
+	PageSize int `form:"page_size" binding:"min=0"` // 见 PaginationRequest.PageSize
+}
+
+// Normalize 校验每页条数并填充默认值，规则同 PaginationRequest.Normalize
+func (r *ListAuditLogsRequest) Normalize() error {
+	pagination := PaginationRequest{PageID: r.PageID, PageSize: r.PageSize}
+	if err := pagination.Normalize(); err != nil {
+		return err
+	}
+	r.PageID, r.PageSize = pagination.PageID, pagination.PageSize
+	return nil
+}
@@ -1,22 +1,94 @@
 package request
 
+import "fmt"
+
+// 分页参数默认值，请求未携带 page_id 时使用
+const DefaultPageID = 1
+
+// MinPageSize 每页条数下限
+const MinPageSize = 5
+
+// PageSizeLimits 每页条数的默认值和上限
+type PageSizeLimits struct {
+	// Default 请求未携带 page_size 时使用
+	Default int
+
+	// Max 每页条数上限
+	Max int
+
+	// RejectOversize 为 true 时超过上限返回参数错误，否则截断到上限
+	RejectOversize bool
+}
+
+// DefaultPageSizeLimits 未调用 SetPageSizeLimits 时使用的限制
+var DefaultPageSizeLimits = PageSizeLimits{Default: 10, Max: 100}
+
+// pageSizeLimits 当前生效的限制，由 SetPageSizeLimits 在启动时设置
+var pageSizeLimits = DefaultPageSizeLimits
+
+// SetPageSizeLimits 设置所有列表接口的每页条数默认值和上限
+// 只应在启动时、开始处理请求之前调用
+func SetPageSizeLimits(limits PageSizeLimits) {
+	pageSizeLimits = limits
+}
+
 // PaginationRequest 分页请求参数
 // 可嵌入到其他请求结构体中使用
+// 参数均可省略，默认返回第 1 页，每页条数见 PageSizeLimits
+// 绑定后应调用 Normalize 校验 page_size 并填充默认值
 type PaginationRequest struct {
 	// PageID 页码 (从1开始)
-	PageID int `form:"page_id" binding:"required,min=1"`
+	PageID int `form:"page_id,default=1" binding:"min=1"`
+
+	// PageSize 每页条数，0 或省略时使用默认值
+	PageSize int `form:"page_size" binding:"min=0"`
+
+	// Sort 排序字段，前缀 "-" 表示降序 (例如 created_at, -amount)
+	// 可选字段由各资源的白名单决定，为空时按 ID 降序
+	Sort string `form:"sort" binding:"omitempty,max=32"`
+}
+
+// Normalize 校验每页条数并填充默认值
+//
+// 省略时使用默认值；小于 MinPageSize 时返回错误；
+// 超过上限时按 PageSizeLimits.RejectOversize 返回错误或截断到上限
+func (p *PaginationRequest) Normalize() error {
+	if p.PageID < 1 {
+		p.PageID = DefaultPageID
+	}
 
-	// PageSize 每页条数
-	PageSize int `form:"page_size" binding:"required,min=5,max=100"`
+	limits := pageSizeLimits
+	switch {
+	case p.PageSize == 0:
+		p.PageSize = limits.Default
+	case p.PageSize < MinPageSize:
+		return fmt.Errorf("page_size must be at least %d", MinPageSize)
+	case p.PageSize > limits.Max:
+		if limits.RejectOversize {
+			return fmt.Errorf("page_size must not exceed %d", limits.Max)
+		}
+		p.PageSize = limits.Max
+	}
+	return nil
 }
 
 // Offset 计算数据库查询的偏移量
 // 例如: PageID=2, PageSize=10 → Offset=10
+// 未经绑定直接构造 (字段为 0) 时按默认值计算
 func (p *PaginationRequest) Offset() int {
-	return (p.PageID - 1) * p.PageSize
+	pageID := p.PageID
+	if pageID < 1 {
+		pageID = DefaultPageID
+	}
+	return (pageID - 1) * p.Limit()
 }
 
 // Limit 返回每页条数 (与 PageSize 相同，但命名更符合数据库习惯)
+// 未调用 Normalize 时同样使用默认值并限制在上限以内
 func (p *PaginationRequest) Limit() int {
-	return p.PageSize
+	limits := pageSizeLimits
+	if p.PageSize <= 0 {
+		return limits.Default
+	}
+	return min(p.PageSize, limits.Max)
 }
@@ -0,0 +1,62 @@
+package request
+
+import "testing"
+
+func TestPaginationDefaults(t *testing.T) {
+	// 未经绑定直接构造时按默认值计算
+	var p PaginationRequest
+	if p.Offset() != 0 || p.Limit() != DefaultPageSizeLimits.Default {
+		t.Errorf("zero value offset/limit = %d/%d, want 0/%d", p.Offset(), p.Limit(), DefaultPageSizeLimits.Default)
+	}
+
+	if err := p.Normalize(); err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if p.PageID != DefaultPageID || p.PageSize != DefaultPageSizeLimits.Default {
+		t.Errorf("normalized = page %d size %d, want page %d size %d",
+			p.PageID, p.PageSize, DefaultPageID, DefaultPageSizeLimits.Default)
+	}
+
+	p = PaginationRequest{PageID: 3, PageSize: 20}
+	if p.Offset() != 40 || p.Limit() != 20 {
+		t.Errorf("offset/limit = %d/%d, want 40/20", p.Offset(), p.Limit())
+	}
+}
+
+func TestNormalizePageSize(t *testing.T) {
+	t.Cleanup(func() { SetPageSizeLimits(DefaultPageSizeLimits) })
+
+	tests := []struct {
+		name     string
+		limits   PageSizeLimits
+		pageSize int
+		want     int
+		wantErr  bool
+	}{
+		{"default", PageSizeLimits{Default: 20, Max: 50}, 0, 20, false},
+		{"within limit", PageSizeLimits{Default: 20, Max: 50}, 30, 30, false},
+		{"clamped to max", PageSizeLimits{Default: 20, Max: 50}, 80, 50, false},
+		{"rejected over max", PageSizeLimits{Default: 20, Max: 50, RejectOversize: true}, 80, 0, true},
+		{"at max with reject", PageSizeLimits{Default: 20, Max: 50, RejectOversize: true}, 50, 50, false},
+		{"below minimum", PageSizeLimits{Default: 20, Max: 50}, MinPageSize - 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPageSizeLimits(tt.limits)
+			p := PaginationRequest{PageID: 1, PageSize: tt.pageSize}
+			err := p.Normalize()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Normalize() = nil, want error (page_size %d)", p.PageSize)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize: %v", err)
+			}
+			if p.PageSize != tt.want || p.Limit() != tt.want {
+				t.Errorf("page_size/limit = %d/%d, want %d", p.PageSize, p.Limit(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package request
+
+// GetRateRequest 查询汇率请求
+// 用于: GET /api/v1/rates
+type GetRateRequest struct {
+	// From 源货币 (ISO 4217，例如 USD)
+	From string `form:"from" binding:"required,len=3,alpha"`
+
+	// To 目标货币 (ISO 4217，例如 EUR)
+	To string `form:"to" binding:"required,len=3,alpha"`
+}
@@ -1,35 +1,287 @@
 package request
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/pkg/currency"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
 // CreateTransferRequest 创建转账请求
 // 用于: POST /api/v1/transfers
 type CreateTransferRequest struct {
-	// FromAccountID 转出账户ID
-	FromAccountID uint `json:"from_account_id" binding:"required,min=1"`
+	// FromAccountID 转出账户公开ID (UUID)
+	FromAccountID string `json:"from_account_id" binding:"required,uuid"`
 
-	// ToAccountID 转入账户ID
-	ToAccountID uint `json:"to_account_id" binding:"required,min=1"`
+	// ToAccountID 转入账户公开ID (UUID)
+	// 与 ToAccountNumber 二选一
+	ToAccountID string `json:"to_account_id" binding:"required_without=ToAccountNumber,omitempty,uuid"`
+
+	// ToAccountNumber 转入账户的账号 (12 位数字)
+	// 收款方只需要告诉付款方账号，与 ToAccountID 二选一
+	ToAccountNumber string `json:"to_account_number" binding:"required_without=ToAccountID,omitempty,numeric,len=12"`
 
 	// Amount 转账金额 (单位: 分)
 	// 例如: 1000 = $10.00
-	Amount int64 `json:"amount" binding:"required,gt=0"`
+	// 与 AmountDecimal 二选一
+	Amount money.Amount `json:"amount" binding:"required_without=AmountDecimal,omitempty,gt=0"`
+
+	// AmountDecimal 十进制格式的转账金额
+	// 例如: "10.50" = $10.50，小数位不能超过货币精度
+	// 与 Amount 二选一，由 Normalize 转换为 Amount
+	AmountDecimal string `json:"amount_decimal" binding:"required_without=Amount,omitempty,max=32"`
 
 	// Currency 货币类型
-	// 必须与两个账户的货币类型匹配
-	Currency string `json:"currency" binding:"required,oneof=USD EUR CNY"`
+	// 必须与两个账户的货币类型匹配; 可省略，省略时使用源账户的货币
+	// 使用 AmountDecimal 时必须指定 (小数位数的校验依赖货币精度)
+	Currency string `json:"currency" binding:"omitempty,currency"`
+}
+
+// Normalize 校验收款账户只指定了一种方式，并将 AmountDecimal 转换为以分为单位的 Amount
+// 应在绑定成功后调用，之后只需读取 Amount
+//
+// 小数位数不能超过货币的精度 (currency.MinorUnits)，例如 USD 最多 2 位，JPY 不能有小数；
+// Amount 本身以最小货币单位表示，任意正整数对所有货币都有效
+func (r *CreateTransferRequest) Normalize() error {
+	if r.ToAccountID != "" && r.ToAccountNumber != "" {
+		return errors.New("only one of to_account_id and to_account_number may be set")
+	}
+
+	if r.AmountDecimal == "" {
+		return nil
+	}
+	if r.Amount != 0 {
+		return errors.New("only one of amount and amount_decimal may be set")
+	}
+	if r.Currency == "" {
+		return errors.New("currency is required when amount_decimal is set")
+	}
+
+	amount, err := money.ParseAmount(r.AmountDecimal, r.Currency)
+	if errors.Is(err, money.ErrTooManyDecimals) {
+		return fmt.Errorf("amount_decimal: %s amounts allow at most %d decimal places",
+			r.Currency, currency.MinorUnits(r.Currency))
+	}
+	if err != nil {
+		return fmt.Errorf("amount_decimal: %w", err)
+	}
+	if amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+
+	r.Amount = money.Amount(amount)
+	r.AmountDecimal = ""
+	return nil
+}
+
+// FromAccountPublicID 返回解析后的转出账户公开ID
+// 绑定时已经过 uuid 校验，解析不会失败；内部调用传入无效值时返回 uuid.Nil (查询不到账户)
+func (r *CreateTransferRequest) FromAccountPublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.FromAccountID)
+	return id
+}
+
+// ToAccountPublicID 返回解析后的转入账户公开ID，使用账号指定收款账户时返回 uuid.Nil
+func (r *CreateTransferRequest) ToAccountPublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.ToAccountID)
+	return id
+}
+
+// ScheduleTransferRequest 创建定时转账请求
+// 用于: POST /api/v1/transfers/schedule
+type ScheduleTransferRequest struct {
+	CreateTransferRequest
+
+	// ExecuteAt 计划执行时间 (RFC 3339)，必须晚于当前时间
+	ExecuteAt time.Time `json:"execute_at" binding:"required"`
+}
+
+// GetScheduledTransferRequest 定时转账 URL 参数
+// 用于: GET/DELETE /api/v1/transfers/schedule/:id
+type GetScheduledTransferRequest struct {
+	ID string `uri:"id" binding:"required,uuid"` // 定时转账公开ID
+}
+
+// PublicID 返回解析后的定时转账公开ID
+// 绑定时已经过 uuid 校验，解析不会失败
+func (r *GetScheduledTransferRequest) PublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.ID)
+	return id
+}
+
+// CreateRecurringTransferRequest 创建周期转账规则请求
+// 用于: POST /api/v1/accounts/:id/recurring-transfers (源账户由 URL 指定)
+type CreateRecurringTransferRequest struct {
+	// ToAccountID 转入账户公开ID (UUID)
+	ToAccountID string `json:"to_account_id" binding:"required,uuid"`
+
+	// Amount 每次转账金额 (单位: 分)
+	Amount money.Amount `json:"amount" binding:"required,gt=0"`
+
+	// Currency 货币类型，必须与两个账户的货币类型匹配
+	Currency string `json:"currency" binding:"required,currency"`
+
+	// Cadence 频率
+	Cadence string `json:"cadence" binding:"required,oneof=daily weekly monthly"`
+
+	// StartAt 第一次执行时间 (RFC 3339)，必须晚于当前时间
+	// 按月执行时以该日期为准，遇到较短的月份取当月最后一天
+	StartAt time.Time `json:"start_at" binding:"required"`
+
+	// EndAt 结束时间 (含，可选)，为空表示一直执行直到取消
+	EndAt *time.Time `json:"end_at"`
+}
+
+// ToAccountPublicID 返回解析后的转入账户公开ID
+func (r *CreateRecurringTransferRequest) ToAccountPublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.ToAccountID)
+	return id
+}
+
+// RecurringTransferURIRequest 周期转账规则 URL 参数
+// 用于: DELETE /api/v1/accounts/:id/recurring-transfers/:recurring_id
+type RecurringTransferURIRequest struct {
+	AccountID   string `uri:"id" binding:"required,uuid"`           // 源账户公开ID
+	RecurringID string `uri:"recurring_id" binding:"required,uuid"` // 规则公开ID
+}
+
+// AccountPublicID 返回解析后的源账户公开ID
+func (r *RecurringTransferURIRequest) AccountPublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.AccountID)
+	return id
+}
+
+// RecurringPublicID 返回解析后的规则公开ID
+func (r *RecurringTransferURIRequest) RecurringPublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.RecurringID)
+	return id
 }
 
 // ListTransfersRequest 获取转账记录请求
 // 用于: GET /api/v1/transfers
+//
+// 两种查询方式二选一:
+//   - account_id: 与该账户相关的全部转账 (转入和转出)
+//   - from_account_id / to_account_id: 按方向过滤，可以只传一个，两个都传时只返回这两个账户之间的转账
 type ListTransfersRequest struct {
-	AccountID uint `form:"account_id" binding:"required,min=1"`
-	PageID    int  `form:"page_id" binding:"required,min=1"`
-	PageSize  int  `form:"page_size" binding:"required,min=5,max=100"`
+	PaginationRequest
+	AccountID     string `form:"account_id" binding:"omitempty,uuid"`      // 账户公开ID
+	FromAccountID string `form:"from_account_id" binding:"omitempty,uuid"` // 转出账户公开ID
+	ToAccountID   string `form:"to_account_id" binding:"omitempty,uuid"`   // 转入账户公开ID
+}
+
+// Normalize 校验查询方式，并校验分页参数、填充默认值
+func (r *ListTransfersRequest) Normalize() error {
+	byPair := r.FromAccountID != "" || r.ToAccountID != ""
+	switch {
+	case r.AccountID == "" && !byPair:
+		return errors.New("account_id or from_account_id/to_account_id is required")
+	case r.AccountID != "" && byPair:
+		return errors.New("account_id cannot be combined with from_account_id/to_account_id")
+	}
+	return r.PaginationRequest.Normalize()
+}
+
+// ByPair 返回是否按 from_account_id / to_account_id 过滤
+func (r *ListTransfersRequest) ByPair() bool {
+	return r.AccountID == ""
+}
+
+// AccountPublicID 返回解析后的账户公开ID
+// 绑定时已经过 uuid 校验，解析不会失败
+func (r *ListTransfersRequest) AccountPublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.AccountID)
+	return id
+}
+
+// FromAccountPublicID 返回解析后的转出账户公开ID，未传时返回 nil
+func (r *ListTransfersRequest) FromAccountPublicID() *uuid.UUID {
+	return optionalUUID(r.FromAccountID)
+}
+
+// ToAccountPublicID 返回解析后的转入账户公开ID，未传时返回 nil
+func (r *ListTransfersRequest) ToAccountPublicID() *uuid.UUID {
+	return optionalUUID(r.ToAccountID)
+}
+
+// optionalUUID 解析已经过 uuid 校验的可选参数，为空时返回 nil
+func optionalUUID(s string) *uuid.UUID {
+	if s == "" {
+		return nil
+	}
+	id, _ := uuid.Parse(s)
+	return &id
+}
+
+// GetTransferRequest 根据公开ID获取转账请求
+// 用于: GET /api/v1/transfers/:id
+type GetTransferRequest struct {
+	// ID 转账公开ID (UUID)
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// PublicID 返回解析后的转账公开ID
+func (r *GetTransferRequest) PublicID() uuid.UUID {
+	id, _ := uuid.Parse(r.ID)
+	return id
+}
+
+// 转账详情可展开的关联数据
+const (
+	TransferExpandAccounts = "accounts" // 转出和转入账户
+)
+
+// GetTransferQuery 获取转账详情的 Query 参数
+// 用于: GET /api/v1/transfers/:id?expand=accounts
+type GetTransferQuery struct {
+	Expand string `form:"expand" binding:"omitempty,oneof=accounts"` // 展开关联数据
+}
+
+// ExpandAccounts 返回是否需要在响应中包含两个账户的信息
+func (r *GetTransferQuery) ExpandAccounts() bool {
+	return r.Expand == TransferExpandAccounts
+}
+
+// GetTransferByReferenceRequest 根据参考号获取转账请求
+// 用于: GET /api/v1/transfers/ref/:reference
+type GetTransferByReferenceRequest struct {
+	Reference string `uri:"reference" binding:"required,max=32,alphanum"`
 }
 
 // ListEntriesRequest 获取账目记录请求
 // 用于: GET /api/v1/accounts/:id/entries
 type ListEntriesRequest struct {
-	AccountID uint `uri:"id" binding:"required,min=1"`
-	PageID    int  `form:"page_id" binding:"required,min=1"`
-	PageSize  int  `form:"page_size" binding:"required,min=5,max=100"`
+	PaginationRequest
+	AccountID string `uri:"id" binding:"required,uuid"` // 账户公开ID
+}
+
+// DateRangeRequest 时间范围过滤参数 (RFC 3339 格式)
+// 用于: GET /api/v1/accounts/:id/entries 和 /entries/export
+type DateRangeRequest struct {
+	// From 起始时间 (包含)，例如 2024-01-01T00:00:00Z
+	From time.Time `form:"from"`
+
+	// To 结束时间 (不包含)
+	To time.Time `form:"to"`
+}
+
+// Validate 检查时间范围是否有效
+func (r *DateRangeRequest) Validate() error {
+	if !r.From.IsZero() && !r.To.IsZero() && !r.From.Before(r.To) {
+		return errors.New("from must be before to")
+	}
+	return nil
+}
+
+// ExportEntriesRequest 导出账目请求
+// 用于: GET /api/v1/accounts/:id/entries/export
+type ExportEntriesRequest struct {
+	DateRangeRequest
+
+	// Format 导出格式: csv (默认) 或 json
+	Format string `form:"format,default=csv" binding:"oneof=csv json"`
 }
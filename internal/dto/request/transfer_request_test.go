@@ -0,0 +1,61 @@
+package request
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+func TestNormalizeAmountDecimalPerCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		decimal  string
+		want     money.Amount
+		wantErr  string
+	}{
+		{name: "USD cents", currency: "USD", decimal: "10.50", want: 1050},
+		{name: "USD too many decimals", currency: "USD", decimal: "10.505", wantErr: "at most 2 decimal places"},
+		{name: "EUR whole units", currency: "EUR", decimal: "7", want: 700},
+		{name: "CNY one decimal", currency: "CNY", decimal: "1.5", want: 150},
+		{name: "JPY whole units", currency: "JPY", decimal: "100", want: 100},
+		{name: "JPY fraction", currency: "JPY", decimal: "1.5", wantErr: "at most 0 decimal places"},
+		{name: "zero", currency: "USD", decimal: "0.00", wantErr: "greater than 0"},
+		{name: "missing currency", decimal: "1.00", wantErr: "currency is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateTransferRequest{AmountDecimal: tt.decimal, Currency: tt.currency}
+			err := req.Normalize()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Normalize() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize: %v", err)
+			}
+			if req.Amount != tt.want || req.AmountDecimal != "" {
+				t.Errorf("amount = %d, decimal = %q; want %d and cleared", req.Amount, req.AmountDecimal, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRecipientIsExclusive(t *testing.T) {
+	req := &CreateTransferRequest{
+		ToAccountID:     "7d7f4f2e-8c2b-4a55-9a57-3d1f6a2b9c10",
+		ToAccountNumber: "123456789012",
+		Amount:          100,
+	}
+	if err := req.Normalize(); err == nil || !strings.Contains(err.Error(), "only one of to_account_id and to_account_number") {
+		t.Errorf("Normalize() error = %v, want mutually exclusive recipient", err)
+	}
+
+	req.ToAccountID = ""
+	if err := req.Normalize(); err != nil {
+		t.Errorf("Normalize with account number: %v", err)
+	}
+}
@@ -27,10 +27,15 @@ type CreateUserRequest struct {
 type LoginUserRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+
+	// ReadOnly 为 true 时签发只读 Token (只有 *:read 权限范围)，
+	// 可以交给第三方看板等只需要查询的客户端，刷新后仍然是只读的
+	ReadOnly bool `json:"read_only"`
 }
 
 // RefreshTokenRequest 刷新 Token 请求
-// 用于: POST /api/v1/token/refresh
+// 用于: POST /api/v1/tokens/renew
+// 启用 Refresh Token Cookie 时可以为空，由 Handler 从 Cookie 中补齐
 type RefreshTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required"`
+	RefreshToken string `json:"refresh_token"`
 }
@@ -0,0 +1,48 @@
+package request
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/proyuen/simple-bank-v2/pkg/currency"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// 自定义校验标签
+//
+//	currency: 字符串必须是支持的货币代码 (见 currency.IsSupported)
+//	scope:    字符串必须是可以授予的权限范围 (见 token.IsValidScope)
+//
+// 在包初始化时注册到 Gin 的校验器，DTO 的 binding 标签可以直接使用
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := v.RegisterValidation("currency", validCurrency); err != nil {
+			panic(err)
+		}
+		if err := v.RegisterValidation("scope", validScope); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetStrictJSON 设置 JSON 请求体是否拒绝 DTO 中未定义的字段
+//
+// 默认情况下多余的字段 (如拼写错误的 "amout") 会被静默忽略，金额按 0 校验；
+// 开启后绑定失败，Handler 返回参数错误 `unknown field "amout"` (CodeInvalidParams，见 apperrors.ErrBinding)
+// 只影响 JSON 请求体 (即写接口)，查询参数不受影响；默认关闭以兼容发送多余字段的老客户端
+// 只应在启动时、开始处理请求之前调用
+func SetStrictJSON(strict bool) {
+	binding.EnableDecoderDisallowUnknownFields = strict
+}
+
+// validCurrency 校验字段是否为支持的货币代码
+func validCurrency(fl validator.FieldLevel) bool {
+	code, ok := fl.Field().Interface().(string)
+	return ok && currency.IsSupported(code)
+}
+
+// validScope 校验字段是否为可以授予的权限范围
+func validScope(fl validator.FieldLevel) bool {
+	scope, ok := fl.Field().Interface().(string)
+	return ok && token.IsValidScope(scope)
+}
@@ -1,31 +1,159 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
 
 // AccountResponse 账户信息响应
 type AccountResponse struct {
-	ID        uint      `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`   // 余额(单位:分)
-	Currency  string    `json:"currency"`  // 货币类型
-	CreatedAt time.Time `json:"created_at"`
+	PublicID       uuid.UUID    `json:"public_id"` // 公开ID，用于 URL
+	Number         string       `json:"number"`    // 账号，转账时可用于指定收款账户
+	Owner          string       `json:"owner"`
+	Name           string       `json:"name"`            // 账户名称(可选)
+	Balance        money.Amount `json:"balance"`         // 余额(单位:分)
+	OverdraftLimit money.Amount `json:"overdraft_limit"` // 透支额度(单位:分)
+	MinBalance     money.Amount `json:"min_balance"`     // 最低余额(单位:分)
+	IsFrozen       bool         `json:"is_frozen"`       // 是否冻结 (冻结期间不能转入转出)
+	Currency       string       `json:"currency"`        // 货币类型
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"` // 最后更新时间，余额变动时更新
+}
+
+// 批量创建账户时每种货币的处理结果
+const (
+	AccountBatchCreated = "created" // 新建了账户
+	AccountBatchSkipped = "skipped" // 已有该货币的账户，跳过
+)
+
+// AccountBatchResult 批量创建账户时单一货币的处理结果
+type AccountBatchResult struct {
+	Currency string `json:"currency"`
+	Status   string `json:"status"` // created/skipped
+}
+
+// CreateAccountsBatchResponse 批量创建账户响应
+type CreateAccountsBatchResponse struct {
+	Created []AccountResponse    `json:"created"` // 新建的账户
+	Skipped []string             `json:"skipped"` // 已有账户而跳过的货币
+	Results []AccountBatchResult `json:"results"` // 按请求顺序的每种货币处理结果
+}
+
+// CurrencyBalanceResponse 单一货币的余额汇总
+type CurrencyBalanceResponse struct {
+	Currency     string       `json:"currency"`
+	TotalBalance money.Amount `json:"total_balance"` // 余额合计(单位:分)
+	AccountCount int64        `json:"account_count"`
+}
+
+// AccountSummaryResponse 账户余额汇总响应 (按货币分组)
+type AccountSummaryResponse struct {
+	Currencies []CurrencyBalanceResponse `json:"currencies"`
 }
 
 // TransferResponse 转账记录响应
 type TransferResponse struct {
-	ID            uint      `json:"id"`
-	FromAccountID uint      `json:"from_account_id"`
-	ToAccountID   uint      `json:"to_account_id"`
-	Amount        int64     `json:"amount"`
-	CreatedAt     time.Time `json:"created_at"`
+	PublicID      uuid.UUID    `json:"public_id"`       // 公开ID，用于 URL
+	Reference     string       `json:"reference"`       // 转账参考号
+	FromAccountID uuid.UUID    `json:"from_account_id"` // 转出账户公开ID
+	ToAccountID   uuid.UUID    `json:"to_account_id"`   // 转入账户公开ID
+	Amount        money.Amount `json:"amount"`
+	ReversalOf    *uuid.UUID   `json:"reversal_of,omitempty"` // 撤销转账对应的原转账公开ID
+	CreatedAt     time.Time    `json:"created_at"`
+
+	// FromAccount、ToAccount 仅在 ?expand=accounts 时返回
+	// 不属于当前用户的账户只包含 public_id、currency 和掩码后的 owner，余额等字段为零值
+	FromAccount *AccountResponse `json:"from_account,omitempty"`
+	ToAccount   *AccountResponse `json:"to_account,omitempty"`
+}
+
+// ScheduledTransferResponse 定时转账响应
+type ScheduledTransferResponse struct {
+	PublicID      uuid.UUID    `json:"public_id"`       // 公开ID，用于 URL
+	FromAccountID uuid.UUID    `json:"from_account_id"` // 转出账户公开ID
+	ToAccountID   uuid.UUID    `json:"to_account_id"`   // 转入账户公开ID
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	ExecuteAt     time.Time    `json:"execute_at"`               // 计划执行时间
+	Status        string       `json:"status"`                   // pending/processing/executed/failed/cancelled
+	TransferID    *uuid.UUID   `json:"transfer_id,omitempty"`    // 执行成功后生成的转账公开ID
+	FailureReason string       `json:"failure_reason,omitempty"` // 执行失败原因
+	ExecutedAt    *time.Time   `json:"executed_at,omitempty"`    // 实际执行时间
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// RecurringTransferResponse 周期转账规则响应
+type RecurringTransferResponse struct {
+	PublicID      uuid.UUID    `json:"public_id"`       // 公开ID，用于 URL
+	FromAccountID uuid.UUID    `json:"from_account_id"` // 转出账户公开ID
+	ToAccountID   uuid.UUID    `json:"to_account_id"`   // 转入账户公开ID
+	Amount        money.Amount `json:"amount"`
+	Currency      string       `json:"currency"`
+	Cadence       string       `json:"cadence"` // daily/weekly/monthly
+	StartAt       time.Time    `json:"start_at"`
+	EndAt         *time.Time   `json:"end_at,omitempty"`
+	Status        string       `json:"status"`                // active/completed/cancelled
+	NextRunAt     *time.Time   `json:"next_run_at,omitempty"` // 下一次计划执行时间 (仅 active)
+	CreatedAt     time.Time    `json:"created_at"`
 }
 
 // EntryResponse 账目记录响应
 type EntryResponse struct {
-	ID        uint      `json:"id"`
-	AccountID uint      `json:"account_id"`
-	Amount    int64     `json:"amount"` // 正数=入账, 负数=出账
-	CreatedAt time.Time `json:"created_at"`
+	ID        uint         `json:"id"`
+	AccountID uuid.UUID    `json:"account_id"` // 账户公开ID
+	Amount    money.Amount `json:"amount"`     // 正数=入账, 负数=出账
+	CreatedAt time.Time    `json:"created_at"`
+
+	// 所属账户的货币，仅跨账户的账目列表 (GET /entries) 返回
+	Currency string `json:"currency,omitempty"`
+}
+
+// 账户交易历史中每条记录的类型
+const (
+	HistoryTypeDeposit     = "deposit"      // 非转账的入账
+	HistoryTypeWithdrawal  = "withdrawal"   // 非转账的出账
+	HistoryTypeTransferIn  = "transfer_in"  // 转入
+	HistoryTypeTransferOut = "transfer_out" // 转出
+)
+
+// HistoryItemResponse 账户交易历史的一条记录 (一条账目及其来源转账)
+type HistoryItemResponse struct {
+	EntryID      uint         `json:"entry_id"`
+	Type         string       `json:"type"`          // deposit/withdrawal/transfer_in/transfer_out
+	Amount       money.Amount `json:"amount"`        // 正数=入账, 负数=出账
+	BalanceAfter money.Amount `json:"balance_after"` // 该笔资金变动后的账户余额
+	CreatedAt    time.Time    `json:"created_at"`
+
+	// 来源转账和转账另一方，仅 transfer_in/transfer_out 返回
+	TransferID   *uuid.UUID            `json:"transfer_id,omitempty"` // 转账的公开ID
+	Reference    string                `json:"reference,omitempty"`   // 转账参考号
+	Counterparty *CounterpartyResponse `json:"counterparty,omitempty"`
+}
+
+// CounterpartyResponse 转账另一方账户的摘要
+type CounterpartyResponse struct {
+	AccountID uuid.UUID `json:"account_id"` // 账户公开ID
+	Owner     string    `json:"owner"`      // 掩码后的所有者，如 "a***e"
+}
+
+// AccountStatsResponse 账户转账统计响应
+// 统计区间为 [PeriodStart, PeriodEnd)，金额单位均为分
+type AccountStatsResponse struct {
+	AccountID       uuid.UUID    `json:"account_id"` // 账户公开ID
+	Currency        string       `json:"currency"`
+	Period          string       `json:"period"`           // 请求的统计窗口，如 30d
+	PeriodStart     time.Time    `json:"period_start"`     // 区间起点 (包含)
+	PeriodEnd       time.Time    `json:"period_end"`       // 区间终点 (不包含)
+	TotalSent       money.Amount `json:"total_sent"`       // 转出总额
+	TotalReceived   money.Amount `json:"total_received"`   // 转入总额
+	SentCount       int64        `json:"sent_count"`       // 转出笔数
+	ReceivedCount   int64        `json:"received_count"`   // 转入笔数
+	TransferCount   int64        `json:"transfer_count"`   // 转账总笔数
+	LargestTransfer money.Amount `json:"largest_transfer"` // 单笔最大金额 (转入或转出)
+	AverageTransfer money.Amount `json:"average_transfer"` // 单笔平均金额 (向下取整)
 }
 
 // TransferResultResponse 转账结果响应
@@ -37,3 +165,18 @@ type TransferResultResponse struct {
 	FromEntry   EntryResponse    `json:"from_entry"`
 	ToEntry     EntryResponse    `json:"to_entry"`
 }
+
+// StatementResponse 月度对账单响应
+type StatementResponse struct {
+	AccountID      uuid.UUID       `json:"account_id"` // 账户公开ID
+	Currency       string          `json:"currency"`
+	Year           int             `json:"year"`
+	Month          int             `json:"month"`
+	PeriodStart    time.Time       `json:"period_start"`    // 区间起点 (包含)
+	PeriodEnd      time.Time       `json:"period_end"`      // 区间终点 (不包含)
+	OpeningBalance money.Amount    `json:"opening_balance"` // 期初余额(单位:分)
+	ClosingBalance money.Amount    `json:"closing_balance"` // 期末余额(单位:分)
+	TotalCredits   money.Amount    `json:"total_credits"`   // 入账总额(单位:分)
+	TotalDebits    money.Amount    `json:"total_debits"`    // 出账总额(单位:分，正数)
+	Entries        []EntryResponse `json:"entries"`         // 区间内的账目，按 ID 升序
+}
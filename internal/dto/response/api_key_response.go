@@ -0,0 +1,26 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyResponse API Key 信息响应 (不包含 Key 本身)
+type APIKeyResponse struct {
+	PublicID   uuid.UUID  `json:"public_id"` // 公开ID，用于 URL
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Key 的前几个字符，用于识别
+	Scopes     []string   `json:"scopes"` // 权限范围
+	Revoked    bool       `json:"revoked"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // 最后使用时间 (按分钟更新)
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse 创建 API Key 响应
+// Key 只在创建时返回一次，服务端不保存明文，丢失后只能撤销并重新创建
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
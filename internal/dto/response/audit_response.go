@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+// AuditLogResponse 审计日志响应
+type AuditLogResponse struct {
+	ID        uint      `json:"id"`
+	Actor     string    `json:"actor"`     // 操作者
+	Action    string    `json:"action"`    // 操作类型
+	Target    string    `json:"target"`    // 操作对象
+	ClientIP  string    `json:"client_ip"` // 客户端IP
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"encoding/json"
+
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
 )
 
@@ -33,3 +35,20 @@ func NewSuccessResponse(message string) SuccessResponse {
 		Message: message,
 	}
 }
+
+// Envelope 成功响应的统一包装
+// 开启 RESPONSE_ENVELOPE 后由 middleware.ResponseEnvelope 使用
+type Envelope struct {
+	Code    int             `json:"code"`    // 业务码，成功时为 0
+	Message string          `json:"message"` // 消息
+	Data    json.RawMessage `json:"data"`    // 原响应体
+}
+
+// NewEnvelope 将已序列化的响应体包装为 Envelope
+func NewEnvelope(data json.RawMessage) Envelope {
+	return Envelope{
+		Code:    apperrors.CodeSuccess,
+		Message: apperrors.GetMessage(apperrors.CodeSuccess),
+		Data:    data,
+	}
+}
@@ -0,0 +1,21 @@
+package response
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+// BalanceEventResponse 账户余额变动事件 (实时推送)
+type BalanceEventResponse struct {
+	AccountID  uuid.UUID    `json:"account_id"`  // 账户公开ID
+	Kind       string       `json:"kind"`        // transfer_in / transfer_out
+	Amount     money.Amount `json:"amount"`      // 本次变动金额(单位:分)，正数=入账, 负数=出账
+	Balance    money.Amount `json:"balance"`     // 变动后的余额(单位:分)
+	Currency   string       `json:"currency"`    // 货币类型
+	TransferID uuid.UUID    `json:"transfer_id"` // 转账公开ID
+	Reference  string       `json:"reference"`   // 转账参考号
+	OccurredAt time.Time    `json:"occurred_at"`
+}
@@ -0,0 +1,28 @@
+package response
+
+// 就绪状态
+const (
+	ReadinessReady    = "ready"
+	ReadinessNotReady = "not_ready"
+	ReadinessDraining = "draining" // 服务正在关闭，不再接收新请求
+)
+
+// 单个依赖的检查结果
+const (
+	ReadinessCheckOK   = "ok"
+	ReadinessCheckFail = "fail"
+)
+
+// ReadinessResponse 就绪检查响应
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"` // 依赖名 → ok / fail，如 {"db": "ok", "token": "ok"}
+}
+
+// VersionResponse 构建信息响应
+type VersionResponse struct {
+	Version   string `json:"version"`    // 版本号，如 v1.2.0，未注入时为 dev
+	Commit    string `json:"commit"`     // Git 提交
+	BuildTime string `json:"build_time"` // 构建时间 (UTC)
+	GoVersion string `json:"go_version"` // 编译使用的 Go 版本
+}
@@ -14,10 +14,20 @@ type PaginationResponse struct {
 //   - page: 当前页码
 //   - pageSize: 每页条数
 //   - totalCount: 总记录数
+//
+// 边界情况:
+//   - totalCount <= 0 时 TotalPages 为 0
+//   - pageSize <= 0 时视为不分页，所有记录在同一页 (TotalPages 为 1)
 func NewPaginationResponse(page, pageSize int, totalCount int64) PaginationResponse {
-	totalPages := int(totalCount) / pageSize
-	if int(totalCount)%pageSize > 0 {
-		totalPages++
+	var totalPages int
+	switch {
+	case totalCount <= 0:
+		totalPages = 0
+	case pageSize <= 0:
+		totalPages = 1
+	default:
+		// 向上取整，用 int64 计算避免大数溢出
+		totalPages = int((totalCount + int64(pageSize) - 1) / int64(pageSize))
 	}
 
 	return PaginationResponse{
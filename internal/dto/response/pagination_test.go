@@ -0,0 +1,31 @@
+package response
+
+import "testing"
+
+func TestNewPaginationResponseTotalPages(t *testing.T) {
+	tests := []struct {
+		name       string
+		pageSize   int
+		totalCount int64
+		want       int
+	}{
+		{"empty result", 10, 0, 0},
+		{"single item", 10, 1, 1},
+		{"exact multiple", 10, 30, 3},
+		{"remainder", 10, 31, 4},
+		{"zero page size", 0, 5, 1},
+		{"negative page size", -1, 5, 1},
+		{"zero page size and empty result", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPaginationResponse(1, tt.pageSize, tt.totalCount)
+			if got.TotalPages != tt.want {
+				t.Errorf("TotalPages = %d, want %d", got.TotalPages, tt.want)
+			}
+			if got.TotalCount != tt.totalCount {
+				t.Errorf("TotalCount = %d, want %d", got.TotalCount, tt.totalCount)
+			}
+		})
+	}
+}
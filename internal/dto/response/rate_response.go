@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+// RateResponse 汇率查询响应
+type RateResponse struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      string    `json:"rate"`      // 1 单位 from 可兑换的 to 数量 (十进制字符串，避免浮点误差)
+	Timestamp time.Time `json:"timestamp"` // 汇率获取时间
+	Stale     bool      `json:"stale"`     // 上游不可用，返回的是过期汇率
+}
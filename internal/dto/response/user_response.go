@@ -10,18 +10,20 @@ type UserResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"full_name"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	AccessToken           string       `json:"access_token"`            // Access Token
-	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"` // Access Token 过期时间
-	RefreshToken          string       `json:"refresh_token"`           // Refresh Token
+	AccessToken           string       `json:"access_token"`             // Access Token
+	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`  // Access Token 过期时间
+	RefreshToken          string       `json:"refresh_token,omitempty"`  // Refresh Token (通过 Cookie 下发时为空)
 	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"` // Refresh Token 过期时间
-	SessionID             string       `json:"session_id"`              // 会话ID
-	User                  UserResponse `json:"user"`                    // 用户信息
+	SessionID             string       `json:"session_id"`               // 会话ID
+	Scopes                []string     `json:"scopes,omitempty"`         // 权限范围 (只读登录时为 ["*:read"]，否则为空)
+	User                  UserResponse `json:"user"`                     // 用户信息
 }
 
 // RefreshTokenResponse 刷新 Token 响应
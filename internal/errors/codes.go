@@ -42,6 +42,12 @@ const (
 
 	// CodeInvalidToken Token 格式错误或被篡改
 	CodeInvalidToken = 40103
+
+	// CodeSessionExpired 会话已失效（如空闲超时），需要重新登录
+	CodeSessionExpired = 40104
+
+	// CodeReauthRequired 敏感操作要求最近登录，Token 的登录时间过早，需要重新登录
+	CodeReauthRequired = 40105
 )
 
 // ==================== 权限错误码 (403xx) ====================
@@ -75,6 +81,15 @@ const (
 
 	// CodeEmailExists 邮箱已被注册
 	CodeEmailExists = 40903
+
+	// CodeStateConflict 资源当前状态不允许该操作 (如取消已执行的定时转账)
+	CodeStateConflict = 40904
+)
+
+// ==================== 请求体错误码 (413xx) ====================
+const (
+	// CodePayloadTooLarge 请求体超过大小限制
+	CodePayloadTooLarge = 41301
 )
 
 // ==================== 业务错误码 (422xx) ====================
@@ -90,6 +105,31 @@ const (
 
 	// CodePasswordWrong 密码错误
 	CodePasswordWrong = 42204
+
+	// CodeTransferLimitExceeded 超出转账限额 (单笔或每日累计)
+	CodeTransferLimitExceeded = 42205
+
+	// CodeReversalWindowExpired 已超过可撤销转账的时间窗口
+	CodeReversalWindowExpired = 42206
+
+	// CodeAccountFrozen 账户已被冻结，不能转入转出
+	CodeAccountFrozen = 42207
+
+	// CodeAccountLimitReached 用户的账户数已达上限
+	CodeAccountLimitReached = 42208
+)
+
+// ==================== 限流错误码 (429xx) ====================
+const (
+	// CodeTooManyRequests 请求过于频繁 (如登录尝试次数超限)，响应带 Retry-After
+	CodeTooManyRequests = 42901
+)
+
+// ==================== 客户端关闭连接错误码 (499xx) ====================
+const (
+	// CodeClientClosed 客户端在请求完成前断开连接 (context.Canceled)
+	// 499 是 Nginx 定义的非标准状态码，客户端通常收不到，主要用于日志和监控
+	CodeClientClosed = 49901
 )
 
 // ==================== 服务器错误码 (500xx) ====================
@@ -101,6 +141,18 @@ const (
 	CodeDatabaseError = 50002
 )
 
+// ==================== 服务不可用错误码 (503xx) ====================
+const (
+	// CodeServiceUnavailable 服务暂不可用（如维护模式）
+	CodeServiceUnavailable = 50301
+)
+
+// ==================== 超时错误码 (504xx) ====================
+const (
+	// CodeRequestTimeout 请求处理超时 (context.DeadlineExceeded)
+	CodeRequestTimeout = 50401
+)
+
 // codeMessages 存储错误码对应的默认消息
 var codeMessages = map[int]string{
 	CodeSuccess: "success",
@@ -110,9 +162,11 @@ var codeMessages = map[int]string{
 	CodeInvalidRequest: "invalid request format",
 
 	// 认证错误
-	CodeUnauthorized: "unauthorized",
-	CodeTokenExpired: "token expired",
-	CodeInvalidToken: "invalid token",
+	CodeUnauthorized:   "unauthorized",
+	CodeTokenExpired:   "token expired",
+	CodeInvalidToken:   "invalid token",
+	CodeSessionExpired: "session expired",
+	CodeReauthRequired: "re-authentication required",
 
 	// 权限错误
 	CodeForbidden:      "access forbidden",
@@ -127,16 +181,36 @@ var codeMessages = map[int]string{
 	CodeAlreadyExists:  "resource already exists",
 	CodeUsernameExists: "username already exists",
 	CodeEmailExists:    "email already exists",
+	CodeStateConflict:  "resource state conflict",
+
+	// 请求体错误
+	CodePayloadTooLarge: "request body too large",
 
 	// 业务错误
-	CodeInsufficientBalance: "insufficient balance",
-	CodeCurrencyMismatch:    "currency mismatch",
-	CodeSameAccount:         "cannot transfer to same account",
-	CodePasswordWrong:       "wrong password",
+	CodeInsufficientBalance:   "insufficient balance",
+	CodeCurrencyMismatch:      "currency mismatch",
+	CodeSameAccount:           "cannot transfer to same account",
+	CodePasswordWrong:         "wrong password",
+	CodeTransferLimitExceeded: "transfer limit exceeded",
+	CodeReversalWindowExpired: "reversal window expired",
+	CodeAccountFrozen:         "account is frozen",
+	CodeAccountLimitReached:   "account limit reached",
+
+	// 限流错误
+	CodeTooManyRequests: "too many requests",
+
+	// 客户端关闭连接
+	CodeClientClosed: "client closed request",
 
 	// 服务器错误
 	CodeInternalError: "internal server error",
 	CodeDatabaseError: "database error",
+
+	// 服务不可用错误
+	CodeServiceUnavailable: "service unavailable",
+
+	// 超时错误
+	CodeRequestTimeout: "request timeout",
 }
 
 // GetMessage 根据错误码获取默认错误消息
@@ -1,8 +1,13 @@
 package errors
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
 )
 
 // AppError 是应用程序的统一错误类型
@@ -11,6 +16,7 @@ type AppError struct {
 	Code       int    `json:"code"`    // 业务错误码
 	Message    string `json:"message"` // 错误消息
 	HTTPStatus int    `json:"-"`       // HTTP 状态码（不输出到 JSON）
+	Err        error  `json:"-"`       // 被包装的底层错误（由 Wrap 设置，可能为 nil）
 }
 
 // Error 实现 error 接口
@@ -19,6 +25,14 @@ func (e *AppError) Error() string {
 	return fmt.Sprintf("code: %d, message: %s", e.Code, e.Message)
 }
 
+// Unwrap 返回被包装的底层错误
+// 使 errors.Is / errors.As 可以穿透 AppError，例如:
+//
+//	errors.Is(apperrors.ErrDatabase(err), gorm.ErrRecordNotFound)
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // ==================== 错误构造函数 ====================
 // 这些函数用于快速创建常见的错误类型
 
@@ -49,6 +63,7 @@ func Wrap(code int, err error) *AppError {
 		Code:       code,
 		Message:    err.Error(),
 		HTTPStatus: codeToHTTPStatus(code),
+		Err:        err,
 	}
 }
 
@@ -64,6 +79,11 @@ func ErrUnauthorized() *AppError {
 	return New(CodeUnauthorized)
 }
 
+// ErrReauthRequired 返回需要重新登录错误 (敏感操作要求最近登录)
+func ErrReauthRequired() *AppError {
+	return New(CodeReauthRequired)
+}
+
 // ErrForbidden 返回禁止访问错误
 func ErrForbidden() *AppError {
 	return New(CodeForbidden)
@@ -104,18 +124,67 @@ func ErrInsufficientBalance() *AppError {
 	return New(CodeInsufficientBalance)
 }
 
+// ErrMinBalanceBreach 返回扣款后低于最低余额的错误 (同样使用余额不足错误码)
+func ErrMinBalanceBreach() *AppError {
+	return NewWithMessage(CodeInsufficientBalance, "would breach minimum balance")
+}
+
+// ErrAccountFrozen 返回账户已冻结错误
+func ErrAccountFrozen() *AppError {
+	return New(CodeAccountFrozen)
+}
+
+// ErrAccountLimitReached 返回账户数已达上限错误
+func ErrAccountLimitReached(limit int) *AppError {
+	return NewWithMessage(CodeAccountLimitReached, fmt.Sprintf("account limit reached: at most %d accounts per user", limit))
+}
+
 // ErrCurrencyMismatch 返回货币类型不匹配错误
 func ErrCurrencyMismatch() *AppError {
 	return New(CodeCurrencyMismatch)
 }
 
+// ErrPayloadTooLarge 返回请求体过大错误
+func ErrPayloadTooLarge(limit int64) *AppError {
+	return NewWithMessage(CodePayloadTooLarge, fmt.Sprintf("request body exceeds %d bytes", limit))
+}
+
+// ErrTooManyRequests 返回请求过于频繁错误
+func ErrTooManyRequests() *AppError {
+	return New(CodeTooManyRequests)
+}
+
+// unknownFieldPrefix 严格模式下 encoding/json 遇到未定义字段时的错误前缀
+// (见 request.SetStrictJSON)，encoding/json 没有为它导出错误类型
+const unknownFieldPrefix = "json: unknown field "
+
+// ErrBinding 将请求参数绑定错误转换为 AppError
+// 请求体超过 MaxBodySize 限制时返回 413，其余返回参数验证错误 (400)
+// 未定义的字段返回 `unknown field "amout"`，指明是哪个字段
+func ErrBinding(err error) *AppError {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return ErrPayloadTooLarge(maxBytesErr.Limit)
+	}
+	if msg := err.Error(); strings.HasPrefix(msg, unknownFieldPrefix) {
+		return ErrInvalidParams(strings.TrimPrefix(msg, "json: "))
+	}
+	return ErrInvalidParams(err.Error())
+}
+
 // ErrInternalServer 返回服务器内部错误
 func ErrInternalServer() *AppError {
 	return New(CodeInternalError)
 }
 
 // ErrDatabase 包装数据库错误
+//
+// Context 取消或超时导致的失败不算数据库故障，分别映射为
+// CodeClientClosed (499) 和 CodeRequestTimeout (504)，避免污染 500 错误监控
 func ErrDatabase(err error) *AppError {
+	if code, ok := contextErrorCode(err); ok {
+		return Wrap(code, err)
+	}
 	return Wrap(CodeDatabaseError, err)
 }
 
@@ -134,26 +203,70 @@ func codeToHTTPStatus(code int) int {
 
 	// 验证是否为有效的 HTTP 状态码
 	switch httpCode {
-	case 400, 401, 403, 404, 409, 422:
+	case 400, 401, 403, 404, 409, 413, 422, 429, 499:
 		return httpCode
-	case 500, 502, 503:
+	case 500, 502, 503, 504:
 		return httpCode
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
-// IsAppError 检查 error 是否为 AppError 类型
+// IsAppError 检查 error 链中是否包含 AppError
 func IsAppError(err error) bool {
-	_, ok := err.(*AppError)
-	return ok
+	var appErr *AppError
+	return errors.As(err, &appErr)
 }
 
 // AsAppError 将 error 转换为 AppError
-// 如果不是 AppError，返回一个内部错误
+// 支持被 fmt.Errorf("...: %w", appErr) 包装过的 AppError
+//
+// error 链中没有 AppError 时，先识别常见的哨兵错误，避免 Service 忘记转换时
+// 把本应是 404 的错误返回成 500:
+//   - gorm.ErrRecordNotFound → CodeNotFound (404)
+//   - gorm.ErrDuplicatedKey → CodeAlreadyExists (409)
+//   - gorm.ErrForeignKeyViolated → CodeNotFound (404，引用的记录不存在)
+//   - context.Canceled → CodeClientClosed (499)
+//   - context.DeadlineExceeded → CodeRequestTimeout (504)
+//
+// 其余错误返回内部错误
 func AsAppError(err error) *AppError {
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
 		return appErr
 	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return newWithCause(CodeNotFound, err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return newWithCause(CodeAlreadyExists, err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return newWithCause(CodeNotFound, err)
+	}
+	if code, ok := contextErrorCode(err); ok {
+		return newWithCause(code, err)
+	}
+
 	return ErrInternalServer()
 }
+
+// newWithCause 创建使用默认消息的 AppError，并保留底层错误
+// 与 Wrap 不同，底层错误的文本不会暴露给客户端
+func newWithCause(code int, err error) *AppError {
+	appErr := New(code)
+	appErr.Err = err
+	return appErr
+}
+
+// contextErrorCode 识别 Context 取消和超时错误
+func contextErrorCode(err error) (int, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeClientClosed, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeRequestTimeout, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestErrDatabaseMapsContextErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"canceled", context.Canceled, CodeClientClosed},
+		{"deadline exceeded", context.DeadlineExceeded, CodeRequestTimeout},
+		{"wrapped deadline", fmt.Errorf("query accounts: %w", context.DeadlineExceeded), CodeRequestTimeout},
+		{"driver error", errors.New("connection refused"), CodeDatabaseError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ErrDatabase(tt.err)
+			if got.Code != tt.code || got.HTTPStatus != codeToHTTPStatus(tt.code) {
+				t.Errorf("ErrDatabase = %d (%d), want %d (%d)", got.Code, got.HTTPStatus, tt.code, codeToHTTPStatus(tt.code))
+			}
+		})
+	}
+
+	if got := codeToHTTPStatus(CodeClientClosed); got != 499 {
+		t.Errorf("CodeClientClosed status = %d, want 499", got)
+	}
+	if got := codeToHTTPStatus(CodeRequestTimeout); got != 504 {
+		t.Errorf("CodeRequestTimeout status = %d, want 504", got)
+	}
+}
+
+func TestAppErrorUnwrap(t *testing.T) {
+	cause := fmt.Errorf("find account: %w", gorm.ErrRecordNotFound)
+	wrapped := fmt.Errorf("get transfer: %w", Wrap(CodeDatabaseError, cause))
+
+	// errors.Is 穿透 fmt.Errorf → AppError → fmt.Errorf 找到底层哨兵错误
+	if !errors.Is(wrapped, gorm.ErrRecordNotFound) {
+		t.Error("errors.Is should see gorm.ErrRecordNotFound through the AppError")
+	}
+
+	// AsAppError 仍然返回链中的 AppError，而不是按底层错误重新识别
+	appErr := AsAppError(wrapped)
+	if appErr.Code != CodeDatabaseError || appErr.Err != cause {
+		t.Errorf("AsAppError = %d (cause %v), want CodeDatabaseError with the original cause", appErr.Code, appErr.Err)
+	}
+
+	if err := New(CodeNotFound).Unwrap(); err != nil {
+		t.Errorf("New(...).Unwrap() = %v, want nil", err)
+	}
+}
+
+func TestAsAppErrorRecognizesSentinels(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"record not found", gorm.ErrRecordNotFound, CodeNotFound},
+		{"wrapped record not found", fmt.Errorf("load user: %w", gorm.ErrRecordNotFound), CodeNotFound},
+		{"duplicated key", gorm.ErrDuplicatedKey, CodeAlreadyExists},
+		{"foreign key violated", gorm.ErrForeignKeyViolated, CodeNotFound},
+		{"canceled", context.Canceled, CodeClientClosed},
+		{"deadline exceeded", context.DeadlineExceeded, CodeRequestTimeout},
+		{"unknown", errors.New("boom"), CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AsAppError(tt.err)
+			if got.Code != tt.code {
+				t.Errorf("AsAppError code = %d, want %d", got.Code, tt.code)
+			}
+			// 使用默认消息，底层错误的文本不暴露给客户端
+			if got.Message != GetMessage(tt.code) {
+				t.Errorf("message = %q, want %q", got.Message, GetMessage(tt.code))
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Package event 提供进程内的事件发布/订阅
+//
+// Service 在事务提交后发布账户余额变动事件，SSE 等实时推送接口订阅并转发给客户端
+// 事件只在当前进程内传递，多实例部署时每个实例只能收到自己处理的转账产生的事件
+package event
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 余额变动的原因
+const (
+	KindTransferIn  = "transfer_in"  // 转账入账
+	KindTransferOut = "transfer_out" // 转账出账
+)
+
+// subscriptionBuffer 每个订阅者缓冲的事件数
+// 订阅者处理过慢、缓冲已满时丢弃新事件，不阻塞发布方
+const subscriptionBuffer = 64
+
+// BalanceChanged 账户余额变动事件
+type BalanceChanged struct {
+	AccountID       uint
+	AccountPublicID uuid.UUID
+	Owner           string
+	Kind            string
+	Amount          int64 // 本次变动金额，正数=入账, 负数=出账
+	Balance         int64 // 变动后的余额
+	Currency        string
+	TransferID      uuid.UUID
+	Reference       string
+	OccurredAt      time.Time
+}
+
+// ForAccount 返回只匹配指定账户 (公开ID) 事件的过滤条件
+func ForAccount(publicID uuid.UUID) func(ev *BalanceChanged) bool {
+	return func(ev *BalanceChanged) bool {
+		return ev.AccountPublicID == publicID
+	}
+}
+
+// ForIncomingTransfers 返回只匹配指定用户所有账户转账入账事件的过滤条件
+func ForIncomingTransfers(owner string) func(ev *BalanceChanged) bool {
+	return func(ev *BalanceChanged) bool {
+		return ev.Owner == owner && ev.Kind == KindTransferIn
+	}
+}
+
+// Bus 进程内的事件总线，可被多个 goroutine 并发使用
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// NewBus 创建 Bus 实例
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription 一个订阅，通过 Events 接收匹配的事件
+// 使用完毕后必须调用 Close 释放
+type Subscription struct {
+	bus    *Bus
+	match  func(ev *BalanceChanged) bool
+	events chan BalanceChanged
+	once   sync.Once
+}
+
+// Subscribe 订阅满足 match 的事件
+// Bus 已关闭时返回的订阅的 Events 立即关闭
+func (b *Bus) Subscribe(match func(ev *BalanceChanged) bool) *Subscription {
+	sub := &Subscription{
+		bus:    b,
+		match:  match,
+		events: make(chan BalanceChanged, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		sub.once.Do(func() { close(sub.events) })
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Publish 把事件发给所有匹配的订阅者，不会阻塞
+func (b *Bus) Publish(ev BalanceChanged) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.match(&ev) {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+			slog.Warn("event subscriber is too slow, dropping event",
+				"account_id", ev.AccountID,
+				"reference", ev.Reference,
+			)
+		}
+	}
+}
+
+// Close 关闭 Bus 和所有订阅，之后的 Publish 不再投递
+// 服务关闭时调用，让长连接的推送接口及时返回
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		sub.once.Do(func() { close(sub.events) })
+	}
+}
+
+// Events 返回接收事件的通道，订阅或 Bus 关闭后通道关闭
+func (s *Subscription) Events() <-chan BalanceChanged {
+	return s.events
+}
+
+// Close 取消订阅，可以重复调用
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	delete(s.bus.subs, s)
+	s.once.Do(func() { close(s.events) })
+}
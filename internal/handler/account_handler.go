@@ -49,6 +49,7 @@ func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 409 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
 // @Security BearerAuth
 // @Router /accounts [post]
 func (h *AccountHandler) CreateAccount(c *gin.Context) {
@@ -76,6 +77,50 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	c.JSON(http.StatusCreated, accountResp)
 }
 
+// CreateAccountsBatch 处理批量创建账户请求
+//
+// 路由: POST /api/v1/accounts/batch (需要认证)
+// 请求体: CreateAccountsBatchRequest (JSON)
+// 响应: 201 Created + CreateAccountsBatchResponse
+//
+// 业务规则:
+//   - 在同一事务中为每种货币创建账户
+//   - 已有该货币账户时跳过，在响应中标记为 skipped，不影响其他货币
+//
+// @Summary 批量创建账户
+// @Description 为当前用户一次创建多个货币的账户，已有的货币跳过
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param request body request.CreateAccountsBatchRequest true "货币列表"
+// @Success 201 {object} response.CreateAccountsBatchResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/batch [post]
+func (h *AccountHandler) CreateAccountsBatch(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证请求体
+	var req request.CreateAccountsBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 批量创建
+	batchResp, err := h.accountService.CreateAccountsBatch(c.Request.Context(), payload.Username, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusCreated, batchResp)
+}
+
 // GetAccount 处理获取账户详情请求
 //
 // 路由: GET /api/v1/accounts/:id (需要认证)
@@ -85,13 +130,16 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 // 业务规则:
 //   - 只能查看自己的账户
 //   - 查看他人账户返回 403 Forbidden
+//   - 响应带 ETag，If-None-Match 匹配时返回 304 Not Modified
 //
 // @Summary 获取账户
 // @Description 获取指定账户的详细信息
 // @Tags accounts
 // @Produce json
-// @Param id path int true "账户ID"
+// @Param id path string true "账户公开ID (UUID)"
+// @Param If-None-Match header string false "上次响应的 ETag"
 // @Success 200 {object} response.AccountResponse
+// @Success 304 "账户未变化"
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
@@ -111,33 +159,38 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 
 	// Step 3: 调用 Service 获取账户
 	// Service 会验证账户是否属于当前用户
-	accountResp, err := h.accountService.GetAccount(c.Request.Context(), payload.Username, req.ID)
+	accountResp, err := h.accountService.GetAccount(c.Request.Context(), payload.Username, req.PublicID())
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	// Step 4: 返回成功响应
-	c.JSON(http.StatusOK, accountResp)
+	// Step 4: 返回成功响应 (账户未变化时返回 304)
+	respondWithETag(c, accountResp)
 }
 
 // ListAccounts 处理获取账户列表请求
 //
 // 路由: GET /api/v1/accounts (需要认证)
-// 参数: page_id, page_size (Query 参数)
+// 参数: currency, page_id, page_size, sort (Query 参数)
 // 响应: 200 OK + ListResponse[AccountResponse]
 //
 // 业务规则:
 //   - 只返回当前用户的账户
+//   - 指定 currency 时只返回该货币的账户
 //   - 支持分页
 //
 // @Summary 获取账户列表
 // @Description 获取当前用户的所有账户（分页）
 // @Tags accounts
 // @Produce json
-// @Param page_id query int true "页码" minimum(1)
-// @Param page_size query int true "每页条数" minimum(5) maximum(100)
+// @Param currency query string false "货币类型" Enums(USD, EUR, CNY)
+// @Param page_id query int false "页码" minimum(1) default(1)
+// @Param page_size query int false "每页条数 (默认和上限见 PAGE_SIZE_DEFAULT / PAGE_SIZE_MAX)" minimum(5)
+// @Param sort query string false "排序字段 (id, created_at, balance)，前缀 - 表示降序"
 // @Success 200 {object} response.ListResponse[response.AccountResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Security BearerAuth
@@ -148,23 +201,319 @@ func (h *AccountHandler) ListAccounts(c *gin.Context) {
 
 	// Step 2: 绑定并验证 Query 参数
 	// ShouldBindQuery 解析 URL 中的查询参数 (如 ?page_id=1&page_size=10)
-	var req request.PaginationRequest
+	var req request.ListAccountsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		h.handleValidationError(c, err)
 		return
 	}
 
-	// Step 3: 调用 Service 获取账户列表
-	listResp, err := h.accountService.ListAccounts(c.Request.Context(), payload.Username, &req)
+	// Step 3: 校验分页参数并填充默认值
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 4: 调用 Service 获取账户列表
+	listResp, err := h.accountService.ListAccounts(c.Request.Context(), payload.Username, req.Currency, &req.PaginationRequest)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	// Step 4: 返回成功响应
+	// Step 5: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
 	c.JSON(http.StatusOK, listResp)
 }
 
+// GetSummary 处理账户余额汇总请求
+//
+// 路由: GET /api/v1/accounts/summary (需要认证)
+// 响应: 200 OK + AccountSummaryResponse
+//
+// 业务规则:
+//   - 只汇总当前用户的账户
+//   - 按货币分组，每种货币返回余额合计和账户数
+//
+// @Summary 账户余额汇总
+// @Description 按货币汇总当前用户的账户余额和账户数
+// @Tags accounts
+// @Produce json
+// @Success 200 {object} response.AccountSummaryResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/summary [get]
+func (h *AccountHandler) GetSummary(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 调用 Service 汇总余额
+	summaryResp, err := h.accountService.GetSummary(c.Request.Context(), payload.Username)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	c.JSON(http.StatusOK, summaryResp)
+}
+
+// RenameAccount 处理修改账户名称请求
+//
+// 路由: PATCH /api/v1/accounts/:id (需要认证)
+// 请求体: UpdateAccountRequest (JSON)
+// 响应: 200 OK + AccountResponse
+//
+// 业务规则:
+//   - 只能修改自己的账户
+//   - 修改他人账户返回 403 Forbidden
+//
+// @Summary 修改账户名称
+// @Description 修改账户的展示名称，空字符串表示清除
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param request body request.UpdateAccountRequest true "账户名称"
+// @Success 200 {object} response.AccountResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id} [patch]
+func (h *AccountHandler) RenameAccount(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和请求体
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 修改名称
+	accountResp, err := h.accountService.RenameAccount(c.Request.Context(), payload.Username, uriReq.PublicID(), *req.Name)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, accountResp)
+}
+
+// SetOverdraftLimit 处理设置透支额度请求
+//
+// 路由: PUT /api/v1/admin/accounts/:id/overdraft-limit (需要管理员权限)
+// 请求体: SetOverdraftLimitRequest (JSON)
+// 响应: 200 OK + AccountResponse
+//
+// @Summary 设置透支额度
+// @Description 设置账户允许透支的额度 (管理员)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param request body request.SetOverdraftLimitRequest true "透支额度"
+// @Success 200 {object} response.AccountResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/accounts/{id}/overdraft-limit [put]
+func (h *AccountHandler) SetOverdraftLimit(c *gin.Context) {
+	// Step 1: 绑定并验证 URL 参数和请求体
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.SetOverdraftLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 2: 调用 Service 设置透支额度 (记录审计日志)
+	payload := middleware.MustGetAuthPayload(c)
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	accountResp, err := h.accountService.SetOverdraftLimit(ctx, payload.Username, uriReq.PublicID(), req.OverdraftLimit.Int64())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	c.JSON(http.StatusOK, accountResp)
+}
+
+// SetMinBalance 处理设置最低余额请求
+//
+// 路由: PUT /api/v1/admin/accounts/:id/min-balance (需要管理员权限)
+// 请求体: SetMinBalanceRequest (JSON)
+// 响应: 200 OK + AccountResponse
+//
+// @Summary 设置最低余额
+// @Description 设置账户必须保留的最低余额 (管理员)，与透支额度互斥
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param request body request.SetMinBalanceRequest true "最低余额"
+// @Success 200 {object} response.AccountResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/accounts/{id}/min-balance [put]
+func (h *AccountHandler) SetMinBalance(c *gin.Context) {
+	// Step 1: 绑定并验证 URL 参数和请求体
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.SetMinBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 2: 调用 Service 设置最低余额 (记录审计日志)
+	payload := middleware.MustGetAuthPayload(c)
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	accountResp, err := h.accountService.SetMinBalance(ctx, payload.Username, uriReq.PublicID(), req.MinBalance.Int64())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	c.JSON(http.StatusOK, accountResp)
+}
+
+// FreezeAccount 处理冻结账户请求
+//
+// 路由: POST /api/v1/admin/accounts/:id/freeze (需要管理员权限)
+// 响应: 200 OK + AccountResponse
+//
+// 业务规则:
+//   - 冻结的账户仍可以查询，但作为转出方或收款方的转账都返回 422 (account is frozen)
+//   - 冻结已冻结的账户不报错
+//
+// @Summary 冻结账户
+// @Description 暂停账户的所有转账，账户仍可查询 (管理员)
+// @Tags admin
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Success 200 {object} response.AccountResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/accounts/{id}/freeze [post]
+func (h *AccountHandler) FreezeAccount(c *gin.Context) {
+	h.setFrozen(c, true)
+}
+
+// UnfreezeAccount 处理解冻账户请求
+//
+// 路由: POST /api/v1/admin/accounts/:id/unfreeze (需要管理员权限)
+// 响应: 200 OK + AccountResponse
+//
+// @Summary 解冻账户
+// @Description 恢复被冻结账户的转账 (管理员)
+// @Tags admin
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Success 200 {object} response.AccountResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/accounts/{id}/unfreeze [post]
+func (h *AccountHandler) UnfreezeAccount(c *gin.Context) {
+	h.setFrozen(c, false)
+}
+
+// setFrozen 冻结或解冻 URL 中指定的账户
+func (h *AccountHandler) setFrozen(c *gin.Context, frozen bool) {
+	// Step 1: 绑定并验证 URL 参数
+	var req request.GetAccountRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 2: 调用 Service 设置冻结状态 (记录审计日志)
+	payload := middleware.MustGetAuthPayload(c)
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	accountResp, err := h.accountService.SetFrozen(ctx, payload.Username, req.PublicID(), frozen)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	c.JSON(http.StatusOK, accountResp)
+}
+
+// RestoreAccount 处理恢复已关闭账户请求
+//
+// 路由: POST /api/v1/admin/accounts/:id/restore (需要管理员权限)
+// 响应: 200 OK + AccountResponse
+//
+// 业务规则:
+//   - 只能恢复已关闭 (软删除) 的账户，否则返回 409
+//   - 用户在账户关闭期间已开立同币种账户时返回 409
+//
+// @Summary 恢复已关闭账户
+// @Description 撤销账户的关闭 (管理员)
+// @Tags admin
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Success 200 {object} response.AccountResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/accounts/{id}/restore [post]
+func (h *AccountHandler) RestoreAccount(c *gin.Context) {
+	// Step 1: 绑定并验证 URL 参数
+	var req request.GetAccountRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 2: 调用 Service 恢复账户 (记录审计日志)
+	payload := middleware.MustGetAuthPayload(c)
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	accountResp, err := h.accountService.RestoreAccount(ctx, payload.Username, req.PublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	c.JSON(http.StatusOK, accountResp)
+}
+
 // ==================== 错误处理辅助方法 ====================
 
 // handleError 统一处理 Service 层返回的错误
@@ -174,7 +523,8 @@ func (h *AccountHandler) handleError(c *gin.Context, err error) {
 }
 
 // handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
 func (h *AccountHandler) handleValidationError(c *gin.Context, err error) {
-	appErr := apperrors.ErrInvalidParams(err.Error())
-	c.JSON(http.StatusBadRequest, response.NewErrorResponse(appErr))
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
 }
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+// newAccountListEngine 创建挂载 GET /accounts 的 Engine，alice 持有 USD、EUR、CNY 三个账户
+// 返回 alice 的认证请求头
+func newAccountListEngine(t *testing.T) (*gin.Engine, http.Header) {
+	t.Helper()
+	ctx := context.Background()
+	repos := memory.New()
+	maker := newTestTokenMaker(t)
+	accounts := service.NewAccountService(repos.TxManager, repos.Accounts, service.NewAuditLogger(repos.AuditLogs))
+
+	for _, currency := range []string{"USD", "EUR", "CNY"} {
+		if err := repos.Accounts.Create(ctx, &model.Account{Owner: "alice", Currency: currency}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accessToken, _, err := maker.CreateToken("alice", model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestEngine()
+	r.GET("/accounts", middleware.AuthMiddleware(maker), NewAccountHandler(accounts).ListAccounts)
+	return r, http.Header{"Authorization": {"Bearer " + accessToken}}
+}
+
+func TestListAccountsDefaultsPagination(t *testing.T) {
+	r, header := newAccountListEngine(t)
+
+	// 不带任何分页参数时返回第 1 页，每页默认 10 条
+	w := doJSON(r, http.MethodGet, "/accounts", nil, header)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	var resp response.ListResponse[response.AccountResponse]
+	decodeJSON(t, w, &resp)
+	if resp.Pagination.Page != 1 || resp.Pagination.PageSize != 10 {
+		t.Errorf("pagination = page %d size %d, want page 1 size 10", resp.Pagination.Page, resp.Pagination.PageSize)
+	}
+	if len(resp.Data) != 3 || resp.Pagination.TotalCount != 3 {
+		t.Errorf("got %d of %d accounts, want 3 of 3", len(resp.Data), resp.Pagination.TotalCount)
+	}
+}
+
+func TestListAccountsCurrencyFilter(t *testing.T) {
+	r, header := newAccountListEngine(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"unfiltered", "", []string{"USD", "EUR", "CNY"}},
+		{"filtered", "?currency=EUR", []string{"EUR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSON(r, http.MethodGet, "/accounts"+tt.query, nil, header)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+			}
+			var resp response.ListResponse[response.AccountResponse]
+			decodeJSON(t, w, &resp)
+			var got []string
+			for _, account := range resp.Data {
+				got = append(got, account.Currency)
+			}
+			slices.Sort(got)
+			want := slices.Sorted(slices.Values(tt.want))
+			if !slices.Equal(got, want) || resp.Pagination.TotalCount != int64(len(want)) {
+				t.Errorf("currencies = %v (total %d), want %v", got, resp.Pagination.TotalCount, want)
+			}
+		})
+	}
+
+	// 不支持的货币返回参数错误
+	w := doJSON(r, http.MethodGet, "/accounts?currency=XYZ", nil, header)
+	var errResp response.ErrorResponse
+	decodeJSON(t, w, &errResp)
+	if w.Code != http.StatusBadRequest || errResp.Code != apperrors.CodeInvalidParams {
+		t.Errorf("unsupported currency = %d %+v, want 400 code %d", w.Code, errResp, apperrors.CodeInvalidParams)
+	}
+}
+
+func TestUpdateAccountRejectsCurrencyChange(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	maker := newTestTokenMaker(t)
+	accounts := service.NewAccountService(repos.TxManager, repos.Accounts, service.NewAuditLogger(repos.AuditLogs))
+
+	account := &model.Account{Owner: "alice", Currency: "USD"}
+	if err := repos.Accounts.Create(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _, err := maker.CreateToken("alice", model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestEngine()
+	r.PATCH("/accounts/:id", middleware.AuthMiddleware(maker), NewAccountHandler(accounts).RenameAccount)
+	header := http.Header{"Authorization": {"Bearer " + accessToken}}
+
+	request.SetStrictJSON(true)
+	t.Cleanup(func() { request.SetStrictJSON(false) })
+
+	w := doJSON(r, http.MethodPatch, "/accounts/"+account.PublicID.String(), map[string]any{"name": "Travel", "currency": "EUR"}, header)
+	var errResp response.ErrorResponse
+	decodeJSON(t, w, &errResp)
+	if w.Code != http.StatusBadRequest || errResp.Code != apperrors.CodeInvalidParams || errResp.Message != `unknown field "currency"` {
+		t.Errorf("currency change = %d %+v, want 400 naming the currency field", w.Code, errResp)
+	}
+
+	// 请求被整体拒绝，名称和货币都不变
+	got, err := repos.Accounts.GetByID(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Currency != "USD" || got.Name != "" {
+		t.Errorf("account = %s %q, want USD with no name", got.Currency, got.Name)
+	}
+}
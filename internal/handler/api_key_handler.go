@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+// ==================== Handler 结构体 ====================
+
+// APIKeyHandler 处理 API Key 管理相关的 HTTP 请求
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 APIKeyHandler 实例
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// ==================== Handler 方法 ====================
+
+// CreateAPIKey 处理创建 API Key 请求
+//
+// 路由: POST /api/v1/api-keys (需要认证，不能使用 API Key)
+// 请求体: CreateAPIKeyRequest (JSON)
+// 响应: 201 Created + CreateAPIKeyResponse
+//
+// 业务规则:
+//   - 响应中的 key 只返回这一次，服务端只保存哈希
+//   - 不指定 scopes 时授予全部权限范围
+//   - 使用方式: Authorization: ApiKey <key>
+//
+// @Summary 创建 API Key
+// @Description 为当前用户创建用于服务端集成的 API Key，Key 明文只在响应中返回一次
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body request.CreateAPIKeyRequest true "API Key 信息"
+// @Success 201 {object} response.CreateAPIKeyResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证请求体
+	var req request.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 创建 Key
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	keyResp, err := h.apiKeyService.CreateAPIKey(ctx, payload.Username, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusCreated, keyResp)
+}
+
+// ListAPIKeys 处理获取 API Key 列表请求
+//
+// 路由: GET /api/v1/api-keys?page_id=1&page_size=10 (需要认证，不能使用 API Key)
+// 响应: 200 OK + ListResponse[APIKeyResponse]
+//
+// @Summary 获取 API Key 列表
+// @Description 获取当前用户的 API Key (包括已撤销的)，不包含 Key 明文
+// @Tags api-keys
+// @Produce json
+// @Param page_id query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.ListResponse[response.APIKeyResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 Query 参数
+	var req request.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 查询
+	listResp, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), payload.Username, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
+	c.JSON(http.StatusOK, listResp)
+}
+
+// RevokeAPIKey 处理撤销 API Key 请求
+//
+// 路由: DELETE /api/v1/api-keys/:id (需要认证，不能使用 API Key)
+// 响应: 200 OK + APIKeyResponse
+//
+// 业务规则:
+//   - 撤销立即生效，之后使用该 Key 的请求返回 401
+//   - 已撤销的 Key 不能再次撤销 (409)
+//
+// @Summary 撤销 API Key
+// @Description 撤销当前用户的 API Key
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API Key 公开ID (UUID)"
+// @Success 200 {object} response.APIKeyResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetAPIKeyRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 撤销
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	keyResp, err := h.apiKeyService.RevokeAPIKey(ctx, payload.Username, req.PublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, keyResp)
+}
+
+// ==================== 错误处理辅助方法 ====================
+
+// handleError 统一处理 Service 层返回的错误
+func (h *APIKeyHandler) handleError(c *gin.Context, err error) {
+	appErr := apperrors.AsAppError(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
+
+// handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
+func (h *APIKeyHandler) handleValidationError(c *gin.Context, err error) {
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
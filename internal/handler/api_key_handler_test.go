@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	maker := newTestTokenMaker(t)
+	keys := service.NewAPIKeyService(repos.APIKeys, service.NewAuditLogger(repos.AuditLogs))
+
+	active, err := keys.CreateAPIKey(ctx, "alice", &request.CreateAPIKeyRequest{Name: "billing-sync"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	revoked, err := keys.CreateAPIKey(ctx, "alice", &request.CreateAPIKeyRequest{Name: "old"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if _, err := keys.RevokeAPIKey(ctx, "alice", revoked.PublicID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	accessToken, _, err := maker.CreateToken("alice", model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 两种认证方式在下游得到相同的 payload
+	r := newTestEngine()
+	r.GET("/whoami", middleware.APIKeyAuth(keys), middleware.AuthMiddleware(maker), func(c *gin.Context) {
+		payload := middleware.MustGetAuthPayload(c)
+		c.JSON(http.StatusOK, gin.H{"username": payload.Username, "api_key": middleware.IsAPIKeyAuth(c)})
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantAPIKey    bool
+	}{
+		{name: "valid key", authorization: "ApiKey " + active.Key, wantStatus: http.StatusOK, wantAPIKey: true},
+		{name: "revoked key", authorization: "ApiKey " + revoked.Key, wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", authorization: "ApiKey sbk_unknown", wantStatus: http.StatusUnauthorized},
+		{name: "jwt", authorization: "Bearer " + accessToken, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Authorization", tt.authorization)
+			w := doJSON(r, http.MethodGet, "/whoami", nil, header)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Username string `json:"username"`
+				APIKey   bool   `json:"api_key"`
+			}
+			decodeJSON(t, w, &body)
+			if body.Username != "alice" || body.APIKey != tt.wantAPIKey {
+				t.Errorf("body = %+v, want alice with api_key=%v", body, tt.wantAPIKey)
+			}
+		})
+	}
+}
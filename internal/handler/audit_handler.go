@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+// ==================== Handler 结构体 ====================
+
+// AuditHandler 处理审计日志相关的 HTTP 请求 (管理员)
+type AuditHandler struct {
+	auditLogger *service.AuditLogger
+}
+
+// NewAuditHandler 创建 AuditHandler 实例
+func NewAuditHandler(auditLogger *service.AuditLogger) *AuditHandler {
+	return &AuditHandler{
+		auditLogger: auditLogger,
+	}
+}
+
+// ==================== Handler 方法 ====================
+
+// ListAuditLogs 处理查询审计日志请求
+//
+// 路由: GET /api/v1/admin/audit-logs (需要管理员权限)
+// 参数: actor, action, page_id, page_size (Query 参数)
+// 响应: 200 OK + ListResponse[AuditLogResponse]
+//
+// @Summary 查询审计日志
+// @Description 按操作者和操作类型过滤审计日志（分页）
+// @Tags admin
+// @Produce json
+// @Param actor query string false "操作者"
+// @Param action query string false "操作类型"
+// @Param page_id query int false "页码" minimum(1) default(1)
+// @Param page_size query int false "每页条数 (默认和上限见 PAGE_SIZE_DEFAULT / PAGE_SIZE_MAX)" minimum(5)
+// @Success 200 {object} response.ListResponse[response.AuditLogResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/audit-logs [get]
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	// Step 1: 绑定并验证 Query 参数
+	var req request.ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 2: 调用 Service 查询审计日志
+	listResp, err := h.auditLogger.ListAuditLogs(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
+	c.JSON(http.StatusOK, listResp)
+}
+
+// ==================== 错误处理辅助方法 ====================
+
+// handleError 统一处理 Service 层返回的错误
+func (h *AuditHandler) handleError(c *gin.Context, err error) {
+	appErr := apperrors.AsAppError(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
+
+// handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
+func (h *AuditHandler) handleValidationError(c *gin.Context, err error) {
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+)
+
+// ==================== Handler 结构体 ====================
+
+// ConfigHandler 处理运行时配置相关的 HTTP 请求 (内部管理接口)
+type ConfigHandler struct {
+	runtime *config.RuntimeStore
+	path    string
+	files   []string
+}
+
+// NewConfigHandler 创建 ConfigHandler 实例
+//
+// 参数:
+//   - runtime: 当前生效的可热更新配置
+//   - path, files: 与启动时传给 config.LoadConfig 的参数相同，重新加载时使用
+func NewConfigHandler(runtime *config.RuntimeStore, path string, files ...string) *ConfigHandler {
+	return &ConfigHandler{
+		runtime: runtime,
+		path:    path,
+		files:   files,
+	}
+}
+
+// ==================== Handler 方法 ====================
+
+// ReloadConfig 处理重新加载配置请求
+//
+// 路由: POST /internal/reload-config (IP 白名单)
+// 响应: 200 OK + RuntimeConfig
+//
+// 只替换可热更新的配置子集 (RuntimeConfig)，数据库、Token 密钥等不受影响
+// 替换是原子的，正在处理的请求仍使用旧配置
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	// Step 1: 重新读取配置文件和环境变量
+	cfg, err := config.LoadConfig(h.path, h.files...)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("reload config", "error", err)
+		appErr := apperrors.NewWithMessage(apperrors.CodeInternalError, "failed to reload config")
+		c.JSON(http.StatusInternalServerError, response.NewErrorResponse(appErr))
+		return
+	}
+
+	// Step 2: 原子替换可热更新配置
+	h.runtime.Store(cfg.Runtime)
+	logging.FromContext(c.Request.Context()).Info("runtime config reloaded",
+		"maintenance_mode", cfg.Runtime.MaintenanceMode,
+		"feature_flags", cfg.Runtime.FeatureFlags,
+	)
+
+	// Step 3: 返回当前生效的配置
+	c.JSON(http.StatusOK, h.runtime.Load())
+}
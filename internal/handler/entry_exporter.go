@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+)
+
+// exportFlushEvery 每写出多少行刷新一次，让客户端尽早收到数据
+const exportFlushEvery = 500
+
+// entryExporter 将账目逐行写出为 CSV 或 JSON 数组
+//
+// 响应头延迟到第一次写出时发送，在此之前发生的错误仍可返回正常的 JSON 错误
+type entryExporter struct {
+	c         *gin.Context
+	format    string
+	accountID string
+
+	started bool
+	rows    int
+	csv     *csv.Writer
+}
+
+// newEntryExporter 创建 entryExporter，format 为 csv 或 json
+func newEntryExporter(c *gin.Context, format string, accountID string) *entryExporter {
+	return &entryExporter{
+		c:         c,
+		format:    format,
+		accountID: accountID,
+	}
+}
+
+// Started 返回是否已经开始写出响应
+func (e *entryExporter) Started() bool {
+	return e.started
+}
+
+// Write 写出一条账目
+func (e *entryExporter) Write(entry *response.EntryResponse) error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+
+	var err error
+	switch e.format {
+	case "json":
+		err = e.writeJSON(entry)
+	default:
+		err = e.csv.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			strconv.FormatInt(entry.Amount.Int64(), 10),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		e.flush()
+	}
+	return nil
+}
+
+// Close 结束输出
+func (e *entryExporter) Close() error {
+	if err := e.begin(); err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		if _, err := e.c.Writer.WriteString("]\n"); err != nil {
+			return err
+		}
+	}
+	e.flush()
+	if e.csv != nil {
+		return e.csv.Error()
+	}
+	return nil
+}
+
+// begin 发送响应头和文件开头 (CSV 表头或 JSON 的 "[")，只执行一次
+func (e *entryExporter) begin() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	filename := fmt.Sprintf("account-%s-entries-%s.%s", e.accountID, time.Now().UTC().Format("20060102"), e.format)
+	e.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	switch e.format {
+	case "json":
+		e.c.Header("Content-Type", "application/json; charset=utf-8")
+		e.c.Status(http.StatusOK)
+		_, err := e.c.Writer.WriteString("[")
+		return err
+	default:
+		e.c.Header("Content-Type", "text/csv; charset=utf-8")
+		e.c.Status(http.StatusOK)
+		e.csv = csv.NewWriter(e.c.Writer)
+		return e.csv.Write([]string{"id", "amount", "created_at"})
+	}
+}
+
+// writeJSON 写出 JSON 数组中的一个元素
+func (e *entryExporter) writeJSON(entry *response.EntryResponse) error {
+	if e.rows > 0 {
+		if _, err := e.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = e.c.Writer.Write(data)
+	return err
+}
+
+// flush 将缓冲的数据发送给客户端
+func (e *entryExporter) flush() {
+	if e.csv != nil {
+		e.csv.Flush()
+	}
+	e.c.Writer.Flush()
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// ==================== 条件请求辅助方法 ====================
+
+// computeETag 根据序列化后的响应体计算强 ETag
+// 响应中任何字段变化都会改变 ETag，不依赖更新时间的精度
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// respondWithETag 序列化 body 并写入带 ETag 的 200 响应
+// 请求头 If-None-Match 与 etag 匹配时返回 304 Not Modified 且不带响应体
+func respondWithETag(c *gin.Context, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		appErr := apperrors.Wrap(apperrors.CodeInternalError, err)
+		c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+		return
+	}
+
+	etag := computeETag(data)
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// etagMatches 判断 If-None-Match 是否匹配 etag
+// 支持 "*"、逗号分隔的多个值以及弱校验前缀 W/
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
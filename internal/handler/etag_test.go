@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+)
+
+// serveWithETag 用 respondWithETag 返回 body，可选携带 If-None-Match
+func serveWithETag(body any, ifNoneMatch string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) { respondWithETag(c, body) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestETagChangesWithinSameSecond(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := response.AccountResponse{PublicID: uuid.New(), Balance: 100, UpdatedAt: updatedAt}
+	after := before
+	after.Balance = 200
+
+	first := serveWithETag(before, "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag header")
+	}
+
+	if w := serveWithETag(before, etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("unchanged: status = %d, body = %q, want 304 without body", w.Code, w.Body.String())
+	}
+
+	// 更新时间只精确到秒，余额变化仍然必须让 ETag 失效
+	w := serveWithETag(after, etag)
+	if w.Code != http.StatusOK {
+		t.Errorf("changed: status = %d, want 200", w.Code)
+	}
+	if w.Header().Get("ETag") == etag {
+		t.Error("ETag did not change with the response body")
+	}
+}
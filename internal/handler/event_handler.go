@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/event"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+// defaultHeartbeatInterval 推送连接的默认心跳间隔
+// 需要短于代理和负载均衡器的空闲超时 (通常为 60 秒)
+const defaultHeartbeatInterval = 15 * time.Second
+
+const (
+	// wsWriteWait 写出一条 WebSocket 消息 (含 ping 和 close) 的超时时间
+	wsWriteWait = 10 * time.Second
+
+	// wsMaxMessageBytes 客户端消息的最大长度，推送连接不需要客户端发送数据
+	wsMaxMessageBytes = 512
+)
+
+// wsUpgrader 升级 WebSocket 连接
+//
+// 认证只依赖握手时携带的 Access Token，不使用 Cookie，
+// 不存在跨站劫持的风险，因此允许任意来源的页面连接
+var wsUpgrader = websocket.Upgrader{
+	Subprotocols: []string{middleware.WebSocketBearerProtocol},
+	CheckOrigin:  func(*http.Request) bool { return true },
+}
+
+// ==================== Handler 结构体 ====================
+
+// EventHandler 处理实时事件推送相关的 HTTP 请求
+type EventHandler struct {
+	accountService *service.AccountService
+	bus            *event.Bus
+	heartbeat      time.Duration
+}
+
+// NewEventHandler 创建 EventHandler 实例
+func NewEventHandler(accountService *service.AccountService, bus *event.Bus) *EventHandler {
+	return &EventHandler{
+		accountService: accountService,
+		bus:            bus,
+		heartbeat:      defaultHeartbeatInterval,
+	}
+}
+
+// WithHeartbeat 设置推送连接的心跳间隔
+func (h *EventHandler) WithHeartbeat(interval time.Duration) *EventHandler {
+	if interval > 0 {
+		h.heartbeat = interval
+	}
+	return h
+}
+
+// ==================== Handler 方法 ====================
+
+// StreamAccountEvents 处理订阅账户余额变动请求 (Server-Sent Events)
+//
+// 路由: GET /api/v1/accounts/:id/events (需要认证)
+// 响应: 200 OK + text/event-stream，每次余额变动推送一个 balance 事件
+//
+// 业务规则:
+//   - 只能订阅自己的账户，所有权在建立连接前验证
+//   - 没有事件时定期发送注释行作为心跳，防止连接被代理断开
+//   - 只推送连接建立之后的变动，断线重连期间的变动需要通过账目接口补齐
+//
+// @Summary 订阅账户余额变动
+// @Description 通过 Server-Sent Events 实时推送账户的余额变动
+// @Tags accounts
+// @Produce text/event-stream
+// @Param id path string true "账户公开ID (UUID)"
+// @Success 200 {object} response.BalanceEventResponse "event: balance"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/events [get]
+func (h *EventHandler) StreamAccountEvents(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetAccountRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 验证账户所有权后再订阅
+	account, err := h.accountService.GetAccount(c.Request.Context(), payload.Username, req.PublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	sub := h.bus.Subscribe(event.ForAccount(account.PublicID))
+	defer sub.Close()
+
+	// Step 4: 发送响应头，之后逐个推送事件直到客户端断开或服务关闭
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁止 Nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			c.SSEvent("balance", toBalanceEventResponse(&ev))
+		case <-ticker.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// ServeWebSocket 处理订阅转账入账通知请求 (WebSocket)
+//
+// 路由: GET /api/v1/ws (握手时认证，见 middleware.WebSocketAuth)
+// 响应: 101 Switching Protocols，之后当前用户任一账户收到转账时推送一条 JSON 文本消息
+//
+// 连接管理:
+//   - 服务端按心跳间隔发送 ping，两个间隔内没有收到 pong 或任何消息时断开
+//   - 客户端发送的消息被忽略
+//   - 服务关闭时发送 1001 (going away) 关闭帧
+//
+// @Summary 订阅转账入账通知
+// @Description 通过 WebSocket 实时推送当前用户收到的转账，Access Token 通过子协议 (bearer, token) 或 access_token 参数传递
+// @Tags transfers
+// @Param access_token query string false "Access Token (无法使用子协议时)"
+// @Success 101 {object} response.BalanceEventResponse "每条消息的格式"
+// @Failure 401 {object} response.ErrorResponse
+// @Router /ws [get]
+func (h *EventHandler) ServeWebSocket(c *gin.Context) {
+	// Step 1: 获取握手时认证的用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 升级连接，失败时 Upgrader 已返回错误响应
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Debug("websocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	sub := h.bus.Subscribe(event.ForIncomingTransfers(payload.Username))
+	defer sub.Close()
+
+	// Step 3: 读循环处理 pong 和客户端关闭，连接断开时关闭 done
+	pongWait := 2 * h.heartbeat
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.SetReadLimit(wsMaxMessageBytes)
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+			_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		}
+	}()
+
+	// Step 4: 写循环推送事件和 ping，直到连接断开或服务关闭
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-c.Request.Context().Done():
+			closeWebSocket(conn, websocket.CloseGoingAway)
+			return
+		case ev, ok := <-sub.Events():
+			if !ok {
+				closeWebSocket(conn, websocket.CloseGoingAway)
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteJSON(toBalanceEventResponse(&ev)); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// closeWebSocket 发送关闭帧，通知客户端连接即将关闭
+func closeWebSocket(conn *websocket.Conn, code int) {
+	msg := websocket.FormatCloseMessage(code, "")
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+}
+
+// toBalanceEventResponse 转换为余额变动事件响应
+func toBalanceEventResponse(ev *event.BalanceChanged) *response.BalanceEventResponse {
+	return &response.BalanceEventResponse{
+		AccountID:  ev.AccountPublicID,
+		Kind:       ev.Kind,
+		Amount:     money.Amount(ev.Amount),
+		Balance:    money.Amount(ev.Balance),
+		Currency:   ev.Currency,
+		TransferID: ev.TransferID,
+		Reference:  ev.Reference,
+		OccurredAt: ev.OccurredAt,
+	}
+}
+
+// ==================== 错误处理辅助方法 ====================
+
+// handleError 统一处理 Service 层返回的错误
+func (h *EventHandler) handleError(c *gin.Context, err error) {
+	appErr := apperrors.AsAppError(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
+
+// handleValidationError 处理请求参数验证错误
+func (h *EventHandler) handleValidationError(c *gin.Context, err error) {
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/event"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// eventTestEnv 推送接口测试的依赖: alice 和 bob 各有一个 USD 账户
+type eventTestEnv struct {
+	maker     token.Maker
+	bus       *event.Bus
+	accounts  *service.AccountService
+	transfers *service.TransferService
+	alice     *model.Account
+	bob       *model.Account
+}
+
+func newEventTestEnv(t *testing.T) *eventTestEnv {
+	t.Helper()
+	ctx := context.Background()
+	repos := memory.New()
+	auditor := service.NewAuditLogger(repos.AuditLogs)
+	env := &eventTestEnv{
+		maker:    newTestTokenMaker(t),
+		bus:      event.NewBus(),
+		accounts: service.NewAccountService(repos.TxManager, repos.Accounts, auditor),
+		alice:    &model.Account{Owner: "alice", Currency: "USD", Balance: 10000},
+		bob:      &model.Account{Owner: "bob", Currency: "USD"},
+	}
+	env.transfers = service.NewTransferService(repos.TxManager, repos.Accounts, repos.Transfers, repos.Entries,
+		auditor, service.TransferLimits{}).WithEventPublisher(env.bus)
+	for _, account := range []*model.Account{env.alice, env.bob} {
+		if err := repos.Accounts.Create(ctx, account); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(env.bus.Close)
+	return env
+}
+
+// accessToken 为 username 签发 Access Token
+func (env *eventTestEnv) accessToken(t *testing.T, username string) string {
+	t.Helper()
+	accessToken, _, err := env.maker.CreateToken(username, model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return accessToken
+}
+
+// transfer 由 alice 向 bob 转账 amount
+func (env *eventTestEnv) transfer(t *testing.T, amount int64) *response.TransferResponse {
+	t.Helper()
+	transfer, err := env.transfers.CreateTransfer(context.Background(), "alice", &request.CreateTransferRequest{
+		FromAccountID: env.alice.PublicID.String(),
+		ToAccountID:   env.bob.PublicID.String(),
+		Amount:        money.Amount(amount),
+	})
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	return transfer
+}
+
+func TestStreamAccountEventsPushesTransfer(t *testing.T) {
+	env := newEventTestEnv(t)
+	r := newTestEngine()
+	r.GET("/accounts/:id/events", middleware.AuthMiddleware(env.maker), NewEventHandler(env.accounts, env.bus).StreamAccountEvents)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/accounts/"+env.bob.PublicID.String()+"/events", nil)
+	req.Header.Set("Authorization", "Bearer "+env.accessToken(t, "bob"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// 收到响应头时已经订阅，之后的转账会推送给 bob
+	transfer := env.transfer(t, 1000)
+
+	var eventName string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event:"); ok {
+			eventName = name
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		var ev response.BalanceEventResponse
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			t.Fatalf("decode event %q: %v", data, err)
+		}
+		if eventName != "balance" || ev.AccountID != env.bob.PublicID || ev.Kind != event.KindTransferIn ||
+			ev.Amount != 1000 || ev.Balance != 1000 || ev.TransferID != transfer.PublicID {
+			t.Errorf("event %s = %+v", eventName, ev)
+		}
+		return
+	}
+	t.Fatalf("stream ended without an event: %v", scanner.Err())
+}
+
+func TestStreamAccountEventsChecksOwnership(t *testing.T) {
+	env := newEventTestEnv(t)
+	r := newTestEngine()
+	r.GET("/accounts/:id/events", middleware.AuthMiddleware(env.maker), NewEventHandler(env.accounts, env.bus).StreamAccountEvents)
+
+	// alice 不能订阅 bob 的账户，在建立推送连接之前就被拒绝
+	w := doJSON(r, http.MethodGet, "/accounts/"+env.bob.PublicID.String()+"/events", nil,
+		http.Header{"Authorization": {"Bearer " + env.accessToken(t, "alice")}})
+	var errResp response.ErrorResponse
+	decodeJSON(t, w, &errResp)
+	if w.Code != http.StatusUnauthorized || errResp.Code != apperrors.CodeUnauthorized {
+		t.Errorf("status = %d %+v, want 401 before streaming", w.Code, errResp)
+	}
+}
+
+func TestServeWebSocketPushesIncomingTransfer(t *testing.T) {
+	env := newEventTestEnv(t)
+	r := newTestEngine()
+	r.GET("/ws", middleware.WebSocketAuth(env.maker), NewEventHandler(env.accounts, env.bus).ServeWebSocket)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	// 未携带 Token 的握手在升级前被拒绝
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated dial = %v, %v; want 401", resp, err)
+	}
+
+	// 通过子协议携带 Token，服务端回应 bearer 子协议
+	dialer := websocket.Dialer{Subprotocols: []string{middleware.WebSocketBearerProtocol, env.accessToken(t, "bob")}}
+	conn, resp, err := dialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || conn.Subprotocol() != middleware.WebSocketBearerProtocol {
+		t.Errorf("handshake = %d subprotocol %q", resp.StatusCode, conn.Subprotocol())
+	}
+
+	// 握手完成时服务端可能尚未订阅，重复转账直到收到第一条消息
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	received := make(chan response.BalanceEventResponse, 1)
+	go func() {
+		var ev response.BalanceEventResponse
+		if err := conn.ReadJSON(&ev); err == nil {
+			received <- ev
+		}
+		close(received)
+	}()
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		env.transfer(t, 100)
+		select {
+		case ev, ok := <-received:
+			if !ok {
+				t.Fatal("connection closed without a message")
+			}
+			if ev.AccountID != env.bob.PublicID || ev.Kind != event.KindTransferIn || ev.Amount != 100 {
+				t.Errorf("message = %+v", ev)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/internal/version"
+)
+
+// readyCheckTimeout 单个依赖检查的超时时间
+const readyCheckTimeout = 2 * time.Second
+
+// ReadinessCheck 检查一个依赖是否可用，不可用时返回错误
+type ReadinessCheck func(ctx context.Context) error
+
+// ==================== Handler 结构体 ====================
+
+// HealthHandler 处理健康检查和就绪检查请求
+type HealthHandler struct {
+	names    []string // 按注册顺序检查
+	checks   map[string]ReadinessCheck
+	draining *atomic.Bool
+}
+
+// NewHealthHandler 创建 HealthHandler 实例
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{
+		checks: make(map[string]ReadinessCheck),
+	}
+}
+
+// WithCheck 注册一个就绪检查的依赖，name 出现在响应的 checks 中
+func (h *HealthHandler) WithCheck(name string, check ReadinessCheck) *HealthHandler {
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+	return h
+}
+
+// WithDraining 设置服务关闭标志，设置后健康检查和就绪检查都返回 503
+func (h *HealthHandler) WithDraining(draining *atomic.Bool) *HealthHandler {
+	h.draining = draining
+	return h
+}
+
+// ==================== Handler 方法 ====================
+
+// Health 处理健康检查请求
+//
+// 路由: GET /health
+// 响应: 200 OK，只表示进程在运行，不检查依赖；服务关闭期间返回 503
+func (h *HealthHandler) Health(c *gin.Context) {
+	if h.isDraining() {
+		c.Header("Connection", "close")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  response.ReadinessDraining,
+			"message": "Simple Bank V2 is shutting down",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "Simple Bank V2 is running",
+	})
+}
+
+// Ready 处理就绪检查请求
+//
+// 路由: GET /ready
+// 响应: 全部依赖可用时 200 OK，任一不可用时 503，响应体中列出每个依赖的状态
+// 服务关闭期间直接返回 503 (status: draining)，不再检查依赖
+//
+// 失败原因只写日志，不出现在响应中
+//
+// @Summary 就绪检查
+// @Description 检查数据库、Token 签发等依赖是否可用
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.ReadinessResponse
+// @Failure 503 {object} response.ReadinessResponse
+// @Router /ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	// Step 1: 服务关闭期间不再接收流量
+	if h.isDraining() {
+		c.Header("Connection", "close")
+		c.JSON(http.StatusServiceUnavailable, response.ReadinessResponse{
+			Status: response.ReadinessDraining,
+			Checks: map[string]string{},
+		})
+		return
+	}
+
+	// Step 2: 依次检查每个依赖
+	resp := response.ReadinessResponse{
+		Status: response.ReadinessReady,
+		Checks: make(map[string]string, len(h.names)),
+	}
+	for _, name := range h.names {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyCheckTimeout)
+		err := h.checks[name](ctx)
+		cancel()
+
+		if err != nil {
+			logging.FromContext(ctx).Warn("readiness check failed", "dependency", name, "error", err)
+			resp.Checks[name] = response.ReadinessCheckFail
+			resp.Status = response.ReadinessNotReady
+			continue
+		}
+		resp.Checks[name] = response.ReadinessCheckOK
+	}
+
+	// Step 3: 任一依赖不可用时返回 503
+	status := http.StatusOK
+	if resp.Status != response.ReadinessReady {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
+
+// Version 处理构建信息请求
+//
+// 路由: GET /version
+// 响应: 200 OK + VersionResponse，用于部署后确认各环境运行的是哪个构建
+// 版本号、Git 提交和构建时间在构建时通过 -ldflags 注入 (见 version 包)
+//
+// @Summary 构建信息
+// @Description 返回当前运行的版本号、Git 提交、构建时间和 Go 版本
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.VersionResponse
+// @Router /version [get]
+func (h *HealthHandler) Version(c *gin.Context) {
+	info := version.Get()
+	c.JSON(http.StatusOK, response.VersionResponse{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+	})
+}
+
+// isDraining 返回服务是否正在关闭
+func (h *HealthHandler) isDraining() bool {
+	return h.draining != nil && h.draining.Load()
+}
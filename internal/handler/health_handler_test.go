@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	"github.com/proyuen/simple-bank-v2/internal/version"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// brokenVerifyMaker 能签发但不能验证 Token，模拟密钥配置错误
+type brokenVerifyMaker struct {
+	token.Maker
+}
+
+func (brokenVerifyMaker) VerifyToken(string) (*token.Payload, error) {
+	return nil, errors.New("signature is invalid")
+}
+
+func TestReadyReportsBrokenTokenMaker(t *testing.T) {
+	tests := []struct {
+		name       string
+		maker      token.Maker
+		wantStatus int
+		wantToken  string
+	}{
+		{"working maker", newTestTokenMaker(t), http.StatusOK, response.ReadinessCheckOK},
+		{"broken maker", brokenVerifyMaker{newTestTokenMaker(t)}, http.StatusServiceUnavailable, response.ReadinessCheckFail},
+		{"missing maker", nil, http.StatusServiceUnavailable, response.ReadinessCheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler().
+				WithCheck("db", func(context.Context) error { return nil }).
+				WithCheck("token", func(context.Context) error { return token.SelfCheck(tt.maker) })
+			r := newTestEngine()
+			r.GET("/ready", h.Ready)
+
+			w := doJSON(r, http.MethodGet, "/ready", nil, nil)
+			var resp response.ReadinessResponse
+			decodeJSON(t, w, &resp)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if resp.Checks["db"] != response.ReadinessCheckOK || resp.Checks["token"] != tt.wantToken {
+				t.Errorf("checks = %v, want db ok and token %s", resp.Checks, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestVersionReturnsBuildInfo(t *testing.T) {
+	// 模拟 -ldflags 注入的构建信息
+	saved := []string{version.Version, version.Commit, version.BuildTime}
+	t.Cleanup(func() { version.Version, version.Commit, version.BuildTime = saved[0], saved[1], saved[2] })
+	version.Version, version.Commit, version.BuildTime = "v1.2.0", "abc1234", "2026-01-02T03:04:05Z"
+
+	r := newTestEngine()
+	r.GET("/version", NewHealthHandler().Version)
+
+	w := doJSON(r, http.MethodGet, "/version", nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp response.VersionResponse
+	decodeJSON(t, w, &resp)
+	want := response.VersionResponse{
+		Version:   "v1.2.0",
+		Commit:    "abc1234",
+		BuildTime: "2026-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+	}
+	if resp != want {
+		t.Errorf("version = %+v, want %+v", resp, want)
+	}
+}
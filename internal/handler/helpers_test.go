@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+const testSecretKey = "01234567890123456789012345678901"
+
+// newTestTokenMaker 创建测试用的 JWTMaker
+func newTestTokenMaker(t *testing.T) token.Maker {
+	t.Helper()
+	maker, err := token.NewJWTMaker(testSecretKey)
+	if err != nil {
+		t.Fatalf("NewJWTMaker: %v", err)
+	}
+	return maker
+}
+
+// newTestUserService 创建使用内存 Repository 的 UserService，并注册密码为 "secret123" 的 users
+func newTestUserService(t *testing.T, repos *memory.Repositories, maker token.Maker, users ...string) *service.UserService {
+	t.Helper()
+	s := service.NewUserService(repos.Users, repos.Sessions, maker, 15*time.Minute, 24*time.Hour, 0,
+		service.NewAuditLogger(repos.AuditLogs))
+	for _, username := range users {
+		_, err := s.CreateUser(context.Background(), &request.CreateUserRequest{
+			Username: username,
+			Password: "secret123",
+			FullName: "Test " + username,
+			Email:    username + "@example.com",
+		})
+		if err != nil {
+			t.Fatalf("CreateUser(%s): %v", username, err)
+		}
+	}
+	return s
+}
+
+// newTestEngine 创建测试模式的 gin.Engine
+func newTestEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+// doJSON 向 r 发送 JSON 请求，body 为 nil 时不带请求体
+func doJSON(r http.Handler, method, path string, body any, header http.Header) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeJSON 解析响应体
+func decodeJSON(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+func TestServiceLogsCarryRequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	repos := memory.New()
+	maker := newTestTokenMaker(t)
+	auditor := service.NewAuditLogger(repos.AuditLogs)
+	accessToken, _, err := maker.CreateToken("alice", model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 审计日志写入失败时 AuditLogger 通过 logging.FromContext 记录错误
+	repos.Store.FailOn(memory.OpAuditLogCreate, 1, errors.New("disk full"))
+	r := newTestEngine()
+	r.POST("/action", middleware.RequestID(), middleware.AuthMiddleware(maker), func(c *gin.Context) {
+		auditor.Record(c.Request.Context(), "alice", model.AuditActionAccountFreeze, "target")
+		c.Status(http.StatusNoContent)
+	})
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+accessToken)
+	header.Set(middleware.RequestIDHeader, "req-123")
+	doJSON(r, http.MethodPost, "/action", nil, header)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "write audit log" || record["request_id"] != "req-123" || record["username"] != "alice" {
+		t.Errorf("log = %v, want request_id and username fields", record)
+	}
+}
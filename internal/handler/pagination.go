@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+)
+
+// ==================== 分页响应头辅助方法 ====================
+
+// pageQueryKey 页码的 Query 参数名 (见 request.PaginationRequest)
+const pageQueryKey = "page_id"
+
+// setPaginationHeaders 根据分页信息写入 REST 风格的分页响应头
+//
+//   - X-Total-Count: 总记录数
+//   - Link (RFC 5988): rel="prev"、rel="next"、rel="last"，不存在的页不输出
+//
+// 链接沿用当前请求的路径和其他 Query 参数，只替换 page_id
+// 使用相对 URL，避免在反向代理后拼出错误的主机名
+func setPaginationHeaders(c *gin.Context, p response.PaginationResponse) {
+	c.Header("X-Total-Count", strconv.FormatInt(p.TotalCount, 10))
+
+	var links []string
+	addLink := func(page int, rel string) {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set(pageQueryKey, strconv.Itoa(page))
+		u.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel))
+	}
+
+	if p.Page > 1 && p.TotalPages > 0 {
+		addLink(min(p.Page-1, p.TotalPages), "prev")
+	}
+	if p.Page < p.TotalPages {
+		addLink(p.Page+1, "next")
+	}
+	if p.TotalPages > 0 {
+		addLink(p.TotalPages, "last")
+	}
+
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
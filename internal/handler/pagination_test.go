@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+)
+
+func TestPaginationHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		total    int64
+		wantLink string
+	}{
+		{"middle page", 2, 25,
+			`</accounts?currency=USD&page_id=1&page_size=10>; rel="prev", ` +
+				`</accounts?currency=USD&page_id=3&page_size=10>; rel="next", ` +
+				`</accounts?currency=USD&page_id=3&page_size=10>; rel="last"`},
+		{"first page", 1, 25,
+			`</accounts?currency=USD&page_id=2&page_size=10>; rel="next", ` +
+				`</accounts?currency=USD&page_id=3&page_size=10>; rel="last"`},
+		{"empty result", 1, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestEngine()
+			r.GET("/accounts", func(c *gin.Context) {
+				setPaginationHeaders(c, response.NewPaginationResponse(tt.page, 10, tt.total))
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/accounts?currency=USD&page_id=2&page_size=10", nil))
+			if got := w.Header().Get("X-Total-Count"); got != strconv.FormatInt(tt.total, 10) {
+				t.Errorf("X-Total-Count = %q, want %d", got, tt.total)
+			}
+			if got := w.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("Link = %s\nwant %s", got, tt.wantLink)
+			}
+		})
+	}
+}
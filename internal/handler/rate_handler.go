@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+// ==================== Handler 结构体 ====================
+
+// RateHandler 处理汇率相关的 HTTP 请求
+type RateHandler struct {
+	rateService *service.RateService
+}
+
+// NewRateHandler 创建 RateHandler 实例
+func NewRateHandler(rateService *service.RateService) *RateHandler {
+	return &RateHandler{
+		rateService: rateService,
+	}
+}
+
+// ==================== Handler 方法 ====================
+
+// GetRate 处理查询汇率请求
+//
+// 路由: GET /api/v1/rates
+// 参数: from, to (Query 参数)
+// 响应: 200 OK + RateResponse
+//
+// 供客户端在转账前预览换算结果
+//
+// @Summary 查询汇率
+// @Description 获取两种货币之间的当前汇率
+// @Tags rates
+// @Produce json
+// @Param from query string true "源货币"
+// @Param to query string true "目标货币"
+// @Success 200 {object} response.RateResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 503 {object} response.ErrorResponse
+// @Router /rates [get]
+func (h *RateHandler) GetRate(c *gin.Context) {
+	// Step 1: 绑定并验证查询参数
+	var req request.GetRateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 2: 调用 Service 查询汇率
+	rateResp, err := h.rateService.GetRate(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 3: 返回成功响应
+	c.JSON(http.StatusOK, rateResp)
+}
+
+// ==================== 错误处理辅助方法 ====================
+
+// handleError 统一处理 Service 层返回的错误
+func (h *RateHandler) handleError(c *gin.Context, err error) {
+	appErr := apperrors.AsAppError(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
+
+// handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
+func (h *RateHandler) handleValidationError(c *gin.Context, err error) {
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+// ==================== Handler 结构体 ====================
+
+// RecurringTransferHandler 处理周期转账 (定期委托) 相关的 HTTP 请求
+type RecurringTransferHandler struct {
+	recurringService *service.RecurringTransferService
+}
+
+// NewRecurringTransferHandler 创建 RecurringTransferHandler 实例
+func NewRecurringTransferHandler(recurringService *service.RecurringTransferService) *RecurringTransferHandler {
+	return &RecurringTransferHandler{
+		recurringService: recurringService,
+	}
+}
+
+// ==================== Handler 方法 ====================
+
+// CreateRecurringTransfer 处理创建周期转账规则请求
+//
+// 路由: POST /api/v1/accounts/:id/recurring-transfers (需要认证)
+// 请求体: CreateRecurringTransferRequest (JSON)
+// 响应: 201 Created + RecurringTransferResponse
+//
+// 业务规则:
+//   - 只能从自己的账户转出，两个账户的货币类型必须相同
+//   - start_at 必须晚于当前时间，end_at (可选) 不能早于 start_at
+//   - 按月执行时遇到没有该日期的月份 (如 30 天的月份中的 31 号) 取当月最后一天
+//   - 某一次余额不足时该次标记为 failed 并跳过，规则继续执行
+//
+// @Summary 创建周期转账
+// @Description 为源账户创建按天/周/月执行的定期转账
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "源账户公开ID (UUID)"
+// @Param request body request.CreateRecurringTransferRequest true "周期转账信息"
+// @Success 201 {object} response.RecurringTransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/recurring-transfers [post]
+func (h *RecurringTransferHandler) CreateRecurringTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和请求体
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.CreateRecurringTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 创建规则
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	recurringResp, err := h.recurringService.CreateRecurringTransfer(ctx, payload.Username, uriReq.PublicID(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusCreated, recurringResp)
+}
+
+// ListRecurringTransfers 处理获取周期转账规则列表请求
+//
+// 路由: GET /api/v1/accounts/:id/recurring-transfers?page_id=1&page_size=10 (需要认证)
+// 响应: 200 OK + ListResponse[RecurringTransferResponse]
+//
+// @Summary 获取周期转账列表
+// @Description 获取源账户的周期转账规则 (包括已结束的)
+// @Tags accounts
+// @Produce json
+// @Param id path string true "源账户公开ID (UUID)"
+// @Param page_id query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.ListResponse[response.RecurringTransferResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/recurring-transfers [get]
+func (h *RecurringTransferHandler) ListRecurringTransfers(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和 Query 参数
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var queryReq request.PaginationRequest
+	if err := c.ShouldBindQuery(&queryReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := queryReq.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 查询
+	listResp, err := h.recurringService.ListRecurringTransfers(c.Request.Context(), payload.Username, uriReq.PublicID(), &queryReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
+	c.JSON(http.StatusOK, listResp)
+}
+
+// CancelRecurringTransfer 处理取消周期转账规则请求
+//
+// 路由: DELETE /api/v1/accounts/:id/recurring-transfers/:recurring_id (需要认证)
+// 响应: 200 OK + RecurringTransferResponse
+//
+// 业务规则:
+//   - 等待中的下一次同时取消
+//   - 已结束的规则不能取消 (409)
+//
+// @Summary 取消周期转账
+// @Description 停止周期转账规则
+// @Tags accounts
+// @Produce json
+// @Param id path string true "源账户公开ID (UUID)"
+// @Param recurring_id path string true "规则公开ID (UUID)"
+// @Success 200 {object} response.RecurringTransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/recurring-transfers/{recurring_id} [delete]
+func (h *RecurringTransferHandler) CancelRecurringTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.RecurringTransferURIRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 取消
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	recurringResp, err := h.recurringService.CancelRecurringTransfer(ctx, payload.Username, req.AccountPublicID(), req.RecurringPublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, recurringResp)
+}
+
+// ==================== 错误处理辅助方法 ====================
+
+// handleError 统一处理 Service 层返回的错误
+func (h *RecurringTransferHandler) handleError(c *gin.Context, err error) {
+	appErr := apperrors.AsAppError(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
+
+// handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
+func (h *RecurringTransferHandler) handleValidationError(c *gin.Context, err error) {
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+// ==================== Handler 结构体 ====================
+
+// ScheduledTransferHandler 处理定时转账相关的 HTTP 请求
+type ScheduledTransferHandler struct {
+	scheduledService *service.ScheduledTransferService
+}
+
+// NewScheduledTransferHandler 创建 ScheduledTransferHandler 实例
+func NewScheduledTransferHandler(scheduledService *service.ScheduledTransferService) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{
+		scheduledService: scheduledService,
+	}
+}
+
+// ==================== Handler 方法 ====================
+
+// ScheduleTransfer 处理创建定时转账请求
+//
+// 路由: POST /api/v1/transfers/schedule (需要认证)
+// 请求体: ScheduleTransferRequest (JSON)
+// 响应: 201 Created + ScheduledTransferResponse
+//
+// 业务规则:
+//   - 只能从自己的账户转出，两个账户的货币类型必须相同
+//   - execute_at 必须晚于当前时间
+//   - 余额和转账限额在执行时校验，不足时定时转账标记为 failed
+//
+// @Summary 创建定时转账
+// @Description 预约在未来某个时间执行的转账
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param request body request.ScheduleTransferRequest true "定时转账信息"
+// @Success 201 {object} response.ScheduledTransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/schedule [post]
+func (h *ScheduledTransferHandler) ScheduleTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证请求体
+	var req request.ScheduleTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	// 十进制金额转换为以分为单位的整数
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 额外验证 - 不能转账给自己
+	if req.FromAccountPublicID() == req.ToAccountPublicID() {
+		appErr := apperrors.NewWithMessage(apperrors.CodeSameAccount, "cannot transfer to same account")
+		c.JSON(http.StatusUnprocessableEntity, response.NewErrorResponse(appErr))
+		return
+	}
+
+	// Step 4: 调用 Service 保存定时转账
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	scheduledResp, err := h.scheduledService.ScheduleTransfer(ctx, payload.Username, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 5: 返回成功响应
+	c.JSON(http.StatusCreated, scheduledResp)
+}
+
+// GetScheduledTransfer 处理查询定时转账请求
+//
+// 路由: GET /api/v1/transfers/schedule/:id (需要认证)
+// 响应: 200 OK + ScheduledTransferResponse
+//
+// @Summary 查询定时转账
+// @Description 查询定时转账及其执行状态，只能查看自己创建的
+// @Tags transfers
+// @Produce json
+// @Param id path string true "定时转账公开ID (UUID)"
+// @Success 200 {object} response.ScheduledTransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/schedule/{id} [get]
+func (h *ScheduledTransferHandler) GetScheduledTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetScheduledTransferRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 查询
+	scheduledResp, err := h.scheduledService.GetScheduledTransfer(c.Request.Context(), payload.Username, req.PublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, scheduledResp)
+}
+
+// CancelScheduledTransfer 处理取消定时转账请求
+//
+// 路由: DELETE /api/v1/transfers/schedule/:id (需要认证)
+// 响应: 200 OK + ScheduledTransferResponse
+//
+// 业务规则:
+//   - 只能取消自己创建的定时转账
+//   - 已开始执行或已结束的定时转账不能取消 (409)
+//
+// @Summary 取消定时转账
+// @Description 在执行前取消定时转账
+// @Tags transfers
+// @Produce json
+// @Param id path string true "定时转账公开ID (UUID)"
+// @Success 200 {object} response.ScheduledTransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/schedule/{id} [delete]
+func (h *ScheduledTransferHandler) CancelScheduledTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetScheduledTransferRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 取消
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	scheduledResp, err := h.scheduledService.CancelScheduledTransfer(ctx, payload.Username, req.PublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, scheduledResp)
+}
+
+// ==================== 错误处理辅助方法 ====================
+
+// handleError 统一处理 Service 层返回的错误
+func (h *ScheduledTransferHandler) handleError(c *gin.Context, err error) {
+	appErr := apperrors.AsAppError(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
+
+// handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
+func (h *ScheduledTransferHandler) handleValidationError(c *gin.Context, err error) {
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+// 对账单 PDF 表格的列宽 (mm)，合计为 A4 纵向去掉左右边距后的 190mm
+var statementColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Date (UTC)", 50, "L"},
+	{"Entry ID", 30, "L"},
+	{"Amount", 55, "R"},
+	{"Balance", 55, "R"},
+}
+
+// writeStatementPDF 将月度对账单渲染为 PDF 写入 w
+//
+// 布局: 标题、账户信息、收支汇总、账目表格 (含每笔后的余额)
+// 当月没有账目时表格只有一行提示
+// 只使用 PDF 内置字体，文本限于 ASCII
+func writeStatementPDF(w io.Writer, accountID string, statement *response.StatementResponse) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 15, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	amount := func(v money.Amount) string {
+		return money.FormatAmount(v.Int64(), statement.Currency)
+	}
+
+	// 1. 标题
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Account Statement", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("%04d-%02d", statement.Year, statement.Month), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	// 2. 账户信息和汇总
+	info := [][2]string{
+		{"Account", accountID},
+		{"Currency", statement.Currency},
+		{"Period", fmt.Sprintf("%s - %s (exclusive)",
+			statement.PeriodStart.UTC().Format(time.DateOnly), statement.PeriodEnd.UTC().Format(time.DateOnly))},
+		{"Opening balance", amount(statement.OpeningBalance)},
+		{"Total credits", amount(statement.TotalCredits)},
+		{"Total debits", amount(statement.TotalDebits)},
+		{"Closing balance", amount(statement.ClosingBalance)},
+	}
+	for _, row := range info {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, row[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	// 3. 账目表格，分页时重复表头
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetFillColor(230, 230, 230)
+		for _, col := range statementColumns {
+			pdf.CellFormat(col.width, 7, col.title, "1", 0, col.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 10)
+	}
+	header()
+
+	if len(statement.Entries) == 0 {
+		pdf.CellFormat(0, 7, "No transactions in this period.", "1", 1, "C", false, 0, "")
+	}
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	balance := statement.OpeningBalance
+	for _, entry := range statement.Entries {
+		if pdf.GetY()+7 > pageHeight-bottom {
+			pdf.AddPage()
+			header()
+		}
+		balance += entry.Amount
+		cells := []string{
+			entry.CreatedAt.UTC().Format(time.DateTime),
+			strconv.FormatUint(uint64(entry.ID), 10),
+			amount(entry.Amount),
+			amount(balance),
+		}
+		for i, col := range statementColumns {
+			pdf.CellFormat(col.width, 7, cells[i], "1", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	return pdf.Output(w)
+}
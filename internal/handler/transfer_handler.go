@@ -1,14 +1,18 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/proyuen/simple-bank-v2/internal/dto/request"
 	"github.com/proyuen/simple-bank-v2/internal/dto/response"
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
 	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
 	"github.com/proyuen/simple-bank-v2/internal/service"
 )
 
@@ -17,6 +21,10 @@ import (
 // TransferHandler 处理转账相关的 HTTP 请求
 type TransferHandler struct {
 	transferService *service.TransferService
+
+	// 不低于 stepUpAmount 的转账要求在 maxTokenAge 之内登录，0 表示不要求
+	stepUpAmount int64
+	maxTokenAge  time.Duration
 }
 
 // NewTransferHandler 创建 TransferHandler 实例
@@ -26,6 +34,15 @@ func NewTransferHandler(transferService *service.TransferService) *TransferHandl
 	}
 }
 
+// WithStepUp 设置大额转账的 step-up 要求
+// 金额不低于 amount (单位: 分) 的转账要求用户在 maxAge 之内登录，否则返回 401 (CodeReauthRequired)
+// amount 或 maxAge 为 0 时不要求
+func (h *TransferHandler) WithStepUp(amount int64, maxAge time.Duration) *TransferHandler {
+	h.stepUpAmount = amount
+	h.maxTokenAge = maxAge
+	return h
+}
+
 // ==================== Handler 方法 ====================
 
 // CreateTransfer 处理转账请求
@@ -36,9 +53,11 @@ func NewTransferHandler(transferService *service.TransferService) *TransferHandl
 //
 // 业务规则:
 //   - 只能从自己的账户转出
+//   - 收款账户通过 to_account_id 或 to_account_number (账号) 指定，二选一
 //   - 两个账户的货币类型必须相同
 //   - 转出账户余额必须充足
 //   - 转账在数据库事务中完成
+//   - 开启 step-up 时，大额转账要求最近登录 (见 WithStepUp)
 //
 // 事务中的操作:
 //  1. 创建 Transfer 记录
@@ -53,7 +72,7 @@ func NewTransferHandler(transferService *service.TransferService) *TransferHandl
 // @Param request body request.CreateTransferRequest true "转账信息"
 // @Success 201 {object} response.TransferResponse
 // @Failure 400 {object} response.ErrorResponse
-// @Failure 401 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse "未认证，或大额转账需要重新登录 (40105)"
 // @Failure 403 {object} response.ErrorResponse
 // @Failure 422 {object} response.ErrorResponse
 // @Security BearerAuth
@@ -69,48 +88,125 @@ func (h *TransferHandler) CreateTransfer(c *gin.Context) {
 		h.handleValidationError(c, err)
 		return
 	}
+	// 十进制金额转换为以分为单位的整数
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
 
 	// Step 3: 额外验证 - 不能转账给自己
-	if req.FromAccountID == req.ToAccountID {
+	if req.FromAccountPublicID() == req.ToAccountPublicID() {
 		appErr := apperrors.NewWithMessage(apperrors.CodeSameAccount, "cannot transfer to same account")
 		c.JSON(http.StatusUnprocessableEntity, response.NewErrorResponse(appErr))
 		return
 	}
 
-	// Step 4: 调用 Service 执行转账
+	// Step 4: 大额转账要求最近登录 (API Key 不能发起大额转账)
+	if h.stepUpAmount > 0 && req.Amount.Int64() >= h.stepUpAmount && !middleware.IsFreshAuth(c, payload, h.maxTokenAge) {
+		h.handleError(c, apperrors.ErrReauthRequired())
+		return
+	}
+
+	// Step 5: 调用 Service 执行转账
 	// Service 会处理:
 	//   - 验证账户所有权
 	//   - 验证货币类型
 	//   - 验证余额
 	//   - 在事务中执行转账
-	transferResp, err := h.transferService.CreateTransfer(c.Request.Context(), payload.Username, &req)
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	transferResp, err := h.transferService.CreateTransfer(ctx, payload.Username, &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	// Step 5: 返回成功响应
+	// Step 6: 返回成功响应
 	c.JSON(http.StatusCreated, transferResp)
 }
 
+// PreviewTransfer 处理转账预览请求 (dry-run)
+//
+// 路由: POST /api/v1/transfers/preview (需要认证)
+// 请求体: CreateTransferRequest (JSON)
+// 响应: 200 OK + TransferResultResponse
+//
+// 业务规则:
+//   - 执行与创建转账相同的校验 (所有权、货币、余额、限额)，校验失败时返回相同的错误
+//   - 返回转账后的双方账户余额和账目，不写入任何数据
+//   - 预览结果中的转账和账目尚未创建，ID 和参考号为空
+//   - 收款账户属于其他用户时只返回基本信息，不包含余额
+//   - 不要求 step-up，实际转账时才检查
+//
+// @Summary 预览转账
+// @Description 校验转账并返回转账后的余额，不实际转账
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param request body request.CreateTransferRequest true "转账信息"
+// @Success 200 {object} response.TransferResultResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/preview [post]
+func (h *TransferHandler) PreviewTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证请求体
+	var req request.CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 额外验证 - 不能转账给自己
+	if req.FromAccountPublicID() == req.ToAccountPublicID() {
+		appErr := apperrors.NewWithMessage(apperrors.CodeSameAccount, "cannot transfer to same account")
+		c.JSON(http.StatusUnprocessableEntity, response.NewErrorResponse(appErr))
+		return
+	}
+
+	// Step 4: 调用 Service 预览转账
+	previewResp, err := h.transferService.PreviewTransfer(c.Request.Context(), payload.Username, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 5: 返回成功响应
+	c.JSON(http.StatusOK, previewResp)
+}
+
 // ListTransfers 处理获取转账记录请求
 //
 // 路由: GET /api/v1/transfers (需要认证)
-// 参数: account_id, page_id, page_size (Query 参数)
+// 参数: account_id 或 from_account_id/to_account_id, page_id, page_size, sort (Query 参数)
 // 响应: 200 OK + ListResponse[TransferResponse]
 //
 // 业务规则:
-//   - 只能查看自己账户的转账记录
-//   - 包括转入和转出的记录
+//   - account_id: 只能查看自己账户的转账记录，包括转入和转出的记录
+//   - from_account_id/to_account_id: 按方向过滤，两个都传时只返回这两个账户之间的转账；
+//     需要拥有其中至少一个账户，管理员可以查看任意账户
 //
 // @Summary 获取转账记录
-// @Description 获取指定账户的转账记录（分页）
+// @Description 获取指定账户的转账记录，或两个账户之间的转账记录（分页）
 // @Tags transfers
 // @Produce json
-// @Param account_id query int true "账户ID"
-// @Param page_id query int true "页码" minimum(1)
-// @Param page_size query int true "每页条数" minimum(5) maximum(100)
+// @Param account_id query string false "账户公开ID (UUID)，与 from_account_id/to_account_id 二选一"
+// @Param from_account_id query string false "转出账户公开ID (UUID)"
+// @Param to_account_id query string false "转入账户公开ID (UUID)"
+// @Param page_id query int false "页码" minimum(1) default(1)
+// @Param page_size query int false "每页条数 (默认和上限见 PAGE_SIZE_DEFAULT / PAGE_SIZE_MAX)" minimum(5)
+// @Param sort query string false "排序字段 (id, created_at, amount)，前缀 - 表示降序"
 // @Success 200 {object} response.ListResponse[response.TransferResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
@@ -127,28 +223,183 @@ func (h *TransferHandler) ListTransfers(c *gin.Context) {
 		return
 	}
 
-	// Step 3: 构造分页参数
-	paginationReq := &request.PaginationRequest{
-		PageID:   req.PageID,
-		PageSize: req.PageSize,
+	// Step 3: 校验查询方式和分页参数，填充默认值
+	if err := req.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
 	}
 
 	// Step 4: 调用 Service 获取转账记录
 	// Service 会验证账户所有权
-	listResp, err := h.transferService.ListTransfers(c.Request.Context(), payload.Username, req.AccountID, paginationReq)
+	var listResp *response.ListResponse[response.TransferResponse]
+	var err error
+	if req.ByPair() {
+		listResp, err = h.transferService.ListTransfersBetween(c.Request.Context(), payload.Username, payload.HasRole(model.RoleAdmin),
+			req.FromAccountPublicID(), req.ToAccountPublicID(), &req.PaginationRequest)
+	} else {
+		listResp, err = h.transferService.ListTransfers(c.Request.Context(), payload.Username, req.AccountPublicID(), &req.PaginationRequest)
+	}
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
 	// Step 5: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
 	c.JSON(http.StatusOK, listResp)
 }
 
+// GetTransferByReference 处理根据参考号获取转账请求
+//
+// 路由: GET /api/v1/transfers/ref/:reference (需要认证)
+// 参数: reference (URL 路径参数)
+// 响应: 200 OK + TransferResponse
+//
+// 业务规则:
+//   - 只有转账的一方可以查看
+//   - 参考号不存在返回 404，非转账一方返回 403
+//
+// @Summary 根据参考号获取转账
+// @Description 根据转账参考号获取转账详情
+// @Tags transfers
+// @Produce json
+// @Param reference path string true "转账参考号"
+// @Success 200 {object} response.TransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/ref/{reference} [get]
+func (h *TransferHandler) GetTransferByReference(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetTransferByReferenceRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 获取转账
+	// Service 会验证当前用户是转账的一方
+	transferResp, err := h.transferService.GetTransferByReference(c.Request.Context(), payload.Username, req.Reference)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, transferResp)
+}
+
+// GetTransfer 处理根据公开ID获取转账请求
+//
+// 路由: GET /api/v1/transfers/:id (需要认证)
+// 参数: id (URL 路径参数，转账公开ID), expand (Query 参数，可选)
+// 响应: 200 OK + TransferResponse
+//
+// 业务规则:
+//   - 只有转账的一方可以查看
+//   - 转账不存在返回 404，非转账一方返回 403
+//   - expand=accounts 时包含 from_account 和 to_account，对方账户的 owner 经过掩码，不含余额
+//
+// @Summary 获取转账
+// @Description 根据转账公开ID获取转账详情，可选包含两个账户的信息
+// @Tags transfers
+// @Produce json
+// @Param id path string true "转账公开ID (UUID)"
+// @Param expand query string false "展开关联数据" Enums(accounts)
+// @Success 200 {object} response.TransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/{id} [get]
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetTransferRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 绑定并验证 Query 参数
+	var queryReq request.GetTransferQuery
+	if err := c.ShouldBindQuery(&queryReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 4: 调用 Service 获取转账
+	// Service 会验证当前用户是转账的一方
+	transferResp, err := h.transferService.GetTransfer(c.Request.Context(), payload.Username, req.PublicID(), queryReq.ExpandAccounts())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 5: 返回成功响应
+	c.JSON(http.StatusOK, transferResp)
+}
+
+// ReverseTransfer 处理撤销转账请求
+//
+// 路由: POST /api/v1/transfers/:id/reverse (需要认证)
+// 参数: id (URL 路径参数，原转账公开ID)
+// 响应: 201 Created + TransferResponse (撤销转账，reversal_of 为原转账ID)
+//
+// 业务规则:
+//   - 只有原转账的转出方可以撤销 (403)
+//   - 超过撤销窗口返回 422，已撤销过或本身是撤销转账返回 409
+//   - 收款账户余额不足以退回时返回 422 (余额不足)
+//
+// @Summary 撤销转账
+// @Description 创建一笔方向相反的转账，撤销误操作的转账
+// @Tags transfers
+// @Produce json
+// @Param id path string true "原转账公开ID (UUID)"
+// @Success 201 {object} response.TransferResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/{id}/reverse [post]
+func (h *TransferHandler) ReverseTransfer(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数
+	var req request.GetTransferRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 撤销转账
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	transferResp, err := h.transferService.ReverseTransfer(ctx, payload.Username, req.PublicID())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusCreated, transferResp)
+}
+
 // ListEntries 处理获取账目记录请求
 //
 // 路由: GET /api/v1/accounts/:id/entries (需要认证)
-// 参数: id (URL 路径参数), page_id, page_size (Query 参数)
+// 参数: id (URL 路径参数), page_id, page_size, sort, from, to (Query 参数)
 // 响应: 200 OK + ListResponse[EntryResponse]
 //
 // 业务规则:
@@ -159,10 +410,15 @@ func (h *TransferHandler) ListTransfers(c *gin.Context) {
 // @Description 获取指定账户的账目记录（分页）
 // @Tags entries
 // @Produce json
-// @Param id path int true "账户ID"
-// @Param page_id query int true "页码" minimum(1)
-// @Param page_size query int true "每页条数" minimum(5) maximum(100)
+// @Param id path string true "账户公开ID (UUID)"
+// @Param page_id query int false "页码" minimum(1) default(1)
+// @Param page_size query int false "每页条数 (默认和上限见 PAGE_SIZE_DEFAULT / PAGE_SIZE_MAX)" minimum(5)
+// @Param sort query string false "排序字段 (id, created_at, amount)，前缀 - 表示降序"
+// @Param from query string false "起始时间 (RFC 3339，包含)"
+// @Param to query string false "结束时间 (RFC 3339，不包含)"
 // @Success 200 {object} response.ListResponse[response.EntryResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
@@ -184,18 +440,401 @@ func (h *TransferHandler) ListEntries(c *gin.Context) {
 		h.handleValidationError(c, err)
 		return
 	}
+	if err := queryReq.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var rangeReq request.DateRangeRequest
+	if err := c.ShouldBindQuery(&rangeReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := rangeReq.Validate(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
 
 	// Step 3: 调用 Service 获取账目记录
-	listResp, err := h.transferService.ListEntries(c.Request.Context(), payload.Username, uriReq.ID, &queryReq)
+	period := model.TimeRange{From: rangeReq.From, To: rangeReq.To}
+	listResp, err := h.transferService.ListEntries(c.Request.Context(), payload.Username, uriReq.PublicID(), period, &queryReq)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
 	// Step 4: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
 	c.JSON(http.StatusOK, listResp)
 }
 
+// GetAccountHistory 处理获取账户交易历史请求
+//
+// 路由: GET /api/v1/accounts/:id/history (需要认证)
+// 参数: id (URL 路径参数), page_id, page_size, from, to (Query 参数)
+// 响应: 200 OK + ListResponse[HistoryItemResponse]
+//
+// 业务规则:
+//   - 只能查看自己账户的交易历史
+//   - 合并账目和转账，每条记录带类型 (deposit/withdrawal/transfer_in/transfer_out)、
+//     转账另一方 (所有者掩码) 和变动后的余额
+//   - 固定按时间倒序 (最新的在前)，不支持 sort 参数
+//
+// @Summary 获取账户交易历史
+// @Description 按时间倒序获取账户的资金变动，合并转账信息（分页）
+// @Tags entries
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param page_id query int false "页码" minimum(1) default(1)
+// @Param page_size query int false "每页条数 (默认和上限见 PAGE_SIZE_DEFAULT / PAGE_SIZE_MAX)" minimum(5)
+// @Param from query string false "起始时间 (RFC 3339，包含)"
+// @Param to query string false "结束时间 (RFC 3339，不包含)"
+// @Success 200 {object} response.ListResponse[response.HistoryItemResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/history [get]
+func (h *TransferHandler) GetAccountHistory(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和 Query 参数
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var queryReq request.PaginationRequest
+	if err := c.ShouldBindQuery(&queryReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := queryReq.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var rangeReq request.DateRangeRequest
+	if err := c.ShouldBindQuery(&rangeReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := rangeReq.Validate(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 获取交易历史
+	period := model.TimeRange{From: rangeReq.From, To: rangeReq.To}
+	listResp, err := h.transferService.GetAccountHistory(c.Request.Context(), payload.Username, uriReq.PublicID(), period, &queryReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
+	c.JSON(http.StatusOK, listResp)
+}
+
+// ListUserEntries 处理获取当前用户所有账户账目记录的请求
+//
+// 路由: GET /api/v1/entries (需要认证)
+// 参数: page_id, page_size, sort, from, to (Query 参数)
+// 响应: 200 OK + ListResponse[EntryResponse]，每条附带 account_public_id 和 currency
+//
+// 业务规则:
+//   - 合并当前用户所有未关闭账户的账目，默认最新的在前
+//
+// @Summary 获取所有账户的账目记录
+// @Description 获取当前用户所有账户的账目记录（分页），按时间排序
+// @Tags entries
+// @Produce json
+// @Param page_id query int false "页码" minimum(1) default(1)
+// @Param page_size query int false "每页条数 (默认和上限见 PAGE_SIZE_DEFAULT / PAGE_SIZE_MAX)" minimum(5)
+// @Param sort query string false "排序字段 (id, created_at, amount)，前缀 - 表示降序"
+// @Param from query string false "起始时间 (RFC 3339，包含)"
+// @Param to query string false "结束时间 (RFC 3339，不包含)"
+// @Success 200 {object} response.ListResponse[response.EntryResponse]
+// @Header 200 {integer} X-Total-Count "总记录数"
+// @Header 200 {string} Link "分页链接 (rel=prev/next/last)"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /entries [get]
+func (h *TransferHandler) ListUserEntries(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 Query 参数
+	var queryReq request.PaginationRequest
+	if err := c.ShouldBindQuery(&queryReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := queryReq.Normalize(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var rangeReq request.DateRangeRequest
+	if err := c.ShouldBindQuery(&rangeReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := rangeReq.Validate(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 获取账目记录
+	period := model.TimeRange{From: rangeReq.From, To: rangeReq.To}
+	listResp, err := h.transferService.ListOwnerEntries(c.Request.Context(), payload.Username, period, &queryReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	setPaginationHeaders(c, listResp.Pagination)
+	c.JSON(http.StatusOK, listResp)
+}
+
+// ExportEntries 处理导出账目请求
+//
+// 路由: GET /api/v1/accounts/:id/entries/export (需要认证)
+// 参数: id (URL 路径参数), format, from, to (Query 参数)
+// 响应: 200 OK + CSV 或 JSON 附件
+//
+// 业务规则:
+//   - 只能导出自己账户的账目
+//   - 导出时间范围内的全部账目 (不分页)，按 ID 升序逐行写出
+//
+// @Summary 导出账目
+// @Description 以 CSV 或 JSON 下载账户的全部账目
+// @Tags entries
+// @Produce text/csv,json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param format query string false "导出格式" Enums(csv, json) default(csv)
+// @Param from query string false "起始时间 (RFC 3339，包含)"
+// @Param to query string false "结束时间 (RFC 3339，不包含)"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/entries/export [get]
+func (h *TransferHandler) ExportEntries(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和 Query 参数
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.ExportEntriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 逐条导出
+	// 响应头在写出第一行时才发送，所有权验证失败时仍可返回正常的错误响应
+	exporter := newEntryExporter(c, req.Format, uriReq.ID)
+	period := model.TimeRange{From: req.From, To: req.To}
+	err := h.transferService.ExportEntries(c.Request.Context(), payload.Username, uriReq.PublicID(), period, exporter.Write)
+	if err != nil {
+		if !exporter.Started() {
+			h.handleError(c, err)
+			return
+		}
+		// 已开始写出时无法再修改状态码，只能中断响应
+		logging.FromContext(c.Request.Context()).Error("export entries", "account_id", uriReq.ID, "error", err)
+		c.Abort()
+		return
+	}
+
+	// Step 4: 结束输出 (没有账目时也会输出表头或空数组)
+	if err := exporter.Close(); err != nil {
+		logging.FromContext(c.Request.Context()).Error("export entries", "account_id", uriReq.ID, "error", err)
+	}
+}
+
+// GetStatement 处理获取月度对账单请求
+//
+// 路由: GET /api/v1/accounts/:id/statement (需要认证)
+// 参数: id (URL 路径参数), year, month (Query 参数)
+// 响应: 200 OK + StatementResponse
+//
+// 业务规则:
+//   - 只能查看自己账户的对账单
+//   - 包含期初/期末余额、入账/出账总额和当月全部账目
+//
+// @Summary 获取月度对账单
+// @Description 获取账户指定月份的对账单
+// @Tags entries
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param year query int true "年份" minimum(1970)
+// @Param month query int true "月份" minimum(1) maximum(12)
+// @Success 200 {object} response.StatementResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/statement [get]
+func (h *TransferHandler) GetStatement(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和 Query 参数
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.GetStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 生成对账单
+	statement, err := h.transferService.GetStatement(c.Request.Context(), payload.Username, uriReq.PublicID(), req.Year, req.Month)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, statement)
+}
+
+// GetAccountStats 处理获取账户转账统计请求
+//
+// 路由: GET /api/v1/accounts/:id/stats (需要认证)
+// 参数: id (URL 路径参数), period (Query 参数，如 30d)
+// 响应: 200 OK + AccountStatsResponse
+//
+// 业务规则:
+//   - 只能查看自己账户的统计
+//   - 统计最近 period 天内的转出/转入总额和笔数、单笔最大金额和平均金额
+//
+// @Summary 获取账户转账统计
+// @Description 统计账户最近一段时间的转账汇总
+// @Tags accounts
+// @Produce json
+// @Param id path string true "账户公开ID (UUID)"
+// @Param period query string false "统计窗口 (1d-366d)" default(30d)
+// @Success 200 {object} response.AccountStatsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/stats [get]
+func (h *TransferHandler) GetAccountStats(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和 Query 参数
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.GetAccountStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+	days, err := req.Days()
+	if err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 统计
+	stats, err := h.transferService.GetAccountStats(c.Request.Context(), payload.Username, uriReq.PublicID(), days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 返回成功响应
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetStatementPDF 处理下载 PDF 月度对账单请求
+//
+// 路由: GET /api/v1/accounts/:id/statement.pdf (需要认证)
+// 参数: id (URL 路径参数), year, month (Query 参数)
+// 响应: 200 OK + application/pdf
+//
+// 业务规则:
+//   - 与 GetStatement 使用相同的统计逻辑，只能下载自己账户的对账单
+//   - 当月没有账目时仍然生成对账单，表格中显示提示
+//
+// @Summary 下载 PDF 月度对账单
+// @Description 以 PDF 格式下载账户指定月份的对账单
+// @Tags entries
+// @Produce application/pdf
+// @Param id path string true "账户公开ID (UUID)"
+// @Param year query int true "年份" minimum(1970)
+// @Param month query int true "月份" minimum(1) maximum(12)
+// @Success 200 {file} binary
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Router /accounts/{id}/statement.pdf [get]
+func (h *TransferHandler) GetStatementPDF(c *gin.Context) {
+	// Step 1: 获取当前登录用户
+	payload := middleware.MustGetAuthPayload(c)
+
+	// Step 2: 绑定并验证 URL 参数和 Query 参数
+	var uriReq request.GetAccountRequest
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	var req request.GetStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.handleValidationError(c, err)
+		return
+	}
+
+	// Step 3: 调用 Service 生成对账单
+	statement, err := h.transferService.GetStatement(c.Request.Context(), payload.Username, uriReq.PublicID(), req.Year, req.Month)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Step 4: 渲染为 PDF 并写出
+	filename := fmt.Sprintf("account-%s-statement-%04d-%02d.pdf", uriReq.ID, req.Year, req.Month)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", "application/pdf")
+	c.Status(http.StatusOK)
+	if err := writeStatementPDF(c.Writer, uriReq.ID, statement); err != nil {
+		logging.FromContext(c.Request.Context()).Error("render statement pdf", "account_id", uriReq.ID, "error", err)
+		c.Abort()
+	}
+}
+
 // ==================== 错误处理辅助方法 ====================
 
 // handleError 统一处理 Service 层返回的错误
@@ -205,7 +844,8 @@ func (h *TransferHandler) handleError(c *gin.Context, err error) {
 }
 
 // handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
 func (h *TransferHandler) handleValidationError(c *gin.Context, err error) {
-	appErr := apperrors.ErrInvalidParams(err.Error())
-	c.JSON(http.StatusBadRequest, response.NewErrorResponse(appErr))
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
 }
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+)
+
+func TestTransferStepUpRejectsAPIKey(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	maker := newTestTokenMaker(t)
+	auditor := service.NewAuditLogger(repos.AuditLogs)
+	apiKeys := service.NewAPIKeyService(repos.APIKeys, auditor)
+	transfers := service.NewTransferService(repos.TxManager, repos.Accounts, repos.Transfers, repos.Entries,
+		auditor, service.TransferLimits{})
+
+	from := &model.Account{Owner: "alice", Currency: "USD", Balance: 100000}
+	to := &model.Account{Owner: "bob", Currency: "USD"}
+	for _, account := range []*model.Account{from, to} {
+		if err := repos.Accounts.Create(ctx, account); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	key, err := apiKeys.CreateAPIKey(ctx, "alice", &request.CreateAPIKeyRequest{Name: "billing-sync"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	accessToken, _, err := maker.CreateToken("alice", model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestEngine()
+	h := NewTransferHandler(transfers).WithStepUp(10000, 5*time.Minute)
+	r.POST("/transfers", middleware.APIKeyAuth(apiKeys), middleware.AuthMiddleware(maker), h.CreateTransfer)
+
+	body := func(amount int64) map[string]any {
+		return map[string]any{
+			"from_account_id": from.PublicID.String(),
+			"to_account_id":   to.PublicID.String(),
+			"amount":          amount,
+		}
+	}
+	apiKeyHeader := http.Header{"Authorization": {"ApiKey " + key.Key}}
+	bearerHeader := http.Header{"Authorization": {"Bearer " + accessToken}}
+
+	// 小额转账不要求 step-up，API Key 可以发起
+	if w := doJSON(r, http.MethodPost, "/transfers", body(1000), apiKeyHeader); w.Code != http.StatusCreated {
+		t.Fatalf("small api key transfer status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	// 大额转账: API Key 被拒绝，刚登录的 Token 可以发起
+	w := doJSON(r, http.MethodPost, "/transfers", body(10000), apiKeyHeader)
+	var errResp response.ErrorResponse
+	decodeJSON(t, w, &errResp)
+	if w.Code != http.StatusUnauthorized || errResp.Code != apperrors.CodeReauthRequired {
+		t.Errorf("large api key transfer = %d %+v, want 401 code %d", w.Code, errResp, apperrors.CodeReauthRequired)
+	}
+	if w := doJSON(r, http.MethodPost, "/transfers", body(10000), bearerHeader); w.Code != http.StatusCreated {
+		t.Errorf("large token transfer status = %d, body %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateTransferStrictJSON(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	maker := newTestTokenMaker(t)
+	transfers := service.NewTransferService(repos.TxManager, repos.Accounts, repos.Transfers, repos.Entries,
+		service.NewAuditLogger(repos.AuditLogs), service.TransferLimits{})
+
+	from := &model.Account{Owner: "alice", Currency: "USD", Balance: 100000}
+	to := &model.Account{Owner: "bob", Currency: "USD"}
+	for _, account := range []*model.Account{from, to} {
+		if err := repos.Accounts.Create(ctx, account); err != nil {
+			t.Fatal(err)
+		}
+	}
+	accessToken, _, err := maker.CreateToken("alice", model.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestEngine()
+	r.POST("/transfers", middleware.AuthMiddleware(maker), NewTransferHandler(transfers).CreateTransfer)
+	header := http.Header{"Authorization": {"Bearer " + accessToken}}
+	body := map[string]any{
+		"from_account_id": from.PublicID.String(),
+		"to_account_id":   to.PublicID.String(),
+		"amount":          100,
+		"memo":            "rent",
+	}
+
+	// 默认忽略未定义的字段
+	if w := doJSON(r, http.MethodPost, "/transfers", body, header); w.Code != http.StatusCreated {
+		t.Fatalf("lenient status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	request.SetStrictJSON(true)
+	t.Cleanup(func() { request.SetStrictJSON(false) })
+
+	w := doJSON(r, http.MethodPost, "/transfers", body, header)
+	var errResp response.ErrorResponse
+	decodeJSON(t, w, &errResp)
+	if w.Code != http.StatusBadRequest || errResp.Code != apperrors.CodeInvalidParams || errResp.Message != `unknown field "memo"` {
+		t.Errorf("strict = %d %+v, want 400 naming the memo field", w.Code, errResp)
+	}
+}
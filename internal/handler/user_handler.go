@@ -8,7 +8,10 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -25,8 +28,18 @@ type UserHandler struct {
 	// userService 是用户服务层的引用
 	// 使用接口而非具体类型，便于测试时注入 mock
 	userService *service.UserService
+
+	// refreshTokenCookie 为 true 时通过 HttpOnly Cookie 下发 Refresh Token
+	refreshTokenCookie bool
 }
 
+// refreshTokenCookieName / refreshTokenCookiePath Refresh Token Cookie 的名称和路径
+// 路径限制为 /api/v1/tokens，浏览器只在刷新 Token 时携带
+const (
+	refreshTokenCookieName = "refresh_token"
+	refreshTokenCookiePath = "/api/v1/tokens"
+)
+
 // NewUserHandler 创建 UserHandler 实例
 //
 // 参数:
@@ -40,6 +53,15 @@ func NewUserHandler(userService *service.UserService) *UserHandler {
 	}
 }
 
+// WithRefreshTokenCookie 设置是否通过 HttpOnly Cookie 下发 Refresh Token
+//
+// 启用后登录响应体不再包含 refresh_token，浏览器中的 JS 无法读取；
+// 刷新 Token 时请求体未提供 refresh_token 则从 Cookie 读取
+func (h *UserHandler) WithRefreshTokenCookie(enabled bool) *UserHandler {
+	h.refreshTokenCookie = enabled
+	return h
+}
+
 // ==================== Handler 方法 ====================
 
 // CreateUser 处理用户注册请求
@@ -48,16 +70,20 @@ func NewUserHandler(userService *service.UserService) *UserHandler {
 // 请求体: CreateUserRequest (JSON)
 // 响应: 201 Created + UserResponse
 //
+// 携带 Idempotency-Key 请求头时，连接中断后的重试返回首次成功的 201 响应
+// (带 Idempotent-Replayed: true)，见 middleware.Idempotency
+//
 // 错误响应:
-//   - 400 Bad Request: 参数验证失败
-//   - 409 Conflict: 用户名或邮箱已存在
+//   - 400 Bad Request: 参数验证失败，或 Idempotency-Key 已用于不同的请求
+//   - 409 Conflict: 用户名或邮箱已存在，或相同 Idempotency-Key 的请求仍在处理
 //   - 500 Internal Server Error: 服务器错误
 //
 // @Summary 用户注册
-// @Description 创建一个新用户
+// @Description 创建一个新用户，可携带 Idempotency-Key 安全地重试
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "幂等键 (建议使用 UUID)"
 // @Param request body request.CreateUserRequest true "用户注册信息"
 // @Success 201 {object} response.UserResponse
 // @Failure 400 {object} response.ErrorResponse
@@ -94,12 +120,14 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // 请求体: LoginUserRequest (JSON)
 // 响应: 200 OK + LoginResponse
 //
+// read_only 为 true 时签发只读 Token: 只能调用查询接口，写操作返回 403
+//
 // 错误响应:
 //   - 400 Bad Request: 参数验证失败
 //   - 422 Unprocessable Entity: 密码错误
 //
 // @Summary 用户登录
-// @Description 用户登录获取 Token
+// @Description 用户登录获取 Token，read_only 为 true 时签发只能查询的只读 Token
 // @Tags users
 // @Accept json
 // @Produce json
@@ -107,6 +135,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Success 200 {object} response.LoginResponse
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 422 {object} response.ErrorResponse
+// @Failure 429 {object} response.ErrorResponse "登录请求过于频繁，Retry-After 头为可重试的秒数"
 // @Router /users/login [post]
 func (h *UserHandler) LoginUser(c *gin.Context) {
 	// Step 1: 绑定并验证请求体
@@ -128,14 +157,20 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	// Step 4: 返回成功响应
+	// Step 4: 按配置把 Refresh Token 放入 Cookie
+	if h.refreshTokenCookie {
+		h.setRefreshTokenCookie(c, loginResp.RefreshToken, loginResp.RefreshTokenExpiresAt)
+		loginResp.RefreshToken = ""
+	}
+
+	// Step 5: 返回成功响应
 	c.JSON(http.StatusOK, loginResp)
 }
 
 // RefreshToken 处理刷新 Token 请求
 //
 // 路由: POST /api/v1/tokens/renew
-// 请求体: RefreshTokenRequest (JSON)
+// 请求体: RefreshTokenRequest (JSON)，启用 Refresh Token Cookie 时可省略
 // 响应: 200 OK + RefreshTokenResponse
 //
 // 工作流程:
@@ -155,14 +190,24 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 // @Router /tokens/renew [post]
 func (h *UserHandler) RefreshToken(c *gin.Context) {
 	// Step 1: 绑定并验证请求体
+	// 启用 Cookie 时允许空请求体，refresh_token 从 Cookie 读取
 	var req request.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil && !(h.refreshTokenCookie && errors.Is(err, io.EOF)) {
 		h.handleValidationError(c, err)
 		return
 	}
+	if req.RefreshToken == "" && h.refreshTokenCookie {
+		req.RefreshToken, _ = c.Cookie(refreshTokenCookieName)
+	}
+	if req.RefreshToken == "" {
+		h.handleError(c, apperrors.ErrInvalidParams("refresh_token is required"))
+		return
+	}
 
 	// Step 2: 调用 Service 刷新 Token
-	refreshResp, err := h.userService.RefreshToken(c.Request.Context(), &req)
+	// 客户端IP 用于会话被封禁时的审计记录
+	ctx := service.ContextWithClientIP(c.Request.Context(), c.ClientIP())
+	refreshResp, err := h.userService.RefreshToken(ctx, &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -172,6 +217,21 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, refreshResp)
 }
 
+// setRefreshTokenCookie 把 Refresh Token 写入 HttpOnly Cookie
+// Cookie 与 Token 同时过期
+func (h *UserHandler) setRefreshTokenCookie(c *gin.Context, token string, expiresAt time.Time) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     refreshTokenCookieName,
+		Value:    token,
+		Path:     refreshTokenCookiePath,
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
 // ==================== 错误处理辅助方法 ====================
 
 // handleError 统一处理 Service 层返回的错误
@@ -187,11 +247,12 @@ func (h *UserHandler) handleError(c *gin.Context, err error) {
 }
 
 // handleValidationError 处理请求参数验证错误
+// 请求体过大时返回 413，其余返回 400
 //
 // Gin 的 binding 验证失败时调用此方法
 // 返回 400 Bad Request 和详细的错误信息
 func (h *UserHandler) handleValidationError(c *gin.Context, err error) {
 	// 创建参数验证错误
-	appErr := apperrors.ErrInvalidParams(err.Error())
-	c.JSON(http.StatusBadRequest, response.NewErrorResponse(appErr))
+	appErr := apperrors.ErrBinding(err)
+	c.JSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
 }
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+)
+
+// newTestUserRouter 注册登录和刷新 Token 路由
+func newTestUserRouter(t *testing.T, cookie bool) http.Handler {
+	repos := memory.New()
+	h := NewUserHandler(newTestUserService(t, repos, newTestTokenMaker(t), "alice")).WithRefreshTokenCookie(cookie)
+	r := newTestEngine()
+	r.POST("/api/v1/users/login", h.LoginUser)
+	r.POST("/api/v1/tokens/renew", h.RefreshToken)
+	return r
+}
+
+var testLogin = map[string]string{"username": "alice", "password": "secret123"}
+
+func TestRefreshTokenInBody(t *testing.T) {
+	r := newTestUserRouter(t, false)
+
+	w := doJSON(r, http.MethodPost, "/api/v1/users/login", testLogin, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login status = %d, body = %s", w.Code, w.Body)
+	}
+	if cookies := w.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("unexpected cookies %v", cookies)
+	}
+	var login response.LoginResponse
+	decodeJSON(t, w, &login)
+	if login.RefreshToken == "" {
+		t.Fatal("refresh_token missing from body")
+	}
+
+	w = doJSON(r, http.MethodPost, "/api/v1/tokens/renew", map[string]string{"refresh_token": login.RefreshToken}, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("renew status = %d, body = %s", w.Code, w.Body)
+	}
+
+	// 未启用 Cookie 时不读取 Cookie
+	header := http.Header{"Cookie": {refreshTokenCookieName + "=" + login.RefreshToken}}
+	if w := doJSON(r, http.MethodPost, "/api/v1/tokens/renew", map[string]string{}, header); w.Code != http.StatusBadRequest {
+		t.Errorf("renew from cookie status = %d, want 400", w.Code)
+	}
+}
+
+func TestRefreshTokenInCookie(t *testing.T) {
+	r := newTestUserRouter(t, true)
+
+	w := doJSON(r, http.MethodPost, "/api/v1/users/login", testLogin, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("login status = %d, body = %s", w.Code, w.Body)
+	}
+	var login response.LoginResponse
+	decodeJSON(t, w, &login)
+	if login.RefreshToken != "" {
+		t.Error("refresh_token must not be in the body when the cookie is enabled")
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("cookies = %v, want one refresh token cookie", cookies)
+	}
+	cookie := cookies[0]
+	if cookie.Name != refreshTokenCookieName || cookie.Path != refreshTokenCookiePath ||
+		!cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie = %+v, want HttpOnly Secure SameSite=Strict on %s", cookie, refreshTokenCookiePath)
+	}
+
+	// 空请求体时从 Cookie 读取
+	header := http.Header{"Cookie": {cookie.Name + "=" + cookie.Value}}
+	w = doJSON(r, http.MethodPost, "/api/v1/tokens/renew", nil, header)
+	if w.Code != http.StatusOK {
+		t.Fatalf("renew status = %d, body = %s", w.Code, w.Body)
+	}
+	var renewed response.RefreshTokenResponse
+	decodeJSON(t, w, &renewed)
+	if renewed.AccessToken == "" {
+		t.Error("renew response has no access token")
+	}
+}
+
+func TestCreateUserIdempotencyReplay(t *testing.T) {
+	repos := memory.New()
+	h := NewUserHandler(newTestUserService(t, repos, newTestTokenMaker(t)))
+	r := newTestEngine()
+	r.POST("/api/v1/users", middleware.Idempotency(middleware.NewMemoryIdempotencyStore(), time.Hour), h.CreateUser)
+
+	body := map[string]string{
+		"username":  "carol",
+		"password":  "secret123",
+		"full_name": "Carol",
+		"email":     "carol@example.com",
+	}
+	header := http.Header{}
+	header.Set(middleware.IdempotencyKeyHeader, "register-carol-1")
+
+	first := doJSON(r, http.MethodPost, "/api/v1/users", body, header)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d, body = %s", first.Code, first.Body)
+	}
+
+	// 连接断开后用同一个键重试，返回首次的结果而不是 409
+	replay := doJSON(r, http.MethodPost, "/api/v1/users", body, header)
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want 201 %s", replay.Code, replay.Body, first.Body)
+	}
+	if got := replay.Header().Get(middleware.IdempotentReplayedHeader); got != "true" {
+		t.Errorf("%s = %q, want true", middleware.IdempotentReplayedHeader, got)
+	}
+
+	// 不带键的重复注册仍然返回 409
+	w := doJSON(r, http.MethodPost, "/api/v1/users", body, nil)
+	var errResp response.ErrorResponse
+	decodeJSON(t, w, &errResp)
+	if w.Code != http.StatusConflict || errResp.Code != apperrors.CodeUsernameExists {
+		t.Errorf("duplicate without key = %d %+v, want 409", w.Code, errResp)
+	}
+
+	// 同一个键用于不同的请求体
+	body["email"] = "other@example.com"
+	if w := doJSON(r, http.MethodPost, "/api/v1/users", body, header); w.Code != http.StatusBadRequest {
+		t.Errorf("reused key status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,33 @@
+// Package logging 在 Context 中传递带请求字段的 *slog.Logger
+//
+// 中间件把请求ID、用户名等字段附加到 Context 中的 Logger 上，
+// Service、Repository 通过 FromContext 取出后记录日志，
+// 同一请求的日志自动带有相同的字段，不需要逐层传递 Logger
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey 是 Context 中存储 Logger 的键
+type loggerKey struct{}
+
+// NewContext 返回携带 logger 的 Context
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 返回 Context 中的 Logger，不存在时返回 slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With 在 Context 中的 Logger 上附加字段，返回携带新 Logger 的 Context
+// args 的格式与 slog.Logger.With 相同，例如 With(ctx, "request_id", id)
+func With(ctx context.Context, args ...any) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureLogs 把默认 Logger 替换为写入缓冲区的 JSON Logger，测试结束后恢复
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// logRecords 解析缓冲区中的每一行 JSON 日志
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode log %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := captureLogs(t)
+
+	r := gin.New()
+	r.Use(RequestID(), AccessLog("/health"))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+
+	var requestIDs []string
+	for _, path := range []string{"/health", "/ping", "/fail"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		requestIDs = append(requestIDs, w.Header().Get(RequestIDHeader))
+	}
+
+	// 健康检查不记录
+	records := logRecords(t, logs)
+	if len(records) != 2 {
+		t.Fatalf("got %d log lines, want 2: %v", len(records), records)
+	}
+
+	tests := []struct {
+		name      string
+		record    map[string]any
+		requestID string
+		path      string
+		status    float64
+		bytes     float64
+		level     string
+	}{
+		{name: "success", record: records[0], requestID: requestIDs[1], path: "/ping", status: 200, bytes: 4, level: "INFO"},
+		{name: "server error", record: records[1], requestID: requestIDs[2], path: "/fail", status: 502, bytes: 0, level: "WARN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := map[string]any{
+				"msg":        "http request",
+				"level":      tt.level,
+				"method":     http.MethodGet,
+				"path":       tt.path,
+				"status":     tt.status,
+				"bytes":      tt.bytes,
+				"client_ip":  "192.0.2.1",
+				"request_id": tt.requestID,
+			}
+			for key, value := range want {
+				if tt.record[key] != value {
+					t.Errorf("%s = %v, want %v", key, tt.record[key], value)
+				}
+			}
+			if _, ok := tt.record["latency"]; !ok {
+				t.Error("latency missing")
+			}
+		})
+	}
+}
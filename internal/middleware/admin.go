@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// IPAllowList 创建一个 IP 白名单中间件
+//
+// entries 可以是单个 IP (127.0.0.1) 或 CIDR (10.0.0.0/8)
+// 无法解析的条目会被忽略并记录警告；对端 IP 不在白名单中时返回 403
+//
+// 按 TCP 连接的对端地址 (RemoteIP) 判断，不读取 X-Forwarded-For 等可以由客户端伪造的请求头
+// 用于保护 /internal 等只允许内网访问的管理接口
+func IPAllowList(entries []string) gin.HandlerFunc {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix)
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		slog.Warn("ignore invalid allow-list entry", "entry", entry)
+	}
+
+	return func(c *gin.Context) {
+		addr, err := netip.ParseAddr(c.RemoteIP())
+		if err == nil {
+			addr = addr.Unmap()
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		appErr := apperrors.ErrForbidden()
+		c.AbortWithStatusJSON(http.StatusForbidden, response.NewErrorResponse(appErr))
+	}
+}
+
+// Maintenance 创建一个维护模式中间件
+//
+// 每个请求都从 RuntimeStore 读取最新配置，热更新后下一个请求立即生效
+// 维护模式开启时返回 503 Service Unavailable
+func Maintenance(runtime *config.RuntimeStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if runtime.Load().MaintenanceMode {
+			appErr := apperrors.NewWithMessage(apperrors.CodeServiceUnavailable, "service under maintenance")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.NewErrorResponse(appErr))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIPAllowListIgnoresForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       int
+	}{
+		{"allowed peer", "127.0.0.1:40000", "", http.StatusOK},
+		{"allowed CIDR", "10.1.2.3:40000", "", http.StatusOK},
+		{"denied peer", "203.0.113.9:40000", "", http.StatusForbidden},
+		{"spoofed forwarded-for", "203.0.113.9:40000", "127.0.0.1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			// 即使信任所有代理，白名单也只看对端地址
+			_ = r.SetTrustedProxies([]string{"0.0.0.0/0"})
+			r.POST("/internal", IPAllowList([]string{"127.0.0.1", "10.0.0.0/8"}), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/internal", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+const (
+	// AuthorizationTypeAPIKey 是 API Key 的认证类型
+	// 请求头格式: Authorization: ApiKey <key>
+	AuthorizationTypeAPIKey = "apikey"
+
+	// apiKeyAuthKey 标记请求是否通过 API Key 认证
+	apiKeyAuthKey = "api_key_auth"
+)
+
+// APIKeyAuthenticator 验证 API Key
+// 由 APIKeyService 实现
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*token.Payload, error)
+}
+
+// APIKeyAuth 创建一个 API Key 认证中间件
+//
+// 必须放在 AuthMiddleware 之前使用:
+//   - Authorization 为 "ApiKey <key>" 时验证 Key，成功后 payload 存入 Context，
+//     AuthMiddleware 看到已有 payload 直接放行
+//   - 其他认证类型 (或没有认证头) 不处理，交给 AuthMiddleware
+//
+// Key 无效或已撤销时返回 401
+func APIKeyAuth(auth APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := strings.Fields(c.GetHeader(AuthorizationHeaderKey))
+		if len(fields) != 2 || strings.ToLower(fields[0]) != AuthorizationTypeAPIKey {
+			c.Next()
+			return
+		}
+
+		payload, err := auth.AuthenticateAPIKey(c.Request.Context(), fields[1])
+		if err != nil {
+			appErr := apperrors.AsAppError(err)
+			c.AbortWithStatusJSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+			return
+		}
+
+		setAuthPayload(c, payload)
+		c.Set(apiKeyAuthKey, true)
+		c.Next()
+	}
+}
+
+// IsAPIKeyAuth 检查请求是否通过 API Key 认证
+func IsAPIKeyAuth(c *gin.Context) bool {
+	return c.GetBool(apiKeyAuthKey)
+}
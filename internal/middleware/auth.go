@@ -10,6 +10,7 @@ import (
 
 	"github.com/proyuen/simple-bank-v2/internal/dto/response"
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
 	"github.com/proyuen/simple-bank-v2/pkg/token"
 )
 
@@ -53,6 +54,12 @@ const (
 func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	// 返回一个闭包函数，捕获 tokenMaker 变量
 	return func(c *gin.Context) {
+		// 已由前面的认证中间件 (如 APIKeyAuth) 认证过的请求直接放行
+		if _, ok := GetAuthPayload(c); ok {
+			c.Next()
+			return
+		}
+
 		// Step 1: 获取 Authorization 请求头
 		// 如果没有提供认证头，返回 401 Unauthorized
 		authHeader := c.GetHeader(AuthorizationHeaderKey)
@@ -85,32 +92,43 @@ func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
-		// Step 4: 提取并验证 Token
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
-		if err != nil {
-			// Token 验证失败 (过期、无效签名等)
-			// 根据错误类型返回不同的错误码
-			var appErr *apperrors.AppError
-			if err == token.ErrExpiredToken {
-				appErr = apperrors.New(apperrors.CodeTokenExpired)
-			} else {
-				appErr = apperrors.New(apperrors.CodeInvalidToken)
-			}
-			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(appErr))
+		// Step 4: 提取并验证 Token，payload 存入 Context
+		// 后续的 Handler 可以通过 c.MustGet(AuthorizationPayloadKey) 获取
+		if !authenticate(c, tokenMaker, fields[1]) {
 			return
 		}
 
-		// Step 5: 将 payload 存入 Context
-		// 后续的 Handler 可以通过 c.MustGet(AuthorizationPayloadKey) 获取
-		c.Set(AuthorizationPayloadKey, payload)
-
-		// Step 6: 调用下一个处理器
+		// Step 5: 调用下一个处理器
 		// c.Next() 继续处理链中的下一个中间件或 Handler
 		c.Next()
 	}
 }
 
+// authenticate 验证 accessToken 并将 payload 存入 Context，用户名附加到请求 Context 的 Logger 上
+// 验证失败 (过期、无效签名等) 时按错误类型返回 401 并中止请求
+func authenticate(c *gin.Context, tokenMaker token.Maker, accessToken string) bool {
+	payload, err := tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if err == token.ErrExpiredToken {
+			appErr = apperrors.New(apperrors.CodeTokenExpired)
+		} else {
+			appErr = apperrors.New(apperrors.CodeInvalidToken)
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(appErr))
+		return false
+	}
+
+	setAuthPayload(c, payload)
+	return true
+}
+
+// setAuthPayload 将 payload 存入 Context，用户名附加到请求 Context 的 Logger 上
+func setAuthPayload(c *gin.Context, payload *token.Payload) {
+	c.Set(AuthorizationPayloadKey, payload)
+	c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "username", payload.Username))
+}
+
 // GetAuthPayload 从 Gin Context 中获取认证 payload
 //
 // 这是一个辅助函数，简化 Handler 中获取当前用户信息的代码
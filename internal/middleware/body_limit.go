@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// MaxBodySize 创建一个请求体大小限制中间件
+//
+// 使用 http.MaxBytesReader 包装请求体，读取超过 n 字节时返回 *http.MaxBytesError，
+// 由 Handler 的参数绑定错误处理转换为 413 (CodePayloadTooLarge)
+// Content-Length 已声明超限的请求直接拒绝，不再读取请求体
+// n <= 0 表示不限制
+func MaxBodySize(n int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if n <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > n {
+			abortPayloadTooLarge(c, n)
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, n)
+		c.Next()
+	}
+}
+
+// abortPayloadTooLarge 返回 413 Payload Too Large
+func abortPayloadTooLarge(c *gin.Context, limit int64) {
+	appErr := apperrors.ErrPayloadTooLarge(limit)
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, response.NewErrorResponse(appErr))
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// Draining 创建一个关闭期间拒绝新请求的中间件
+//
+// 服务开始关闭时设置 draining，http.Server.Shutdown 等待处理中的请求完成，
+// 这期间新到达的请求直接返回 503 并带 Connection: close，
+// 客户端不再复用该连接，负载均衡器据此把流量切到其他实例
+//
+// skipPaths 中的路径 (精确匹配，如健康检查) 不拦截，由 Handler 自己报告关闭状态
+func Draining(draining *atomic.Bool, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if !draining.Load() {
+			c.Next()
+			return
+		}
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		c.Header("Connection", "close")
+		appErr := apperrors.NewWithMessage(apperrors.CodeServiceUnavailable, "server is shutting down")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.NewErrorResponse(appErr))
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+)
+
+// envelopeWriter 缓冲 Handler 写出的响应体，由 ResponseEnvelope 决定最终输出
+//
+// 附件下载 (Content-Disposition: attachment) 和调用过 Flush 的流式响应
+// 直接透传，不缓冲也不包装
+type envelopeWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if w.passthrough() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	if w.passthrough() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// Flush 切换为透传模式，先写出已缓冲的内容
+func (w *envelopeWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		if w.body.Len() > 0 {
+			_, _ = w.ResponseWriter.Write(w.body.Bytes())
+			w.body.Reset()
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// passthrough 返回是否应直接透传响应体
+func (w *envelopeWriter) passthrough() bool {
+	if !w.streaming && strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment") {
+		w.streaming = true
+	}
+	return w.streaming
+}
+
+// ResponseEnvelope 创建一个响应包装中间件
+//
+// 将成功的 JSON 响应包装为与错误响应一致的格式:
+//
+//	{"code": 0, "message": "success", "data": <原响应体>}
+//
+// 只包装 2xx 且 Content-Type 为 JSON 的非空响应；
+// 错误响应本身已带 code/message，保持原样不会被二次包装；
+// 附件下载和流式响应不包装
+func ResponseEnvelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		original := c.Writer
+		writer := &envelopeWriter{ResponseWriter: original}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		body := writer.body.Bytes()
+		if len(body) == 0 {
+			return
+		}
+
+		status := original.Status()
+		isJSON := strings.HasPrefix(original.Header().Get("Content-Type"), "application/json")
+		if status < 200 || status >= 300 || !isJSON {
+			_, _ = original.Write(body)
+			return
+		}
+
+		wrapped, err := json.Marshal(response.NewEnvelope(body))
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("wrap response envelope", "error", err)
+			_, _ = original.Write(body)
+			return
+		}
+		_, _ = original.Write(wrapped)
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// RequireFreshToken 创建一个要求最近登录的中间件 (step-up)
+//
+// 必须放在 AuthMiddleware 之后使用，用于创建 API Key、撤销转账等敏感操作:
+// 用户登录 (输入密码) 超过 maxAge 时返回 401 (CodeReauthRequired)，客户端应引导用户重新登录
+// 登录时间取 Token 的 auth_time，刷新 Token 不会更新它 (见 token.Payload.AuthenticatedAt)
+//
+// API Key 不代表用户刚输入过密码，总是视为需要重新登录 (只能用登录 Token 完成)
+// maxAge <= 0 表示不检查
+func RequireFreshToken(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := GetAuthPayload(c)
+		if !ok {
+			err := apperrors.New(apperrors.CodeUnauthorized)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		if !IsFreshAuth(c, payload, maxAge) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(apperrors.ErrReauthRequired()))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsFreshAuth 检查当前请求的认证是否满足 step-up 要求
+// 与 IsFreshToken 相同，但通过 API Key 认证的请求永远不满足 (maxAge <= 0 时除外)
+func IsFreshAuth(c *gin.Context, payload *token.Payload, maxAge time.Duration) bool {
+	if maxAge <= 0 {
+		return true
+	}
+	return !IsAPIKeyAuth(c) && IsFreshToken(payload, maxAge)
+}
+
+// IsFreshToken 检查用户是否在 maxAge 之内登录
+// 只看 Token 本身，Handler 中应使用 IsFreshAuth 以排除 API Key；maxAge <= 0 时总是返回 true
+func IsFreshToken(payload *token.Payload, maxAge time.Duration) bool {
+	if maxAge <= 0 {
+		return true
+	}
+	return time.Since(payload.AuthenticatedAt()) <= maxAge
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+func TestRequireFreshTokenRejectsAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		authTime time.Time
+		apiKey   bool
+		maxAge   time.Duration
+		want     int
+	}{
+		{"fresh token", now, false, time.Minute, http.StatusOK},
+		{"stale token", now.Add(-time.Hour), false, time.Minute, http.StatusUnauthorized},
+		// API Key 的 payload 签发时间总是当前时间，但不能通过 step-up
+		{"api key", now, true, time.Minute, http.StatusUnauthorized},
+		{"api key without step-up", now, true, 0, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.POST("/sensitive", func(c *gin.Context) {
+				setAuthPayload(c, &token.Payload{Username: "alice", IssuedAt: now, AuthTime: tt.authTime})
+				if tt.apiKey {
+					c.Set(apiKeyAuthKey, true)
+				}
+				c.Next()
+			}, RequireFreshToken(tt.maxAge), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sensitive", nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
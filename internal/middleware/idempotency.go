@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+const (
+	// IdempotencyKeyHeader 客户端生成的幂等键 (建议使用 UUID)
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader 响应是重放的首次结果时设置为 "true"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLen 幂等键的最大长度
+	maxIdempotencyKeyLen = 255
+)
+
+// Idempotency 创建一个幂等请求中间件
+//
+// 客户端在请求头携带 Idempotency-Key 时，同一个键在 ttl 内的重复请求不会再次执行，
+// 而是直接返回首次成功的响应 (状态码和响应体相同，并带 Idempotent-Replayed: true)；
+// 不带该请求头的请求不受影响
+//
+// 规则:
+//   - 键是全局的 (不区分用户)，可用于认证之前的接口，如用户注册
+//   - 同一个键用于不同的请求 (方法、路径或请求体不同) 时返回 400
+//   - 首次请求仍在处理中时，重复请求返回 409
+//   - 只缓存 2xx 响应；失败的请求不占用键，客户端可以用同一个键重试
+//
+// 键的状态保存在 store 中: 使用 MemoryIdempotencyStore 时缓存在进程内，
+// 多实例部署时重试被路由到其他实例的请求会按普通请求处理，需要使用共享的存储 (如数据库)
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || ttl <= 0 {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			err := apperrors.ErrInvalidParams("Idempotency-Key must not exceed 255 characters")
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.NewErrorResponse(err))
+			return
+		}
+
+		// Step 1: 读取请求体计算请求指纹，之后恢复请求体供 Handler 绑定
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			appErr := apperrors.ErrBinding(err)
+			c.AbortWithStatusJSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		// Step 2: 占用键，或检查已有的相同键的请求
+		ctx := c.Request.Context()
+		existing, err := store.Reserve(ctx, &model.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   time.Now().Add(ttl),
+		})
+		if err != nil {
+			appErr := apperrors.AsAppError(err)
+			c.AbortWithStatusJSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+			return
+		}
+		switch idempotencyStateOf(existing, fingerprint) {
+		case idempotencyMismatch:
+			err := apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "Idempotency-Key was already used for a different request")
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.NewErrorResponse(err))
+			return
+		case idempotencyInProgress:
+			err := apperrors.NewWithMessage(apperrors.CodeStateConflict, "a request with this Idempotency-Key is still being processed")
+			c.AbortWithStatusJSON(http.StatusConflict, response.NewErrorResponse(err))
+			return
+		case idempotencyReplay:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			c.Abort()
+			return
+		}
+
+		// Step 3: 首次请求，执行 Handler 并记录响应
+		// 保存结果时请求可能已被客户端取消，使用不会取消的 Context
+		storeCtx := context.WithoutCancel(ctx)
+		writer := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+		completed := false
+		defer func() {
+			// Handler panic 时释放键，允许客户端重试
+			if !completed {
+				releaseIdempotencyKey(storeCtx, store, key)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status < 200 || status >= 300 {
+			releaseIdempotencyKey(storeCtx, store, key)
+		} else if err := store.Complete(storeCtx, key, status, writer.Header().Get("Content-Type"), writer.body.Bytes(), time.Now().Add(ttl)); err != nil {
+			// 响应已经发出，保存失败只影响之后的重试 (键在过期前一直按处理中返回 409)
+			logging.FromContext(ctx).Error("save idempotent response failed", slog.String("key", key), slog.Any("error", err))
+		}
+		completed = true
+	}
+}
+
+// releaseIdempotencyKey 释放键，失败时只记录日志 (键过期后同样可以重试)
+func releaseIdempotencyKey(ctx context.Context, store IdempotencyStore, key string) {
+	if err := store.Release(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("release idempotency key failed", slog.String("key", key), slog.Any("error", err))
+	}
+}
+
+// requestFingerprint 计算请求的指纹 (十六进制)，用于识别同一个键被用于不同的请求
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder 写出响应的同时保留一份响应体
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyState 幂等键的检查结果
+type idempotencyState int
+
+const (
+	idempotencyNew        idempotencyState = iota // 首次请求，已占用键
+	idempotencyInProgress                         // 相同请求正在处理
+	idempotencyReplay                             // 相同请求已成功，重放响应
+	idempotencyMismatch                           // 键已用于不同的请求
+)
+
+// idempotencyStateOf 根据 IdempotencyStore.Reserve 返回的已有记录判断请求的处理方式
+func idempotencyStateOf(existing *model.IdempotencyKey, fingerprint string) idempotencyState {
+	switch {
+	case existing == nil:
+		return idempotencyNew
+	case existing.Fingerprint != fingerprint:
+		return idempotencyMismatch
+	case !existing.Done:
+		return idempotencyInProgress
+	default:
+		return idempotencyReplay
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// IdempotencyStore 保存幂等键的处理状态和首次成功的响应
+// 由 MemoryIdempotencyStore (进程内)、repository.IdempotencyKeyRepository (数据库)
+// 和 repository.RedisIdempotencyKeyRepository (Redis) 实现
+type IdempotencyStore interface {
+	// Reserve 原子地占用幂等键
+	// 键不存在或已过期时保存 record 并返回 (nil, nil)；否则返回已有的记录 (可能仍在处理中)，
+	// 并发的相同请求只有一个能占用成功
+	Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error)
+
+	// Complete 保存首次成功的响应，并把过期时间更新为 expiresAt
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, expiresAt time.Time) error
+
+	// Release 释放未成功的请求占用的键，已完成的键不受影响
+	Release(ctx context.Context, key string) error
+}
+
+// maxIdempotencyEntries MemoryIdempotencyStore 保存的最大幂等键数，超出时清理过期条目
+const maxIdempotencyEntries = 10000
+
+// MemoryIdempotencyStore 进程内的 IdempotencyStore
+// 单实例部署时使用；多实例部署时每个实例分别保存
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]*model.IdempotencyKey
+}
+
+// NewMemoryIdempotencyStore 创建 MemoryIdempotencyStore 实例
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]*model.IdempotencyKey),
+	}
+}
+
+// Reserve 原子地占用幂等键，返回值是已有记录的副本
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := s.entries[record.Key]; ok && !entry.IsExpired(now) {
+		existing := *entry
+		return &existing, nil
+	}
+
+	if len(s.entries) >= maxIdempotencyEntries {
+		for k, entry := range s.entries {
+			if entry.IsExpired(now) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	entry := *record
+	s.entries[record.Key] = &entry
+	return nil, nil
+}
+
+// Complete 保存首次成功的响应
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.Done {
+		return nil
+	}
+	entry.Done = true
+	entry.StatusCode = statusCode
+	entry.ContentType = contentType
+	entry.Body = bytes.Clone(body)
+	entry.ExpiresAt = expiresAt
+	return nil
+}
+
+// Release 释放未成功的请求占用的键
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && !entry.Done {
+		delete(s.entries, key)
+	}
+	return nil
+}
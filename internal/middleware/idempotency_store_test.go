@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestMemoryIdempotencyReserveIsAtomic(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	const workers = 50
+	var (
+		wg       sync.WaitGroup
+		reserved atomic.Int32
+	)
+	start := make(chan struct{})
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			got, err := store.Reserve(ctx, &model.IdempotencyKey{Key: "k1", Fingerprint: "fp", ExpiresAt: expiresAt})
+			if err != nil {
+				t.Errorf("Reserve: %v", err)
+				return
+			}
+			if got == nil {
+				reserved.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := reserved.Load(); got != 1 {
+		t.Errorf("reserved %d times, want exactly 1", got)
+	}
+}
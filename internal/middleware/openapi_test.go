@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// testSwaggerSpec swag init 输出格式的最小规范
+const testSwaggerSpec = `{
+	"swagger": "2.0",
+	"host": "localhost:8080",
+	"basePath": "/api/v1",
+	"paths": {
+		"/transfers": {
+			"post": {
+				"consumes": ["application/json"],
+				"parameters": [{
+					"in": "body",
+					"name": "request",
+					"required": true,
+					"schema": {"$ref": "#/definitions/request.CreateTransferRequest"}
+				}],
+				"responses": {"201": {"description": "Created"}}
+			},
+			"get": {
+				"responses": {"200": {"description": "OK"}}
+			}
+		}
+	},
+	"definitions": {
+		"request.CreateTransferRequest": {
+			"type": "object",
+			"required": ["from_account_id"],
+			"properties": {
+				"from_account_id": {"type": "string"},
+				"to_account_id": {"type": "string"},
+				"amount": {"type": "integer"}
+			}
+		}
+	}
+}`
+
+// newOpenAPIEngine 创建挂载了 OpenAPIValidation 的测试路由，Handler 回显收到的请求体
+func newOpenAPIEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+	spec, err := LoadOpenAPISpec([]byte(testSwaggerSpec))
+	if err != nil {
+		t.Fatalf("LoadOpenAPISpec: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	v1 := r.Group("/api/v1", OpenAPIValidation(spec))
+	echo := func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.Data(http.StatusOK, "application/json", body)
+	}
+	v1.POST("/transfers", echo)
+	v1.GET("/transfers", echo)
+	v1.POST("/accounts", echo)
+	return r
+}
+
+func TestOpenAPIValidationRejectsInvalidBodies(t *testing.T) {
+	r := newOpenAPIEngine(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantMsg  string
+	}{
+		{"valid body", http.MethodPost, "/api/v1/transfers", `{"from_account_id":"a","amount":100}`, http.StatusOK, ""},
+		{"unknown field", http.MethodPost, "/api/v1/transfers", `{"from_account_id":"a","amout":100}`, http.StatusBadRequest, `"amout"`},
+		{"wrong type", http.MethodPost, "/api/v1/transfers", `{"from_account_id":"a","amount":"100"}`, http.StatusBadRequest, "amount"},
+		{"missing required", http.MethodPost, "/api/v1/transfers", `{"amount":100}`, http.StatusBadRequest, "from_account_id"},
+		// 没有请求体的接口和规范中没有的接口不校验
+		{"operation without body", http.MethodGet, "/api/v1/transfers", ``, http.StatusOK, ""},
+		{"route not in spec", http.MethodPost, "/api/v1/accounts", `{"anything":true}`, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "http://bank.example.com"+tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode == http.StatusOK {
+				// Handler 仍能读到完整的请求体
+				if got := w.Body.String(); got != tt.body {
+					t.Errorf("handler body = %q, want %q", got, tt.body)
+				}
+				return
+			}
+
+			var body struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Code != apperrors.CodeInvalidParams {
+				t.Errorf("code = %d, want %d", body.Code, apperrors.CodeInvalidParams)
+			}
+			if !strings.Contains(body.Message, tt.wantMsg) {
+				t.Errorf("message = %q, want it to mention %s", body.Message, tt.wantMsg)
+			}
+		})
+	}
+}
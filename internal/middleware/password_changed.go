@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// PasswordChangeLookup 查询用户最近一次修改密码的时间
+// 由 UserService 实现
+type PasswordChangeLookup interface {
+	PasswordChangedAt(ctx context.Context, username string) (time.Time, error)
+}
+
+// maxPasswordChangeCacheEntries 缓存的最大用户数，超出时清理过期条目
+const maxPasswordChangeCacheEntries = 10000
+
+// RejectTokensBeforePasswordChange 创建一个拒绝修改密码前签发的 Token 的中间件
+//
+// 必须放在 AuthMiddleware 之后使用
+// 每个请求都要查询一次用户的修改密码时间，Token 签发时间早于它时返回 401 (Token 已过期)
+//
+// cacheTTL > 0 时在进程内缓存查询结果以减少数据库读取，
+// 代价是修改密码后最多 cacheTTL 内旧 Token 仍然有效；多实例部署时每个实例各自缓存
+func RejectTokensBeforePasswordChange(lookup PasswordChangeLookup, cacheTTL time.Duration) gin.HandlerFunc {
+	cache := &passwordChangeCache{
+		ttl:     cacheTTL,
+		entries: make(map[string]passwordChangeEntry),
+	}
+
+	return func(c *gin.Context) {
+		payload, ok := GetAuthPayload(c)
+		if !ok {
+			err := apperrors.New(apperrors.CodeUnauthorized)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		changedAt, ok := cache.get(payload.Username)
+		if !ok {
+			var err error
+			changedAt, err = lookup.PasswordChangedAt(c.Request.Context(), payload.Username)
+			if err != nil {
+				appErr := apperrors.AsAppError(err)
+				c.AbortWithStatusJSON(appErr.HTTPStatus, response.NewErrorResponse(appErr))
+				return
+			}
+			cache.set(payload.Username, changedAt)
+		}
+
+		if payload.IssuedBefore(changedAt) {
+			err := apperrors.NewWithMessage(apperrors.CodeTokenExpired, "password changed, please log in again")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// passwordChangeEntry 缓存的修改密码时间
+type passwordChangeEntry struct {
+	changedAt time.Time
+	expiresAt time.Time
+}
+
+// passwordChangeCache 按用户名缓存修改密码时间，ttl <= 0 时不缓存
+type passwordChangeCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]passwordChangeEntry
+}
+
+// get 返回未过期的缓存结果
+func (c *passwordChangeCache) get(username string) (time.Time, bool) {
+	if c.ttl <= 0 {
+		return time.Time{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[username]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return time.Time{}, false
+	}
+	return entry.changedAt, true
+}
+
+// set 缓存查询结果，条目过多时先清理过期条目，仍然过多时清空
+func (c *passwordChangeCache) set(username string, changedAt time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxPasswordChangeCacheEntries {
+		for name, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, name)
+			}
+		}
+		if len(c.entries) >= maxPasswordChangeCacheEntries {
+			clear(c.entries)
+		}
+	}
+	c.entries[username] = passwordChangeEntry{changedAt: changedAt, expiresAt: now.Add(c.ttl)}
+}
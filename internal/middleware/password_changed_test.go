@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// passwordChangeLookupFunc 用函数实现 PasswordChangeLookup
+type passwordChangeLookupFunc func(ctx context.Context, username string) (time.Time, error)
+
+func (f passwordChangeLookupFunc) PasswordChangedAt(ctx context.Context, username string) (time.Time, error) {
+	return f(ctx, username)
+}
+
+// newPasswordChangeEngine 创建以 issuedAt 签发的 Token 访问 /me 的 Engine
+func newPasswordChangeEngine(lookup PasswordChangeLookup, cacheTTL time.Duration, issuedAt time.Time) *gin.Engine {
+	r := gin.New()
+	r.GET("/me", func(c *gin.Context) {
+		setAuthPayload(c, &token.Payload{Username: "alice", IssuedAt: issuedAt})
+		c.Next()
+	}, RejectTokensBeforePasswordChange(lookup, cacheTTL), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRejectTokensBeforePasswordChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	changedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	lookup := passwordChangeLookupFunc(func(context.Context, string) (time.Time, error) {
+		return changedAt, nil
+	})
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		want     int
+	}{
+		{"issued before password change", changedAt.Add(-time.Minute), http.StatusUnauthorized},
+		{"issued in the same second", changedAt.Add(500 * time.Millisecond), http.StatusOK},
+		{"issued after password change", changedAt.Add(time.Minute), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newPasswordChangeEngine(lookup, 0, tt.issuedAt)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusUnauthorized {
+				var body response.ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				if body.Code != apperrors.CodeTokenExpired {
+					t.Errorf("code = %d, want CodeTokenExpired", body.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestPasswordChangeLookupIsCached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lookups := 0
+	lookup := passwordChangeLookupFunc(func(context.Context, string) (time.Time, error) {
+		lookups++
+		return time.Now().Add(-time.Hour), nil
+	})
+	issuedAt := time.Now()
+
+	for _, tt := range []struct {
+		ttl  time.Duration
+		want int
+	}{{0, 3}, {time.Minute, 1}} {
+		lookups = 0
+		r := newPasswordChangeEngine(lookup, tt.ttl, issuedAt)
+		for range 3 {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/me", nil))
+		}
+		if lookups != tt.want {
+			t.Errorf("ttl %s: lookups = %d, want %d", tt.ttl, lookups, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+const (
+	// RetryAfterHeader 429 响应中告诉客户端多少秒后可以重试
+	RetryAfterHeader = "Retry-After"
+
+	// maxRateLimitEntries 记录的最大客户端数，超出时清理已结束窗口的条目
+	maxRateLimitEntries = 10000
+)
+
+// RateLimit 创建一个按客户端 IP 限流的中间件
+//
+// 按 TCP 连接的对端地址 (RemoteIP) 计数，客户端伪造的 X-Forwarded-For 无法换出新的窗口
+//
+// 使用固定窗口计数: 每个 IP 的第一次请求开启一个长度为 window 的窗口，
+// 窗口内超过 limit 次的请求直接返回 429 (CodeTooManyRequests)，
+// 并通过 Retry-After 头告诉客户端距离窗口结束还有多少秒 (向上取整，至少 1)
+//
+// limit <= 0 或 window <= 0 表示不限流
+// 计数在进程内，多实例部署时每个实例分别计数
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := &rateLimiter{
+		limit:   limit,
+		window:  window,
+		entries: make(map[string]*rateLimitEntry),
+	}
+
+	return func(c *gin.Context) {
+		if limit <= 0 || window <= 0 {
+			c.Next()
+			return
+		}
+
+		retryAfter, ok := limiter.allow(c.RemoteIP(), time.Now())
+		if ok {
+			c.Next()
+			return
+		}
+
+		c.Header(RetryAfterHeader, strconv.Itoa(retryAfterSeconds(retryAfter)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, response.NewErrorResponse(apperrors.ErrTooManyRequests()))
+	}
+}
+
+// retryAfterSeconds 把剩余等待时间转换为 Retry-After 的秒数 (向上取整，至少 1)
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
+
+// rateLimitEntry 一个客户端当前窗口的请求计数
+type rateLimitEntry struct {
+	count   int
+	resetAt time.Time
+}
+
+// rateLimiter 按键统计固定窗口内的请求次数
+type rateLimiter struct {
+	limit   int
+	window  time.Duration
+	mu      sync.Mutex
+	entries map[string]*rateLimitEntry
+}
+
+// allow 记录一次请求，超过限制时返回 false 和距离窗口结束的时间
+func (l *rateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.resetAt) {
+		if len(l.entries) >= maxRateLimitEntries {
+			for k, e := range l.entries {
+				if !now.Before(e.resetAt) {
+					delete(l.entries, k)
+				}
+			}
+		}
+		entry = &rateLimitEntry{resetAt: now.Add(l.window)}
+		l.entries[key] = entry
+	}
+
+	if entry.count >= l.limit {
+		return entry.resetAt.Sub(now), false
+	}
+	entry.count++
+	return 0, true
+}
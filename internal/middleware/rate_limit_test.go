@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// newRateLimitedEngine 创建一个挂载了 RateLimit 的测试路由，信任所有代理
+func newRateLimitedEngine(limit int, window time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	_ = r.SetTrustedProxies([]string{"0.0.0.0/0"})
+	r.POST("/login", RateLimit(limit, window), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+// postLogin 以指定的对端地址和 X-Forwarded-For 发起一次请求
+func postLogin(r http.Handler, remoteAddr, forwarded string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = remoteAddr
+	if forwarded != "" {
+		req.Header.Set("X-Forwarded-For", forwarded)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitSetsRetryAfter(t *testing.T) {
+	r := newRateLimitedEngine(2, time.Minute)
+
+	for i := range 2 {
+		if w := postLogin(r, "203.0.113.9:40000", ""); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, w.Code)
+		}
+	}
+
+	w := postLogin(r, "203.0.113.9:40000", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get(RetryAfterHeader); got != "60" {
+		t.Errorf("Retry-After = %q, want %q", got, "60")
+	}
+	var body struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Code != apperrors.CodeTooManyRequests {
+		t.Errorf("code = %d, want %d", body.Code, apperrors.CodeTooManyRequests)
+	}
+
+	// 其他对端地址有自己的窗口
+	if w := postLogin(r, "198.51.100.7:40000", ""); w.Code != http.StatusOK {
+		t.Errorf("other peer status = %d, want 200", w.Code)
+	}
+}
+
+func TestRateLimitIgnoresForwardedFor(t *testing.T) {
+	r := newRateLimitedEngine(1, time.Minute)
+
+	if w := postLogin(r, "203.0.113.9:40000", "192.0.2.1"); w.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", w.Code)
+	}
+	// 每次换一个 X-Forwarded-For 也不会得到新的窗口
+	for _, forwarded := range []string{"192.0.2.2", "192.0.2.3", ""} {
+		if w := postLogin(r, "203.0.113.9:40000", forwarded); w.Code != http.StatusTooManyRequests {
+			t.Errorf("forwarded %q: status = %d, want 429", forwarded, w.Code)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want int
+	}{
+		{0, 1},
+		{100 * time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{time.Minute, 60},
+	}
+	for _, tt := range tests {
+		if got := retryAfterSeconds(tt.in); got != tt.want {
+			t.Errorf("retryAfterSeconds(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
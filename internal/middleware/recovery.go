@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+)
+
+// Recovery 创建一个 panic 恢复中间件，替代 gin.Recovery
+//
+// Handler panic 时记录错误和调用栈 (日志带有请求ID)，
+// 并返回与其他错误一致的 ErrorResponse JSON 和 500 状态码
+// 响应已开始写出时无法再修改状态码，只中断请求
+//
+// 应注册在 RequestID 之后，使日志能带上请求ID
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			logging.FromContext(c.Request.Context()).Error("panic recovered",
+				"panic", recovered,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, response.NewErrorResponse(apperrors.ErrInternalServer()))
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+func TestRecoveryReturnsErrorResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logs := captureLogs(t)
+
+	r := gin.New()
+	r.Use(RequestID(), Recovery())
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	var body response.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	if want := response.NewErrorResponse(apperrors.ErrInternalServer()); body != want {
+		t.Errorf("body = %+v, want %+v", body, want)
+	}
+
+	// 日志带有请求ID和调用栈
+	records := logRecords(t, logs)
+	if len(records) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(records))
+	}
+	record := records[0]
+	if record["request_id"] != w.Header().Get(RequestIDHeader) {
+		t.Errorf("log request_id = %v, want %q", record["request_id"], w.Header().Get(RequestIDHeader))
+	}
+	if stack, _ := record["stack"].(string); !strings.Contains(stack, "recovery_test.go") {
+		t.Errorf("log stack does not include the panicking handler: %q", stack)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+)
+
+const (
+	// RequestIDHeader 请求ID的请求头和响应头
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDKey 是存储在 Gin Context 中的请求ID键名
+	RequestIDKey = "request_id"
+
+	// maxRequestIDLength 客户端传入的请求ID的最大长度
+	maxRequestIDLength = 64
+)
+
+// RequestID 创建一个请求ID中间件
+//
+// 客户端 (或网关) 传入合法的 X-Request-ID 时沿用，否则生成 UUID
+// 请求ID写入响应头和 Gin Context，并附加到请求 Context 中的 Logger 上，
+// 之后通过 logging.FromContext 记录的日志都带有 request_id 字段
+//
+// 应作为第一个中间件注册，使后续中间件的日志也能带上请求ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "request_id", id))
+
+		c.Next()
+	}
+}
+
+// GetRequestID 从 Gin Context 中获取请求ID，未经过 RequestID 中间件时返回空字符串
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// validRequestID 检查客户端传入的请求ID
+// 只接受长度有限的可打印 ASCII 字符，防止日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// CacheStatusHeader 响应是否来自 ResponseCache: HIT 或 MISS
+	CacheStatusHeader = "X-Cache"
+
+	// maxResponseCacheEntries 缓存的最大响应数，超出时清理过期条目，仍然超出时不再缓存新的响应
+	maxResponseCacheEntries = 10000
+)
+
+// ResponseCache 进程内的 GET 响应缓存，用于访问频繁的列表接口 (如账户列表第一页)
+//
+// 缓存键为 用户名 + 路径 + 查询参数，不同用户永远不会共享缓存
+// 每个缓存的路由属于一个资源 (如 "accounts")，该资源的写请求成功后 (见 Invalidate)
+// 清除所有用户的相关缓存: 一笔转账同时改变转出方和收款方的余额，只清除当前用户的缓存不够
+//
+// 后台任务 (如执行定时转账) 不经过 HTTP 中间件，它们造成的变动最多在 ttl 之后可见，因此 ttl 应保持很短
+// 缓存在进程内，多实例部署时每个实例分别缓存和失效
+type ResponseCache struct {
+	ttl         time.Duration
+	mu          sync.Mutex
+	entries     map[string]*responseCacheEntry
+	generations map[string]uint64 // 每个资源被失效的次数，用于丢弃失效前开始处理的请求的响应
+}
+
+// responseCacheEntry 一个缓存的响应
+type responseCacheEntry struct {
+	resource    string
+	status      int
+	contentType string
+	header      http.Header // Handler 设置的其他响应头 (如 X-Total-Count、Link)，命中时一起返回
+	body        []byte
+	expiresAt   time.Time
+}
+
+// NewResponseCache 创建响应缓存，ttl <= 0 表示不缓存
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		ttl:         ttl,
+		entries:     make(map[string]*responseCacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+// Cache 创建一个缓存 resource 的 GET 响应的中间件
+//
+// 必须放在认证中间件之后，未认证的请求不缓存；只缓存 200 响应
+// 命中时直接返回缓存的响应 (包括 Handler 设置的分页头等响应头)，并带 X-Cache: HIT
+func (rc *ResponseCache) Cache(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := GetAuthPayload(c)
+		if rc.ttl <= 0 || c.Request.Method != http.MethodGet || !ok {
+			c.Next()
+			return
+		}
+
+		// Step 1: 查找缓存
+		key := responseCacheKey(payload.Username, c.Request.URL)
+		entry, generation := rc.get(key, resource, time.Now())
+		if entry != nil {
+			for name, values := range entry.header {
+				c.Writer.Header()[name] = slices.Clone(values)
+			}
+			c.Header(CacheStatusHeader, "HIT")
+			c.Data(entry.status, entry.contentType, entry.body)
+			c.Abort()
+			return
+		}
+
+		// Step 2: 未命中，执行 Handler 并记录响应
+		c.Header(CacheStatusHeader, "MISS")
+		before := c.Writer.Header().Clone()
+		writer := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		if writer.Status() == http.StatusOK {
+			rc.put(key, generation, &responseCacheEntry{
+				resource:    resource,
+				status:      http.StatusOK,
+				contentType: writer.Header().Get("Content-Type"),
+				header:      handlerHeaders(before, writer.Header()),
+				body:        writer.body.Bytes(),
+				expiresAt:   time.Now().Add(rc.ttl),
+			})
+		}
+	}
+}
+
+// Invalidate 创建一个中间件，请求成功 (2xx) 后清除 resources 的所有缓存
+// 用于会改变缓存内容的写操作，如创建账户、创建转账
+func (rc *ResponseCache) Invalidate(resources ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if status := c.Writer.Status(); rc.ttl > 0 && status >= 200 && status < 300 {
+			rc.invalidate(resources...)
+		}
+	}
+}
+
+// get 返回未过期的缓存和资源当前的失效次数
+func (rc *ResponseCache) get(key, resource string, now time.Time) (*responseCacheEntry, uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if ok && now.After(entry.expiresAt) {
+		delete(rc.entries, key)
+		entry = nil
+	}
+	return entry, rc.generations[resource]
+}
+
+// put 保存响应；处理请求期间资源被失效过时 (generation 变化) 丢弃，响应可能已经过时
+func (rc *ResponseCache) put(key string, generation uint64, entry *responseCacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.generations[entry.resource] != generation {
+		return
+	}
+	if len(rc.entries) >= maxResponseCacheEntries {
+		now := time.Now()
+		for k, e := range rc.entries {
+			if now.After(e.expiresAt) {
+				delete(rc.entries, k)
+			}
+		}
+		if len(rc.entries) >= maxResponseCacheEntries {
+			return
+		}
+	}
+	rc.entries[key] = entry
+}
+
+// invalidate 清除 resources 的所有缓存
+func (rc *ResponseCache) invalidate(resources ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for _, resource := range resources {
+		rc.generations[resource]++
+	}
+	for k, e := range rc.entries {
+		for _, resource := range resources {
+			if e.resource == resource {
+				delete(rc.entries, k)
+				break
+			}
+		}
+	}
+}
+
+// handlerHeaders 返回 Handler 新设置或修改的响应头 (after 中与 before 不同的部分)
+// 之前的中间件设置的头 (如 X-Request-ID) 每个请求都不同，不缓存；Content-Type 单独保存，Set-Cookie 永远不缓存
+func handlerHeaders(before, after http.Header) http.Header {
+	header := make(http.Header)
+	for name, values := range after {
+		if name == "Content-Type" || name == "Set-Cookie" || slices.Equal(before[name], values) {
+			continue
+		}
+		header[name] = slices.Clone(values)
+	}
+	return header
+}
+
+// responseCacheKey 用户名 + 路径 + 查询参数 (按参数名排序，参数顺序不同的请求共享缓存)
+func responseCacheKey(username string, u *url.URL) string {
+	return username + "\x00" + u.Path + "?" + u.Query().Encode()
+}
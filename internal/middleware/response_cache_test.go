@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// newCachedEngine 创建挂载了 ResponseCache 的测试路由，返回 Handler 被调用的次数
+// 用户名取自 X-User 请求头，每个请求带不同的 X-Request-Seq 响应头 (模拟 X-Request-ID)
+func newCachedEngine(ttl time.Duration) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	rc := NewResponseCache(ttl)
+	calls := 0
+	seq := 0
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		seq++
+		c.Header("X-Request-Seq", strconv.Itoa(seq))
+		setAuthPayload(c, &token.Payload{Username: c.GetHeader("X-User")})
+		c.Next()
+	})
+	r.GET("/accounts", rc.Cache("accounts"), func(c *gin.Context) {
+		calls++
+		c.Header("X-Total-Count", "42")
+		c.Header("Link", `</accounts?page_id=2>; rel="next"`)
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+	r.POST("/accounts", rc.Invalidate("accounts"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return r, &calls
+}
+
+// doCached 以 user 的身份发起请求
+func doCached(r http.Handler, method, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/accounts", nil)
+	req.Header.Set("X-User", user)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCacheReplaysHeaders(t *testing.T) {
+	r, calls := newCachedEngine(time.Minute)
+
+	miss := doCached(r, http.MethodGet, "alice")
+	hit := doCached(r, http.MethodGet, "alice")
+
+	if got := miss.Header().Get(CacheStatusHeader); got != "MISS" {
+		t.Errorf("first X-Cache = %q, want MISS", got)
+	}
+	if got := hit.Header().Get(CacheStatusHeader); got != "HIT" {
+		t.Errorf("second X-Cache = %q, want HIT", got)
+	}
+	if *calls != 1 {
+		t.Errorf("handler calls = %d, want 1", *calls)
+	}
+	if hit.Body.String() != miss.Body.String() {
+		t.Errorf("HIT body = %q, want %q", hit.Body.String(), miss.Body.String())
+	}
+	for _, name := range []string{"X-Total-Count", "Link", "Content-Type"} {
+		if got, want := hit.Header().Get(name), miss.Header().Get(name); got != want || got == "" {
+			t.Errorf("HIT %s = %q, want %q", name, got, want)
+		}
+	}
+	// 之前的中间件设置的头属于当前请求，不从缓存中重放
+	if got := hit.Header().Get("X-Request-Seq"); got != "2" {
+		t.Errorf("HIT X-Request-Seq = %q, want 2", got)
+	}
+}
+
+func TestResponseCacheIsolationAndInvalidation(t *testing.T) {
+	r, calls := newCachedEngine(time.Minute)
+
+	doCached(r, http.MethodGet, "alice")
+	// 其他用户不共享缓存
+	if got := doCached(r, http.MethodGet, "bob").Header().Get(CacheStatusHeader); got != "MISS" {
+		t.Errorf("bob X-Cache = %q, want MISS", got)
+	}
+
+	// 写请求成功后清除所有用户的缓存
+	if w := doCached(r, http.MethodPost, "bob"); w.Code != http.StatusCreated {
+		t.Fatalf("POST status = %d", w.Code)
+	}
+	if got := doCached(r, http.MethodGet, "alice").Header().Get(CacheStatusHeader); got != "MISS" {
+		t.Errorf("alice after invalidate X-Cache = %q, want MISS", got)
+	}
+	if *calls != 3 {
+		t.Errorf("handler calls = %d, want 3", *calls)
+	}
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	r, calls := newCachedEngine(0)
+
+	for range 2 {
+		if got := doCached(r, http.MethodGet, "alice").Header().Get(CacheStatusHeader); got != "" {
+			t.Errorf("X-Cache = %q, want none when disabled", got)
+		}
+	}
+	if *calls != 2 {
+		t.Errorf("handler calls = %d, want 2", *calls)
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// RequireRole 创建一个角色校验中间件
+//
+// 必须放在 AuthMiddleware 之后使用
+// 当前用户的角色不在 roles 中时返回 403 Forbidden
+//
+// 使用示例:
+//
+//	admin := authRoutes.Group("/admin")
+//	admin.Use(RequireRole(model.RoleAdmin))
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := GetAuthPayload(c)
+		if !ok {
+			err := apperrors.New(apperrors.CodeUnauthorized)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		for _, role := range roles {
+			if payload.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		err := apperrors.ErrForbidden()
+		c.AbortWithStatusJSON(http.StatusForbidden, response.NewErrorResponse(err))
+	}
+}
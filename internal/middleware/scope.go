@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// RequireScope 创建一个权限范围校验中间件
+//
+// 必须放在 AuthMiddleware 之后使用
+// 当前凭证 (只读 Token、API Key) 没有被授予 scope 时返回 403 Forbidden；
+// 普通登录签发的 Token 不限制 scope，总是放行
+//
+// 使用示例:
+//
+//	transfers.POST("", RequireScope(token.ScopeTransfersWrite), handler.CreateTransfer)
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := GetAuthPayload(c)
+		if !ok {
+			err := apperrors.New(apperrors.CodeUnauthorized)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		if !payload.HasScope(scope) {
+			err := apperrors.NewWithMessage(apperrors.CodeForbidden, "missing scope: "+scope)
+			c.AbortWithStatusJSON(http.StatusForbidden, response.NewErrorResponse(err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireReadScope 创建一个读操作的权限范围校验中间件
+//
+// 作用于整个路由组: GET/HEAD 请求要求 *:read，其他方法直接放行，
+// 写操作的 scope 由各路由自己的 RequireScope 校验
+func RequireReadScope() gin.HandlerFunc {
+	read := RequireScope(token.ScopeReadAll)
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		read(c)
+	}
+}
+
+// RequireFullAccess 创建一个只允许不受限凭证的中间件
+//
+// 用于 API Key 管理等只允许登录用户本人操作的路由:
+// API Key 和带 scope 的 Token (如只读 Token) 都返回 403，
+// 否则受限凭证可以借此创建权限更大的 API Key
+func RequireFullAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := GetAuthPayload(c)
+		if !ok {
+			err := apperrors.New(apperrors.CodeUnauthorized)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		if IsAPIKeyAuth(c) || !payload.IsUnrestricted() {
+			err := apperrors.NewWithMessage(apperrors.CodeForbidden, "restricted credentials cannot be used for this operation")
+			c.AbortWithStatusJSON(http.StatusForbidden, response.NewErrorResponse(err))
+			return
+		}
+
+		c.Next()
+	}
+}
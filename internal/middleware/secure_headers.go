@@ -0,0 +1,28 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// hstsValue Strict-Transport-Security 的取值: 一年，包含子域名
+const hstsValue = "max-age=31536000; includeSubDomains"
+
+// SecureHeaders 创建一个安全响应头中间件
+//
+// 为所有响应设置:
+//   - X-Content-Type-Options: nosniff  禁止浏览器猜测 MIME 类型
+//   - X-Frame-Options: DENY            禁止被嵌入 iframe (防点击劫持)
+//   - Referrer-Policy: no-referrer     不向其他站点泄露来源 URL
+//
+// hsts 为 true 时额外设置 Strict-Transport-Security，
+// 只应在生产环境 (HTTPS) 开启，否则本地 HTTP 开发会被浏览器强制跳转
+func SecureHeaders(hsts bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		if hsts {
+			header.Set("Strict-Transport-Security", hstsValue)
+		}
+		c.Next()
+	}
+}
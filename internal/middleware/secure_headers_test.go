@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecureHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, hsts := range []bool{false, true} {
+		r := gin.New()
+		r.Use(SecureHeaders(hsts))
+		r.GET("/ping", func(c *gin.Context) {
+			c.String(http.StatusOK, "pong")
+		})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		for name, want := range map[string]string{
+			"X-Content-Type-Options": "nosniff",
+			"X-Frame-Options":        "DENY",
+			"Referrer-Policy":        "no-referrer",
+		} {
+			if got := w.Header().Get(name); got != want {
+				t.Errorf("hsts=%v: %s = %q, want %q", hsts, name, got, want)
+			}
+		}
+
+		// HSTS 只在生产环境开启
+		got := w.Header().Get("Strict-Transport-Security")
+		if hsts && got != hstsValue {
+			t.Errorf("Strict-Transport-Security = %q, want %q", got, hstsValue)
+		}
+		if !hsts && got != "" {
+			t.Errorf("Strict-Transport-Security = %q, want unset outside production", got)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+const (
+	// WebSocketTokenQueryKey WebSocket 握手时携带 Access Token 的 Query 参数
+	WebSocketTokenQueryKey = "access_token"
+
+	// WebSocketBearerProtocol WebSocket 握手时表示下一个子协议是 Access Token 的子协议名
+	// 客户端请求子协议 ["bearer", "<token>"]，服务端回应 "bearer"
+	WebSocketBearerProtocol = "bearer"
+)
+
+// WebSocketAuth 创建一个 WebSocket 握手的 JWT 认证中间件
+//
+// 浏览器的 WebSocket API 不能设置请求头，Access Token 按以下顺序查找:
+//  1. Sec-WebSocket-Protocol: bearer, <token> (推荐，不会出现在访问日志中)
+//  2. Query 参数 access_token
+//  3. Authorization: Bearer <token> (非浏览器客户端)
+//
+// 验证通过后与 AuthMiddleware 一样将 payload 存入 Context，
+// 未携带或验证失败时在升级前返回 401
+func WebSocketAuth(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken := webSocketToken(c)
+		if accessToken == "" {
+			err := apperrors.New(apperrors.CodeUnauthorized)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewErrorResponse(err))
+			return
+		}
+
+		if !authenticate(c, tokenMaker, accessToken) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// webSocketToken 从握手请求中提取 Access Token，找不到时返回空字符串
+func webSocketToken(c *gin.Context) string {
+	var protocols []string
+	for _, header := range c.Request.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(p))
+		}
+	}
+	for i := 0; i+1 < len(protocols); i++ {
+		if strings.EqualFold(protocols[i], WebSocketBearerProtocol) {
+			return protocols[i+1]
+		}
+	}
+
+	if accessToken := c.Query(WebSocketTokenQueryKey); accessToken != "" {
+		return accessToken
+	}
+
+	fields := strings.Fields(c.GetHeader(AuthorizationHeaderKey))
+	if len(fields) == 2 && strings.ToLower(fields[0]) == AuthorizationTypeBearer {
+		return fields[1]
+	}
+	return ""
+}
@@ -1,8 +1,12 @@
 package model
 
 import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -11,24 +15,44 @@ import (
 // 重要字段说明:
 //   - Balance: 以"分"为单位存储，避免浮点数精度问题
 //     例如: $100.50 存储为 10050
-//   - Currency: 货币代码 (USD, EUR, CNY 等)
+//   - OverdraftLimit: 透支额度 (单位: 分)，默认 0 表示不允许透支
+//   - MinBalance: 最低余额 (单位: 分)，默认 0 表示不要求；与透支额度互斥
+//   - IsFrozen: 管理员冻结的账户，不能转入转出，但仍可以查询 (与关闭即软删除不同)
+//   - Currency: 货币代码 (USD, EUR, CNY 等，见 currency 包)
+//   - PublicID: URL 中使用的公开ID (UUID)，不暴露自增 ID 的数量和顺序；
+//     ID 只用于内部关联 (外键)
+//   - Number: 12 位数字账号，用户把它告诉付款方，转账时可以代替账户ID指定收款账户
 //   - Owner: 关联到 users.username
+//   - Name: 用户自定义的账户名称 (如 "Savings")，仅用于展示，可为空、可重复
 //
 // 业务规则:
-//   - 同一用户同一货币只能有一个账户 (由数据库唯一索引保证)
-//   - 余额不能为负数 (由业务逻辑保证)
+//   - 同一用户同一货币只能有一个未删除的账户
+//     (由唯一索引 owner + currency + active 保证，active 是由 deleted_at 生成的列，
+//     软删除后为 NULL，因此关闭账户后可以重新开立同币种账户)
+//   - 扣款后余额不能低于 BalanceFloor() (由数据库条件更新保证)
+//   - 货币创建后不能修改: 余额和分录都以账户货币计价，
+//     Currency 只允许在创建时写入 (<-:create)，任何 Update / Updates / Save 都不会更新 currency 列
 type Account struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Owner     string         `gorm:"not null;index;size:255" json:"owner"`           // 账户所有者(用户名)
-	Balance   int64          `gorm:"not null;default:0" json:"balance"`              // 余额(单位:分)
-	Currency  string         `gorm:"not null;size:3" json:"currency"`                // 货币类型
-	CreatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	PublicID       uuid.UUID      `gorm:"type:char(36);not null;uniqueIndex" json:"public_id"`                                                 // 公开ID
+	Number         string         `gorm:"type:char(12);not null;uniqueIndex" json:"number"`                                                    // 账号
+	Owner          string         `gorm:"not null;index;size:255;uniqueIndex:idx_accounts_owner_currency_active,priority:1" json:"owner"`      // 账户所有者(用户名)
+	Name           string         `gorm:"size:64" json:"name"`                                                                                 // 账户名称(可选)
+	Balance        int64          `gorm:"not null;default:0" json:"balance"`                                                                   // 余额(单位:分)
+	OverdraftLimit int64          `gorm:"not null;default:0" json:"overdraft_limit"`                                                           // 透支额度(单位:分)
+	MinBalance     int64          `gorm:"not null;default:0" json:"min_balance"`                                                               // 最低余额(单位:分)
+	IsFrozen       bool           `gorm:"not null;default:false" json:"is_frozen"`                                                             // 是否冻结
+	Currency       string         `gorm:"<-:create;not null;size:3;uniqueIndex:idx_accounts_owner_currency_active,priority:2" json:"currency"` // 货币类型
+	CreatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Active 由 deleted_at 生成的只读列 (未删除为 1，已删除为 NULL)，仅用于唯一索引
+	Active *bool `gorm:"->;type:TINYINT GENERATED ALWAYS AS (IF(deleted_at IS NULL, 1, NULL)) STORED;uniqueIndex:idx_accounts_owner_currency_active,priority:3" json:"-"`
 
 	// 关联关系
-	User     User    `gorm:"foreignKey:Owner;references:Username" json:"-"`
-	Entries  []Entry `gorm:"foreignKey:AccountID" json:"entries,omitempty"`
+	User    User    `gorm:"foreignKey:Owner;references:Username" json:"-"`
+	Entries []Entry `gorm:"foreignKey:AccountID" json:"entries,omitempty"`
 }
 
 // TableName 指定表名
@@ -36,8 +60,54 @@ func (Account) TableName() string {
 	return "accounts"
 }
 
+// AccountNumberLength 账号的位数
+const AccountNumberLength = 12
+
+// BeforeCreate GORM 钩子: 创建前生成公开ID和账号 (已设置时保留)
+func (a *Account) BeforeCreate(tx *gorm.DB) error {
+	if a.PublicID == uuid.Nil {
+		publicID, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		a.PublicID = publicID
+	}
+	if a.Number == "" {
+		number, err := newAccountNumber()
+		if err != nil {
+			return err
+		}
+		a.Number = number
+	}
+	return nil
+}
+
+// newAccountNumber 生成随机账号
+// 首位不为 0，与迁移时为已有账户生成的账号 (补零的 ID) 区分，两者不会冲突
+func newAccountNumber() (string, error) {
+	lo := new(big.Int).Exp(big.NewInt(10), big.NewInt(AccountNumberLength-1), nil) // 100000000000
+	span := new(big.Int).Mul(lo, big.NewInt(9))                                    // 900000000000
+	n, err := rand.Int(rand.Reader, span)
+	if err != nil {
+		return "", fmt.Errorf("generate account number: %w", err)
+	}
+	return n.Add(n, lo).String(), nil
+}
+
 // BalanceInDollars 返回以美元为单位的余额 (仅用于显示)
 // 例如: Balance = 10050 → 返回 100.50
 func (a *Account) BalanceInDollars() float64 {
 	return float64(a.Balance) / 100
 }
+
+// BalanceFloor 返回扣款后余额允许的下限
+// MinBalance 与 OverdraftLimit 互斥 (由 AccountService 保证)，因此结果为
+// MinBalance (要求最低余额) 或 -OverdraftLimit (允许透支)
+func (a *Account) BalanceFloor() int64 {
+	return a.MinBalance - a.OverdraftLimit
+}
+
+// AvailableBalance 返回可用余额 (余额 - 余额下限)
+func (a *Account) AvailableBalance() int64 {
+	return a.Balance - a.BalanceFloor()
+}
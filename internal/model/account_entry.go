@@ -0,0 +1,11 @@
+package model
+
+import "github.com/google/uuid"
+
+// AccountEntry 带所属账户信息的账目 (entries JOIN accounts 的结果)
+// 用于跨账户的账目列表，每条账目需要标明来自哪个账户、什么货币
+type AccountEntry struct {
+	Entry
+	AccountPublicID uuid.UUID // 所属账户的公开ID
+	Currency        string    // 所属账户的货币类型
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey API Key 模型 - 对应 api_keys 表
+//
+// 用途: 服务端集成无法走交互式登录时，使用长期有效的 API Key 访问接口
+// 请求头格式: Authorization: ApiKey <key>
+//
+// 重要字段说明:
+//   - SecretHash: 完整 Key 的 SHA-256 (十六进制)，Key 本身只在创建时返回一次，不保存明文
+//   - Prefix: Key 的前几个字符，用于在列表中识别是哪一个 Key
+//   - Scopes: 授予的权限范围，逗号分隔 (见 token 包的 Scope* 常量)
+//   - Revoked: 撤销后立即失效，不能恢复
+//   - LastUsedAt: 最后一次认证成功的时间 (按分钟更新)
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	PublicID   uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex" json:"public_id"` // 公开ID
+	Owner      string     `gorm:"not null;index;size:255" json:"owner"`                // 所有者(用户名)
+	Name       string     `gorm:"not null;size:64" json:"name"`                        // 名称(用于识别用途)
+	Prefix     string     `gorm:"not null;size:16" json:"prefix"`                      // Key 前缀(明文)
+	SecretHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`         // Key 的 SHA-256
+	Scopes     string     `gorm:"not null;size:255" json:"scopes"`                     // 权限范围(逗号分隔)
+	Revoked    bool       `gorm:"not null;default:false" json:"revoked"`               // 是否已撤销
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                                // 撤销时间
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`                              // 最后使用时间
+	CreatedAt  time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+
+	// 关联关系
+	User User `gorm:"foreignKey:Owner;references:Username" json:"-"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeCreate GORM 钩子: 创建前生成公开ID (已设置时保留)
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.PublicID != uuid.Nil {
+		return nil
+	}
+	publicID, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	k.PublicID = publicID
+	return nil
+}
+
+// ScopeList 返回授予的权限范围列表
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return nil
+	}
+	return strings.Split(k.Scopes, ",")
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+)
+
+// AuditLog 审计日志模型 - 对应 audit_logs 表
+//
+// 用途: 以只追加的方式记录敏感操作，满足合规审计要求
+//
+// 字段说明:
+//   - Actor: 执行操作的用户名
+//   - Action: 操作类型 (见 AuditAction* 常量)
+//   - Target: 操作对象 (如会话ID、转账参考号)
+//   - ClientIP: 发起操作的客户端IP
+//
+// 注意: 审计日志只允许插入，不提供更新和删除
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Actor     string    `gorm:"not null;index;size:255" json:"actor"`         // 操作者(用户名)
+	Action    string    `gorm:"not null;index;size:64" json:"action"`         // 操作类型
+	Target    string    `gorm:"not null;size:255;default:''" json:"target"`   // 操作对象
+	ClientIP  string    `gorm:"not null;size:45;default:''" json:"client_ip"` // 客户端IP
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// 审计操作类型
+const (
+	// AuditActionLogin 登录成功
+	AuditActionLogin = "login"
+
+	// AuditActionLoginFailed 登录失败
+	AuditActionLoginFailed = "login_failed"
+
+	// AuditActionTransfer 创建转账
+	AuditActionTransfer = "transfer"
+
+	// AuditActionTransferReverse 撤销转账
+	AuditActionTransferReverse = "transfer_reverse"
+
+	// AuditActionTransferSchedule 创建定时转账
+	AuditActionTransferSchedule = "transfer_schedule"
+
+	// AuditActionTransferScheduleCancel 取消定时转账
+	AuditActionTransferScheduleCancel = "transfer_schedule_cancel"
+
+	// AuditActionRecurringTransferCreate 创建周期转账规则
+	AuditActionRecurringTransferCreate = "recurring_transfer_create"
+
+	// AuditActionRecurringTransferCancel 取消周期转账规则
+	AuditActionRecurringTransferCancel = "recurring_transfer_cancel"
+
+	// AuditActionSessionBlock 封禁会话
+	AuditActionSessionBlock = "session_block"
+
+	// AuditActionAPIKeyCreate 创建 API Key
+	AuditActionAPIKeyCreate = "api_key_create"
+
+	// AuditActionAPIKeyRevoke 撤销 API Key
+	AuditActionAPIKeyRevoke = "api_key_revoke"
+
+	// AuditActionAccountOverdraftLimit 设置账户透支额度 (管理员)
+	AuditActionAccountOverdraftLimit = "account_overdraft_limit"
+
+	// AuditActionAccountMinBalance 设置账户最低余额 (管理员)
+	AuditActionAccountMinBalance = "account_min_balance"
+
+	// AuditActionAccountFreeze 冻结账户 (管理员)
+	AuditActionAccountFreeze = "account_freeze"
+
+	// AuditActionAccountUnfreeze 解冻账户 (管理员)
+	AuditActionAccountUnfreeze = "account_unfreeze"
+
+	// AuditActionAccountRestore 恢复已关闭账户 (管理员)
+	AuditActionAccountRestore = "account_restore"
+)
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,8 @@
+package model
+
+// CurrencyBalance 某一货币下账户余额的汇总 (GROUP BY currency 的结果)
+type CurrencyBalance struct {
+	Currency     string
+	TotalBalance int64 // 余额合计(单位:分)
+	AccountCount int64
+}
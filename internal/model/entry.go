@@ -12,15 +12,18 @@ import (
 //   - 正数: 入账 (例如: +100 表示收到 $1.00)
 //   - 负数: 出账 (例如: -100 表示支出 $1.00)
 //
+// TransferID 指向产生该账目的转账，为空表示不是由转账产生的资金变动 (存款/取款)
+//
 // 示例:
 //   转账 $10 从账户A到账户B:
 //   - Entry 1: AccountID=A, Amount=-1000 (出账)
 //   - Entry 2: AccountID=B, Amount=+1000 (入账)
 type Entry struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	AccountID uint      `gorm:"not null;index" json:"account_id"` // 关联的账户ID
-	Amount    int64     `gorm:"not null" json:"amount"`           // 金额(正=入账, 负=出账)
-	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	AccountID  uint      `gorm:"not null;index" json:"account_id"`   // 关联的账户ID
+	Amount     int64     `gorm:"not null" json:"amount"`             // 金额(正=入账, 负=出账)
+	TransferID *uint     `gorm:"index" json:"transfer_id,omitempty"` // 产生该账目的转账ID
+	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 
 	// 关联关系
 	Account Account `gorm:"foreignKey:AccountID" json:"-"`
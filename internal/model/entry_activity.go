@@ -0,0 +1,13 @@
+package model
+
+import "github.com/google/uuid"
+
+// EntryActivity 带来源转账信息的账目 (entries LEFT JOIN transfers 的结果)
+// 用于账户交易历史，不是由转账产生的账目各字段为 nil
+type EntryActivity struct {
+	Entry
+	TransferPublicID     *uuid.UUID // 来源转账的公开ID
+	TransferReference    *string    // 来源转账的参考号
+	CounterpartyPublicID *uuid.UUID // 转账另一方账户的公开ID
+	CounterpartyOwner    *string    // 转账另一方账户的所有者
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// IdempotencyKey 幂等键模型 - 对应 idempotency_keys 表
+//
+// 用途: 多实例部署时共享 Idempotency-Key 的处理状态，
+// 重试被路由到其他实例时同样返回首次成功的响应 (见 middleware.Idempotency)
+//
+// 重要字段说明:
+//   - Fingerprint: 请求的指纹 (方法、路径和请求体的 SHA-256，十六进制)，用于识别同一个键被用于不同的请求
+//   - Done: 首次请求已成功完成，StatusCode/ContentType/Body 为要重放的响应；为 false 表示仍在处理中
+//   - ExpiresAt: 过期后键可以被重新占用，需要定期清理过期记录
+type IdempotencyKey struct {
+	Key         string    `gorm:"column:idempotency_key;primaryKey;size:255" json:"key"`
+	Fingerprint string    `gorm:"type:char(64);not null" json:"fingerprint"`        // 请求指纹
+	Done        bool      `gorm:"not null;default:false" json:"done"`               // 是否已完成
+	StatusCode  int       `gorm:"not null;default:0" json:"status_code"`            // 首次成功响应的状态码
+	ContentType string    `gorm:"not null;size:255;default:''" json:"content_type"` // 首次成功响应的 Content-Type
+	Body        []byte    `gorm:"type:mediumblob" json:"-"`                         // 首次成功响应的响应体
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expires_at"`                 // 过期时间
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsExpired 检查幂等键在 now 时刻是否已过期
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 周期转账频率
+const (
+	CadenceDaily   = "daily"
+	CadenceWeekly  = "weekly"
+	CadenceMonthly = "monthly"
+)
+
+// 周期转账规则状态
+const (
+	// RecurringTransferActive 生效中，每次执行后生成下一次
+	RecurringTransferActive = "active"
+
+	// RecurringTransferCompleted 已到结束日期，不再生成
+	RecurringTransferCompleted = "completed"
+
+	// RecurringTransferCancelled 被用户取消
+	RecurringTransferCancelled = "cancelled"
+)
+
+// RecurringTransfer 周期转账规则 (定期委托) - 对应 recurring_transfers 表
+//
+// 用途: 按固定频率从同一个源账户重复转账，例如每月 1 日交房租
+//
+// 执行方式:
+//   - 规则本身不执行转账，而是每次生成一条 ScheduledTransfer (RecurringTransferID 指向规则)，
+//     由定时转账的后台任务执行
+//   - 创建规则时生成第 0 次；每次执行结束 (无论成功或失败) 后生成下一次，
+//     OccurrenceCount 记录已生成的次数
+//   - 某一次因余额不足等原因失败时，该次 ScheduledTransfer 标记为 failed，规则继续生效
+//
+// 业务规则:
+//   - 第 n 次的执行时间由 StartAt 和 n 计算 (见 Occurrence)，不依赖上一次的实际执行时间
+//   - EndAt 不为空时，执行时间晚于 EndAt 的次数不再生成，规则标记为 completed
+type RecurringTransfer struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	PublicID        uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex" json:"public_id"` // 公开ID
+	Owner           string     `gorm:"not null;index;size:255" json:"owner"`                // 创建者(用户名)
+	FromAccountID   uint       `gorm:"not null;index" json:"from_account_id"`               // 转出账户ID
+	ToAccountID     uint       `gorm:"not null" json:"to_account_id"`                       // 转入账户ID
+	Amount          int64      `gorm:"not null" json:"amount"`                              // 每次转账金额(单位:分)
+	Currency        string     `gorm:"not null;size:3" json:"currency"`                     // 货币类型
+	Cadence         string     `gorm:"not null;size:16" json:"cadence"`                     // 频率 (见 Cadence* 常量)
+	StartAt         time.Time  `gorm:"not null" json:"start_at"`                            // 第一次执行时间
+	EndAt           *time.Time `json:"end_at,omitempty"`                                    // 结束时间 (含)，为空表示不结束
+	OccurrenceCount int        `gorm:"not null;default:0" json:"occurrence_count"`          // 已生成的次数
+	Status          string     `gorm:"not null;size:16;default:active" json:"status"`       // 状态 (见 RecurringTransfer* 常量)
+	CreatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (RecurringTransfer) TableName() string {
+	return "recurring_transfers"
+}
+
+// BeforeCreate GORM 钩子: 创建前生成公开ID (已设置时保留)
+func (t *RecurringTransfer) BeforeCreate(tx *gorm.DB) error {
+	if t.PublicID != uuid.Nil {
+		return nil
+	}
+	publicID, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	t.PublicID = publicID
+	return nil
+}
+
+// Occurrence 返回第 n 次 (从 0 开始) 的计划执行时间
+//
+// 按月执行时以 StartAt 的日期为准，目标月份没有这一天时取该月最后一天，
+// 例如从 1 月 31 日开始: 2 月 28/29 日、3 月 31 日、4 月 30 日...
+// 月末的调整不会累积到之后的月份
+func (t *RecurringTransfer) Occurrence(n int) time.Time {
+	switch t.Cadence {
+	case CadenceDaily:
+		return t.StartAt.AddDate(0, 0, n)
+	case CadenceWeekly:
+		return t.StartAt.AddDate(0, 0, 7*n)
+	default:
+		return addMonthsClamped(t.StartAt, n)
+	}
+}
+
+// addMonthsClamped 增加 months 个月，日期超出目标月份天数时取该月最后一天
+// (time.AddDate 会把 1 月 31 日 + 1 个月规范化为 3 月 3 日)
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	day = min(day, lastDay)
+
+	hour, minute, sec := t.Clock()
+	return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
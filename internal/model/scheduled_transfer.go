@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 定时转账状态
+const (
+	// ScheduledTransferPending 等待执行，可以取消
+	ScheduledTransferPending = "pending"
+
+	// ScheduledTransferProcessing 已被后台任务领取，正在执行
+	ScheduledTransferProcessing = "processing"
+
+	// ScheduledTransferExecuted 执行成功，TransferID 指向生成的转账
+	ScheduledTransferExecuted = "executed"
+
+	// ScheduledTransferFailed 执行失败，FailureReason 记录原因
+	ScheduledTransferFailed = "failed"
+
+	// ScheduledTransferCancelled 执行前被用户取消
+	ScheduledTransferCancelled = "cancelled"
+)
+
+// ScheduledTransfer 定时转账模型 - 对应 scheduled_transfers 表
+//
+// 用途: 保存用户预约在未来某个时间执行的转账
+//
+// 状态流转:
+//
+//	pending → processing → executed / failed
+//	pending → cancelled
+//
+// 业务规则:
+//   - 创建时只校验请求格式和账户所有权，余额、限额等在执行时重新校验
+//   - 只有 pending 状态可以取消；后台任务领取后 (processing) 不能再取消
+//   - 状态变更使用条件更新 (WHERE status = ?)，避免取消和执行并发时重复处理
+//   - RecurringTransferID 不为空时是周期转账规则生成的某一次 (见 RecurringTransfer)
+type ScheduledTransfer struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	PublicID            uuid.UUID  `gorm:"type:char(36);not null;uniqueIndex" json:"public_id"`                                         // 公开ID
+	Owner               string     `gorm:"not null;index;size:255" json:"owner"`                                                        // 创建者(用户名)
+	FromAccountID       uint       `gorm:"not null;index" json:"from_account_id"`                                                       // 转出账户ID
+	ToAccountID         uint       `gorm:"not null" json:"to_account_id"`                                                               // 转入账户ID
+	Amount              int64      `gorm:"not null" json:"amount"`                                                                      // 转账金额(单位:分)
+	Currency            string     `gorm:"not null;size:3" json:"currency"`                                                             // 货币类型
+	ExecuteAt           time.Time  `gorm:"not null;index:idx_scheduled_transfers_due,priority:2" json:"execute_at"`                     // 计划执行时间
+	Status              string     `gorm:"not null;size:16;default:pending;index:idx_scheduled_transfers_due,priority:1" json:"status"` // 状态 (见 ScheduledTransfer* 常量)
+	RecurringTransferID *uint      `gorm:"index" json:"recurring_transfer_id,omitempty"`                                                // 由周期转账规则生成时指向规则
+	TransferID          *uint      `json:"transfer_id,omitempty"`                                                                       // 执行成功后生成的转账
+	FailureReason       string     `gorm:"size:255;not null;default:''" json:"failure_reason,omitempty"`                                // 执行失败原因
+	ExecutedAt          *time.Time `json:"executed_at,omitempty"`                                                                       // 实际执行(或失败)时间
+	CreatedAt           time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ScheduledTransfer) TableName() string {
+	return "scheduled_transfers"
+}
+
+// BeforeCreate GORM 钩子: 创建前生成公开ID (已设置时保留)
+func (t *ScheduledTransfer) BeforeCreate(tx *gorm.DB) error {
+	if t.PublicID != uuid.Nil {
+		return nil
+	}
+	publicID, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	t.PublicID = publicID
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Session 会话模型 - 对应 sessions 表
@@ -11,23 +12,25 @@ import (
 // 用途: 存储 JWT Refresh Token，实现 token 轮换和会话管理
 //
 // 工作原理:
-//   1. 用户登录成功后，创建一个 Session 记录
-//   2. Session.ID 作为 Refresh Token 的 payload
-//   3. 刷新 token 时，验证 Session 是否存在且未被封禁
-//   4. 用户登出时，删除或封禁对应的 Session
+//  1. 用户登录成功后，创建一个 Session 记录
+//  2. Session.ID 作为 Refresh Token 的 payload
+//  3. 刷新 token 时，验证 Session 是否存在且未被封禁
+//  4. 用户登出时，删除或封禁对应的 Session
 //
 // 安全特性:
 //   - IsBlocked: 可以手动封禁某个会话(如检测到异常登录)
 //   - UserAgent/ClientIP: 用于审计和异常检测
 //   - ExpiresAt: 自动过期，需要定期清理过期记录
+//   - LastUsedAt: 最后一次使用时间，用于空闲超时 (自动登出)
 type Session struct {
-	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Username     string    `gorm:"not null;index;size:255" json:"username"`       // 关联的用户名
-	RefreshToken string    `gorm:"not null;size:512" json:"-"`                    // Refresh Token (不输出到JSON)
-	UserAgent    string    `gorm:"not null;size:255;default:''" json:"user_agent"` // 客户端标识
-	ClientIP     string    `gorm:"not null;size:45;default:''" json:"client_ip"`   // 客户端IP
-	IsBlocked    bool      `gorm:"not null;default:false" json:"is_blocked"`       // 是否被封禁
-	ExpiresAt    time.Time `gorm:"not null" json:"expires_at"`                     // 过期时间
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Username     string    `gorm:"not null;index;size:255" json:"username"`                // 关联的用户名
+	RefreshToken string    `gorm:"not null;size:512" json:"-"`                             // Refresh Token (不输出到JSON)
+	UserAgent    string    `gorm:"not null;size:255;default:''" json:"user_agent"`         // 客户端标识
+	ClientIP     string    `gorm:"not null;size:45;default:''" json:"client_ip"`           // 客户端IP
+	IsBlocked    bool      `gorm:"not null;default:false" json:"is_blocked"`               // 是否被封禁
+	ExpiresAt    time.Time `gorm:"not null" json:"expires_at"`                             // 过期时间
+	LastUsedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"last_used_at"` // 最后使用时间
 	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 
 	// 关联关系
@@ -39,11 +42,36 @@ func (Session) TableName() string {
 	return "sessions"
 }
 
+// BeforeCreate GORM 钩子: ID 未设置时生成随机 UUID
+//
+// 正常流程中 ID 由 Service 设置为 Refresh Token 的 ID，这里只是兜底，
+// 避免插入全零 UUID 导致主键冲突
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID != uuid.Nil {
+		return nil
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	s.ID = id
+	return nil
+}
+
 // IsExpired 检查会话是否已过期
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
 
+// IsIdle 检查会话在 now 时刻是否已空闲超过 timeout
+// timeout <= 0 表示不启用空闲超时
+func (s *Session) IsIdle(now time.Time, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return false
+	}
+	return now.Sub(s.LastUsedAt) > timeout
+}
+
 // IsValid 检查会话是否有效 (未过期且未封禁)
 func (s *Session) IsValid() bool {
 	return !s.IsExpired() && !s.IsBlocked
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// TimeRange 时间范围过滤条件 [From, To)
+// 零值表示该端不限制
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
@@ -1,9 +1,18 @@
 package model
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// TransferReferencePrefix 转账参考号前缀
+const TransferReferencePrefix = "TR"
+
 // Transfer 转账记录模型 - 对应 transfers 表
 //
 // 用途: 记录账户间的转账操作
@@ -12,18 +21,25 @@ import (
 //   - Amount 必须为正数
 //   - FromAccountID 和 ToAccountID 必须不同
 //   - 两个账户的货币类型必须相同
+//   - Reference 是面向用户的参考号，全局唯一 (由唯一索引保证)
+//   - PublicID 是 URL 中使用的公开ID，ID 只用于内部关联
+//   - ReversalOf 不为空表示这是对另一笔转账的撤销 (方向相反、金额相同)，
+//     每笔转账最多被撤销一次 (由唯一索引保证)
 //
 // 转账流程:
-//   1. 检查转出账户余额充足
-//   2. 创建 Transfer 记录
-//   3. 创建两条 Entry 记录 (一出一入)
-//   4. 更新两个账户余额
-//   以上操作在一个数据库事务中完成
+//  1. 检查转出账户余额充足
+//  2. 创建 Transfer 记录
+//  3. 创建两条 Entry 记录 (一出一入)
+//  4. 更新两个账户余额
+//     以上操作在一个数据库事务中完成
 type Transfer struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
-	FromAccountID uint      `gorm:"not null;index" json:"from_account_id"` // 转出账户ID
-	ToAccountID   uint      `gorm:"not null;index" json:"to_account_id"`   // 转入账户ID
-	Amount        int64     `gorm:"not null" json:"amount"`                // 转账金额(必须>0)
+	PublicID      uuid.UUID `gorm:"type:char(36);not null;uniqueIndex" json:"public_id"` // 公开ID
+	Reference     string    `gorm:"not null;uniqueIndex;size:32" json:"reference"`       // 转账参考号(用户可见)
+	FromAccountID uint      `gorm:"not null;index" json:"from_account_id"`               // 转出账户ID
+	ToAccountID   uint      `gorm:"not null;index" json:"to_account_id"`                 // 转入账户ID
+	Amount        int64     `gorm:"not null" json:"amount"`                              // 转账金额(必须>0)
+	ReversalOf    *uint     `gorm:"uniqueIndex" json:"reversal_of,omitempty"`            // 被撤销的原转账ID
 	CreatedAt     time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 
 	// 关联关系
@@ -35,3 +51,33 @@ type Transfer struct {
 func (Transfer) TableName() string {
 	return "transfers"
 }
+
+// BeforeCreate GORM 钩子: 创建前生成公开ID和转账参考号 (已设置时保留)
+func (t *Transfer) BeforeCreate(tx *gorm.DB) error {
+	if t.PublicID == uuid.Nil {
+		publicID, err := uuid.NewRandom()
+		if err != nil {
+			return err
+		}
+		t.PublicID = publicID
+	}
+	if t.Reference != "" {
+		return nil
+	}
+	reference, err := NewTransferReference()
+	if err != nil {
+		return err
+	}
+	t.Reference = reference
+	return nil
+}
+
+// NewTransferReference 生成一个随机的转账参考号
+// 格式: TR + 16 位大写十六进制字符，例如 TR3F9A0C1B7D2E4F60
+func NewTransferReference() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return TransferReferencePrefix + strings.ToUpper(hex.EncodeToString(buf)), nil
+}
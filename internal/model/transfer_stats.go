@@ -0,0 +1,24 @@
+package model
+
+// TransferStats 一个账户在时间范围内的转账统计 (SQL 聚合的结果)
+// 金额单位均为分
+type TransferStats struct {
+	TotalSent     int64 // 转出总额
+	SentCount     int64 // 转出笔数
+	TotalReceived int64 // 转入总额
+	ReceivedCount int64 // 转入笔数
+	Largest       int64 // 单笔最大金额 (转入或转出)，没有转账时为 0
+}
+
+// Count 返回转账总笔数 (转入 + 转出)
+func (s *TransferStats) Count() int64 {
+	return s.SentCount + s.ReceivedCount
+}
+
+// Average 返回单笔平均金额 (向下取整)，没有转账时为 0
+func (s *TransferStats) Average() int64 {
+	if s.Count() == 0 {
+		return 0
+	}
+	return (s.TotalSent + s.TotalReceived) / s.Count()
+}
@@ -13,6 +13,7 @@ import (
 // 重要字段说明:
 //   - HashedPassword: 存储 bcrypt 加密后的密码，永远不要存储明文密码
 //   - PasswordChangedAt: 用于强制用户在密码修改后重新登录
+//   - Role: 用户角色 (user/admin)，会写入 Token 用于权限控制
 //
 // 关联关系:
 //   - User 1:N Accounts (一个用户可以有多个账户)
@@ -23,6 +24,7 @@ type User struct {
 	HashedPassword    string         `gorm:"not null;size:255" json:"-"` // json:"-" 不输出到 JSON
 	FullName          string         `gorm:"not null;size:255" json:"full_name"`
 	Email             string         `gorm:"uniqueIndex;not null;size:255" json:"email"`
+	Role              string         `gorm:"not null;size:32;default:user" json:"role"`
 	PasswordChangedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"password_changed_at"`
 	CreatedAt         time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt         time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
@@ -33,6 +35,15 @@ type User struct {
 	Sessions []Session `gorm:"foreignKey:Username;references:Username" json:"-"`
 }
 
+// 用户角色
+const (
+	// RoleUser 普通用户
+	RoleUser = "user"
+
+	// RoleAdmin 管理员
+	RoleAdmin = "admin"
+)
+
 // TableName 指定表名 (GORM 默认会将 User 转为 users)
 func (User) TableName() string {
 	return "users"
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"sort"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
 	"github.com/proyuen/simple-bank-v2/internal/model"
@@ -20,14 +22,20 @@ func NewAccountRepository(db *gorm.DB) *AccountRepository {
 	return &AccountRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的 AccountRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *AccountRepository) WithTx(tx *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: tx}
+}
+
 // Create 创建新账户
 func (r *AccountRepository) Create(ctx context.Context, account *model.Account) error {
-	result := r.db.WithContext(ctx).Create(account)
+	result := conn(ctx, r.db).Create(account)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		if isDuplicateKey(result.Error) {
 			return apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "account with this currency already exists")
 		}
-		return apperrors.ErrDatabase(result.Error)
+		return wrapDBError(result.Error, nil)
 	}
 	return nil
 }
@@ -35,86 +43,369 @@ func (r *AccountRepository) Create(ctx context.Context, account *model.Account)
 // GetByID 根据ID查询账户
 func (r *AccountRepository) GetByID(ctx context.Context, id uint) (*model.Account, error) {
 	var account model.Account
-	result := r.db.WithContext(ctx).First(&account, id)
+	result := conn(ctx, r.db).First(&account, id)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrAccountNotFound()
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrAccountNotFound())
+	}
+	return &account, nil
+}
+
+// ExistsOwnedBy 检查账户是否存在且属于 owner
+// 只执行 SELECT 1 ... LIMIT 1，不读取整行，用于只需要鉴权、不需要账户数据的场景
+// 软删除的账户视为不存在
+func (r *AccountRepository) ExistsOwnedBy(ctx context.Context, id uint, owner string) (bool, error) {
+	var found int
+	result := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Select("1").
+		Where("id = ? AND owner = ?", id, owner).
+		Limit(1).
+		Scan(&found)
+	if result.Error != nil {
+		return false, apperrors.ErrDatabase(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByIDs 用一次 IN 查询批量读取账户，返回 账户ID → 账户
+// 不存在 (或已删除) 的 ID 不出现在结果中，由调用方决定如何处理
+func (r *AccountRepository) GetByIDs(ctx context.Context, ids []uint) (map[uint]*model.Account, error) {
+	accounts := make(map[uint]*model.Account, len(ids))
+	if len(ids) == 0 {
+		return accounts, nil
+	}
+
+	var found []model.Account
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range found {
+		accounts[found[i].ID] = &found[i]
+	}
+	return accounts, nil
+}
+
+// GetByPublicIDs 用一次 IN 查询按公开ID批量读取账户，返回 公开ID → 账户
+// 不存在 (或已删除) 的公开ID不出现在结果中，由调用方决定如何处理
+func (r *AccountRepository) GetByPublicIDs(ctx context.Context, publicIDs []uuid.UUID) (map[uuid.UUID]*model.Account, error) {
+	accounts := make(map[uuid.UUID]*model.Account, len(publicIDs))
+	if len(publicIDs) == 0 {
+		return accounts, nil
+	}
+
+	var found []model.Account
+	if err := conn(ctx, r.db).Where("public_id IN ?", publicIDs).Find(&found).Error; err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for i := range found {
+		accounts[found[i].PublicID] = &found[i]
+	}
+	return accounts, nil
+}
+
+// PublicIDs 批量查询账户的公开ID，返回 账户ID → 公开ID
+// 包括已关闭的账户，用于在历史记录 (转账、定时转账) 的响应中引用账户
+func (r *AccountRepository) PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error) {
+	return publicIDs(conn(ctx, r.db).Unscoped().Model(&model.Account{}), ids)
+}
+
+// GetByPublicID 根据公开ID查询账户
+func (r *AccountRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Account, error) {
+	var account model.Account
+	result := conn(ctx, r.db).Where("public_id = ?", publicID).First(&account)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrAccountNotFound())
+	}
+	return &account, nil
+}
+
+// GetByNumber 根据账号查询账户
+func (r *AccountRepository) GetByNumber(ctx context.Context, number string) (*model.Account, error) {
+	var account model.Account
+	result := conn(ctx, r.db).Where("number = ?", number).First(&account)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrAccountNotFound())
 	}
 	return &account, nil
 }
 
 // GetByOwnerAndCurrency 根据所有者和货币类型查询账户
+// 只返回未删除的账户 (GORM 默认附加 deleted_at IS NULL)，
+// 与唯一索引的语义一致: 软删除的账户不阻止重新开立同币种账户
 func (r *AccountRepository) GetByOwnerAndCurrency(ctx context.Context, owner, currency string) (*model.Account, error) {
 	var account model.Account
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Where("owner = ? AND currency = ?", owner, currency).
 		First(&account)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrAccountNotFound()
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrAccountNotFound())
 	}
 	return &account, nil
 }
 
+// CountByOwner 统计用户未关闭的账户数
+func (r *AccountRepository) CountByOwner(ctx context.Context, owner string) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Where("owner = ?", owner).
+		Count(&count).Error; err != nil {
+		return 0, apperrors.ErrDatabase(err)
+	}
+	return count, nil
+}
+
 // ListByOwner 获取用户的所有账户 (带分页)
-func (r *AccountRepository) ListByOwner(ctx context.Context, owner string, limit, offset int) ([]model.Account, int64, error) {
-	var accounts []model.Account
-	var total int64
+// currency 不为空时只返回该货币的账户
+// sort 支持 id、created_at、balance (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *AccountRepository) ListByOwner(ctx context.Context, owner, currency, sort string, limit, offset int) ([]model.Account, int64, error) {
+	order, err := sortOrder(sort, "id", "created_at", "balance")
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if err := r.db.WithContext(ctx).
+	query := conn(ctx, r.db).
 		Model(&model.Account{}).
-		Where("owner = ?", owner).
-		Count(&total).Error; err != nil {
-		return nil, 0, apperrors.ErrDatabase(err)
+		Where("owner = ?", owner)
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
 	}
 
-	if err := r.db.WithContext(ctx).
-		Where("owner = ?", owner).
-		Order("id DESC").
+	return paginate[model.Account](query, order, limit, offset)
+}
+
+// ListAfterID 按 ID 升序返回 ID 大于 afterID 的账户，最多 limit 个
+// 用于遍历全部账户 (键集分页)，不受 OFFSET 翻页越往后越慢的影响
+func (r *AccountRepository) ListAfterID(ctx context.Context, afterID uint, limit int) ([]model.Account, error) {
+	var accounts []model.Account
+	if err := conn(ctx, r.db).
+		Where("id > ?", afterID).
+		Order("id ASC").
 		Limit(limit).
-		Offset(offset).
 		Find(&accounts).Error; err != nil {
-		return nil, 0, apperrors.ErrDatabase(err)
+		return nil, apperrors.ErrDatabase(err)
 	}
+	return accounts, nil
+}
 
-	return accounts, total, nil
+// SumByOwnerGroupedByCurrency 按货币汇总用户的账户余额和账户数，按货币代码排序
+func (r *AccountRepository) SumByOwnerGroupedByCurrency(ctx context.Context, owner string) ([]model.CurrencyBalance, error) {
+	var sums []model.CurrencyBalance
+	result := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Select("currency, COALESCE(SUM(balance), 0) AS total_balance, COUNT(*) AS account_count").
+		Where("owner = ?", owner).
+		Group("currency").
+		Order("currency").
+		Scan(&sums)
+	if result.Error != nil {
+		return nil, apperrors.ErrDatabase(result.Error)
+	}
+	return sums, nil
 }
 
 // GetForUpdate 获取账户并锁定 (FOR UPDATE)
+// 必须在事务中调用，行锁持续到事务结束；已软删除的账户视为不存在
 func (r *AccountRepository) GetForUpdate(ctx context.Context, id uint) (*model.Account, error) {
 	var account model.Account
-	result := r.db.WithContext(ctx).
-		Raw("SELECT * FROM accounts WHERE id = ? FOR UPDATE", id).
-		Scan(&account)
+	result := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		First(&account, id)
 	if result.Error != nil {
-		return nil, apperrors.ErrDatabase(result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return nil, apperrors.ErrAccountNotFound()
+		return nil, wrapDBError(result.Error, apperrors.ErrAccountNotFound())
 	}
 	return &account, nil
 }
 
 // UpdateBalance 更新账户余额
+// 扣款 (amount < 0) 时余额不能低于 min_balance - overdraft_limit，否则返回余额不足
 func (r *AccountRepository) UpdateBalance(ctx context.Context, id uint, amount int64) (*model.Account, error) {
 	var account model.Account
 
-	result := r.db.WithContext(ctx).
+	if err := r.addBalance(ctx, id, amount); err != nil {
+		return nil, err
+	}
+
+	if err := conn(ctx, r.db).First(&account, id).Error; err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+
+	return &account, nil
+}
+
+// SetBalance 直接设置账户余额，不检查透支额度和最低余额
+// 只用于对账修复: 调用方应在事务中先用 GetForUpdate 锁定账户，再根据账目重新计算余额
+func (r *AccountRepository) SetBalance(ctx context.Context, id uint, balance int64) (*model.Account, error) {
+	result := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Where("id = ?", id).
+		Update("balance", balance)
+	if result.Error != nil {
+		return nil, apperrors.ErrDatabase(result.Error)
+	}
+
+	// 新值与旧值相同时 MySQL 的 RowsAffected 为 0，统一通过查询确认账户是否存在
+	return r.GetByID(ctx, id)
+}
+
+// SetOverdraftLimit 设置账户透支额度
+func (r *AccountRepository) SetOverdraftLimit(ctx context.Context, id uint, limit int64) (*model.Account, error) {
+	result := conn(ctx, r.db).
 		Model(&model.Account{}).
 		Where("id = ?", id).
-		Update("balance", gorm.Expr("balance + ?", amount))
+		Update("overdraft_limit", limit)
+	if result.Error != nil {
+		return nil, apperrors.ErrDatabase(result.Error)
+	}
+
+	// 新值与旧值相同时 MySQL 的 RowsAffected 为 0，统一通过查询确认账户是否存在
+	return r.GetByID(ctx, id)
+}
 
+// SetMinBalance 设置账户最低余额
+func (r *AccountRepository) SetMinBalance(ctx context.Context, id uint, minBalance int64) (*model.Account, error) {
+	result := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Where("id = ?", id).
+		Update("min_balance", minBalance)
 	if result.Error != nil {
 		return nil, apperrors.ErrDatabase(result.Error)
 	}
 
-	if err := r.db.WithContext(ctx).First(&account, id).Error; err != nil {
-		return nil, apperrors.ErrDatabase(err)
+	// 新值与旧值相同时 MySQL 的 RowsAffected 为 0，统一通过查询确认账户是否存在
+	return r.GetByID(ctx, id)
+}
+
+// SetFrozen 冻结或解冻账户
+func (r *AccountRepository) SetFrozen(ctx context.Context, id uint, frozen bool) (*model.Account, error) {
+	result := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Where("id = ?", id).
+		Update("is_frozen", frozen)
+	if result.Error != nil {
+		return nil, apperrors.ErrDatabase(result.Error)
 	}
 
-	return &account, nil
+	// 新值与旧值相同时 MySQL 的 RowsAffected 为 0，统一通过查询确认账户是否存在
+	return r.GetByID(ctx, id)
+}
+
+// SetName 修改账户名称
+func (r *AccountRepository) SetName(ctx context.Context, id uint, name string) (*model.Account, error) {
+	result := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Where("id = ?", id).
+		Update("name", name)
+	if result.Error != nil {
+		return nil, apperrors.ErrDatabase(result.Error)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Restore 恢复已软删除 (关闭) 的账户
+//
+// 账户未关闭时返回 CodeStateConflict
+// 关闭期间同一用户已创建了同币种的账户时，唯一索引 idx_accounts_owner_currency_active
+// 冲突，返回 CodeAlreadyExists
+func (r *AccountRepository) Restore(ctx context.Context, publicID uuid.UUID) (*model.Account, error) {
+	var account model.Account
+	result := conn(ctx, r.db).Unscoped().Where("public_id = ?", publicID).First(&account)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrAccountNotFound())
+	}
+	if !account.DeletedAt.Valid {
+		return nil, apperrors.NewWithMessage(apperrors.CodeStateConflict, "account is not closed")
+	}
+
+	result = conn(ctx, r.db).
+		Unscoped().
+		Model(&model.Account{}).
+		Where("id = ? AND deleted_at IS NOT NULL", account.ID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return nil, apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "account with this currency already exists")
+		}
+		return nil, wrapDBError(result.Error, nil)
+	}
+	// 并发恢复时只有一个请求能更新成功
+	if result.RowsAffected == 0 {
+		return nil, apperrors.NewWithMessage(apperrors.CodeStateConflict, "account is not closed")
+	}
+
+	return r.GetByID(ctx, account.ID)
+}
+
+// addBalance 对单个账户执行条件更新 balance = balance + amount
+//
+// 扣款时在 WHERE 中检查 balance + amount >= -overdraft_limit，
+// 由数据库保证并发扣款不会超出透支额度；入账不受限制
+// 未更新任何行时区分账户不存在和余额不足
+func (r *AccountRepository) addBalance(ctx context.Context, id uint, amount int64) error {
+	query := conn(ctx, r.db).
+		Model(&model.Account{}).
+		Where("id = ?", id)
+	if amount < 0 {
+		query = query.Where("balance + ? >= min_balance - overdraft_limit", amount)
+	}
+
+	result := query.Update("balance", gorm.Expr("balance + ?", amount))
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		account, err := r.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		// amount 为 0 时值未变化，MySQL 同样返回 0 行
+		if amount < 0 {
+			return insufficientBalance(account)
+		}
+	}
+	return nil
+}
+
+// UpdateBalances 批量更新多个账户余额
+//
+// deltas 为 账户ID → 净变动金额，调用方应先把同一账户的多笔变动合并
+// 更新按账户ID升序执行以避免死锁，净变动为 0 的账户不会发出 UPDATE
+// 扣款超出透支额度或低于最低余额时返回余额不足，调用方应回滚事务
+// 返回更新后的账户 (包含净变动为 0 的账户)，只用一次查询读取
+func (r *AccountRepository) UpdateBalances(ctx context.Context, deltas map[uint]int64) (map[uint]*model.Account, error) {
+	ids := make([]uint, 0, len(deltas))
+	for id := range deltas {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		amount := deltas[id]
+		if amount == 0 {
+			continue
+		}
+
+		if err := r.addBalance(ctx, id, amount); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := r.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(updated) != len(ids) {
+		return nil, apperrors.ErrAccountNotFound()
+	}
+	return updated, nil
+}
+
+// insufficientBalance 返回扣款失败的原因
+// 账户要求最低余额时提示会低于最低余额，否则为普通的余额不足
+func insufficientBalance(account *model.Account) *apperrors.AppError {
+	if account.MinBalance > 0 {
+		return apperrors.ErrMinBalanceBreach()
+	}
+	return apperrors.ErrInsufficientBalance()
 }
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestUpdateBalancesAppliesNetDeltasInIDOrder(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAccountRepository(db)
+
+	// 账户 3 扣款 (条件更新)，账户 1 入账，账户 2 的净变动为 0 不发出 UPDATE
+	// 按账户ID升序更新，避免两个事务以相反顺序加锁
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `accounts` SET `balance`=balance + ?")).
+		WithArgs(int64(700), sqlmock.AnyArg(), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `accounts` SET `balance`=balance + ?")+".*"+regexp.QuoteMeta("balance + ? >= min_balance - overdraft_limit")).
+		WithArgs(int64(-700), sqlmock.AnyArg(), uint(3), int64(-700)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `accounts` WHERE id IN (?,?,?)")).
+		WithArgs(uint(1), uint(2), uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).
+			AddRow(1, 700).AddRow(2, 100).AddRow(3, 300))
+
+	updated, err := repo.UpdateBalances(context.Background(), map[uint]int64{3: -700, 1: 700, 2: 0})
+	if err != nil {
+		t.Fatalf("UpdateBalances: %v", err)
+	}
+	if len(updated) != 3 || updated[1].Balance != 700 || updated[3].Balance != 300 {
+		t.Errorf("updated = %v", updated)
+	}
+}
+
+func TestContextErrorsMapToRequestCodes(t *testing.T) {
+	db, _ := newMockDB(t)
+	repo := NewAccountRepository(db)
+
+	// 客户端断开: 查询在发给数据库之前就因 Context 已取消而失败
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repo.GetByIDs(canceled, []uint{1})
+	if appErr := apperrors.AsAppError(err); appErr.Code != apperrors.CodeClientClosed || appErr.HTTPStatus != 499 {
+		t.Errorf("canceled context = %d (%d), want CodeClientClosed (499)", appErr.Code, appErr.HTTPStatus)
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = repo.GetByIDs(expired, []uint{1})
+	if appErr := apperrors.AsAppError(err); appErr.Code != apperrors.CodeRequestTimeout || appErr.HTTPStatus != http.StatusGatewayTimeout {
+		t.Errorf("expired context = %d (%d), want CodeRequestTimeout (504)", appErr.Code, appErr.HTTPStatus)
+	}
+}
+
+func TestGetByOwnerAndCurrencyExcludesClosedAccounts(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAccountRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE (owner = ? AND currency = ?) AND `accounts`.`deleted_at` IS NULL")).
+		WithArgs("alice", "USD", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByOwnerAndCurrency(context.Background(), "alice", "USD")
+	if appErr := apperrors.AsAppError(err); appErr.Code != apperrors.CodeAccountNotFound {
+		t.Errorf("error = %v, want CodeAccountNotFound", err)
+	}
+}
+
+func TestGetForUpdateLocksLiveRow(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAccountRepository(db)
+
+	query := regexp.QuoteMeta("SELECT * FROM `accounts` WHERE `accounts`.`id` = ? AND `accounts`.`deleted_at` IS NULL ORDER BY `accounts`.`id` LIMIT ? FOR UPDATE")
+	mock.ExpectQuery(query).
+		WithArgs(uint(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow(7, 500))
+	mock.ExpectQuery(query).
+		WithArgs(uint(8), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	account, err := repo.GetForUpdate(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetForUpdate: %v", err)
+	}
+	if account.ID != 7 || account.Balance != 500 {
+		t.Errorf("account = %+v", account)
+	}
+
+	// 不存在或已关闭的账户
+	_, err = repo.GetForUpdate(context.Background(), 8)
+	if appErr := apperrors.AsAppError(err); appErr.Code != apperrors.CodeAccountNotFound {
+		t.Errorf("error = %v, want CodeAccountNotFound", err)
+	}
+}
+
+func TestRestoreAccount(t *testing.T) {
+	publicID := uuid.New()
+	closedRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "public_id", "owner", "currency", "deleted_at"}).
+			AddRow(5, publicID.String(), "alice", "USD", time.Now())
+	}
+	selectClosed := regexp.QuoteMeta("SELECT * FROM `accounts` WHERE public_id = ?")
+	restore := regexp.QuoteMeta("UPDATE `accounts` SET `deleted_at`=?,`updated_at`=? WHERE id = ? AND deleted_at IS NOT NULL")
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery(selectClosed).WithArgs(publicID, 1).WillReturnRows(closedRow())
+		mock.ExpectBegin()
+		mock.ExpectExec(restore).WithArgs(nil, sqlmock.AnyArg(), uint(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `accounts` WHERE `accounts`.`id` = ? AND `accounts`.`deleted_at` IS NULL")).
+			WithArgs(uint(5), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "owner", "currency"}).
+				AddRow(5, publicID.String(), "alice", "USD"))
+
+		account, err := NewAccountRepository(db).Restore(context.Background(), publicID)
+		if err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		if account.ID != 5 || account.DeletedAt.Valid {
+			t.Errorf("restored account = %+v", account)
+		}
+	})
+
+	t.Run("currency taken meanwhile", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery(selectClosed).WithArgs(publicID, 1).WillReturnRows(closedRow())
+		mock.ExpectBegin()
+		mock.ExpectExec(restore).
+			WithArgs(nil, sqlmock.AnyArg(), uint(5)).
+			WillReturnError(&mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry for key 'idx_accounts_owner_currency_active'"})
+		mock.ExpectRollback()
+
+		_, err := NewAccountRepository(db).Restore(context.Background(), publicID)
+		if appErr := apperrors.AsAppError(err); appErr.Code != apperrors.CodeAlreadyExists {
+			t.Errorf("error = %v, want CodeAlreadyExists", err)
+		}
+	})
+}
+
+func TestGetByIDsIssuesSingleQuery(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAccountRepository(db)
+
+	// sqlmock 对没有预期的查询直接返回错误，这里只允许一次 IN 查询
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `accounts` WHERE id IN (?,?,?,?,?) AND `accounts`.`deleted_at` IS NULL")).
+		WithArgs(uint(1), uint(2), uint(3), uint(4), uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).
+			AddRow(1, 100).AddRow(2, 200).AddRow(3, 300).AddRow(5, 500))
+
+	accounts, err := repo.GetByIDs(context.Background(), []uint{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("GetByIDs: %v", err)
+	}
+	// 不存在的 ID 不出现在结果中
+	if len(accounts) != 4 || accounts[3].Balance != 300 || accounts[4] != nil {
+		t.Errorf("accounts = %v", accounts)
+	}
+
+	// 空列表不查询数据库
+	if accounts, err := repo.GetByIDs(context.Background(), nil); err != nil || len(accounts) != 0 {
+		t.Errorf("GetByIDs(nil) = %v, %v", accounts, err)
+	}
+}
+
+func TestAccountCurrencyIsNotUpdated(t *testing.T) {
+	db, _ := newMockDB(t)
+	dryRun := db.Session(&gorm.Session{DryRun: true, SkipDefaultTransaction: true})
+	account := &model.Account{ID: 1, Owner: "alice", Name: "Travel", Currency: "EUR"}
+
+	// Save / Updates 都不会写入 currency 列
+	statements := map[string]*gorm.Statement{
+		"save":    dryRun.Save(account).Statement,
+		"updates": dryRun.Model(account).Updates(map[string]any{"name": "Travel", "currency": "EUR"}).Statement,
+	}
+	for name, stmt := range statements {
+		sql := stmt.SQL.String()
+		if !strings.HasPrefix(sql, "UPDATE `accounts`") || strings.Contains(sql, "`currency`") {
+			t.Errorf("%s SQL = %s, want an UPDATE without currency", name, sql)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// APIKeyRepository API Key 数据访问实现
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository 创建 APIKeyRepository 实例
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的 APIKeyRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *APIKeyRepository) WithTx(tx *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: tx}
+}
+
+// Create 创建 API Key
+func (r *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	if err := conn(ctx, r.db).Create(key).Error; err != nil {
+		return wrapDBError(err, nil)
+	}
+	return nil
+}
+
+// GetByPublicID 根据公开ID查询 API Key
+func (r *APIKeyRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.APIKey, error) {
+	var key model.APIKey
+	result := conn(ctx, r.db).Where("public_id = ?", publicID).First(&key)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("api key"))
+	}
+	return &key, nil
+}
+
+// GetBySecretHash 根据 Key 的哈希查询 API Key (包括已撤销的)
+func (r *APIKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (*model.APIKey, error) {
+	var key model.APIKey
+	result := conn(ctx, r.db).Where("secret_hash = ?", secretHash).First(&key)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("api key"))
+	}
+	return &key, nil
+}
+
+// ListByOwner 获取用户的所有 API Key (带分页，按 ID 降序)
+func (r *APIKeyRepository) ListByOwner(ctx context.Context, owner string, limit, offset int) ([]model.APIKey, int64, error) {
+	query := conn(ctx, r.db).
+		Model(&model.APIKey{}).
+		Where("owner = ?", owner)
+
+	return paginate[model.APIKey](query, "id DESC", limit, offset)
+}
+
+// Revoke 撤销 API Key
+// 已撤销时返回 CodeStateConflict
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&model.APIKey{}).
+		Where("id = ? AND revoked = ?", id, false).
+		Updates(map[string]any{
+			"revoked":    true,
+			"revoked_at": at,
+		})
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "api key is already revoked")
+	}
+	return nil
+}
+
+// Touch 更新最后使用时间
+func (r *APIKeyRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&model.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at)
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	return nil
+}
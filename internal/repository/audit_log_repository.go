@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// AuditLogRepository 审计日志数据访问实现
+// 审计日志只追加，不提供更新和删除方法
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository 创建 AuditLogRepository 实例
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的 AuditLogRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *AuditLogRepository) WithTx(tx *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: tx}
+}
+
+// Create 写入一条审计日志
+func (r *AuditLogRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	result := conn(ctx, r.db).Create(log)
+	if result.Error != nil {
+		return wrapDBError(result.Error, nil)
+	}
+	return nil
+}
+
+// List 查询审计日志 (带分页)
+// actor、action 为空时不作为过滤条件
+func (r *AuditLogRepository) List(ctx context.Context, actor, action string, limit, offset int) ([]model.AuditLog, int64, error) {
+	query := conn(ctx, r.db).Model(&model.AuditLog{})
+	if actor != "" {
+		query = query.Where("actor = ?", actor)
+	}
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	return paginate[model.AuditLog](query, defaultOrder, limit, offset)
+}
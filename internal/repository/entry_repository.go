@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -20,11 +20,17 @@ func NewEntryRepository(db *gorm.DB) *EntryRepository {
 	return &EntryRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的 EntryRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *EntryRepository) WithTx(tx *gorm.DB) *EntryRepository {
+	return &EntryRepository{db: tx}
+}
+
 // Create 创建账目记录
 func (r *EntryRepository) Create(ctx context.Context, entry *model.Entry) error {
-	result := r.db.WithContext(ctx).Create(entry)
+	result := conn(ctx, r.db).Create(entry)
 	if result.Error != nil {
-		return apperrors.ErrDatabase(result.Error)
+		return wrapDBError(result.Error, nil)
 	}
 	return nil
 }
@@ -32,36 +38,194 @@ func (r *EntryRepository) Create(ctx context.Context, entry *model.Entry) error
 // GetByID 根据ID查询账目
 func (r *EntryRepository) GetByID(ctx context.Context, id uint) (*model.Entry, error) {
 	var entry model.Entry
-	result := r.db.WithContext(ctx).First(&entry, id)
+	result := conn(ctx, r.db).First(&entry, id)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrNotFound("entry")
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("entry"))
 	}
 	return &entry, nil
 }
 
 // ListByAccountID 获取账户的所有账目 (带分页)
-func (r *EntryRepository) ListByAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.Entry, int64, error) {
-	var entries []model.Entry
+// period 限制创建时间范围 (零值不限制)
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *EntryRepository) ListByAccountID(ctx context.Context, accountID uint, period model.TimeRange, sort string, limit, offset int) ([]model.Entry, int64, error) {
+	order, err := sortOrder(sort, "id", "created_at", "amount")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := r.accountEntries(ctx, accountID, period)
+	return paginate[model.Entry](query, order, limit, offset)
+}
+
+// ListByOwner 获取用户所有未关闭账户的账目 (带分页)，附带所属账户的公开ID和货币
+// period 限制创建时间范围 (零值不限制)
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序 (即最新的在前)
+func (r *EntryRepository) ListByOwner(ctx context.Context, owner string, period model.TimeRange, sort string, limit, offset int) ([]model.AccountEntry, int64, error) {
+	order, err := sortOrder(sort, "id", "created_at", "amount")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := conn(ctx, r.db).
+		Model(&model.Entry{}).
+		Select("entries.*", "accounts.public_id AS account_public_id", "accounts.currency").
+		Joins("JOIN accounts ON accounts.id = entries.account_id AND accounts.deleted_at IS NULL").
+		Where("accounts.owner = ?", owner)
+	if !period.From.IsZero() {
+		query = query.Where("entries.created_at >= ?", period.From)
+	}
+	if !period.To.IsZero() {
+		query = query.Where("entries.created_at < ?", period.To)
+	}
+
+	return paginate[model.AccountEntry](query, qualifyOrder("entries", order), limit, offset)
+}
+
+// ListActivityByAccountID 获取账户的账目及其来源转账和转账另一方账户 (带分页)
+// 按 ID 降序 (即最新的在前)，period 限制创建时间范围 (零值不限制)
+// 另一方账户已关闭时仍然返回其信息
+func (r *EntryRepository) ListActivityByAccountID(ctx context.Context, accountID uint, period model.TimeRange, limit, offset int) ([]model.EntryActivity, int64, error) {
+	query := conn(ctx, r.db).
+		Model(&model.Entry{}).
+		Select("entries.*",
+			"transfers.public_id AS transfer_public_id",
+			"transfers.reference AS transfer_reference",
+			"counterparty.public_id AS counterparty_public_id",
+			"counterparty.owner AS counterparty_owner").
+		Joins("LEFT JOIN transfers ON transfers.id = entries.transfer_id").
+		Joins("LEFT JOIN accounts counterparty ON counterparty.id = "+
+			"CASE WHEN entries.amount < 0 THEN transfers.to_account_id ELSE transfers.from_account_id END").
+		Where("entries.account_id = ?", accountID)
+	if !period.From.IsZero() {
+		query = query.Where("entries.created_at >= ?", period.From)
+	}
+	if !period.To.IsZero() {
+		query = query.Where("entries.created_at < ?", period.To)
+	}
+
+	return paginate[model.EntryActivity](query, qualifyOrder("entries", defaultOrder), limit, offset)
+}
+
+// SumThroughID 统计账户 ID 不大于 id 的所有账目的金额之和 (即该账目入账后的余额)
+func (r *EntryRepository) SumThroughID(ctx context.Context, accountID, id uint) (int64, error) {
 	var total int64
+	if err := conn(ctx, r.db).
+		Model(&model.Entry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account_id = ? AND id <= ?", accountID, id).
+		Scan(&total).Error; err != nil {
+		return 0, apperrors.ErrDatabase(err)
+	}
+	return total, nil
+}
 
-	if err := r.db.WithContext(ctx).
+// StreamByAccountID 按 ID 升序逐条读取账户的账目并交给 fn 处理 (不分页)
+//
+// 使用数据库游标逐行扫描，不会一次性把所有记录加载到内存，适合导出大账户的账单
+// fn 返回错误时停止读取并返回该错误
+func (r *EntryRepository) StreamByAccountID(ctx context.Context, accountID uint, period model.TimeRange, fn func(entry *model.Entry) error) error {
+	rows, err := r.accountEntries(ctx, accountID, period).Order("id ASC").Rows()
+	if err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	defer rows.Close()
+
+	db := conn(ctx, r.db)
+	for rows.Next() {
+		var entry model.Entry
+		if err := db.ScanRows(rows, &entry); err != nil {
+			return apperrors.ErrDatabase(err)
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+	return nil
+}
+
+// SumBeforeDate 统计账户在 before 之前所有账目的金额之和 (即 before 时刻的余额)
+func (r *EntryRepository) SumBeforeDate(ctx context.Context, accountID uint, before time.Time) (int64, error) {
+	var total int64
+	if err := conn(ctx, r.db).
+		Model(&model.Entry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account_id = ? AND created_at < ?", accountID, before).
+		Scan(&total).Error; err != nil {
+		return 0, apperrors.ErrDatabase(err)
+	}
+	return total, nil
+}
+
+// SumByAccountIDs 用一次 GROUP BY 查询统计多个账户全部账目的金额之和
+// 返回 账户ID → 金额之和 (即按账目计算的余额)，没有账目的账户不出现在结果中
+func (r *EntryRepository) SumByAccountIDs(ctx context.Context, accountIDs []uint) (map[uint]int64, error) {
+	totals := make(map[uint]int64, len(accountIDs))
+	if len(accountIDs) == 0 {
+		return totals, nil
+	}
+
+	var rows []struct {
+		AccountID uint
+		Total     int64
+	}
+	if err := conn(ctx, r.db).
 		Model(&model.Entry{}).
-		Where("account_id = ?", accountID).
-		Count(&total).Error; err != nil {
-		return nil, 0, apperrors.ErrDatabase(err)
+		Select("account_id, COALESCE(SUM(amount), 0) AS total").
+		Where("account_id IN ?", accountIDs).
+		Group("account_id").
+		Scan(&rows).Error; err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for _, row := range rows {
+		totals[row.AccountID] = row.Total
 	}
+	return totals, nil
+}
 
-	if err := r.db.WithContext(ctx).
-		Where("account_id = ?", accountID).
-		Order("id DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&entries).Error; err != nil {
-		return nil, 0, apperrors.ErrDatabase(err)
+// SumInRange 统计账户在时间范围内的入账总额和出账总额
+// 两个返回值都是非负数 (出账返回绝对值)
+func (r *EntryRepository) SumInRange(ctx context.Context, accountID uint, period model.TimeRange) (credits, debits int64, err error) {
+	var sums struct {
+		Credits int64
+		Debits  int64
+	}
+	if err := r.accountEntries(ctx, accountID, period).
+		Select("COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) AS credits, " +
+			"COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0) AS debits").
+		Scan(&sums).Error; err != nil {
+		return 0, 0, apperrors.ErrDatabase(err)
 	}
+	return sums.Credits, sums.Debits, nil
+}
 
-	return entries, total, nil
+// accountEntries 构造账户在时间范围内的账目查询
+func (r *EntryRepository) accountEntries(ctx context.Context, accountID uint, period model.TimeRange) *gorm.DB {
+	query := conn(ctx, r.db).
+		Model(&model.Entry{}).
+		Where("account_id = ?", accountID)
+	if !period.From.IsZero() {
+		query = query.Where("created_at >= ?", period.From)
+	}
+	if !period.To.IsZero() {
+		query = query.Where("created_at < ?", period.To)
+	}
+	return query
+}
+
+// SumDebitsSince 统计账户自 since 以来的出账总额
+// 返回值为正数 (出账金额的绝对值之和)，没有出账时返回 0
+func (r *EntryRepository) SumDebitsSince(ctx context.Context, accountID uint, since time.Time) (int64, error) {
+	var total int64
+	if err := conn(ctx, r.db).
+		Model(&model.Entry{}).
+		Select("COALESCE(SUM(-amount), 0)").
+		Where("account_id = ? AND amount < 0 AND created_at >= ?", accountID, since).
+		Scan(&total).Error; err != nil {
+		return 0, apperrors.ErrDatabase(err)
+	}
+	return total, nil
 }
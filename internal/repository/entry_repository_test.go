@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestEntryListByOwnerJoinsOwnedAccounts(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewEntryRepository(db)
+	usd, eur := uuid.New(), uuid.New()
+
+	join := regexp.QuoteMeta("JOIN accounts ON accounts.id = entries.account_id AND accounts.deleted_at IS NULL WHERE accounts.owner = ?")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `entries` ") + join).
+		WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT entries.*,accounts.public_id AS account_public_id,accounts.currency FROM `entries` ")+join+
+		regexp.QuoteMeta(" ORDER BY entries.id DESC LIMIT ?")).
+		WithArgs("alice", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "amount", "account_public_id", "currency"}).
+			AddRow(4, 2, -300, eur.String(), "EUR").
+			AddRow(3, 1, 400, usd.String(), "USD"))
+
+	entries, total, err := repo.ListByOwner(context.Background(), "alice", model.TimeRange{}, "", 10, 0)
+	if err != nil {
+		t.Fatalf("ListByOwner: %v", err)
+	}
+	if total != 2 || len(entries) != 2 {
+		t.Fatalf("got %d of %d entries, want 2 of 2", len(entries), total)
+	}
+	if entries[0].AccountPublicID != eur || entries[0].Currency != "EUR" || entries[1].AccountPublicID != usd || entries[1].Amount != 400 {
+		t.Errorf("entries = %+v", entries)
+	}
+}
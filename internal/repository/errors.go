@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// MySQL 错误码 (https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html)
+const (
+	mysqlErrBadNull         = 1048 // ER_BAD_NULL_ERROR: NOT NULL 列写入 NULL
+	mysqlErrDupEntry        = 1062 // ER_DUP_ENTRY: 唯一约束冲突
+	mysqlErrNoReferencedRow = 1452 // ER_NO_REFERENCED_ROW_2: 外键引用的记录不存在
+)
+
+// wrapDBError 把 GORM 返回的错误转换为 AppError，所有 Repository 共用同一套映射:
+//   - gorm.ErrRecordNotFound → notFound (如 ErrAccountNotFound)，notFound 为 nil 时按数据库错误处理
+//   - 约束冲突 → 见 constraintError (唯一键 409、外键 404、非空 400)
+//   - 其余错误 → ErrDatabase (Context 取消和超时另有映射，见 ErrDatabase)
+//
+// 需要更具体的冲突消息时 (如用户名已存在)，调用方先自行判断 isDuplicateKey
+func wrapDBError(err error, notFound *apperrors.AppError) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound) && notFound != nil:
+		return notFound
+	}
+	if appErr := constraintError(err); appErr != nil {
+		return appErr
+	}
+	return apperrors.ErrDatabase(err)
+}
+
+// constraintError 把数据库约束冲突转换为对应的 AppError，不是约束冲突时返回 nil
+//   - 唯一约束 (1062) → CodeAlreadyExists (409)
+//   - 外键引用的记录不存在 (1452，如为不存在的用户创建账户) → CodeNotFound (404)
+//   - NOT NULL 列缺少值 (1048) → CodeInvalidParams (400)
+//
+// 同时识别 MySQL 驱动的原始错误和 GORM 翻译后的哨兵错误 (gorm.Config.TranslateError)
+// 响应中只有通用的消息，不暴露约束和列名；原始错误保留在 Err 中，便于日志排查
+func constraintError(err error) *apperrors.AppError {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlErrDupEntry:
+			return withCause(apperrors.New(apperrors.CodeAlreadyExists), err)
+		case mysqlErrNoReferencedRow:
+			return withCause(apperrors.NewWithMessage(apperrors.CodeNotFound, "referenced record not found"), err)
+		case mysqlErrBadNull:
+			return withCause(apperrors.NewWithMessage(apperrors.CodeInvalidParams, "missing required field"), err)
+		}
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return withCause(apperrors.New(apperrors.CodeAlreadyExists), err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return withCause(apperrors.NewWithMessage(apperrors.CodeNotFound, "referenced record not found"), err)
+	}
+	return nil
+}
+
+// isDuplicateKey 判断是否为唯一约束冲突
+func isDuplicateKey(err error) bool {
+	appErr := constraintError(err)
+	return appErr != nil && appErr.Code == apperrors.CodeAlreadyExists
+}
+
+// withCause 为 AppError 设置原始错误 (不出现在响应中)
+func withCause(appErr *apperrors.AppError, err error) *apperrors.AppError {
+	appErr.Err = err
+	return appErr
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestCreateMapsDriverErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int
+		wantStatus int
+	}{
+		{"duplicate entry", &mysql.MySQLError{Number: mysqlErrDupEntry, Message: "Duplicate entry"}, apperrors.CodeAlreadyExists, http.StatusConflict},
+		{"missing foreign key", &mysql.MySQLError{Number: mysqlErrNoReferencedRow, Message: "Cannot add or update a child row"}, apperrors.CodeNotFound, http.StatusNotFound},
+		{"not null", &mysql.MySQLError{Number: mysqlErrBadNull, Message: "Column 'owner' cannot be null"}, apperrors.CodeInvalidParams, http.StatusBadRequest},
+		{"other driver error", &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, apperrors.CodeDatabaseError, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `accounts`")).WillReturnError(tt.err)
+			mock.ExpectRollback()
+
+			err := NewAccountRepository(db).Create(context.Background(), &model.Account{Owner: "ghost", Currency: "USD"})
+			appErr := apperrors.AsAppError(err)
+			if appErr.Code != tt.wantCode || appErr.HTTPStatus != tt.wantStatus {
+				t.Errorf("error = %v (code %d, status %d), want code %d status %d",
+					err, appErr.Code, appErr.HTTPStatus, tt.wantCode, tt.wantStatus)
+			}
+			// 约束冲突的响应消息不暴露约束和列名
+			if tt.wantCode != apperrors.CodeDatabaseError && regexp.MustCompile(`Column|child row|Duplicate`).MatchString(appErr.Message) {
+				t.Errorf("message %q leaks the driver error", appErr.Message)
+			}
+		})
+	}
+}
+
+func TestConstraintError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int // 0 表示不是约束冲突
+	}{
+		{"raw duplicate", &mysql.MySQLError{Number: mysqlErrDupEntry}, apperrors.CodeAlreadyExists},
+		{"wrapped raw foreign key", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: mysqlErrNoReferencedRow}), apperrors.CodeNotFound},
+		{"translated duplicate", gorm.ErrDuplicatedKey, apperrors.CodeAlreadyExists},
+		{"translated foreign key", fmt.Errorf("insert: %w", gorm.ErrForeignKeyViolated), apperrors.CodeNotFound},
+		{"other driver error", &mysql.MySQLError{Number: 1213}, 0},
+		{"not a driver error", errors.New("connection reset"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := constraintError(tt.err)
+			if tt.wantCode == 0 {
+				if appErr != nil {
+					t.Errorf("constraintError = %v, want nil", appErr)
+				}
+				return
+			}
+			if appErr == nil || appErr.Code != tt.wantCode {
+				t.Fatalf("constraintError = %v, want code %d", appErr, tt.wantCode)
+			}
+			// 原始错误保留在 Err 中，便于日志排查
+			if !errors.Is(appErr, tt.err) {
+				t.Errorf("cause %v not kept", tt.err)
+			}
+		})
+	}
+}
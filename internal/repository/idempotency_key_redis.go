@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// redisIdempotencyKeyPrefix Redis 中幂等键的前缀，避免与其他数据冲突
+const redisIdempotencyKeyPrefix = "idempotency:"
+
+// RedisIdempotencyKeyRepository 保存在 Redis 中的幂等键
+// 实现 middleware.IdempotencyStore，多实例部署时共享幂等键的处理状态
+//
+// 每个键是一个带过期时间的字符串 (JSON)，过期由 Redis 自动删除，不需要后台清理
+type RedisIdempotencyKeyRepository struct {
+	client redis.UniversalClient
+}
+
+// NewRedisIdempotencyKeyRepository 创建 RedisIdempotencyKeyRepository 实例
+func NewRedisIdempotencyKeyRepository(client redis.UniversalClient) *RedisIdempotencyKeyRepository {
+	return &RedisIdempotencyKeyRepository{client: client}
+}
+
+// redisIdempotencyKey Redis 中保存的记录
+// model.IdempotencyKey 的 Body 不参与 JSON 序列化，这里单独保存
+type redisIdempotencyKey struct {
+	Fingerprint string    `json:"fingerprint"`
+	Done        bool      `json:"done"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Reserve 原子地占用幂等键
+// 键不存在 (或已过期被 Redis 删除) 时保存 record 并返回 (nil, nil)；否则返回已有的记录
+//
+// 由 SET NX 保证并发的相同请求只有一个能占用成功，其余请求读取到占用成功的那条记录
+func (r *RedisIdempotencyKeyRepository) Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	value, err := json.Marshal(redisIdempotencyKey{
+		Fingerprint: record.Fingerprint,
+		ExpiresAt:   record.ExpiresAt,
+		CreatedAt:   record.CreatedAt,
+	})
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternalError, err)
+	}
+
+	// 已有的键可能在占用失败和读取之间被释放或过期，此时重试一次
+	for range 2 {
+		// 1. 键不存在时写入
+		err := r.client.SetArgs(ctx, redisIdempotencyKeyPrefix+record.Key, value, redis.SetArgs{
+			Mode:     "NX",
+			ExpireAt: record.ExpiresAt,
+		}).Err()
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return nil, apperrors.Wrap(apperrors.CodeInternalError, err)
+		}
+
+		// 2. 读取已有的键
+		existing, err := r.get(ctx, r.client, record.Key)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.CodeInternalError, err)
+		}
+		if existing == nil {
+			continue
+		}
+		return existing, nil
+	}
+
+	// 键一直在被其他请求占用和释放，按相同请求仍在处理中返回
+	return &model.IdempotencyKey{Key: record.Key, Fingerprint: record.Fingerprint}, nil
+}
+
+// Complete 保存首次成功的响应，并把过期时间更新为 expiresAt
+// 只更新仍在处理中的键 (WATCH 保证读取和写入之间键没有被修改)
+func (r *RedisIdempotencyKeyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, expiresAt time.Time) error {
+	return r.update(ctx, key, func(tx *redis.Tx, existing *model.IdempotencyKey) error {
+		value, err := json.Marshal(redisIdempotencyKey{
+			Fingerprint: existing.Fingerprint,
+			Done:        true,
+			StatusCode:  statusCode,
+			ContentType: contentType,
+			Body:        body,
+			ExpiresAt:   expiresAt,
+			CreatedAt:   existing.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return pipe.SetArgs(ctx, redisIdempotencyKeyPrefix+key, value, redis.SetArgs{ExpireAt: expiresAt}).Err()
+		})
+		return err
+	})
+}
+
+// Release 释放未成功的请求占用的键，已完成的键不受影响
+func (r *RedisIdempotencyKeyRepository) Release(ctx context.Context, key string) error {
+	return r.update(ctx, key, func(tx *redis.Tx, _ *model.IdempotencyKey) error {
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return pipe.Del(ctx, redisIdempotencyKeyPrefix+key).Err()
+		})
+		return err
+	})
+}
+
+// update 在 WATCH 保护下读取仍在处理中的键并调用 fn 修改
+// 键不存在或已完成时什么都不做；其他请求同时修改了键时 (事务失败) 重试
+func (r *RedisIdempotencyKeyRepository) update(ctx context.Context, key string, fn func(tx *redis.Tx, existing *model.IdempotencyKey) error) error {
+	const maxRetries = 3
+
+	for range maxRetries {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			existing, err := r.get(ctx, tx, key)
+			if err != nil {
+				return err
+			}
+			if existing == nil || existing.Done {
+				return nil
+			}
+			return fn(tx, existing)
+		}, redisIdempotencyKeyPrefix+key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return apperrors.Wrap(apperrors.CodeInternalError, err)
+		}
+		return nil
+	}
+	return apperrors.NewWithMessage(apperrors.CodeInternalError, "idempotency key is being modified concurrently")
+}
+
+// get 读取幂等键，不存在时返回 (nil, nil)
+func (r *RedisIdempotencyKeyRepository) get(ctx context.Context, client redis.Cmdable, key string) (*model.IdempotencyKey, error) {
+	data, err := client.Get(ctx, redisIdempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored redisIdempotencyKey
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return &model.IdempotencyKey{
+		Key:         key,
+		Fingerprint: stored.Fingerprint,
+		Done:        stored.Done,
+		StatusCode:  stored.StatusCode,
+		ContentType: stored.ContentType,
+		Body:        stored.Body,
+		ExpiresAt:   stored.ExpiresAt,
+		CreatedAt:   stored.CreatedAt,
+	}, nil
+}
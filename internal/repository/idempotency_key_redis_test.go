@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// newTestRedisStore 创建连接到 miniredis 的 RedisIdempotencyKeyRepository
+func newTestRedisStore(t *testing.T) (*RedisIdempotencyKeyRepository, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisIdempotencyKeyRepository(client), mr
+}
+
+func TestRedisIdempotencyReserveIsAtomic(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	const workers = 20
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+		existing int
+	)
+	start := make(chan struct{})
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			got, err := store.Reserve(ctx, &model.IdempotencyKey{Key: "k1", Fingerprint: "fp", ExpiresAt: expiresAt})
+			if err != nil {
+				t.Errorf("Reserve: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got == nil {
+				reserved++
+			} else if got.Fingerprint == "fp" && !got.Done {
+				existing++
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if reserved != 1 || existing != workers-1 {
+		t.Errorf("reserved = %d, in-progress = %d, want 1 and %d", reserved, existing, workers-1)
+	}
+}
+
+func TestRedisIdempotencyCompleteAndRelease(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	ctx := context.Background()
+	reserve := func(key string) *model.IdempotencyKey {
+		t.Helper()
+		got, err := store.Reserve(ctx, &model.IdempotencyKey{Key: key, Fingerprint: "fp", ExpiresAt: time.Now().Add(time.Minute)})
+		if err != nil {
+			t.Fatalf("Reserve(%s): %v", key, err)
+		}
+		return got
+	}
+
+	// 完成后重放首次成功的响应，之后的 Release 不影响已完成的键
+	reserve("done")
+	if err := store.Complete(ctx, "done", 201, "application/json", []byte(`{"id":1}`), time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if err := store.Release(ctx, "done"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	got := reserve("done")
+	if got == nil || !got.Done || got.StatusCode != 201 || string(got.Body) != `{"id":1}` || got.ContentType != "application/json" {
+		t.Fatalf("completed key = %+v", got)
+	}
+	if ttl := mr.TTL(redisIdempotencyKeyPrefix + "done"); ttl <= time.Minute {
+		t.Errorf("completed key ttl = %s, want extended to about an hour", ttl)
+	}
+
+	// 释放后可以重新占用
+	reserve("failed")
+	if err := store.Release(ctx, "failed"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if got := reserve("failed"); got != nil {
+		t.Errorf("released key = %+v, want reservable", got)
+	}
+
+	// 过期后可以重新占用
+	reserve("expired")
+	mr.FastForward(2 * time.Minute)
+	if got := reserve("expired"); got != nil {
+		t.Errorf("expired key = %+v, want reservable", got)
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// IdempotencyKeyRepository 幂等键数据访问实现
+// 实现 middleware.IdempotencyStore，多实例部署时共享幂等键的处理状态
+type IdempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository 创建 IdempotencyKeyRepository 实例
+func NewIdempotencyKeyRepository(db *gorm.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的 IdempotencyKeyRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *IdempotencyKeyRepository) WithTx(tx *gorm.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: tx}
+}
+
+// Reserve 原子地占用幂等键
+// 键不存在或已过期时保存 record 并返回 (nil, nil)；否则返回已有的记录
+//
+// 由主键保证并发的相同请求只有一个能插入成功 (INSERT ... ON DUPLICATE KEY UPDATE 不修改已有的行)，
+// 其余请求读取到插入成功的那条记录
+func (r *IdempotencyKeyRepository) Reserve(ctx context.Context, record *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	db := conn(ctx, r.db)
+
+	// 已有的键可能在插入失败和读取之间被释放或过期，此时重试一次
+	for range 2 {
+		// 1. 删除已过期的同名键，之后的插入才能占用它
+		if err := db.
+			Where("idempotency_key = ? AND expires_at <= ?", record.Key, time.Now()).
+			Delete(&model.IdempotencyKey{}).Error; err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+
+		// 2. 插入，键已存在时影响行数为 0
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return nil, apperrors.ErrDatabase(result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return nil, nil
+		}
+
+		// 3. 读取已有的键
+		var existing model.IdempotencyKey
+		err := db.Where("idempotency_key = ?", record.Key).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, apperrors.ErrDatabase(err)
+		}
+		if existing.IsExpired(time.Now()) {
+			continue
+		}
+		return &existing, nil
+	}
+
+	// 键一直在被其他请求占用和释放，按相同请求仍在处理中返回
+	return &model.IdempotencyKey{Key: record.Key, Fingerprint: record.Fingerprint}, nil
+}
+
+// Complete 保存首次成功的响应，并把过期时间更新为 expiresAt
+// 只更新仍在处理中的键
+func (r *IdempotencyKeyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte, expiresAt time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&model.IdempotencyKey{}).
+		Where("idempotency_key = ? AND done = ?", key, false).
+		Updates(map[string]any{
+			"done":         true,
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"body":         body,
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	return nil
+}
+
+// Release 释放未成功的请求占用的键，已完成的键不受影响
+func (r *IdempotencyKeyRepository) Release(ctx context.Context, key string) error {
+	result := conn(ctx, r.db).
+		Where("idempotency_key = ? AND done = ?", key, false).
+		Delete(&model.IdempotencyKey{})
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	return nil
+}
+
+// DeleteExpired 删除已过期的幂等键
+// 由后台任务定期调用，过期的键在 Reserve 时也会被清理，这里只是避免表无限增长
+func (r *IdempotencyKeyRepository) DeleteExpired(ctx context.Context) error {
+	result := conn(ctx, r.db).
+		Where("expires_at <= ?", time.Now()).
+		Delete(&model.IdempotencyKey{})
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	return nil
+}
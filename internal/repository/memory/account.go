@@ -0,0 +1,351 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// AccountRepository 账户数据访问的内存实现
+// 与 GORM 实现一致，软删除的账户对所有查询不可见
+type AccountRepository struct {
+	s *Store
+}
+
+// Create 创建新账户
+// 同一用户同一货币已有未删除的账户时返回 CodeAlreadyExists
+func (r *AccountRepository) Create(ctx context.Context, account *model.Account) error {
+	if err := r.s.fail(OpAccountCreate); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, a := range r.s.accounts {
+		if !a.DeletedAt.Valid && a.Owner == account.Owner && a.Currency == account.Currency {
+			return apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "account with this currency already exists")
+		}
+	}
+	if err := account.BeforeCreate(nil); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	now := r.s.now()
+	account.ID = r.s.nextID("accounts")
+	account.CreatedAt = now
+	account.UpdatedAt = now
+	r.s.accounts[account.ID] = *account
+	return nil
+}
+
+// GetByID 根据ID查询账户
+func (r *AccountRepository) GetByID(ctx context.Context, id uint) (*model.Account, error) {
+	return r.find(func(a *model.Account) bool { return a.ID == id })
+}
+
+// ExistsOwnedBy 检查账户是否存在且属于 owner
+func (r *AccountRepository) ExistsOwnedBy(ctx context.Context, id uint, owner string) (bool, error) {
+	accounts := r.filter(func(a *model.Account) bool { return a.ID == id && a.Owner == owner })
+	return len(accounts) > 0, nil
+}
+
+// GetByIDs 批量读取账户，不存在 (或已删除) 的 ID 不出现在结果中
+func (r *AccountRepository) GetByIDs(ctx context.Context, ids []uint) (map[uint]*model.Account, error) {
+	found := r.filter(func(a *model.Account) bool { return slices.Contains(ids, a.ID) })
+	accounts := make(map[uint]*model.Account, len(found))
+	for i := range found {
+		accounts[found[i].ID] = &found[i]
+	}
+	return accounts, nil
+}
+
+// GetByPublicIDs 按公开ID批量读取账户，不存在 (或已删除) 的公开ID不出现在结果中
+func (r *AccountRepository) GetByPublicIDs(ctx context.Context, publicIDs []uuid.UUID) (map[uuid.UUID]*model.Account, error) {
+	found := r.filter(func(a *model.Account) bool { return slices.Contains(publicIDs, a.PublicID) })
+	accounts := make(map[uuid.UUID]*model.Account, len(found))
+	for i := range found {
+		accounts[found[i].PublicID] = &found[i]
+	}
+	return accounts, nil
+}
+
+// PublicIDs 批量查询账户的公开ID，包括已关闭的账户
+func (r *AccountRepository) PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	result := make(map[uint]uuid.UUID, len(ids))
+	for _, id := range ids {
+		if a, ok := r.s.accounts[id]; ok {
+			result[id] = a.PublicID
+		}
+	}
+	return result, nil
+}
+
+// GetByPublicID 根据公开ID查询账户
+func (r *AccountRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Account, error) {
+	return r.find(func(a *model.Account) bool { return a.PublicID == publicID })
+}
+
+// GetByNumber 根据账号查询账户
+func (r *AccountRepository) GetByNumber(ctx context.Context, number string) (*model.Account, error) {
+	return r.find(func(a *model.Account) bool { return a.Number == number })
+}
+
+// GetByOwnerAndCurrency 根据所有者和货币类型查询账户
+func (r *AccountRepository) GetByOwnerAndCurrency(ctx context.Context, owner, currency string) (*model.Account, error) {
+	return r.find(func(a *model.Account) bool { return a.Owner == owner && a.Currency == currency })
+}
+
+// GetForUpdate 获取账户
+// 内存事务本身是串行的 (见 TxManager)，这里不需要额外加锁
+func (r *AccountRepository) GetForUpdate(ctx context.Context, id uint) (*model.Account, error) {
+	if err := r.s.fail(OpAccountGetForUpdate); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// CountByOwner 统计用户未关闭的账户数
+func (r *AccountRepository) CountByOwner(ctx context.Context, owner string) (int64, error) {
+	accounts := r.filter(func(a *model.Account) bool { return a.Owner == owner })
+	return int64(len(accounts)), nil
+}
+
+// ListByOwner 获取用户的所有账户 (带分页)
+// currency 不为空时只返回该货币的账户
+// sort 支持 id、created_at、balance (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *AccountRepository) ListByOwner(ctx context.Context, owner, currency, sort string, limit, offset int) ([]model.Account, int64, error) {
+	accounts := r.filter(func(a *model.Account) bool {
+		return a.Owner == owner && (currency == "" || a.Currency == currency)
+	})
+
+	err := sortItems(accounts, sort, func(a model.Account) uint { return a.ID }, map[string]func(a, b model.Account) int{
+		"id":         func(a, b model.Account) int { return compare(a.ID, b.ID) },
+		"created_at": func(a, b model.Account) int { return a.CreatedAt.Compare(b.CreatedAt) },
+		"balance":    func(a, b model.Account) int { return compare(a.Balance, b.Balance) },
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := page(accounts, limit, offset)
+	return items, total, nil
+}
+
+// SumByOwnerGroupedByCurrency 按货币汇总用户的账户余额和账户数，按货币代码排序
+func (r *AccountRepository) SumByOwnerGroupedByCurrency(ctx context.Context, owner string) ([]model.CurrencyBalance, error) {
+	byCurrency := make(map[string]*model.CurrencyBalance)
+	for _, a := range r.filter(func(a *model.Account) bool { return a.Owner == owner }) {
+		sum, ok := byCurrency[a.Currency]
+		if !ok {
+			sum = &model.CurrencyBalance{Currency: a.Currency}
+			byCurrency[a.Currency] = sum
+		}
+		sum.TotalBalance += a.Balance
+		sum.AccountCount++
+	}
+
+	sums := make([]model.CurrencyBalance, 0, len(byCurrency))
+	for _, sum := range byCurrency {
+		sums = append(sums, *sum)
+	}
+	slices.SortFunc(sums, func(a, b model.CurrencyBalance) int { return compare(a.Currency, b.Currency) })
+	return sums, nil
+}
+
+// UpdateBalance 更新账户余额
+// 扣款 (amount < 0) 时余额不能低于 min_balance - overdraft_limit，否则返回余额不足
+func (r *AccountRepository) UpdateBalance(ctx context.Context, id uint, amount int64) (*model.Account, error) {
+	accounts, err := r.UpdateBalances(ctx, map[uint]int64{id: amount})
+	if err != nil {
+		return nil, err
+	}
+	return accounts[id], nil
+}
+
+// ListAfterID 按 ID 升序返回 ID 大于 afterID 的账户，最多 limit 个
+func (r *AccountRepository) ListAfterID(ctx context.Context, afterID uint, limit int) ([]model.Account, error) {
+	accounts := r.filter(func(a *model.Account) bool { return a.ID > afterID })
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+	}
+	return accounts, nil
+}
+
+// SetBalance 直接设置账户余额，不检查透支额度和最低余额 (仅用于对账修复)
+func (r *AccountRepository) SetBalance(ctx context.Context, id uint, balance int64) (*model.Account, error) {
+	if err := r.s.fail(OpAccountSetBalance); err != nil {
+		return nil, err
+	}
+
+	return r.update(id, func(a *model.Account) { a.Balance = balance })
+}
+
+// UpdateBalances 按账户 ID 升序批量更新余额
+// 与 GORM 实现一样，中途失败时已更新的账户不会自动恢复，需要在事务中调用
+func (r *AccountRepository) UpdateBalances(ctx context.Context, deltas map[uint]int64) (map[uint]*model.Account, error) {
+	if err := r.s.fail(OpAccountUpdateBalances); err != nil {
+		return nil, err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make([]uint, 0, len(deltas))
+	for id := range deltas {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	now := r.s.now()
+	updated := make(map[uint]*model.Account, len(ids))
+	for _, id := range ids {
+		account, ok := r.s.accounts[id]
+		if !ok || account.DeletedAt.Valid {
+			return nil, apperrors.ErrAccountNotFound()
+		}
+
+		amount := deltas[id]
+		if amount < 0 && account.Balance+amount < account.BalanceFloor() {
+			if account.MinBalance > 0 {
+				return nil, apperrors.ErrMinBalanceBreach()
+			}
+			return nil, apperrors.ErrInsufficientBalance()
+		}
+		if amount != 0 {
+			account.Balance += amount
+			account.UpdatedAt = now
+			r.s.accounts[id] = account
+		}
+		updated[id] = &account
+	}
+	return updated, nil
+}
+
+// SetOverdraftLimit 设置账户透支额度
+func (r *AccountRepository) SetOverdraftLimit(ctx context.Context, id uint, limit int64) (*model.Account, error) {
+	if err := r.s.fail(OpAccountSetOverdraft); err != nil {
+		return nil, err
+	}
+
+	return r.update(id, func(a *model.Account) { a.OverdraftLimit = limit })
+}
+
+// SetMinBalance 设置账户最低余额
+func (r *AccountRepository) SetMinBalance(ctx context.Context, id uint, minBalance int64) (*model.Account, error) {
+	if err := r.s.fail(OpAccountSetMinBalance); err != nil {
+		return nil, err
+	}
+
+	return r.update(id, func(a *model.Account) { a.MinBalance = minBalance })
+}
+
+// SetFrozen 冻结或解冻账户
+func (r *AccountRepository) SetFrozen(ctx context.Context, id uint, frozen bool) (*model.Account, error) {
+	if err := r.s.fail(OpAccountSetFrozen); err != nil {
+		return nil, err
+	}
+
+	return r.update(id, func(a *model.Account) { a.IsFrozen = frozen })
+}
+
+// SetName 修改账户名称
+func (r *AccountRepository) SetName(ctx context.Context, id uint, name string) (*model.Account, error) {
+	if err := r.s.fail(OpAccountSetName); err != nil {
+		return nil, err
+	}
+
+	return r.update(id, func(a *model.Account) { a.Name = name })
+}
+
+// Restore 恢复已软删除 (关闭) 的账户
+// 同一用户已有同币种的未删除账户时返回 CodeAlreadyExists (对应 GORM 实现的唯一索引冲突)
+func (r *AccountRepository) Restore(ctx context.Context, publicID uuid.UUID) (*model.Account, error) {
+	if err := r.s.fail(OpAccountRestore); err != nil {
+		return nil, err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var account model.Account
+	found := false
+	for _, a := range r.s.accounts {
+		if a.PublicID == publicID {
+			account, found = a, true
+			break
+		}
+	}
+	if !found {
+		return nil, apperrors.ErrAccountNotFound()
+	}
+	if !account.DeletedAt.Valid {
+		return nil, apperrors.NewWithMessage(apperrors.CodeStateConflict, "account is not closed")
+	}
+	for _, a := range r.s.accounts {
+		if !a.DeletedAt.Valid && a.Owner == account.Owner && a.Currency == account.Currency {
+			return nil, apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "account with this currency already exists")
+		}
+	}
+
+	account.DeletedAt.Time, account.DeletedAt.Valid = time.Time{}, false
+	account.UpdatedAt = r.s.now()
+	r.s.accounts[account.ID] = account
+	return &account, nil
+}
+
+// Close 关闭 (软删除) 账户，之后所有查询都看不到它
+// 应用目前没有关闭账户的接口，测试用它模拟账户被关闭
+func (r *AccountRepository) Close(ctx context.Context, id uint) error {
+	_, err := r.update(id, func(a *model.Account) {
+		a.DeletedAt.Time, a.DeletedAt.Valid = r.s.now(), true
+	})
+	return err
+}
+
+// update 修改一个未删除的账户并返回修改后的副本
+func (r *AccountRepository) update(id uint, fn func(a *model.Account)) (*model.Account, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	account, ok := r.s.accounts[id]
+	if !ok || account.DeletedAt.Valid {
+		return nil, apperrors.ErrAccountNotFound()
+	}
+	fn(&account)
+	account.UpdatedAt = r.s.now()
+	r.s.accounts[id] = account
+	return &account, nil
+}
+
+// find 返回第一个满足 match 的未删除账户副本
+func (r *AccountRepository) find(match func(a *model.Account) bool) (*model.Account, error) {
+	accounts := r.filter(match)
+	if len(accounts) == 0 {
+		return nil, apperrors.ErrAccountNotFound()
+	}
+	return &accounts[0], nil
+}
+
+// filter 返回所有满足 match 的未删除账户副本，按 ID 升序
+func (r *AccountRepository) filter(match func(a *model.Account) bool) []model.Account {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var accounts []model.Account
+	for _, a := range sortedValues(r.s.accounts) {
+		if !a.DeletedAt.Valid && match(&a) {
+			accounts = append(accounts, a)
+		}
+	}
+	return accounts
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestRecreateCurrencyAccountAfterClose(t *testing.T) {
+	ctx := context.Background()
+	repos := New()
+
+	old := &model.Account{Owner: "alice", Currency: "USD"}
+	if err := repos.Accounts.Create(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+	duplicate := &model.Account{Owner: "alice", Currency: "USD"}
+	if err := repos.Accounts.Create(ctx, duplicate); apperrors.AsAppError(err).Code != apperrors.CodeAlreadyExists {
+		t.Fatalf("duplicate open account error = %v, want CodeAlreadyExists", err)
+	}
+
+	// 关闭 (软删除) 旧账户
+	if err := repos.Accounts.Close(ctx, old.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	recreated := &model.Account{Owner: "alice", Currency: "USD"}
+	if err := repos.Accounts.Create(ctx, recreated); err != nil {
+		t.Fatalf("recreate after close: %v", err)
+	}
+	got, err := repos.Accounts.GetByOwnerAndCurrency(ctx, "alice", "USD")
+	if err != nil {
+		t.Fatalf("GetByOwnerAndCurrency: %v", err)
+	}
+	if got.ID != recreated.ID {
+		t.Errorf("GetByOwnerAndCurrency returned account %d, want the new account %d", got.ID, recreated.ID)
+	}
+
+	// 新账户未关闭时不能恢复旧账户
+	_, err = repos.Accounts.Restore(ctx, old.PublicID)
+	if apperrors.AsAppError(err).Code != apperrors.CodeAlreadyExists {
+		t.Errorf("Restore error = %v, want CodeAlreadyExists", err)
+	}
+}
+
+func TestRestoreClosedAccount(t *testing.T) {
+	ctx := context.Background()
+	repos := New()
+
+	account := &model.Account{Owner: "alice", Currency: "USD"}
+	if err := repos.Accounts.Create(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+	_, err := repos.Accounts.Restore(ctx, account.PublicID)
+	if apperrors.AsAppError(err).Code != apperrors.CodeStateConflict {
+		t.Errorf("restore open account error = %v, want CodeStateConflict", err)
+	}
+
+	if err := repos.Accounts.Close(ctx, account.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := repos.Accounts.Restore(ctx, account.PublicID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.DeletedAt.Valid {
+		t.Error("restored account is still closed")
+	}
+	if _, err := repos.Accounts.GetByID(ctx, account.ID); err != nil {
+		t.Errorf("GetByID after restore: %v", err)
+	}
+}
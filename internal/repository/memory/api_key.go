@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// APIKeyRepository API Key 数据访问的内存实现
+type APIKeyRepository struct {
+	s *Store
+}
+
+// Create 创建 API Key
+// Key 的哈希重复时返回 CodeAlreadyExists (与唯一索引一致)
+func (r *APIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	if err := r.s.fail(OpAPIKeyCreate); err != nil {
+		return err
+	}
+	if err := key.BeforeCreate(nil); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, k := range r.s.apiKeys {
+		if k.SecretHash == key.SecretHash {
+			return apperrors.New(apperrors.CodeAlreadyExists)
+		}
+	}
+
+	key.ID = r.s.nextID("api_keys")
+	key.CreatedAt = r.s.now()
+	r.s.apiKeys[key.ID] = *key
+	return nil
+}
+
+// GetByPublicID 根据公开ID查询 API Key
+func (r *APIKeyRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.APIKey, error) {
+	return r.find(func(k *model.APIKey) bool { return k.PublicID == publicID })
+}
+
+// GetBySecretHash 根据 Key 的哈希查询 API Key (包括已撤销的)
+func (r *APIKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (*model.APIKey, error) {
+	return r.find(func(k *model.APIKey) bool { return k.SecretHash == secretHash })
+}
+
+// ListByOwner 获取用户的所有 API Key (带分页，按 ID 降序)
+func (r *APIKeyRepository) ListByOwner(ctx context.Context, owner string, limit, offset int) ([]model.APIKey, int64, error) {
+	r.s.mu.Lock()
+	all := sortedValues(r.s.apiKeys)
+	r.s.mu.Unlock()
+
+	var items []model.APIKey
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].Owner == owner {
+			items = append(items, all[i])
+		}
+	}
+
+	result, total := page(items, limit, offset)
+	return result, total, nil
+}
+
+// Revoke 撤销 API Key
+// 已撤销时返回 CodeStateConflict
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	if err := r.s.fail(OpAPIKeyRevoke); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key, ok := r.s.apiKeys[id]
+	if !ok || key.Revoked {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "api key is already revoked")
+	}
+	key.Revoked = true
+	key.RevokedAt = &at
+	r.s.apiKeys[id] = key
+	return nil
+}
+
+// Touch 更新最后使用时间
+func (r *APIKeyRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if key, ok := r.s.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		r.s.apiKeys[id] = key
+	}
+	return nil
+}
+
+// find 返回第一个满足 match 的 API Key 副本
+func (r *APIKeyRepository) find(match func(k *model.APIKey) bool) (*model.APIKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, k := range r.s.apiKeys {
+		if match(&k) {
+			return &k, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("api key")
+}
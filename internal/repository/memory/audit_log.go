@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// AuditLogRepository 审计日志数据访问的内存实现
+type AuditLogRepository struct {
+	s *Store
+}
+
+// Create 写入一条审计日志
+func (r *AuditLogRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	if err := r.s.fail(OpAuditLogCreate); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	log.ID = r.s.nextID("audit_logs")
+	log.CreatedAt = r.s.now()
+	r.s.auditLogs[log.ID] = *log
+	return nil
+}
+
+// List 查询审计日志 (带分页，按 ID 降序)
+// actor、action 为空时不作为过滤条件
+func (r *AuditLogRepository) List(ctx context.Context, actor, action string, limit, offset int) ([]model.AuditLog, int64, error) {
+	r.s.mu.Lock()
+	all := sortedValues(r.s.auditLogs)
+	r.s.mu.Unlock()
+
+	var logs []model.AuditLog
+	for i := len(all) - 1; i >= 0; i-- {
+		log := all[i]
+		if (actor == "" || log.Actor == actor) && (action == "" || log.Action == action) {
+			logs = append(logs, log)
+		}
+	}
+
+	items, total := page(logs, limit, offset)
+	return items, total, nil
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// EntryRepository 账目数据访问的内存实现
+type EntryRepository struct {
+	s *Store
+}
+
+// Create 创建账目记录
+func (r *EntryRepository) Create(ctx context.Context, entry *model.Entry) error {
+	if err := r.s.fail(OpEntryCreate); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	entry.ID = r.s.nextID("entries")
+	entry.CreatedAt = r.s.now()
+	r.s.entries[entry.ID] = *entry
+	return nil
+}
+
+// GetByID 根据ID查询账目
+func (r *EntryRepository) GetByID(ctx context.Context, id uint) (*model.Entry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	entry, ok := r.s.entries[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("entry")
+	}
+	return &entry, nil
+}
+
+// ListByAccountID 获取账户的所有账目 (带分页)
+// period 限制创建时间范围 (零值不限制)
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *EntryRepository) ListByAccountID(ctx context.Context, accountID uint, period model.TimeRange, sort string, limit, offset int) ([]model.Entry, int64, error) {
+	entries := r.accountEntries(accountID, period)
+
+	err := sortItems(entries, sort, func(e model.Entry) uint { return e.ID }, map[string]func(a, b model.Entry) int{
+		"id":         func(a, b model.Entry) int { return compare(a.ID, b.ID) },
+		"created_at": func(a, b model.Entry) int { return a.CreatedAt.Compare(b.CreatedAt) },
+		"amount":     func(a, b model.Entry) int { return compare(a.Amount, b.Amount) },
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := page(entries, limit, offset)
+	return items, total, nil
+}
+
+// ListByOwner 获取用户所有未关闭账户的账目 (带分页)，附带所属账户的公开ID和货币
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *EntryRepository) ListByOwner(ctx context.Context, owner string, period model.TimeRange, sort string, limit, offset int) ([]model.AccountEntry, int64, error) {
+	r.s.mu.Lock()
+	var entries []model.AccountEntry
+	for _, entry := range sortedValues(r.s.entries) {
+		account, ok := r.s.accounts[entry.AccountID]
+		if !ok || account.DeletedAt.Valid || account.Owner != owner || !inRange(entry.CreatedAt, period) {
+			continue
+		}
+		entries = append(entries, model.AccountEntry{
+			Entry:           entry,
+			AccountPublicID: account.PublicID,
+			Currency:        account.Currency,
+		})
+	}
+	r.s.mu.Unlock()
+
+	err := sortItems(entries, sort, func(e model.AccountEntry) uint { return e.ID }, map[string]func(a, b model.AccountEntry) int{
+		"id":         func(a, b model.AccountEntry) int { return compare(a.ID, b.ID) },
+		"created_at": func(a, b model.AccountEntry) int { return a.CreatedAt.Compare(b.CreatedAt) },
+		"amount":     func(a, b model.AccountEntry) int { return compare(a.Amount, b.Amount) },
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := page(entries, limit, offset)
+	return items, total, nil
+}
+
+// ListActivityByAccountID 获取账户的账目及其来源转账和转账另一方账户 (带分页)
+// 按 ID 降序 (即最新的在前)
+func (r *EntryRepository) ListActivityByAccountID(ctx context.Context, accountID uint, period model.TimeRange, limit, offset int) ([]model.EntryActivity, int64, error) {
+	entries := r.accountEntries(accountID, period)
+	slices.Reverse(entries)
+	entries, total := page(entries, limit, offset)
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	items := make([]model.EntryActivity, len(entries))
+	for i, entry := range entries {
+		items[i].Entry = entry
+		if entry.TransferID == nil {
+			continue
+		}
+		transfer, ok := r.s.transfers[*entry.TransferID]
+		if !ok {
+			continue
+		}
+		items[i].TransferPublicID = &transfer.PublicID
+		items[i].TransferReference = &transfer.Reference
+
+		counterpartyID := transfer.FromAccountID
+		if entry.Amount < 0 {
+			counterpartyID = transfer.ToAccountID
+		}
+		if account, ok := r.s.accounts[counterpartyID]; ok {
+			items[i].CounterpartyPublicID = &account.PublicID
+			items[i].CounterpartyOwner = &account.Owner
+		}
+	}
+	return items, total, nil
+}
+
+// SumThroughID 统计账户 ID 不大于 id 的所有账目的金额之和
+func (r *EntryRepository) SumThroughID(ctx context.Context, accountID, id uint) (int64, error) {
+	var total int64
+	for _, entry := range r.accountEntries(accountID, model.TimeRange{}) {
+		if entry.ID <= id {
+			total += entry.Amount
+		}
+	}
+	return total, nil
+}
+
+// StreamByAccountID 按 ID 升序逐条把账户的账目交给 fn 处理
+// fn 返回错误时停止并返回该错误
+func (r *EntryRepository) StreamByAccountID(ctx context.Context, accountID uint, period model.TimeRange, fn func(entry *model.Entry) error) error {
+	for _, entry := range r.accountEntries(accountID, period) {
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SumBeforeDate 统计账户在 before 之前所有账目的金额之和
+func (r *EntryRepository) SumBeforeDate(ctx context.Context, accountID uint, before time.Time) (int64, error) {
+	var total int64
+	for _, entry := range r.accountEntries(accountID, model.TimeRange{To: before}) {
+		total += entry.Amount
+	}
+	return total, nil
+}
+
+// SumByAccountIDs 统计多个账户全部账目的金额之和，没有账目的账户不出现在结果中
+func (r *EntryRepository) SumByAccountIDs(ctx context.Context, accountIDs []uint) (map[uint]int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	totals := make(map[uint]int64, len(accountIDs))
+	for _, entry := range r.s.entries {
+		if slices.Contains(accountIDs, entry.AccountID) {
+			totals[entry.AccountID] += entry.Amount
+		}
+	}
+	return totals, nil
+}
+
+// SumInRange 统计账户在时间范围内的入账总额和出账总额 (出账返回绝对值)
+func (r *EntryRepository) SumInRange(ctx context.Context, accountID uint, period model.TimeRange) (credits, debits int64, err error) {
+	for _, entry := range r.accountEntries(accountID, period) {
+		if entry.Amount > 0 {
+			credits += entry.Amount
+		} else {
+			debits -= entry.Amount
+		}
+	}
+	return credits, debits, nil
+}
+
+// SumDebitsSince 统计账户自 since 以来的出账总额 (正数)
+func (r *EntryRepository) SumDebitsSince(ctx context.Context, accountID uint, since time.Time) (int64, error) {
+	var total int64
+	for _, entry := range r.accountEntries(accountID, model.TimeRange{From: since}) {
+		if entry.Amount < 0 {
+			total -= entry.Amount
+		}
+	}
+	return total, nil
+}
+
+// accountEntries 返回账户在时间范围内的账目副本，按 ID 升序
+func (r *EntryRepository) accountEntries(accountID uint, period model.TimeRange) []model.Entry {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var entries []model.Entry
+	for _, entry := range sortedValues(r.s.entries) {
+		if entry.AccountID == accountID && inRange(entry.CreatedAt, period) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,78 @@
+package memory
+
+// ==================== 故障注入 ====================
+
+// 可以注入故障的操作，格式为 "<Repository>.<方法名>"
+// 只覆盖写操作和 GetForUpdate，足够模拟事务中任意一步失败
+const (
+	OpUserCreate            = "Users.Create"
+	OpUserUpdate            = "Users.Update"
+	OpAccountCreate         = "Accounts.Create"
+	OpAccountGetForUpdate   = "Accounts.GetForUpdate"
+	OpAccountUpdateBalances = "Accounts.UpdateBalances"
+	OpAccountSetBalance     = "Accounts.SetBalance"
+	OpAccountSetOverdraft   = "Accounts.SetOverdraftLimit"
+	OpAccountSetMinBalance  = "Accounts.SetMinBalance"
+	OpAccountSetName        = "Accounts.SetName"
+	OpAccountSetFrozen      = "Accounts.SetFrozen"
+	OpAccountRestore        = "Accounts.Restore"
+	OpTransferCreate        = "Transfers.Create"
+	OpEntryCreate           = "Entries.Create"
+	OpSessionCreate         = "Sessions.Create"
+	OpSessionDeleteByUser   = "Sessions.DeleteByUsername"
+	OpSessionBlock          = "Sessions.Block"
+	OpSessionTouch          = "Sessions.Touch"
+	OpScheduledCreate       = "ScheduledTransfers.Create"
+	OpScheduledClaim        = "ScheduledTransfers.Claim"
+	OpScheduledMark         = "ScheduledTransfers.Mark"
+	OpRecurringCreate       = "RecurringTransfers.Create"
+	OpRecurringAdvance      = "RecurringTransfers.SetOccurrenceCount"
+	OpAuditLogCreate        = "AuditLogs.Create"
+	OpAPIKeyCreate          = "APIKeys.Create"
+	OpAPIKeyRevoke          = "APIKeys.Revoke"
+)
+
+// fault 一个待触发的故障
+type fault struct {
+	remaining int // 还需要放行的调用次数
+	err       error
+}
+
+// FailOn 让 op 的第 n 次调用 (从 1 开始计数，从调用 FailOn 时算起) 返回 err
+// 故障只触发一次，之后 op 恢复正常；对同一个 op 再次调用会覆盖之前的设置
+//
+// 例如让转账在写入第二条账目时失败，验证事务回滚后没有残留数据:
+//
+//	repos.Store.FailOn(memory.OpEntryCreate, 2, errors.New("disk full"))
+//	_, err := transferService.CreateTransfer(ctx, owner, req)
+//	// err 非空，两个账户余额、转账记录、账目都与转账前一致
+func (s *Store) FailOn(op string, n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[op] = &fault{remaining: n - 1, err: err}
+}
+
+// ClearFaults 清除所有尚未触发的故障
+func (s *Store) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.faults)
+}
+
+// fail 在操作开始前调用，op 的故障到期时返回注入的错误
+// 调用方不能持有 mu
+func (s *Store) fail(op string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.faults[op]
+	if !ok {
+		return nil
+	}
+	if f.remaining > 0 {
+		f.remaining--
+		return nil
+	}
+	delete(s.faults, op)
+	return f.err
+}
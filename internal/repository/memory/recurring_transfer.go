@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// RecurringTransferRepository 周期转账规则数据访问的内存实现
+type RecurringTransferRepository struct {
+	s *Store
+}
+
+// Create 创建周期转账规则
+func (r *RecurringTransferRepository) Create(ctx context.Context, recurring *model.RecurringTransfer) error {
+	if err := r.s.fail(OpRecurringCreate); err != nil {
+		return err
+	}
+	if err := recurring.BeforeCreate(nil); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.now()
+	recurring.ID = r.s.nextID("recurring_transfers")
+	if recurring.Status == "" {
+		recurring.Status = model.RecurringTransferActive
+	}
+	recurring.CreatedAt = now
+	recurring.UpdatedAt = now
+	r.s.recurringTransfers[recurring.ID] = *recurring
+	return nil
+}
+
+// GetByID 根据ID查询周期转账规则
+func (r *RecurringTransferRepository) GetByID(ctx context.Context, id uint) (*model.RecurringTransfer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	recurring, ok := r.s.recurringTransfers[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound("recurring transfer")
+	}
+	return &recurring, nil
+}
+
+// GetByPublicID 根据公开ID查询周期转账规则
+func (r *RecurringTransferRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.RecurringTransfer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, recurring := range r.s.recurringTransfers {
+		if recurring.PublicID == publicID {
+			return &recurring, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("recurring transfer")
+}
+
+// ListByFromAccountID 获取源账户的所有周期转账规则 (带分页，按 ID 降序)
+func (r *RecurringTransferRepository) ListByFromAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.RecurringTransfer, int64, error) {
+	r.s.mu.Lock()
+	all := sortedValues(r.s.recurringTransfers)
+	r.s.mu.Unlock()
+
+	var items []model.RecurringTransfer
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].FromAccountID == accountID {
+			items = append(items, all[i])
+		}
+	}
+
+	result, total := page(items, limit, offset)
+	return result, total, nil
+}
+
+// SetOccurrenceCount 更新已生成的次数
+// 仅当规则仍为 active 且次数为 from 时更新
+func (r *RecurringTransferRepository) SetOccurrenceCount(ctx context.Context, id uint, from, to int) error {
+	if err := r.s.fail(OpRecurringAdvance); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	recurring, ok := r.s.recurringTransfers[id]
+	if !ok || recurring.Status != model.RecurringTransferActive || recurring.OccurrenceCount != from {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "recurring transfer has changed")
+	}
+	recurring.OccurrenceCount = to
+	recurring.UpdatedAt = r.s.now()
+	r.s.recurringTransfers[id] = recurring
+	return nil
+}
+
+// Finish 结束一条生效中的规则 (active → completed / cancelled)
+func (r *RecurringTransferRepository) Finish(ctx context.Context, id uint, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	recurring, ok := r.s.recurringTransfers[id]
+	if !ok || recurring.Status != model.RecurringTransferActive {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "recurring transfer is no longer active")
+	}
+	recurring.Status = status
+	recurring.UpdatedAt = r.s.now()
+	r.s.recurringTransfers[id] = recurring
+	return nil
+}
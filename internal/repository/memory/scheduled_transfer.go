@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// ScheduledTransferRepository 定时转账数据访问的内存实现
+type ScheduledTransferRepository struct {
+	s *Store
+}
+
+// Create 创建定时转账
+func (r *ScheduledTransferRepository) Create(ctx context.Context, scheduled *model.ScheduledTransfer) error {
+	if err := r.s.fail(OpScheduledCreate); err != nil {
+		return err
+	}
+	if err := scheduled.BeforeCreate(nil); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.now()
+	scheduled.ID = r.s.nextID("scheduled_transfers")
+	if scheduled.Status == "" {
+		scheduled.Status = model.ScheduledTransferPending
+	}
+	scheduled.CreatedAt = now
+	scheduled.UpdatedAt = now
+	r.s.scheduledTransfers[scheduled.ID] = *scheduled
+	return nil
+}
+
+// GetByPublicID 根据公开ID查询定时转账
+func (r *ScheduledTransferRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.ScheduledTransfer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, scheduled := range r.s.scheduledTransfers {
+		if scheduled.PublicID == publicID {
+			return &scheduled, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound("scheduled transfer")
+}
+
+// ListDue 查询到期 (execute_at <= now) 且仍在等待的定时转账
+// 按计划执行时间升序返回，最多 limit 条
+func (r *ScheduledTransferRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]model.ScheduledTransfer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var due []model.ScheduledTransfer
+	for _, scheduled := range sortedValues(r.s.scheduledTransfers) {
+		if scheduled.Status == model.ScheduledTransferPending && !scheduled.ExecuteAt.After(now) {
+			due = append(due, scheduled)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b model.ScheduledTransfer) int { return a.ExecuteAt.Compare(b.ExecuteAt) })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// Claim 领取一条等待中的定时转账 (pending → processing)
+func (r *ScheduledTransferRepository) Claim(ctx context.Context, id uint) error {
+	if err := r.s.fail(OpScheduledClaim); err != nil {
+		return err
+	}
+
+	return r.transition(id, model.ScheduledTransferPending, func(t *model.ScheduledTransfer) {
+		t.Status = model.ScheduledTransferProcessing
+	})
+}
+
+// Cancel 取消一条等待中的定时转账 (pending → cancelled)
+func (r *ScheduledTransferRepository) Cancel(ctx context.Context, id uint) error {
+	return r.transition(id, model.ScheduledTransferPending, func(t *model.ScheduledTransfer) {
+		t.Status = model.ScheduledTransferCancelled
+	})
+}
+
+// CancelPendingByRecurring 取消周期转账规则生成的、仍在等待的定时转账
+func (r *ScheduledTransferRepository) CancelPendingByRecurring(ctx context.Context, recurringID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.now()
+	for id, scheduled := range r.s.scheduledTransfers {
+		if scheduled.RecurringTransferID != nil && *scheduled.RecurringTransferID == recurringID &&
+			scheduled.Status == model.ScheduledTransferPending {
+			scheduled.Status = model.ScheduledTransferCancelled
+			scheduled.UpdatedAt = now
+			r.s.scheduledTransfers[id] = scheduled
+		}
+	}
+	return nil
+}
+
+// MarkExecuted 记录执行成功 (processing → executed)
+func (r *ScheduledTransferRepository) MarkExecuted(ctx context.Context, id, transferID uint, at time.Time) error {
+	if err := r.s.fail(OpScheduledMark); err != nil {
+		return err
+	}
+
+	return r.transition(id, model.ScheduledTransferProcessing, func(t *model.ScheduledTransfer) {
+		t.Status = model.ScheduledTransferExecuted
+		t.TransferID = &transferID
+		t.ExecutedAt = &at
+	})
+}
+
+// MarkFailed 记录执行失败 (processing → failed)
+func (r *ScheduledTransferRepository) MarkFailed(ctx context.Context, id uint, reason string, at time.Time) error {
+	if err := r.s.fail(OpScheduledMark); err != nil {
+		return err
+	}
+
+	return r.transition(id, model.ScheduledTransferProcessing, func(t *model.ScheduledTransfer) {
+		t.Status = model.ScheduledTransferFailed
+		t.FailureReason = reason
+		t.ExecutedAt = &at
+	})
+}
+
+// transition 仅当当前状态为 from 时修改
+func (r *ScheduledTransferRepository) transition(id uint, from string, fn func(t *model.ScheduledTransfer)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	scheduled, ok := r.s.scheduledTransfers[id]
+	if !ok || scheduled.Status != from {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "scheduled transfer is no longer "+from)
+	}
+	fn(&scheduled)
+	scheduled.UpdatedAt = r.s.now()
+	r.s.scheduledTransfers[id] = scheduled
+	return nil
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// SessionRepository 会话数据访问的内存实现
+type SessionRepository struct {
+	s *Store
+}
+
+// Create 创建会话
+// ID 未设置时由 model.Session.BeforeCreate 生成
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session) error {
+	if err := r.s.fail(OpSessionCreate); err != nil {
+		return err
+	}
+
+	if err := session.BeforeCreate(nil); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.sessions[session.ID]; ok {
+		return apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "session already exists")
+	}
+
+	now := r.s.now()
+	if session.LastUsedAt.IsZero() {
+		session.LastUsedAt = now
+	}
+	session.CreatedAt = now
+	r.s.sessions[session.ID] = *session
+	return nil
+}
+
+// GetByID 根据ID查询会话
+// id: UUID 字符串格式
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*model.Session, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "invalid session id")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	session, ok := r.s.sessions[sessionID]
+	if !ok {
+		return nil, apperrors.ErrNotFound("session")
+	}
+	return &session, nil
+}
+
+// DeleteByUsername 删除用户的所有会话
+func (r *SessionRepository) DeleteByUsername(ctx context.Context, username string) error {
+	if err := r.s.fail(OpSessionDeleteByUser); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, session := range r.s.sessions {
+		if session.Username == username {
+			delete(r.s.sessions, id)
+		}
+	}
+	return nil
+}
+
+// Block 封禁会话
+func (r *SessionRepository) Block(ctx context.Context, id string) error {
+	if err := r.s.fail(OpSessionBlock); err != nil {
+		return err
+	}
+
+	return r.update(id, func(s *model.Session) { s.IsBlocked = true })
+}
+
+// Touch 更新会话的最后使用时间
+func (r *SessionRepository) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	if err := r.s.fail(OpSessionTouch); err != nil {
+		return err
+	}
+
+	return r.update(id, func(s *model.Session) { s.LastUsedAt = usedAt })
+}
+
+// update 修改一个已存在的会话
+func (r *SessionRepository) update(id string, fn func(s *model.Session)) error {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "invalid session id")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	session, ok := r.s.sessions[sessionID]
+	if !ok {
+		return apperrors.ErrNotFound("session")
+	}
+	fn(&session)
+	r.s.sessions[sessionID] = session
+	return nil
+}
@@ -0,0 +1,264 @@
+// Package memory 提供基于内存的 Repository 实现
+//
+// 所有实现都满足 service 包定义的 Repository 接口，可以在 Service 测试中
+// 直接替换 GORM 实现，不需要 MySQL 也不需要手写 mock:
+//
+//	repos := memory.New()
+//	transferService := service.NewTransferService(repos.TxManager,
+//	    repos.Accounts, repos.Transfers, repos.Entries, auditor, limits)
+//
+// 数据以值的形式保存在 map 中，读写都会复制，调用方修改返回值不会影响存储
+// 错误与 GORM 实现保持一致 (同样返回 *apperrors.AppError)
+//
+// TxManager 支持回滚: 事务函数返回错误时，事务内的所有写入都会被撤销。
+// 配合 Store.FailOn 可以让事务在指定的一步失败，验证不会留下部分写入:
+//
+//	repos.Store.FailOn(memory.OpAccountUpdateBalances, 1, errors.New("boom"))
+//	_, err := transferService.CreateTransfer(ctx, owner, req)
+//	// err 非空，repos.Transfers 中没有新的转账记录
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// Store 保存所有表的数据
+// mu 保护单次读写，txMu 保证事务串行执行 (见 TxManager)
+type Store struct {
+	mu   sync.Mutex
+	txMu sync.Mutex
+
+	users     map[uint]model.User
+	accounts  map[uint]model.Account
+	transfers map[uint]model.Transfer
+	entries   map[uint]model.Entry
+	sessions  map[uuid.UUID]model.Session
+	auditLogs map[uint]model.AuditLog
+	apiKeys   map[uint]model.APIKey
+
+	scheduledTransfers map[uint]model.ScheduledTransfer
+	recurringTransfers map[uint]model.RecurringTransfer
+
+	lastID map[string]uint // 每张表的自增 ID
+
+	faults map[string]*fault // 待触发的故障 (见 FailOn)，不参与快照和回滚
+
+	now func() time.Time
+}
+
+// NewStore 创建空的 Store
+func NewStore() *Store {
+	return &Store{
+		users:     make(map[uint]model.User),
+		accounts:  make(map[uint]model.Account),
+		transfers: make(map[uint]model.Transfer),
+		entries:   make(map[uint]model.Entry),
+		sessions:  make(map[uuid.UUID]model.Session),
+		auditLogs: make(map[uint]model.AuditLog),
+		apiKeys:   make(map[uint]model.APIKey),
+
+		scheduledTransfers: make(map[uint]model.ScheduledTransfer),
+		recurringTransfers: make(map[uint]model.RecurringTransfer),
+
+		lastID: make(map[string]uint),
+		faults: make(map[string]*fault),
+		now:    time.Now,
+	}
+}
+
+// nextID 返回 table 的下一个自增 ID，调用方必须持有 mu
+func (s *Store) nextID(table string) uint {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// snapshot 复制当前所有数据，用于事务回滚
+func (s *Store) snapshot() *Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Store{
+		users:     maps.Clone(s.users),
+		accounts:  maps.Clone(s.accounts),
+		transfers: maps.Clone(s.transfers),
+		entries:   maps.Clone(s.entries),
+		sessions:  maps.Clone(s.sessions),
+		auditLogs: maps.Clone(s.auditLogs),
+		apiKeys:   maps.Clone(s.apiKeys),
+
+		scheduledTransfers: maps.Clone(s.scheduledTransfers),
+		recurringTransfers: maps.Clone(s.recurringTransfers),
+
+		lastID: maps.Clone(s.lastID),
+	}
+}
+
+// restore 用快照覆盖当前数据
+func (s *Store) restore(snap *Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = snap.users
+	s.accounts = snap.accounts
+	s.transfers = snap.transfers
+	s.entries = snap.entries
+	s.sessions = snap.sessions
+	s.auditLogs = snap.auditLogs
+	s.apiKeys = snap.apiKeys
+	s.scheduledTransfers = snap.scheduledTransfers
+	s.recurringTransfers = snap.recurringTransfers
+	s.lastID = snap.lastID
+}
+
+// ==================== 完整的 Repository 集合 ====================
+
+// Repositories 共享同一个 Store 的全部内存 Repository
+type Repositories struct {
+	Store     *Store
+	Users     *UserRepository
+	Accounts  *AccountRepository
+	Transfers *TransferRepository
+	Entries   *EntryRepository
+	Sessions  *SessionRepository
+	AuditLogs *AuditLogRepository
+	APIKeys   *APIKeyRepository
+	TxManager *TxManager
+
+	ScheduledTransfers *ScheduledTransferRepository
+	RecurringTransfers *RecurringTransferRepository
+}
+
+// New 创建一组共享存储的内存 Repository
+func New() *Repositories {
+	store := NewStore()
+	return &Repositories{
+		Store:     store,
+		Users:     &UserRepository{s: store},
+		Accounts:  &AccountRepository{s: store},
+		Transfers: &TransferRepository{s: store},
+		Entries:   &EntryRepository{s: store},
+		Sessions:  &SessionRepository{s: store},
+		AuditLogs: &AuditLogRepository{s: store},
+		APIKeys:   &APIKeyRepository{s: store},
+		TxManager: &TxManager{s: store},
+
+		ScheduledTransfers: &ScheduledTransferRepository{s: store},
+		RecurringTransfers: &RecurringTransferRepository{s: store},
+	}
+}
+
+// ==================== 事务 ====================
+
+// TxManager 内存事务管理器
+//
+// 事务之间串行执行 (相当于整个库的排他锁)，因此 GetForUpdate 不需要额外加锁；
+// fc 返回错误 (包括 FailOn 注入的错误) 时把数据恢复到事务开始前的快照，模拟回滚
+// 与 GORM 实现一样，事务通过 Context 传递
+// 注意: 回滚同样会丢弃事务期间在事务之外写入的数据，测试中应避免两者并发
+type TxManager struct {
+	s *Store
+}
+
+// txKey 标记 Context 已处于事务中
+type txKey struct{}
+
+// Transaction 在事务中执行 fc
+// 已在事务中时不再加锁，失败时只回滚本层 (类似 SAVEPOINT)
+func (t *TxManager) Transaction(ctx context.Context, fc func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) == nil {
+		t.s.txMu.Lock()
+		defer t.s.txMu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, struct{}{})
+	}
+
+	snap := t.s.snapshot()
+	if err := fc(ctx); err != nil {
+		t.s.restore(snap)
+		return err
+	}
+	return nil
+}
+
+// ==================== 辅助函数 ====================
+
+// sortItems 按 API 的排序参数对 items 排序，语义与 GORM 实现的 sortOrder 一致:
+// 字段名前缀 "-" 表示降序，以 id 作为第二排序键，为空时按 ID 降序
+func sortItems[T any](items []T, sort string, id func(T) uint, fields map[string]func(a, b T) int) error {
+	byID := func(a, b T) int { return compare(id(a), id(b)) }
+	if sort == "" {
+		slices.SortFunc(items, func(a, b T) int { return -byID(a, b) })
+		return nil
+	}
+
+	column, desc := strings.CutPrefix(sort, "-")
+	cmp, ok := fields[column]
+	if !ok {
+		return apperrors.ErrInvalidParams(fmt.Sprintf("unsupported sort field %q", column))
+	}
+
+	slices.SortFunc(items, func(a, b T) int {
+		c := cmp(a, b)
+		if c == 0 {
+			c = byID(a, b)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+	return nil
+}
+
+// compare 比较两个有序值
+func compare[T int64 | uint | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// page 返回 items 中 [offset, offset+limit) 的部分和总数
+func page[T any](items []T, limit, offset int) ([]T, int64) {
+	total := int64(len(items))
+	if offset >= len(items) {
+		return []T{}, total
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end], total
+}
+
+// inRange 检查 t 是否在时间范围 [From, To) 内，零值端不限制
+func inRange(t time.Time, period model.TimeRange) bool {
+	if !period.From.IsZero() && t.Before(period.From) {
+		return false
+	}
+	if !period.To.IsZero() && !t.Before(period.To) {
+		return false
+	}
+	return true
+}
+
+// sortedValues 返回 map 中按 ID 升序排列的值
+func sortedValues[T any](m map[uint]T) []T {
+	ids := slices.Sorted(maps.Keys(m))
+	values := make([]T, len(ids))
+	for i, id := range ids {
+		values[i] = m[id]
+	}
+	return values
+}
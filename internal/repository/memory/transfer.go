@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// TransferRepository 转账数据访问的内存实现
+type TransferRepository struct {
+	s *Store
+}
+
+// Create 创建转账记录
+// 参考号和公开ID由 model.Transfer.BeforeCreate 生成
+func (r *TransferRepository) Create(ctx context.Context, transfer *model.Transfer) error {
+	if err := r.s.fail(OpTransferCreate); err != nil {
+		return err
+	}
+
+	if err := transfer.BeforeCreate(nil); err != nil {
+		return apperrors.ErrDatabase(err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, t := range r.s.transfers {
+		if t.Reference == transfer.Reference {
+			return apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "transfer reference already exists")
+		}
+		if transfer.ReversalOf != nil && t.ReversalOf != nil && *t.ReversalOf == *transfer.ReversalOf {
+			return apperrors.NewWithMessage(apperrors.CodeStateConflict, "transfer already reversed")
+		}
+	}
+
+	transfer.ID = r.s.nextID("transfers")
+	transfer.CreatedAt = r.s.now()
+	r.s.transfers[transfer.ID] = *transfer
+	return nil
+}
+
+// GetByID 根据ID查询转账
+func (r *TransferRepository) GetByID(ctx context.Context, id uint) (*model.Transfer, error) {
+	return r.find(func(t *model.Transfer) bool { return t.ID == id })
+}
+
+// GetByReference 根据参考号查询转账
+func (r *TransferRepository) GetByReference(ctx context.Context, reference string) (*model.Transfer, error) {
+	return r.find(func(t *model.Transfer) bool { return t.Reference == reference })
+}
+
+// GetByPublicID 根据公开ID查询转账
+func (r *TransferRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Transfer, error) {
+	return r.find(func(t *model.Transfer) bool { return t.PublicID == publicID })
+}
+
+// PublicIDs 批量查询转账的公开ID
+func (r *TransferRepository) PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	result := make(map[uint]uuid.UUID, len(ids))
+	for _, id := range ids {
+		if t, ok := r.s.transfers[id]; ok {
+			result[id] = t.PublicID
+		}
+	}
+	return result, nil
+}
+
+// GetReversal 查询原转账的撤销转账，未被撤销时返回 404
+func (r *TransferRepository) GetReversal(ctx context.Context, transferID uint) (*model.Transfer, error) {
+	return r.find(func(t *model.Transfer) bool { return t.ReversalOf != nil && *t.ReversalOf == transferID })
+}
+
+// ListByAccountID 获取与账户相关的所有转账
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *TransferRepository) ListByAccountID(ctx context.Context, accountID uint, sort string, limit, offset int) ([]model.Transfer, int64, error) {
+	transfers := r.filter(func(t *model.Transfer) bool {
+		return t.FromAccountID == accountID || t.ToAccountID == accountID
+	})
+
+	err := sortItems(transfers, sort, func(t model.Transfer) uint { return t.ID }, map[string]func(a, b model.Transfer) int{
+		"id":         func(a, b model.Transfer) int { return compare(a.ID, b.ID) },
+		"created_at": func(a, b model.Transfer) int { return a.CreatedAt.Compare(b.CreatedAt) },
+		"amount":     func(a, b model.Transfer) int { return compare(a.Amount, b.Amount) },
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := page(transfers, limit, offset)
+	return items, total, nil
+}
+
+// ListBetween 按方向获取转账，fromAccountID、toAccountID 为 0 时不按该方向过滤
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *TransferRepository) ListBetween(ctx context.Context, fromAccountID, toAccountID uint, sort string, limit, offset int) ([]model.Transfer, int64, error) {
+	transfers := r.filter(func(t *model.Transfer) bool {
+		return (fromAccountID == 0 || t.FromAccountID == fromAccountID) &&
+			(toAccountID == 0 || t.ToAccountID == toAccountID)
+	})
+
+	err := sortItems(transfers, sort, func(t model.Transfer) uint { return t.ID }, map[string]func(a, b model.Transfer) int{
+		"id":         func(a, b model.Transfer) int { return compare(a.ID, b.ID) },
+		"created_at": func(a, b model.Transfer) int { return a.CreatedAt.Compare(b.CreatedAt) },
+		"amount":     func(a, b model.Transfer) int { return compare(a.Amount, b.Amount) },
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := page(transfers, limit, offset)
+	return items, total, nil
+}
+
+// find 返回第一个满足 match 的转账副本
+func (r *TransferRepository) find(match func(t *model.Transfer) bool) (*model.Transfer, error) {
+	transfers := r.filter(match)
+	if len(transfers) == 0 {
+		return nil, apperrors.ErrNotFound("transfer")
+	}
+	return &transfers[0], nil
+}
+
+// filter 返回所有满足 match 的转账副本，按 ID 升序
+func (r *TransferRepository) filter(match func(t *model.Transfer) bool) []model.Transfer {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var transfers []model.Transfer
+	for _, t := range sortedValues(r.s.transfers) {
+		if match(&t) {
+			transfers = append(transfers, t)
+		}
+	}
+	return transfers
+}
+
+// StatsByAccountID 统计账户在时间范围内的转入、转出和单笔最大金额
+func (r *TransferRepository) StatsByAccountID(ctx context.Context, accountID uint, period model.TimeRange) (*model.TransferStats, error) {
+	transfers := r.filter(func(t *model.Transfer) bool {
+		return (t.FromAccountID == accountID || t.ToAccountID == accountID) && inRange(t.CreatedAt, period)
+	})
+
+	var stats model.TransferStats
+	for _, t := range transfers {
+		if t.FromAccountID == accountID {
+			stats.TotalSent += t.Amount
+			stats.SentCount++
+		}
+		if t.ToAccountID == accountID {
+			stats.TotalReceived += t.Amount
+			stats.ReceivedCount++
+		}
+		stats.Largest = max(stats.Largest, t.Amount)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// UserRepository 用户数据访问的内存实现
+type UserRepository struct {
+	s *Store
+}
+
+// Create 创建新用户
+// 用户名或邮箱已存在时返回 CodeUsernameExists (与唯一索引冲突时的行为一致)
+func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
+	if err := r.s.fail(OpUserCreate); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range r.s.users {
+		if u.Username == user.Username || u.Email == user.Email {
+			return apperrors.New(apperrors.CodeUsernameExists)
+		}
+	}
+
+	now := r.s.now()
+	user.ID = r.s.nextID("users")
+	if user.Role == "" {
+		user.Role = model.RoleUser
+	}
+	if user.PasswordChangedAt.IsZero() {
+		user.PasswordChangedAt = now
+	}
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+// GetByUsername 根据用户名查询用户
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.Username == username })
+}
+
+// GetByEmail 根据邮箱查询用户
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.Email == email })
+}
+
+// GetByID 根据ID查询用户
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.ID == id })
+}
+
+// Update 更新用户信息
+func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
+	if err := r.s.fail(OpUserUpdate); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[user.ID]; !ok {
+		return apperrors.ErrUserNotFound()
+	}
+	user.UpdatedAt = r.s.now()
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+// find 返回第一个满足 match 的用户副本
+func (r *UserRepository) find(match func(u *model.User) bool) (*model.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, u := range r.s.users {
+		if match(&u) {
+			return &u, nil
+		}
+	}
+	return nil, apperrors.ErrUserNotFound()
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// paginate 对同一个查询执行 COUNT 和分页 Find
+//
+// query 应已设置 Model 和 WHERE 条件，两次查询共用同一组条件，
+// 避免总数和列表的过滤条件不一致
+//
+// 使用示例:
+//
+//	query := conn(ctx, r.db).Model(&model.Entry{}).Where("account_id = ?", accountID)
+//	return paginate[model.Entry](query, "id DESC", limit, offset)
+func paginate[T any](query *gorm.DB, order string, limit, offset int) ([]T, int64, error) {
+	// 新建 Session 使 query 可被安全复用，Count 不会污染后续的 Find
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, apperrors.ErrDatabase(err)
+	}
+
+	var items []T
+	if err := query.
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Find(&items).Error; err != nil {
+		return nil, 0, apperrors.ErrDatabase(err)
+	}
+
+	return items, total, nil
+}
+
+// defaultOrder 未指定排序时的默认顺序 (最新的在前)
+const defaultOrder = "id DESC"
+
+// sortOrder 将 API 的排序参数转换为 ORDER BY 子句
+//
+// sort 格式为字段名，前缀 "-" 表示降序，例如 "created_at"、"-amount"
+// 字段必须在 allowed 白名单中，防止通过列名进行 SQL 注入
+// 以 id 作为第二排序键，保证分页结果稳定
+func sortOrder(sort string, allowed ...string) (string, error) {
+	if sort == "" {
+		return defaultOrder, nil
+	}
+
+	column, desc := strings.CutPrefix(sort, "-")
+	if !slices.Contains(allowed, column) {
+		return "", apperrors.ErrInvalidParams(fmt.Sprintf("unsupported sort field %q", column))
+	}
+
+	if desc {
+		return column + " DESC, id DESC", nil
+	}
+	return column + " ASC, id ASC", nil
+}
+
+// qualifyOrder 为 sortOrder 生成的 ORDER BY 子句中的每一列加上表名前缀
+// 用于 JOIN 查询，避免 id、created_at 等两张表都有的列产生歧义
+func qualifyOrder(table, order string) string {
+	columns := strings.Split(order, ", ")
+	for i, column := range columns {
+		columns[i] = table + "." + column
+	}
+	return strings.Join(columns, ", ")
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestSortOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		want string
+	}{
+		{"default", "", "id DESC"},
+		{"ascending", "amount", "amount ASC, id ASC"},
+		{"descending", "-created_at", "created_at DESC, id DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortOrder(tt.sort, "id", "created_at", "amount")
+			if err != nil {
+				t.Fatalf("sortOrder(%q): %v", tt.sort, err)
+			}
+			if got != tt.want {
+				t.Errorf("sortOrder(%q) = %q, want %q", tt.sort, got, tt.want)
+			}
+		})
+	}
+
+	// 白名单以外的字段 (包括注入的 SQL) 被拒绝
+	for _, sort := range []string{"balance", "-owner", "amount; DROP TABLE transfers"} {
+		_, err := sortOrder(sort, "id", "created_at", "amount")
+		if appErr := apperrors.AsAppError(err); appErr == nil || appErr.Code != apperrors.CodeInvalidParams {
+			t.Errorf("sortOrder(%q) error = %v, want CodeInvalidParams", sort, err)
+		}
+	}
+}
+
+// exactSQL 完整匹配一条 SQL
+func exactSQL(sql string) string {
+	return "^" + regexp.QuoteMeta(sql) + "$"
+}
+
+func TestPaginateSharesWhereClause(t *testing.T) {
+	db, mock := newMockDB(t)
+	ctx := context.Background()
+
+	// COUNT 与分页查询使用相同的 WHERE 条件，ORDER BY / LIMIT / OFFSET 只用于分页查询
+	mock.ExpectQuery(exactSQL("SELECT count(*) FROM `entries` WHERE account_id = ?")).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(exactSQL("SELECT * FROM `entries` WHERE account_id = ? ORDER BY amount ASC, id ASC LIMIT ? OFFSET ?")).
+		WithArgs(7, 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "amount"}).
+			AddRow(21, 7, 100).
+			AddRow(22, 7, 200))
+
+	query := conn(ctx, db).Model(&model.Entry{}).Where("account_id = ?", 7)
+	entries, total, err := paginate[model.Entry](query, "amount ASC, id ASC", 10, 20)
+	if err != nil {
+		t.Fatalf("paginate: %v", err)
+	}
+	if total != 25 || len(entries) != 2 || entries[0].ID != 21 {
+		t.Errorf("got %d of %d entries (%+v), want 2 of 25", len(entries), total, entries)
+	}
+}
+
+func TestPaginateCountErrorSkipsFind(t *testing.T) {
+	db, mock := newMockDB(t)
+	ctx := context.Background()
+	boom := errors.New("connection reset")
+
+	// 没有为分页查询设置预期: COUNT 失败后仍执行 Find 会得到 sqlmock 的错误而不是 boom
+	mock.ExpectQuery(exactSQL("SELECT count(*) FROM `entries` WHERE account_id = ?")).
+		WithArgs(7).
+		WillReturnError(boom)
+
+	query := conn(ctx, db).Model(&model.Entry{}).Where("account_id = ?", 7)
+	entries, total, err := paginate[model.Entry](query, defaultOrder, 10, 0)
+	if appErr := apperrors.AsAppError(err); appErr == nil || appErr.Code != apperrors.CodeDatabaseError || !errors.Is(err, boom) {
+		t.Fatalf("error = %v, want CodeDatabaseError caused by the COUNT", err)
+	}
+	if entries != nil || total != 0 {
+		t.Errorf("got %v, %d; want nil, 0", entries, total)
+	}
+}
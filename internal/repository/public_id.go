@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+)
+
+// publicIDs 用一次 IN 查询读取 ids 对应的公开ID，返回 ID → 公开ID
+// query 应已设置 Model；不存在的 ID 不出现在结果中
+func publicIDs(query *gorm.DB, ids []uint) (map[uint]uuid.UUID, error) {
+	result := make(map[uint]uuid.UUID, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		ID       uint
+		PublicID uuid.UUID
+	}
+	if err := query.Select("id", "public_id").Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	for _, row := range rows {
+		result[row.ID] = row.PublicID
+	}
+	return result, nil
+}
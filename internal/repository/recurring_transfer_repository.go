@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// RecurringTransferRepository 周期转账规则数据访问实现
+type RecurringTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewRecurringTransferRepository 创建 RecurringTransferRepository 实例
+func NewRecurringTransferRepository(db *gorm.DB) *RecurringTransferRepository {
+	return &RecurringTransferRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的 RecurringTransferRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *RecurringTransferRepository) WithTx(tx *gorm.DB) *RecurringTransferRepository {
+	return &RecurringTransferRepository{db: tx}
+}
+
+// Create 创建周期转账规则
+func (r *RecurringTransferRepository) Create(ctx context.Context, recurring *model.RecurringTransfer) error {
+	if err := conn(ctx, r.db).Create(recurring).Error; err != nil {
+		return wrapDBError(err, nil)
+	}
+	return nil
+}
+
+// GetByID 根据ID查询周期转账规则
+func (r *RecurringTransferRepository) GetByID(ctx context.Context, id uint) (*model.RecurringTransfer, error) {
+	var recurring model.RecurringTransfer
+	result := conn(ctx, r.db).First(&recurring, id)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("recurring transfer"))
+	}
+	return &recurring, nil
+}
+
+// GetByPublicID 根据公开ID查询周期转账规则
+func (r *RecurringTransferRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.RecurringTransfer, error) {
+	var recurring model.RecurringTransfer
+	result := conn(ctx, r.db).Where("public_id = ?", publicID).First(&recurring)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("recurring transfer"))
+	}
+	return &recurring, nil
+}
+
+// ListByFromAccountID 获取源账户的所有周期转账规则 (带分页，按 ID 降序)
+func (r *RecurringTransferRepository) ListByFromAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.RecurringTransfer, int64, error) {
+	query := conn(ctx, r.db).
+		Model(&model.RecurringTransfer{}).
+		Where("from_account_id = ?", accountID)
+
+	return paginate[model.RecurringTransfer](query, "id DESC", limit, offset)
+}
+
+// SetOccurrenceCount 更新已生成的次数
+// 仅当规则仍为 active 且次数为 from 时更新，避免重复生成同一次
+func (r *RecurringTransferRepository) SetOccurrenceCount(ctx context.Context, id uint, from, to int) error {
+	result := conn(ctx, r.db).
+		Model(&model.RecurringTransfer{}).
+		Where("id = ? AND status = ? AND occurrence_count = ?", id, model.RecurringTransferActive, from).
+		Update("occurrence_count", to)
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "recurring transfer has changed")
+	}
+	return nil
+}
+
+// Finish 结束一条生效中的规则 (active → completed / cancelled)
+// 规则已结束时返回 CodeStateConflict
+func (r *RecurringTransferRepository) Finish(ctx context.Context, id uint, status string) error {
+	result := conn(ctx, r.db).
+		Model(&model.RecurringTransfer{}).
+		Where("id = ? AND status = ?", id, model.RecurringTransferActive).
+		Update("status", status)
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "recurring transfer is no longer active")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// ScheduledTransferRepository 定时转账数据访问实现
+type ScheduledTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledTransferRepository 创建 ScheduledTransferRepository 实例
+func NewScheduledTransferRepository(db *gorm.DB) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{db: db}
+}
+
+// WithTx 返回绑定到事务 tx 的 ScheduledTransferRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *ScheduledTransferRepository) WithTx(tx *gorm.DB) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{db: tx}
+}
+
+// Create 创建定时转账
+func (r *ScheduledTransferRepository) Create(ctx context.Context, scheduled *model.ScheduledTransfer) error {
+	if err := conn(ctx, r.db).Create(scheduled).Error; err != nil {
+		return wrapDBError(err, nil)
+	}
+	return nil
+}
+
+// GetByPublicID 根据公开ID查询定时转账
+func (r *ScheduledTransferRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.ScheduledTransfer, error) {
+	var scheduled model.ScheduledTransfer
+	result := conn(ctx, r.db).Where("public_id = ?", publicID).First(&scheduled)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("scheduled transfer"))
+	}
+	return &scheduled, nil
+}
+
+// ListDue 查询到期 (execute_at <= now) 且仍在等待的定时转账
+// 按计划执行时间升序返回，最多 limit 条
+func (r *ScheduledTransferRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]model.ScheduledTransfer, error) {
+	var due []model.ScheduledTransfer
+	result := conn(ctx, r.db).
+		Where("status = ? AND execute_at <= ?", model.ScheduledTransferPending, now).
+		Order("execute_at, id").
+		Limit(limit).
+		Find(&due)
+	if result.Error != nil {
+		return nil, apperrors.ErrDatabase(result.Error)
+	}
+	return due, nil
+}
+
+// Claim 领取一条等待中的定时转账 (pending → processing)
+// 已被取消或被其他实例领取时返回 CodeStateConflict
+func (r *ScheduledTransferRepository) Claim(ctx context.Context, id uint) error {
+	return r.transition(ctx, id, model.ScheduledTransferPending, map[string]any{
+		"status": model.ScheduledTransferProcessing,
+	})
+}
+
+// Cancel 取消一条等待中的定时转账 (pending → cancelled)
+// 已开始执行或已结束时返回 CodeStateConflict
+func (r *ScheduledTransferRepository) Cancel(ctx context.Context, id uint) error {
+	return r.transition(ctx, id, model.ScheduledTransferPending, map[string]any{
+		"status": model.ScheduledTransferCancelled,
+	})
+}
+
+// CancelPendingByRecurring 取消周期转账规则生成的、仍在等待的定时转账
+// 已被后台任务领取的不受影响
+func (r *ScheduledTransferRepository) CancelPendingByRecurring(ctx context.Context, recurringID uint) error {
+	result := conn(ctx, r.db).
+		Model(&model.ScheduledTransfer{}).
+		Where("recurring_transfer_id = ? AND status = ?", recurringID, model.ScheduledTransferPending).
+		Update("status", model.ScheduledTransferCancelled)
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	return nil
+}
+
+// MarkExecuted 记录执行成功 (processing → executed)
+func (r *ScheduledTransferRepository) MarkExecuted(ctx context.Context, id, transferID uint, at time.Time) error {
+	return r.transition(ctx, id, model.ScheduledTransferProcessing, map[string]any{
+		"status":      model.ScheduledTransferExecuted,
+		"transfer_id": transferID,
+		"executed_at": at,
+	})
+}
+
+// MarkFailed 记录执行失败 (processing → failed)
+func (r *ScheduledTransferRepository) MarkFailed(ctx context.Context, id uint, reason string, at time.Time) error {
+	return r.transition(ctx, id, model.ScheduledTransferProcessing, map[string]any{
+		"status":         model.ScheduledTransferFailed,
+		"failure_reason": reason,
+		"executed_at":    at,
+	})
+}
+
+// transition 仅当当前状态为 from 时更新，保证状态只按预期流转
+func (r *ScheduledTransferRepository) transition(ctx context.Context, id uint, from string, updates map[string]any) error {
+	result := conn(ctx, r.db).
+		Model(&model.ScheduledTransfer{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.NewWithMessage(apperrors.CodeStateConflict, "scheduled transfer is no longer "+from)
+	}
+	return nil
+}
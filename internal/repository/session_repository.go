@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -21,11 +21,17 @@ func NewSessionRepository(db *gorm.DB) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的 SessionRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *SessionRepository) WithTx(tx *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: tx}
+}
+
 // Create 创建会话
 func (r *SessionRepository) Create(ctx context.Context, session *model.Session) error {
-	result := r.db.WithContext(ctx).Create(session)
+	result := conn(ctx, r.db).Create(session)
 	if result.Error != nil {
-		return apperrors.ErrDatabase(result.Error)
+		return wrapDBError(result.Error, nil)
 	}
 	return nil
 }
@@ -39,12 +45,9 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*model.Sess
 	}
 
 	var session model.Session
-	result := r.db.WithContext(ctx).Where("id = ?", sessionID).First(&session)
+	result := conn(ctx, r.db).Where("id = ?", sessionID).First(&session)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrNotFound("session")
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("session"))
 	}
 	return &session, nil
 }
@@ -52,7 +55,7 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*model.Sess
 // DeleteByUsername 删除用户的所有会话
 // 用于"登出所有设备"功能
 func (r *SessionRepository) DeleteByUsername(ctx context.Context, username string) error {
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Where("username = ?", username).
 		Delete(&model.Session{})
 	if result.Error != nil {
@@ -69,7 +72,7 @@ func (r *SessionRepository) Block(ctx context.Context, id string) error {
 		return apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "invalid session id")
 	}
 
-	result := r.db.WithContext(ctx).
+	result := conn(ctx, r.db).
 		Model(&model.Session{}).
 		Where("id = ?", sessionID).
 		Update("is_blocked", true)
@@ -81,3 +84,23 @@ func (r *SessionRepository) Block(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// Touch 更新会话的最后使用时间
+// 用于空闲超时判断，每次刷新 token 时调用；会话不存在时什么都不做
+func (r *SessionRepository) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "invalid session id")
+	}
+
+	result := conn(ctx, r.db).
+		Model(&model.Session{}).
+		Where("id = ?", sessionID).
+		Update("last_used_at", usedAt)
+	if result.Error != nil {
+		return apperrors.ErrDatabase(result.Error)
+	}
+	// 不检查 RowsAffected: last_used_at 精确到秒，同一秒内再次刷新时 MySQL 的 RowsAffected 为 0
+	// 调用方刚读取过会话，不需要再确认会话是否存在
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+func TestSessionCreateGeneratesMissingID(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewSessionRepository(db)
+
+	for range 2 {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `sessions`")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	// 未设置 ID 时由 BeforeCreate 生成
+	generated := &model.Session{Username: "alice", RefreshToken: "token", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.Create(context.Background(), generated); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if generated.ID == uuid.Nil {
+		t.Error("session id was not generated")
+	}
+
+	// Service 显式设置的 ID 保持不变
+	explicitID := uuid.New()
+	explicit := &model.Session{ID: explicitID, Username: "alice", RefreshToken: "token", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.Create(context.Background(), explicit); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if explicit.ID != explicitID {
+		t.Errorf("session id = %s, want %s", explicit.ID, explicitID)
+	}
+}
+
+func TestSessionTouchWithinSameSecond(t *testing.T) {
+	db, mock := newMockDB(t)
+	id := uuid.New()
+	usedAt := time.Now()
+
+	// 同一秒内再次刷新时 last_used_at 不变，MySQL 报告 0 行受影响
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sessions` SET `last_used_at`=? WHERE id = ?")).
+		WithArgs(usedAt, id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := NewSessionRepository(db).Touch(context.Background(), id.String(), usedAt); err != nil {
+		t.Errorf("Touch: %v", err)
+	}
+}
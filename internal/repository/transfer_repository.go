@@ -2,8 +2,8 @@ package repository
 
 import (
 	"context"
-	"errors"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
@@ -20,50 +20,136 @@ func NewTransferRepository(db *gorm.DB) *TransferRepository {
 	return &TransferRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的 TransferRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *TransferRepository) WithTx(tx *gorm.DB) *TransferRepository {
+	return &TransferRepository{db: tx}
+}
+
 // Create 创建转账记录
 func (r *TransferRepository) Create(ctx context.Context, transfer *model.Transfer) error {
-	result := r.db.WithContext(ctx).Create(transfer)
+	result := conn(ctx, r.db).Create(transfer)
 	if result.Error != nil {
-		return apperrors.ErrDatabase(result.Error)
+		if isDuplicateKey(result.Error) {
+			if transfer.ReversalOf != nil {
+				return errAlreadyReversed()
+			}
+			return apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "transfer reference already exists")
+		}
+		return wrapDBError(result.Error, nil)
 	}
 	return nil
 }
 
+// errAlreadyReversed 返回原转账已被撤销的错误
+func errAlreadyReversed() error {
+	return apperrors.NewWithMessage(apperrors.CodeStateConflict, "transfer already reversed")
+}
+
 // GetByID 根据ID查询转账
 func (r *TransferRepository) GetByID(ctx context.Context, id uint) (*model.Transfer, error) {
 	var transfer model.Transfer
-	result := r.db.WithContext(ctx).First(&transfer, id)
+	result := conn(ctx, r.db).First(&transfer, id)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrNotFound("transfer")
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("transfer"))
+	}
+	return &transfer, nil
+}
+
+// GetByReference 根据参考号查询转账
+// reference 列上有唯一索引，查询最多命中一行
+func (r *TransferRepository) GetByReference(ctx context.Context, reference string) (*model.Transfer, error) {
+	var transfer model.Transfer
+	result := conn(ctx, r.db).Where("reference = ?", reference).First(&transfer)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("transfer"))
+	}
+	return &transfer, nil
+}
+
+// GetByPublicID 根据公开ID查询转账
+func (r *TransferRepository) GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Transfer, error) {
+	var transfer model.Transfer
+	result := conn(ctx, r.db).Where("public_id = ?", publicID).First(&transfer)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("transfer"))
 	}
 	return &transfer, nil
 }
 
+// PublicIDs 批量查询转账的公开ID，返回 转账ID → 公开ID
+func (r *TransferRepository) PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error) {
+	return publicIDs(conn(ctx, r.db).Model(&model.Transfer{}), ids)
+}
+
+// GetReversal 查询原转账的撤销转账
+// reversal_of 列上有唯一索引，查询最多命中一行；未被撤销时返回 404
+func (r *TransferRepository) GetReversal(ctx context.Context, transferID uint) (*model.Transfer, error) {
+	var transfer model.Transfer
+	result := conn(ctx, r.db).Where("reversal_of = ?", transferID).First(&transfer)
+	if result.Error != nil {
+		return nil, wrapDBError(result.Error, apperrors.ErrNotFound("transfer"))
+	}
+	return &transfer, nil
+}
+
+// ListBetween 按方向获取转账 (带分页)
+// fromAccountID、toAccountID 为 0 时不按该方向过滤，两者都不为 0 时只返回这两个账户之间的转账
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *TransferRepository) ListBetween(ctx context.Context, fromAccountID, toAccountID uint, sort string, limit, offset int) ([]model.Transfer, int64, error) {
+	order, err := sortOrder(sort, "id", "created_at", "amount")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := conn(ctx, r.db).Model(&model.Transfer{})
+	if fromAccountID != 0 {
+		query = query.Where("from_account_id = ?", fromAccountID)
+	}
+	if toAccountID != 0 {
+		query = query.Where("to_account_id = ?", toAccountID)
+	}
+
+	return paginate[model.Transfer](query, order, limit, offset)
+}
+
 // ListByAccountID 获取与账户相关的所有转账
-func (r *TransferRepository) ListByAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.Transfer, int64, error) {
-	var transfers []model.Transfer
-	var total int64
+// sort 支持 id、created_at、amount (前缀 "-" 表示降序)，为空时按 ID 降序
+func (r *TransferRepository) ListByAccountID(ctx context.Context, accountID uint, sort string, limit, offset int) ([]model.Transfer, int64, error) {
+	order, err := sortOrder(sort, "id", "created_at", "amount")
+	if err != nil {
+		return nil, 0, err
+	}
 
-	condition := "from_account_id = ? OR to_account_id = ?"
+	query := conn(ctx, r.db).
+		Model(&model.Transfer{}).
+		Where("from_account_id = ? OR to_account_id = ?", accountID, accountID)
 
-	if err := r.db.WithContext(ctx).
+	return paginate[model.Transfer](query, order, limit, offset)
+}
+
+// StatsByAccountID 用一次聚合查询统计账户在时间范围内的转入、转出和单笔最大金额
+// period 限制创建时间范围 (零值不限制)
+func (r *TransferRepository) StatsByAccountID(ctx context.Context, accountID uint, period model.TimeRange) (*model.TransferStats, error) {
+	query := conn(ctx, r.db).
 		Model(&model.Transfer{}).
-		Where(condition, accountID, accountID).
-		Count(&total).Error; err != nil {
-		return nil, 0, apperrors.ErrDatabase(err)
+		Select("COALESCE(SUM(CASE WHEN from_account_id = ? THEN amount ELSE 0 END), 0) AS total_sent, "+
+			"COUNT(CASE WHEN from_account_id = ? THEN 1 END) AS sent_count, "+
+			"COALESCE(SUM(CASE WHEN to_account_id = ? THEN amount ELSE 0 END), 0) AS total_received, "+
+			"COUNT(CASE WHEN to_account_id = ? THEN 1 END) AS received_count, "+
+			"COALESCE(MAX(amount), 0) AS largest",
+			accountID, accountID, accountID, accountID).
+		Where("from_account_id = ? OR to_account_id = ?", accountID, accountID)
+	if !period.From.IsZero() {
+		query = query.Where("created_at >= ?", period.From)
 	}
-
-	if err := r.db.WithContext(ctx).
-		Where(condition, accountID, accountID).
-		Order("id DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&transfers).Error; err != nil {
-		return nil, 0, apperrors.ErrDatabase(err)
+	if !period.To.IsZero() {
+		query = query.Where("created_at < ?", period.To)
 	}
 
-	return transfers, total, nil
+	var stats model.TransferStats
+	if err := query.Scan(&stats).Error; err != nil {
+		return nil, apperrors.ErrDatabase(err)
+	}
+	return &stats, nil
 }
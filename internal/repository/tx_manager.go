@@ -1,10 +1,36 @@
 package repository
 
-import "gorm.io/gorm"
+// 事务中使用 Repository 有两种方式:
+//
+//  1. 通过 Context (Service 层推荐，不依赖 gorm):
+//
+//	txManager.Transaction(ctx, func(ctx context.Context) error {
+//	    if err := transferRepo.Create(ctx, transfer); err != nil {
+//	        return err // 回滚
+//	    }
+//	    return entryRepo.Create(ctx, entry)
+//	})
+//
+//  2. 通过 WithTx 显式绑定事务句柄 (直接持有 *gorm.DB 事务时):
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//	    if err := transferRepo.WithTx(tx).Create(ctx, transfer); err != nil {
+//	        return err // 回滚
+//	    }
+//	    return entryRepo.WithTx(tx).Create(ctx, entry)
+//	})
+//
+// 两者同时存在时以 Context 中的事务为准 (见 conn)
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 // TransactionManager 定义事务管理接口
 type TransactionManager interface {
-	Transaction(fc func(tx *gorm.DB) error) error
+	Transaction(ctx context.Context, fc func(ctx context.Context) error) error
 }
 
 // GormTxManager 使用 GORM 实现事务管理
@@ -19,6 +45,24 @@ func NewTxManager(db *gorm.DB) *GormTxManager {
 
 // Transaction 执行数据库事务
 // 如果 fc 返回错误，事务会自动回滚；否则自动提交
-func (t *GormTxManager) Transaction(fc func(tx *gorm.DB) error) error {
-	return t.db.Transaction(fc)
+//
+// 事务句柄通过 fc 收到的 Context 传递，Repository 使用该 Context 时
+// 自动在事务中执行 (见 conn)，因此 fc 内的所有写操作会一起提交或回滚
+// 已在事务中时再次调用会使用 SAVEPOINT 嵌套
+func (t *GormTxManager) Transaction(ctx context.Context, fc func(ctx context.Context) error) error {
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fc(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// txKey 是 Context 中存储事务句柄的键
+type txKey struct{}
+
+// conn 返回本次操作应使用的数据库句柄
+// Context 中带有事务 (由 GormTxManager.Transaction 设置) 时使用事务句柄，否则使用 db
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// createTransferWithEntry 在一个事务中通过两个 Repository 写入转账和分录 (事务通过 Context 传递)
+func createTransferWithEntry(db *gorm.DB) error {
+	transfers := NewTransferRepository(db)
+	entries := NewEntryRepository(db)
+	return NewTxManager(db).Transaction(context.Background(), func(ctx context.Context) error {
+		transfer := &model.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 100}
+		if err := transfers.Create(ctx, transfer); err != nil {
+			return err
+		}
+		return entries.Create(ctx, &model.Entry{AccountID: 1, TransferID: &transfer.ID, Amount: -100})
+	})
+}
+
+// createTransferWithEntryWithTx 同上，通过 WithTx 绑定事务句柄
+func createTransferWithEntryWithTx(db *gorm.DB) error {
+	transfers := NewTransferRepository(db)
+	entries := NewEntryRepository(db)
+	ctx := context.Background()
+	return db.Transaction(func(tx *gorm.DB) error {
+		transfer := &model.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 100}
+		if err := transfers.WithTx(tx).Create(ctx, transfer); err != nil {
+			return err
+		}
+		return entries.WithTx(tx).Create(ctx, &model.Entry{AccountID: 1, TransferID: &transfer.ID, Amount: -100})
+	})
+}
+
+var transactionPatterns = []struct {
+	name string
+	run  func(db *gorm.DB) error
+}{
+	{"context", createTransferWithEntry},
+	{"with tx", createTransferWithEntryWithTx},
+}
+
+func TestTransactionCommitsAllRepositories(t *testing.T) {
+	for _, tt := range transactionPatterns {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+
+			mock.ExpectBegin()
+			mock.ExpectExec("INSERT INTO `transfers`").WillReturnResult(sqlmock.NewResult(7, 1))
+			mock.ExpectExec("INSERT INTO `entries`").WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+
+			if err := tt.run(db); err != nil {
+				t.Fatalf("transaction: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransactionRollsBackAllRepositories(t *testing.T) {
+	for _, tt := range transactionPatterns {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			boom := errors.New("disk full")
+
+			mock.ExpectBegin()
+			mock.ExpectExec("INSERT INTO `transfers`").WillReturnResult(sqlmock.NewResult(7, 1))
+			mock.ExpectExec("INSERT INTO `entries`").WillReturnError(boom)
+			mock.ExpectRollback()
+
+			if err := tt.run(db); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"errors"
 
 	"gorm.io/gorm"
 
@@ -20,16 +19,22 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// WithTx 返回绑定到事务 tx 的 UserRepository 副本
+// 副本的所有操作都在 tx 中执行，原实例不受影响
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
 // Create 创建新用户
 // 如果用户名或邮箱已存在，返回相应错误
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
-	result := r.db.WithContext(ctx).Create(user)
+	result := conn(ctx, r.db).Create(user)
 	if result.Error != nil {
 		// 检查是否是唯一约束冲突
-		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		if isDuplicateKey(result.Error) {
 			return apperrors.New(apperrors.CodeUsernameExists)
 		}
-		return apperrors.ErrDatabase(result.Error)
+		return wrapDBError(result.Error, nil)
 	}
 	return nil
 }
@@ -37,12 +42,9 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 // GetByUsername 根据用户名查询用户
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	result := r.db.WithContext(ctx).Where("username = ?", username).First(&user)
+	result := conn(ctx, r.db).Where("username = ?", username).First(&user)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrUserNotFound()
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrUserNotFound())
 	}
 	return &user, nil
 }
@@ -50,12 +52,9 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 // GetByEmail 根据邮箱查询用户
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
+	result := conn(ctx, r.db).Where("email = ?", email).First(&user)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrUserNotFound()
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrUserNotFound())
 	}
 	return &user, nil
 }
@@ -63,21 +62,18 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.U
 // GetByID 根据ID查询用户
 func (r *UserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	result := r.db.WithContext(ctx).First(&user, id)
+	result := conn(ctx, r.db).First(&user, id)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrUserNotFound()
-		}
-		return nil, apperrors.ErrDatabase(result.Error)
+		return nil, wrapDBError(result.Error, apperrors.ErrUserNotFound())
 	}
 	return &user, nil
 }
 
 // Update 更新用户信息
 func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
-	result := r.db.WithContext(ctx).Save(user)
+	result := conn(ctx, r.db).Save(user)
 	if result.Error != nil {
-		return apperrors.ErrDatabase(result.Error)
+		return wrapDBError(result.Error, nil)
 	}
 	return nil
 }
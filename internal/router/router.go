@@ -3,10 +3,17 @@
 package router
 
 import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/getkin/kin-openapi/routers"
 	"github.com/gin-gonic/gin"
 
+	"github.com/proyuen/simple-bank-v2/internal/config"
 	"github.com/proyuen/simple-bank-v2/internal/handler"
 	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
 	"github.com/proyuen/simple-bank-v2/pkg/token"
 )
 
@@ -23,59 +30,197 @@ type Handlers struct {
 
 	// Transfer Handler 处理转账和账目相关路由
 	Transfer *handler.TransferHandler
+
+	// ScheduledTransfer Handler 处理定时转账路由
+	ScheduledTransfer *handler.ScheduledTransferHandler
+
+	// RecurringTransfer Handler 处理周期转账路由
+	RecurringTransfer *handler.RecurringTransferHandler
+
+	// Audit Handler 处理审计日志相关路由 (管理员)
+	Audit *handler.AuditHandler
+
+	// Rate Handler 处理汇率查询路由
+	Rate *handler.RateHandler
+
+	// Event Handler 处理实时事件推送路由
+	Event *handler.EventHandler
+
+	// APIKey Handler 处理 API Key 管理路由
+	APIKey *handler.APIKeyHandler
+}
+
+// Options 路由的可选行为
+type Options struct {
+	// Runtime 可热更新配置，维护模式开启时 /api/v1 下的路由返回 503
+	Runtime *config.RuntimeStore
+
+	// ResponseEnvelope 为 true 时成功响应包装为 {"code": 0, "message": ..., "data": ...}
+	ResponseEnvelope bool
+
+	// MaxRequestBytes 请求体大小上限 (字节)，超出返回 413，<= 0 表示不限制
+	MaxRequestBytes int64
+
+	// RequestSpec 不为空时按 OpenAPI 规范校验 /api/v1 的请求体 (见 middleware.LoadOpenAPISpec)
+	RequestSpec routers.Router
+
+	// HSTS 为 true 时响应带 Strict-Transport-Security 头 (仅生产环境开启)
+	HSTS bool
+
+	// AccessLogSkipPaths 不记录访问日志的路径 (精确匹配)，如健康检查
+	AccessLogSkipPaths []string
+
+	// Draining 不为空且被设置后 (服务关闭期间)，除健康检查外的请求返回 503
+	Draining *atomic.Bool
+
+	// PasswordChanges 不为空时拒绝修改密码前签发的 Access Token
+	PasswordChanges middleware.PasswordChangeLookup
+
+	// PasswordChangeCacheTTL 修改密码时间的缓存有效期，0 表示不缓存
+	PasswordChangeCacheTTL time.Duration
+
+	// APIKeys 不为空时受保护路由同时接受 Authorization: ApiKey <key>
+	APIKeys middleware.APIKeyAuthenticator
+
+	// FreshTokenMaxAge 敏感操作要求用户在该时间之内登录，0 表示不要求
+	FreshTokenMaxAge time.Duration
+
+	// IdempotencyTTL 用户注册的 Idempotency-Key 保留时间，0 表示不支持幂等键
+	IdempotencyTTL time.Duration
+
+	// IdempotencyStore 保存幂等键的处理状态，为空时使用进程内的 MemoryIdempotencyStore
+	IdempotencyStore middleware.IdempotencyStore
+
+	// TrustedProxies 信任其 X-Forwarded-For 的代理 IP/CIDR，为空表示不信任任何代理
+	// 决定 c.ClientIP() 的取值 (审计日志)，客户端自己设置的 X-Forwarded-For 不会生效
+	TrustedProxies []string
+
+	// LoginRateLimit 每个 IP 在 LoginRateWindow 内允许的登录请求数，0 表示不限流
+	LoginRateLimit  int
+	LoginRateWindow time.Duration
+
+	// ResponseCacheTTL 账户、转账列表等热点 GET 接口的响应缓存时间，0 表示不缓存
+	ResponseCacheTTL time.Duration
 }
 
+// 响应缓存的资源 (见 middleware.ResponseCache)
+// 转账改变双方账户的余额，因此同时失效账户和转账的缓存
+const (
+	cacheAccounts  = "accounts"
+	cacheTransfers = "transfers"
+)
+
 // ==================== 路由配置 ====================
 
 // SetupRouter 配置并返回 Gin 路由引擎
 //
-// 路由结构:
+// 路由结构 (路径中的 :id 均为公开ID，即 UUID):
+//
+// 受保护路由的读操作需要 *:read，写操作需要的 scope 标注在方括号中 (见 token 包的 Scope* 常量)
+// 标注 (step-up) 的路由要求最近登录 (见 Options.FreshTokenMaxAge)
+// 标注 (cached) 的路由在 Options.ResponseCacheTTL 内按用户缓存响应，账户或转账的写操作成功后失效
 //
 //	/api/v1
 //	├── /users              (公开)
-//	│   ├── POST /          → 用户注册
-//	│   └── POST /login     → 用户登录
+//	│   ├── POST /          → 用户注册 (支持 Idempotency-Key)
+//	│   └── POST /login     → 用户登录 (按 IP 限流，超限返回 429 + Retry-After)
 //	├── /tokens             (公开)
 //	│   └── POST /renew     → 刷新 Token
+//	├── /rates              (公开)
+//	│   └── GET /           → 查询汇率
+//	├── /ws                 (握手时认证)
+//	│   └── GET /           → 转账入账通知 (WebSocket)
 //	├── /accounts           (需认证)
-//	│   ├── POST /          → 创建账户
-//	│   ├── GET /           → 获取账户列表
+//	│   ├── POST /          → 创建账户 [accounts:write]
+//	│   ├── POST /batch     → 批量创建账户 [accounts:write]
+//	│   ├── GET /           → 获取账户列表 (cached)
+//	│   ├── GET /summary    → 按货币汇总余额 (cached)
 //	│   ├── GET /:id        → 获取账户详情
-//	│   └── GET /:id/entries → 获取账目记录
+//	│   ├── PATCH /:id      → 修改账户名称 [accounts:write]
+//	│   ├── GET /:id/entries → 获取账目记录
+//	│   ├── GET /:id/entries/export → 导出账目 (CSV/JSON)
+//	│   ├── GET /:id/history → 交易历史 (合并账目和转账)
+//	│   ├── GET /:id/stats  → 转账统计 (?period=30d)
+//	│   ├── GET /:id/statement → 月度对账单
+//	│   ├── GET /:id/statement.pdf → 月度对账单 (PDF)
+//	│   ├── GET /:id/events → 订阅余额变动 (SSE)
+//	│   ├── POST /:id/recurring-transfers → 创建周期转账 [transfers:write]
+//	│   ├── GET /:id/recurring-transfers  → 获取周期转账列表
+//	│   └── DELETE /:id/recurring-transfers/:recurring_id → 取消周期转账 [transfers:write]
+//	├── /entries            (需认证)
+//	│   └── GET /           → 获取所有账户的账目记录 (cached)
+//	├── /api-keys           (需认证，不接受 API Key 和只读 Token)
+//	│   ├── POST /          → 创建 API Key (step-up)
+//	│   ├── GET /           → 获取 API Key 列表
+//	│   └── DELETE /:id     → 撤销 API Key (step-up)
 //	└── /transfers          (需认证)
-//	    ├── POST /          → 创建转账
-//	    └── GET /           → 获取转账记录
+//	    ├── POST /          → 创建转账 [transfers:write]
+//	    ├── POST /preview   → 预览转账 (不实际转账) [transfers:write]
+//	    ├── GET /           → 获取转账记录 (cached)
+//	    ├── GET /:id        → 根据公开ID获取转账
+//	    ├── POST /:id/reverse → 撤销转账 [transfers:write] (step-up)
+//	    └── GET /ref/:reference → 根据参考号获取转账
+//	└── /admin              (需认证 + 管理员)
+//	    ├── GET /audit-logs → 查询审计日志
+//	    ├── PUT /accounts/:id/overdraft-limit → 设置透支额度 [accounts:write]
+//	    ├── PUT /accounts/:id/min-balance → 设置最低余额 [accounts:write]
+//	    ├── POST /accounts/:id/freeze → 冻结账户 [accounts:write]
+//	    ├── POST /accounts/:id/unfreeze → 解冻账户 [accounts:write]
+//	    └── POST /accounts/:id/restore → 恢复已关闭账户 [accounts:write]
 //
 // 参数:
 //   - handlers: 包含所有 Handler 的容器
 //   - tokenMaker: JWT 验证器，用于认证中间件
+//   - opts: 路由的可选行为 (见 Options)
 //
 // 返回:
 //   - *gin.Engine: 配置好的 Gin 路由引擎
-func SetupRouter(handlers *Handlers, tokenMaker token.Maker) *gin.Engine {
-	// 创建默认的 Gin 路由引擎
-	// 默认包含 Logger 和 Recovery 中间件
-	router := gin.Default()
+func SetupRouter(handlers *Handlers, tokenMaker token.Maker, opts Options) *gin.Engine {
+	// 创建不带任何中间件的 Gin 路由引擎，全局中间件见 globalMiddleware
+	router := gin.New()
+	if err := router.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		// 配置加载时已校验，这里只作为兜底: 不信任任何代理
+		slog.Warn("invalid trusted proxies, trusting none", "error", err)
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.Use(globalMiddleware(opts)...)
 
 	// ==================== API V1 路由组 ====================
 	// 所有 API 路由都以 /api/v1 为前缀
 	// 使用版本号便于 API 升级时保持向后兼容
+	// 维护模式只作用于业务 API，健康检查和内部管理接口不受影响
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.Maintenance(opts.Runtime))
+	if opts.RequestSpec != nil {
+		v1.Use(middleware.OpenAPIValidation(opts.RequestSpec))
+	}
+	if opts.ResponseEnvelope {
+		v1.Use(middleware.ResponseEnvelope())
+	}
 
 	// ==================== 公开路由 (无需认证) ====================
 	// 这些路由任何人都可以访问
 
+	responseCache := middleware.NewResponseCache(opts.ResponseCacheTTL)
+
+	idempotencyStore := opts.IdempotencyStore
+	if idempotencyStore == nil {
+		idempotencyStore = middleware.NewMemoryIdempotencyStore()
+	}
+
 	// 用户路由组
 	// /api/v1/users
 	users := v1.Group("/users")
 	{
 		// POST /api/v1/users - 用户注册
 		// 任何人都可以注册新账户
-		users.POST("", handlers.User.CreateUser)
+		// 携带 Idempotency-Key 的重试返回首次成功的结果，而不是 409
+		users.POST("", middleware.Idempotency(idempotencyStore, opts.IdempotencyTTL), handlers.User.CreateUser)
 
 		// POST /api/v1/users/login - 用户登录
 		// 返回 Access Token 和 Refresh Token
-		users.POST("/login", handlers.User.LoginUser)
+		// 按客户端 IP 限流，减缓密码暴力破解
+		users.POST("/login", middleware.RateLimit(opts.LoginRateLimit, opts.LoginRateWindow), handlers.User.LoginUser)
 	}
 
 	// Token 路由组
@@ -88,14 +233,39 @@ func SetupRouter(handlers *Handlers, tokenMaker token.Maker) *gin.Engine {
 		tokens.POST("/renew", handlers.User.RefreshToken)
 	}
 
+	// GET /api/v1/rates - 查询汇率
+	// 汇率是公开信息，客户端可在转账前预览换算结果
+	v1.GET("/rates", handlers.Rate.GetRate)
+
+	// GET /api/v1/ws - 订阅转账入账通知 (WebSocket)
+	// 浏览器无法设置 Authorization 头，Access Token 在握手时通过子协议或 Query 参数传递
+	ws := v1.Group("/ws")
+	ws.Use(middleware.WebSocketAuth(tokenMaker))
+	if opts.PasswordChanges != nil {
+		ws.Use(middleware.RejectTokensBeforePasswordChange(opts.PasswordChanges, opts.PasswordChangeCacheTTL))
+	}
+	ws.GET("", handlers.Event.ServeWebSocket)
+
 	// ==================== 受保护路由 (需要认证) ====================
-	// 这些路由需要在请求头中携带有效的 Access Token
+	// 这些路由需要在请求头中携带有效的 Access Token 或 API Key
 	// Authorization: Bearer <access_token>
+	// Authorization: ApiKey <api_key>
+	//
+	// 只读 Token 和 API Key 受权限范围 (scope) 限制:
+	// 读操作要求 *:read (RequireReadScope)，写操作在各路由上用 RequireScope 声明
+	// (转账类 transfers:write，账户类 accounts:write)，缺少时返回 403
 
 	// 创建认证路由组
-	// 应用 AuthMiddleware 中间件
+	// 应用 AuthMiddleware 中间件，API Key 认证在它之前
 	authRoutes := v1.Group("")
+	if opts.APIKeys != nil {
+		authRoutes.Use(middleware.APIKeyAuth(opts.APIKeys))
+	}
 	authRoutes.Use(middleware.AuthMiddleware(tokenMaker))
+	if opts.PasswordChanges != nil {
+		authRoutes.Use(middleware.RejectTokensBeforePasswordChange(opts.PasswordChanges, opts.PasswordChangeCacheTTL))
+	}
+	authRoutes.Use(middleware.RequireReadScope())
 	{
 		// 账户路由组
 		// /api/v1/accounts
@@ -103,20 +273,88 @@ func SetupRouter(handlers *Handlers, tokenMaker token.Maker) *gin.Engine {
 		{
 			// POST /api/v1/accounts - 创建账户
 			// 为当前用户创建一个新的银行账户
-			accounts.POST("", handlers.Account.CreateAccount)
+			accounts.POST("", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.CreateAccount)
+
+			// POST /api/v1/accounts/batch - 批量创建账户
+			// 一次创建多个货币的账户，已有的货币跳过
+			accounts.POST("/batch", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.CreateAccountsBatch)
 
 			// GET /api/v1/accounts - 获取账户列表
 			// 获取当前用户的所有账户 (支持分页)
-			accounts.GET("", handlers.Account.ListAccounts)
+			// 频繁请求的列表 (如第一页) 短时间缓存，见 middleware.ResponseCache
+			accounts.GET("", responseCache.Cache(cacheAccounts), handlers.Account.ListAccounts)
+
+			// GET /api/v1/accounts/summary - 账户余额汇总
+			// 按货币汇总当前用户的余额和账户数
+			accounts.GET("/summary", responseCache.Cache(cacheAccounts), handlers.Account.GetSummary)
 
 			// GET /api/v1/accounts/:id - 获取账户详情
 			// 获取指定账户的详细信息
 			// 只能查看自己的账户
 			accounts.GET("/:id", handlers.Account.GetAccount)
 
+			// PATCH /api/v1/accounts/:id - 修改账户名称
+			// 只能修改自己的账户
+			accounts.PATCH("/:id", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.RenameAccount)
+
 			// GET /api/v1/accounts/:id/entries - 获取账目记录
 			// 获取指定账户的所有资金变动记录 (支持分页)
 			accounts.GET("/:id/entries", handlers.Transfer.ListEntries)
+
+			// GET /api/v1/accounts/:id/history - 交易历史
+			// 合并账目和转账，带类型、转账另一方和变动后的余额 (最新的在前)
+			accounts.GET("/:id/history", handlers.Transfer.GetAccountHistory)
+
+			// GET /api/v1/accounts/:id/entries/export - 导出账目
+			// 以 CSV 或 JSON 附件形式下载全部账目 (不分页)
+			accounts.GET("/:id/entries/export", handlers.Transfer.ExportEntries)
+
+			// GET /api/v1/accounts/:id/stats - 转账统计
+			// 最近 period 天 (默认 30d) 的转出/转入汇总
+			accounts.GET("/:id/stats", handlers.Transfer.GetAccountStats)
+
+			// GET /api/v1/accounts/:id/statement - 月度对账单
+			// 包含期初/期末余额、收支汇总和当月账目
+			accounts.GET("/:id/statement", handlers.Transfer.GetStatement)
+
+			// GET /api/v1/accounts/:id/statement.pdf - 下载 PDF 月度对账单
+			accounts.GET("/:id/statement.pdf", handlers.Transfer.GetStatementPDF)
+
+			// GET /api/v1/accounts/:id/events - 订阅余额变动
+			// Server-Sent Events 长连接，每次余额变动推送一个事件
+			accounts.GET("/:id/events", handlers.Event.StreamAccountEvents)
+
+			// POST /api/v1/accounts/:id/recurring-transfers - 创建周期转账
+			// 按天/周/月从该账户转出，每一次由定时转账的后台任务执行
+			accounts.POST("/:id/recurring-transfers", middleware.RequireScope(token.ScopeTransfersWrite), handlers.RecurringTransfer.CreateRecurringTransfer)
+
+			// GET /api/v1/accounts/:id/recurring-transfers - 获取周期转账列表
+			accounts.GET("/:id/recurring-transfers", handlers.RecurringTransfer.ListRecurringTransfers)
+
+			// DELETE /api/v1/accounts/:id/recurring-transfers/:recurring_id - 取消周期转账
+			accounts.DELETE("/:id/recurring-transfers/:recurring_id", middleware.RequireScope(token.ScopeTransfersWrite), handlers.RecurringTransfer.CancelRecurringTransfer)
+		}
+
+		// GET /api/v1/entries - 获取所有账户的账目记录
+		// 合并当前用户所有账户的资金变动记录 (支持分页)
+		authRoutes.GET("/entries", responseCache.Cache(cacheTransfers), handlers.Transfer.ListUserEntries)
+
+		// API Key 路由组
+		// /api/v1/api-keys
+		// 只能由登录用户本人管理，API Key 和只读 Token 不能创建或撤销 Key
+		apiKeys := authRoutes.Group("/api-keys")
+		apiKeys.Use(middleware.RequireFullAccess())
+		{
+			// POST /api/v1/api-keys - 创建 API Key
+			// Key 明文只在响应中返回一次
+			apiKeys.POST("", middleware.RequireFreshToken(opts.FreshTokenMaxAge), handlers.APIKey.CreateAPIKey)
+
+			// GET /api/v1/api-keys - 获取 API Key 列表
+			apiKeys.GET("", handlers.APIKey.ListAPIKeys)
+
+			// DELETE /api/v1/api-keys/:id - 撤销 API Key
+			// 立即生效，不能恢复
+			apiKeys.DELETE("/:id", middleware.RequireFreshToken(opts.FreshTokenMaxAge), handlers.APIKey.RevokeAPIKey)
 		}
 
 		// 转账路由组
@@ -126,18 +364,102 @@ func SetupRouter(handlers *Handlers, tokenMaker token.Maker) *gin.Engine {
 			// POST /api/v1/transfers - 创建转账
 			// 从一个账户转账到另一个账户
 			// 只能从自己的账户转出
-			transfers.POST("", handlers.Transfer.CreateTransfer)
+			transfers.POST("", middleware.RequireScope(token.ScopeTransfersWrite), responseCache.Invalidate(cacheAccounts, cacheTransfers), handlers.Transfer.CreateTransfer)
+
+			// POST /api/v1/transfers/preview - 预览转账
+			// 执行与创建转账相同的校验，返回转账后的余额，不写入数据
+			transfers.POST("/preview", middleware.RequireScope(token.ScopeTransfersWrite), handlers.Transfer.PreviewTransfer)
 
 			// GET /api/v1/transfers - 获取转账记录
 			// 获取指定账户的转账记录 (支持分页)
 			// 需要指定 account_id 参数
-			transfers.GET("", handlers.Transfer.ListTransfers)
+			transfers.GET("", responseCache.Cache(cacheTransfers), handlers.Transfer.ListTransfers)
+
+			// GET /api/v1/transfers/ref/:reference - 根据参考号获取转账
+			// 只有转账的一方可以查看
+			transfers.GET("/ref/:reference", handlers.Transfer.GetTransferByReference)
+
+			// POST /api/v1/transfers/schedule - 创建定时转账
+			// 到期后由后台任务执行，余额在执行时校验
+			transfers.POST("/schedule", middleware.RequireScope(token.ScopeTransfersWrite), handlers.ScheduledTransfer.ScheduleTransfer)
+
+			// GET /api/v1/transfers/schedule/:id - 查询定时转账及执行状态
+			transfers.GET("/schedule/:id", handlers.ScheduledTransfer.GetScheduledTransfer)
+
+			// DELETE /api/v1/transfers/schedule/:id - 取消定时转账
+			// 只能取消尚未开始执行的定时转账
+			transfers.DELETE("/schedule/:id", middleware.RequireScope(token.ScopeTransfersWrite), handlers.ScheduledTransfer.CancelScheduledTransfer)
+
+			// GET /api/v1/transfers/:id - 根据公开ID获取转账
+			// 只有转账的一方可以查看
+			transfers.GET("/:id", handlers.Transfer.GetTransfer)
+
+			// POST /api/v1/transfers/:id/reverse - 撤销转账
+			// 只有转出方可以在撤销窗口内撤销，收款方余额必须足以退回
+			transfers.POST("/:id/reverse",
+				middleware.RequireScope(token.ScopeTransfersWrite),
+				middleware.RequireFreshToken(opts.FreshTokenMaxAge),
+				responseCache.Invalidate(cacheAccounts, cacheTransfers),
+				handlers.Transfer.ReverseTransfer)
+		}
+
+		// 管理员路由组
+		// /api/v1/admin
+		// 在认证基础上要求管理员角色
+		admin := authRoutes.Group("/admin")
+		admin.Use(middleware.RequireRole(model.RoleAdmin))
+		{
+			// GET /api/v1/admin/audit-logs - 查询审计日志
+			// 支持按操作者和操作类型过滤 (支持分页)
+			admin.GET("/audit-logs", handlers.Audit.ListAuditLogs)
+
+			// PUT /api/v1/admin/accounts/:id/overdraft-limit - 设置透支额度
+			// 允许账户余额透支到 -overdraft_limit
+			admin.PUT("/accounts/:id/overdraft-limit", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.SetOverdraftLimit)
+
+			// PUT /api/v1/admin/accounts/:id/min-balance - 设置最低余额
+			// 扣款后余额不能低于 min_balance
+			admin.PUT("/accounts/:id/min-balance", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.SetMinBalance)
+
+			// POST /api/v1/admin/accounts/:id/freeze - 冻结账户
+			// 冻结的账户仍可查询，但不能转入转出
+			admin.POST("/accounts/:id/freeze", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.FreezeAccount)
+
+			// POST /api/v1/admin/accounts/:id/unfreeze - 解冻账户
+			admin.POST("/accounts/:id/unfreeze", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.UnfreezeAccount)
+
+			// POST /api/v1/admin/accounts/:id/restore - 恢复已关闭账户
+			// 用户已开立同币种新账户时返回 409
+			admin.POST("/accounts/:id/restore", middleware.RequireScope(token.ScopeAccountsWrite), responseCache.Invalidate(cacheAccounts), handlers.Account.RestoreAccount)
 		}
 	}
 
 	return router
 }
 
+// globalMiddleware 返回作用于所有路由的中间件，按执行顺序排列
+//
+// 全局中间件只在这里组装，顺序有依赖关系:
+//  1. RequestID 最先执行，之后所有日志 (包括访问日志和 panic 日志) 都带请求ID
+//  2. 访问日志 (代替 gin.Logger) 在 Recovery 之外，panic 的请求也会以 500 记录
+//  3. Recovery 代替 gin.Recovery，返回统一格式的 JSON 错误
+//  4. 安全响应头和请求体大小限制作用于所有响应和请求
+//  5. 关闭期间的 503 在最后，被拒绝的请求同样有访问日志和安全响应头
+func globalMiddleware(opts Options) []gin.HandlerFunc {
+	handlers := []gin.HandlerFunc{
+		middleware.RequestID(),
+		middleware.AccessLog(opts.AccessLogSkipPaths...),
+		middleware.Recovery(),
+		middleware.SecureHeaders(opts.HSTS),
+		middleware.MaxBodySize(opts.MaxRequestBytes),
+	}
+	if opts.Draining != nil {
+		// 健康检查由 HealthHandler 自己报告关闭状态 (见 SetupHealthRoutes)
+		handlers = append(handlers, middleware.Draining(opts.Draining, "/health", "/ready"))
+	}
+	return handlers
+}
+
 // ==================== 健康检查路由 ====================
 
 // SetupHealthRoutes 添加健康检查路由
@@ -147,21 +469,40 @@ func SetupRouter(handlers *Handlers, tokenMaker token.Maker) *gin.Engine {
 //
 // 参数:
 //   - router: Gin 路由引擎
-func SetupHealthRoutes(router *gin.Engine) {
+//   - healthHandler: 健康检查 Handler，就绪检查的依赖在创建时注册
+//
+// 服务关闭期间两个接口都返回 503 (status: draining)，负载均衡器据此摘除实例
+func SetupHealthRoutes(router *gin.Engine, healthHandler *handler.HealthHandler) {
 	// GET /health - 健康检查
 	// 返回服务状态，用于负载均衡器/Kubernetes 探针
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "Simple Bank V2 is running",
-		})
-	})
+	router.GET("/health", healthHandler.Health)
 
 	// GET /ready - 就绪检查
-	// 可以在这里检查数据库连接等依赖
-	router.GET("/ready", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ready",
-		})
-	})
+	// 检查数据库连接、Token 签发等依赖，任一不可用时返回 503
+	router.GET("/ready", healthHandler.Ready)
+
+	// GET /version - 构建信息
+	// 返回版本号、Git 提交和构建时间，用于确认部署的是哪个构建
+	router.GET("/version", healthHandler.Version)
+}
+
+// ==================== 内部管理路由 ====================
+
+// SetupInternalRoutes 添加内部管理路由
+//
+// 这些路由只允许白名单内的 IP 访问 (如运维机器、本机)
+// 不走 Token 认证，也不受维护模式影响
+//
+// 参数:
+//   - router: Gin 路由引擎
+//   - configHandler: 运行时配置 Handler
+//   - allowedIPs: 允许访问的 IP 或 CIDR 列表
+func SetupInternalRoutes(router *gin.Engine, configHandler *handler.ConfigHandler, allowedIPs []string) {
+	internal := router.Group("/internal")
+	internal.Use(middleware.IPAllowList(allowedIPs))
+	{
+		// POST /internal/reload-config - 重新加载可热更新配置
+		// 维护模式、功能开关等无需重启即可生效
+		internal.POST("/reload-config", configHandler.ReloadConfig)
+	}
 }
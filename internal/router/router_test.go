@@ -0,0 +1,147 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/handler"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/internal/service"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+func TestSetupRouterUsesCustomMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := SetupRouter(&Handlers{}, nil, Options{})
+
+	// 未匹配的路由同样经过全局中间件
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/not-found", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w.Header().Get(middleware.RequestIDHeader) == "" {
+		t.Errorf("%s header missing", middleware.RequestIDHeader)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+}
+
+func TestDrainingRejectsNewRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var draining atomic.Bool
+	r := SetupRouter(&Handlers{}, nil, Options{Draining: &draining})
+	SetupHealthRoutes(r, handler.NewHealthHandler().WithDraining(&draining))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/health"); w.Code != http.StatusOK {
+		t.Fatalf("health before shutdown = %d, want 200", w.Code)
+	}
+
+	// 开始关闭后到达的请求
+	draining.Store(true)
+
+	w := get("/api/v1/accounts")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Connection") != "close" {
+		t.Errorf("api status = %d, Connection = %q; want 503 and close", w.Code, w.Header().Get("Connection"))
+	}
+
+	// 健康检查不被中间件拦截，由 Handler 报告关闭状态
+	w = get("/health")
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode health %q: %v", w.Body.String(), err)
+	}
+	if w.Code != http.StatusServiceUnavailable || body["status"] != response.ReadinessDraining {
+		t.Errorf("health = %d %v, want 503 draining", w.Code, body)
+	}
+}
+
+func TestReadOnlyTokenCannotCreateTransfers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	repos := memory.New()
+	maker, err := token.NewJWTMaker("01234567890123456789012345678901")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditor := service.NewAuditLogger(repos.AuditLogs)
+	users := service.NewUserService(repos.Users, repos.Sessions, maker, 15*time.Minute, 24*time.Hour, 0, auditor)
+	transfers := service.NewTransferService(repos.TxManager, repos.Accounts, repos.Transfers, repos.Entries,
+		auditor, service.TransferLimits{})
+	r := SetupRouter(&Handlers{Transfer: handler.NewTransferHandler(transfers)}, maker, Options{
+		Runtime: config.NewRuntimeStore(config.RuntimeConfig{}),
+	})
+
+	if _, err := users.CreateUser(ctx, &request.CreateUserRequest{
+		Username: "alice", Password: "secret123", FullName: "Alice", Email: "alice@example.com",
+	}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	from := &model.Account{Owner: "alice", Currency: "USD", Balance: 10000}
+	to := &model.Account{Owner: "bob", Currency: "USD"}
+	for _, account := range []*model.Account{from, to} {
+		if err := repos.Accounts.Create(ctx, account); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 登录时请求只读 Token
+	login, err := users.LoginUser(ctx, &request.LoginUserRequest{Username: "alice", Password: "secret123", ReadOnly: true}, "", "")
+	if err != nil {
+		t.Fatalf("LoginUser: %v", err)
+	}
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+login.AccessToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodGet, "/api/v1/transfers?account_id="+from.PublicID.String(), nil); w.Code != http.StatusOK {
+		t.Errorf("list status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	w := do(http.MethodPost, "/api/v1/transfers", map[string]any{
+		"from_account_id": from.PublicID.String(),
+		"to_account_id":   to.PublicID.String(),
+		"amount":          100,
+	})
+	var errResp response.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	if w.Code != http.StatusForbidden || errResp.Code != apperrors.CodeForbidden {
+		t.Errorf("create = %d %+v, want 403 code %d", w.Code, errResp, apperrors.CodeForbidden)
+	}
+	if _, total, _ := repos.Transfers.ListByAccountID(ctx, from.ID, "", 10, 0); total != 0 {
+		t.Errorf("transfers = %d, want 0", total)
+	}
+}
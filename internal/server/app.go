@@ -3,20 +3,33 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
 
+	"github.com/getkin/kin-openapi/routers"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
 	"github.com/proyuen/simple-bank-v2/internal/config"
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/event"
 	"github.com/proyuen/simple-bank-v2/internal/handler"
+	"github.com/proyuen/simple-bank-v2/internal/middleware"
+	"github.com/proyuen/simple-bank-v2/internal/model"
 	"github.com/proyuen/simple-bank-v2/internal/repository"
 	"github.com/proyuen/simple-bank-v2/internal/router"
 	"github.com/proyuen/simple-bank-v2/internal/service"
+	"github.com/proyuen/simple-bank-v2/internal/worker"
+	"github.com/proyuen/simple-bank-v2/pkg/fx"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
 	"github.com/proyuen/simple-bank-v2/pkg/token"
 )
 
@@ -26,11 +39,37 @@ type App struct {
 	db         *gorm.DB
 	tokenMaker token.Maker
 	httpServer *http.Server
+	workers    *worker.Manager
+	runtime    *config.RuntimeStore
+	rates      *fx.CachedProvider
+	events     *event.Bus
+	redis      *redis.Client // IDEMPOTENCY_STORE=redis 时创建，否则为 nil
+
+	// requestSpec 开启 OPENAPI_VALIDATION 时加载的接口规范
+	requestSpec routers.Router
+
+	// listener 在 Run 中创建，创建后关闭 ready
+	listener net.Listener
+	ready    chan struct{}
+
+	// draining 在 shutdown 开始时设置，之后新请求返回 503 (见 middleware.Draining)
+	draining atomic.Bool
 }
 
 // NewApp 创建并初始化应用程序
 func NewApp(cfg config.Config) (*App, error) {
-	app := &App{config: cfg}
+	app := &App{
+		config:  cfg,
+		workers: worker.NewManager(),
+		runtime: config.NewRuntimeStore(cfg.Runtime),
+		events:  event.NewBus(),
+		ready:   make(chan struct{}),
+	}
+
+	// 在连接数据库之前检查，配置错误时尽早失败
+	if err := cfg.ValidateTLS(); err != nil {
+		return nil, fmt.Errorf("validate tls config: %w", err)
+	}
 
 	if err := app.setupDatabase(); err != nil {
 		return nil, fmt.Errorf("setup database: %w", err)
@@ -40,6 +79,18 @@ func NewApp(cfg config.Config) (*App, error) {
 		return nil, fmt.Errorf("setup token maker: %w", err)
 	}
 
+	if err := app.setupRedis(); err != nil {
+		return nil, fmt.Errorf("setup redis: %w", err)
+	}
+
+	if err := app.setupRateProvider(); err != nil {
+		return nil, fmt.Errorf("setup rate provider: %w", err)
+	}
+
+	if err := app.setupRequestValidation(); err != nil {
+		return nil, fmt.Errorf("setup request validation: %w", err)
+	}
+
 	app.setupHTTPServer()
 
 	return app, nil
@@ -47,40 +98,121 @@ func NewApp(cfg config.Config) (*App, error) {
 
 // setupDatabase 初始化数据库连接
 func (a *App) setupDatabase() error {
-	var gormLogMode logger.LogLevel
-	if a.config.IsProduction() {
-		gormLogMode = logger.Error
-	} else {
-		gormLogMode = logger.Info
+	db, err := OpenDatabase(a.config)
+	if err != nil {
+		return err
+	}
+
+	a.db = db
+	slog.Info("database connected",
+		"host", a.config.DBHost,
+		"database", a.config.DBName,
+	)
+
+	if a.config.DBAutoMigrate {
+		if err := autoMigrate(db); err != nil {
+			return fmt.Errorf("auto migrate: %w", err)
+		}
 	}
+	return nil
+}
+
+// OpenDatabase 按配置连接数据库并设置连接池
+// 服务端和运维命令 (如 cmd/reconcile) 共用，保证连接参数和 SQL 日志一致
+func OpenDatabase(cfg config.Config) (*gorm.DB, error) {
+	// DATETIME 精度与迁移文件中的 TIMESTAMP 保持一致 (秒)，
+	// 否则 AutoMigrate 生成的 DATETIME(3) DEFAULT CURRENT_TIMESTAMP 在 MySQL 中无效
+	datetimePrecision := 0
+	dialector := mysql.New(mysql.Config{
+		DSN:                      cfg.DBSource(),
+		DefaultDatetimePrecision: &datetimePrecision,
+	})
 
-	db, err := gorm.Open(mysql.Open(a.config.DBSource()), &gorm.Config{
-		Logger: logger.Default.LogMode(gormLogMode),
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: newGormLogger(cfg),
+		// 把唯一键、外键冲突翻译为 gorm.ErrDuplicatedKey / gorm.ErrForeignKeyViolated
+		TranslateError: true,
 	})
 	if err != nil {
-		return fmt.Errorf("connect to database: %w", err)
+		return nil, fmt.Errorf("connect to database: %w", err)
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("get underlying sql.DB: %w", err)
+		return nil, fmt.Errorf("get underlying sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(a.config.DBMaxIdleConns)
-	sqlDB.SetMaxOpenConns(a.config.DBMaxOpenConns)
-	sqlDB.SetConnMaxLifetime(a.config.DBConnMaxLifetime)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	return db, nil
+}
 
-	a.db = db
-	slog.Info("database connected",
-		"host", a.config.DBHost,
-		"database", a.config.DBName,
-	)
+// autoMigrate 根据模型定义创建或更新表结构
+//
+// 只用于本地开发快速启动，AutoMigrate 只会新增表、列和索引，不会删除或重命名；
+// 生产环境应使用 db/migration 下经过评审的迁移文件
+func autoMigrate(db *gorm.DB) error {
+	// 按依赖顺序排列: 被外键引用的表在前
+	models := []interface{ TableName() string }{
+		&model.User{},
+		&model.Account{},
+		&model.Transfer{},
+		&model.Entry{},
+		&model.Session{},
+		&model.APIKey{},
+		&model.AuditLog{},
+		&model.RecurringTransfer{},
+		&model.ScheduledTransfer{},
+		&model.IdempotencyKey{},
+	}
+
+	tables := make([]string, len(models))
+	for i, m := range models {
+		if err := db.AutoMigrate(m); err != nil {
+			return fmt.Errorf("migrate %s: %w", m.TableName(), err)
+		}
+		tables[i] = m.TableName()
+	}
+
+	slog.Warn("database auto-migrated, use SQL migrations in production", "tables", tables)
+	return nil
+}
+
+// redisPingTimeout 启动时检查 Redis 连接的超时时间
+const redisPingTimeout = 5 * time.Second
+
+// setupRedis 连接 Redis (只有 IDEMPOTENCY_STORE=redis 时需要)
+// 启动时 PING 一次，地址或密码错误时尽早失败
+func (a *App) setupRedis() error {
+	if a.config.IdempotencyStore != config.IdempotencyStoreRedis {
+		return nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     a.config.RedisAddr,
+		Password: a.config.RedisPassword,
+		DB:       a.config.RedisDB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), redisPingTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return fmt.Errorf("ping %s: %w", a.config.RedisAddr, err)
+	}
+
+	a.redis = client
+	slog.Info("redis connected", "addr", a.config.RedisAddr, "db", a.config.RedisDB)
 	return nil
 }
 
 // setupTokenMaker 初始化 JWT Token 生成器
 func (a *App) setupTokenMaker() error {
-	tokenMaker, err := token.NewJWTMaker(a.config.TokenSecretKey)
+	tokenMaker, err := token.NewJWTMakerWithOptions(a.config.TokenSecretKey, token.JWTOptions{
+		PreviousKeys: a.config.TokenPreviousKeys,
+		Issuer:       a.config.TokenIssuer,
+		Audience:     a.config.TokenAudience,
+	})
 	if err != nil {
 		return fmt.Errorf("create token maker: %w", err)
 	}
@@ -88,6 +220,47 @@ func (a *App) setupTokenMaker() error {
 	return nil
 }
 
+// setupRateProvider 初始化汇率来源
+// 配置了外部汇率服务时优先使用外部服务，失败时回退到静态汇率表
+func (a *App) setupRateProvider() error {
+	static, err := fx.NewStaticProvider(a.config.FXRates)
+	if err != nil {
+		return err
+	}
+
+	if a.config.FXProviderURL == "" {
+		a.rates = fx.NewCachedProvider(static, a.config.FXCacheTTL)
+		return nil
+	}
+
+	upstream := fx.NewHTTPProvider(a.config.FXProviderURL, &http.Client{Timeout: 5 * time.Second})
+	a.rates = fx.NewCachedProvider(upstream, a.config.FXCacheTTL).
+		WithStaleOnError(a.config.FXStaleOnError).
+		WithFallback(static)
+	return nil
+}
+
+// setupRequestValidation 加载 OpenAPI 规范 (OPENAPI_VALIDATION 开启时)
+// 规范文件不存在或无法解析时启动失败，而不是静默关闭校验
+func (a *App) setupRequestValidation() error {
+	if !a.config.OpenAPIValidation {
+		return nil
+	}
+
+	data, err := os.ReadFile(a.config.OpenAPISpecPath)
+	if err != nil {
+		return fmt.Errorf("read openapi spec (generate it with swag init): %w", err)
+	}
+	spec, err := middleware.LoadOpenAPISpec(data)
+	if err != nil {
+		return fmt.Errorf("load openapi spec %s: %w", a.config.OpenAPISpecPath, err)
+	}
+
+	a.requestSpec = spec
+	slog.Info("openapi request validation enabled", "spec", a.config.OpenAPISpecPath)
+	return nil
+}
+
 // setupHTTPServer 初始化 HTTP 服务器
 func (a *App) setupHTTPServer() {
 	if a.config.IsProduction() {
@@ -100,34 +273,113 @@ func (a *App) setupHTTPServer() {
 	sessionRepo := repository.NewSessionRepository(a.db)
 	transferRepo := repository.NewTransferRepository(a.db)
 	entryRepo := repository.NewEntryRepository(a.db)
+	auditLogRepo := repository.NewAuditLogRepository(a.db)
+	scheduledRepo := repository.NewScheduledTransferRepository(a.db)
+	recurringRepo := repository.NewRecurringTransferRepository(a.db)
+	apiKeyRepo := repository.NewAPIKeyRepository(a.db)
 	txManager := repository.NewTxManager(a.db)
 
 	// 创建 Services
+	auditLogger := service.NewAuditLogger(auditLogRepo)
 	userService := service.NewUserService(
 		userRepo,
 		sessionRepo,
 		a.tokenMaker,
 		a.config.AccessTokenDuration,
 		a.config.RefreshTokenDuration,
-	)
-	accountService := service.NewAccountService(accountRepo)
+		a.config.SessionIdleTimeout,
+		auditLogger,
+	).WithPasswordChangeCheck(a.config.TokenCheckPasswordChange)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, auditLogger)
+	accountService := service.NewAccountService(txManager, accountRepo, auditLogger).
+		WithMaxAccounts(a.config.MaxAccountsPerUser).
+		WithDefaultCurrency(a.config.DefaultCurrency)
+	rateService := service.NewRateService(a.rates)
 	transferService := service.NewTransferService(
 		txManager,
 		accountRepo,
 		transferRepo,
 		entryRepo,
+		auditLogger,
+		service.TransferLimits{
+			MinAmount:  a.config.MinTransferAmount,
+			MaxAmount:  a.config.TransferMaxAmount,
+			DailyLimit: a.config.TransferDailyLimit,
+		},
+	).WithReversalWindow(a.config.TransferReversalWindow).
+		WithEventPublisher(a.events)
+
+	scheduledService := service.NewScheduledTransferService(
+		scheduledRepo,
+		accountRepo,
+		transferRepo,
+		transferService,
+		auditLogger,
 	)
+	recurringService := service.NewRecurringTransferService(
+		txManager,
+		recurringRepo,
+		scheduledRepo,
+		accountRepo,
+		auditLogger,
+	)
+	// 周期转账的每一次由定时转账执行，执行后生成下一次
+	scheduledService.WithOccurrenceScheduler(recurringService)
+
+	// 注册后台任务 (在 Run 中启动)
+	a.workers.Add(worker.NewPeriodic("scheduled-transfers", a.config.ScheduledTransferInterval, scheduledService.ExecuteDue))
+
+	// 列表接口的每页条数限制 (所有 Handler 共用)
+	request.SetPageSizeLimits(request.PageSizeLimits{
+		Default:        a.config.PageSizeDefault,
+		Max:            a.config.PageSizeMax,
+		RejectOversize: a.config.PageSizeRejectOversize,
+	})
+
+	// JSON 请求体是否拒绝未定义的字段 (所有 Handler 共用)
+	request.SetStrictJSON(a.config.StrictRequestFields)
+
+	// 响应中金额的 JSON 格式 (所有 Handler 共用)
+	money.SetAmountsAsStrings(a.config.JSONAmountsAsStrings)
 
 	// 创建 Handlers
 	handlers := &router.Handlers{
-		User:     handler.NewUserHandler(userService),
-		Account:  handler.NewAccountHandler(accountService),
-		Transfer: handler.NewTransferHandler(transferService),
+		User:              handler.NewUserHandler(userService).WithRefreshTokenCookie(a.config.RefreshTokenCookie),
+		Account:           handler.NewAccountHandler(accountService),
+		Transfer:          handler.NewTransferHandler(transferService).WithStepUp(a.config.StepUpTransferAmount, a.config.StepUpMaxTokenAge),
+		ScheduledTransfer: handler.NewScheduledTransferHandler(scheduledService),
+		RecurringTransfer: handler.NewRecurringTransferHandler(recurringService),
+		Audit:             handler.NewAuditHandler(auditLogger),
+		Rate:              handler.NewRateHandler(rateService),
+		Event:             handler.NewEventHandler(accountService, a.events),
+		APIKey:            handler.NewAPIKeyHandler(apiKeyService),
 	}
 
 	// 设置路由
-	r := router.SetupRouter(handlers, a.tokenMaker)
-	router.SetupHealthRoutes(r)
+	routerOpts := router.Options{
+		Runtime:            a.runtime,
+		ResponseEnvelope:   a.config.ResponseEnvelope,
+		MaxRequestBytes:    a.config.MaxRequestBytes,
+		RequestSpec:        a.requestSpec,
+		HSTS:               a.config.IsProduction(),
+		AccessLogSkipPaths: a.config.AccessLogSkipPaths,
+		Draining:           &a.draining,
+		APIKeys:            apiKeyService,
+		FreshTokenMaxAge:   a.config.StepUpMaxTokenAge,
+		IdempotencyTTL:     a.config.IdempotencyKeyTTL,
+		IdempotencyStore:   a.newIdempotencyStore(),
+		TrustedProxies:     a.config.TrustedProxies,
+		LoginRateLimit:     a.config.LoginRateLimit,
+		LoginRateWindow:    a.config.LoginRateWindow,
+		ResponseCacheTTL:   a.config.ResponseCacheTTL,
+	}
+	if a.config.TokenCheckPasswordChange {
+		routerOpts.PasswordChanges = userService
+		routerOpts.PasswordChangeCacheTTL = a.config.TokenPasswordChangeCacheTTL
+	}
+	r := router.SetupRouter(handlers, a.tokenMaker, routerOpts)
+	router.SetupHealthRoutes(r, a.newHealthHandler())
+	router.SetupInternalRoutes(r, handler.NewConfigHandler(a.runtime, a.config.Path, a.config.Files...), a.config.AdminAllowedIPs)
 
 	a.httpServer = &http.Server{
 		Addr:    a.config.ServerAddress,
@@ -135,19 +387,79 @@ func (a *App) setupHTTPServer() {
 	}
 }
 
-// Run 启动 HTTP 服务器并等待关闭信号
+// idempotencyCleanupInterval 清理过期幂等键的间隔 (IDEMPOTENCY_STORE=database 时)
+const idempotencyCleanupInterval = time.Hour
+
+// newIdempotencyStore 按 IDEMPOTENCY_STORE 创建幂等键的存储
+// 使用数据库时同时注册定期清理过期记录的后台任务 (Redis 的键自动过期，不需要清理)
+func (a *App) newIdempotencyStore() middleware.IdempotencyStore {
+	switch a.config.IdempotencyStore {
+	case config.IdempotencyStoreDatabase:
+		repo := repository.NewIdempotencyKeyRepository(a.db)
+		a.workers.Add(worker.NewPeriodic("idempotency-keys-cleanup", idempotencyCleanupInterval, repo.DeleteExpired))
+		return repo
+	case config.IdempotencyStoreRedis:
+		return repository.NewRedisIdempotencyKeyRepository(a.redis)
+	default:
+		return middleware.NewMemoryIdempotencyStore()
+	}
+}
+
+// newHealthHandler 创建健康检查 Handler 并注册就绪检查的依赖
+// Token 签发失败时认证不可用，即使数据库正常也不应接收流量
+func (a *App) newHealthHandler() *handler.HealthHandler {
+	h := handler.NewHealthHandler().
+		WithDraining(&a.draining).
+		WithCheck("db", func(ctx context.Context) error {
+			sqlDB, err := a.db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}).
+		WithCheck("token", func(context.Context) error {
+			return token.SelfCheck(a.tokenMaker)
+		})
+	if a.redis != nil {
+		h.WithCheck("redis", func(ctx context.Context) error {
+			return a.redis.Ping(ctx).Err()
+		})
+	}
+	return h
+}
+
+// Run 启动 HTTP 服务器和后台任务，并等待关闭信号
 func (a *App) Run(ctx context.Context) error {
 	errCh := make(chan error, 1)
 
+	// 先绑定端口再启动后台任务，端口被占用时直接返回错误
+	listener, err := net.Listen("tcp", a.config.ServerAddress)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	a.listener = listener
+	close(a.ready)
+
+	// 后台任务与服务器共享根 Context
+	a.workers.Start(ctx)
+
 	go func() {
-		slog.Info("server starting", "address", a.config.ServerAddress)
-		if err := a.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if a.config.TLSEnabled() {
+			slog.Info("server starting", "address", a.Addr(), "mode", "https")
+			err = a.httpServer.ServeTLS(listener, a.config.TLSCertFile, a.config.TLSKeyFile)
+		} else {
+			slog.Info("server starting", "address", a.Addr(), "mode", "http")
+			err = a.httpServer.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
 			errCh <- err
 		}
 	}()
 
 	select {
 	case err := <-errCh:
+		_ = a.shutdown()
 		return err
 	case <-ctx.Done():
 		slog.Info("shutdown signal received")
@@ -155,18 +467,60 @@ func (a *App) Run(ctx context.Context) error {
 	}
 }
 
-// shutdown 优雅关闭服务器
+// Ready 返回一个在端口绑定完成后关闭的 channel
+// 之后即可接受连接，用于测试中等待服务器启动
+func (a *App) Ready() <-chan struct{} {
+	return a.ready
+}
+
+// Addr 返回服务器实际监听的地址
+// 配置端口为 0 时可以通过它获得系统分配的端口；绑定前返回配置的地址
+func (a *App) Addr() string {
+	select {
+	case <-a.ready:
+		return a.listener.Addr().String()
+	default:
+		return a.config.ServerAddress
+	}
+}
+
+// shutdown 优雅关闭服务器和后台任务
+//
+// 两者同时开始关闭，分别使用 SERVER_SHUTDOWN_TIMEOUT 和 WORKER_SHUTDOWN_TIMEOUT:
+// 后台任务立即停止开始新的一轮，正在执行的一轮 (如定时转账) 完成后退出；
+// HTTP 服务关闭失败时仍然等待后台任务，避免数据库连接在任务执行中途被关闭
 func (a *App) shutdown() error {
+	slog.Info("shutting down server",
+		"timeout", a.config.ServerShutdownTimeout, "worker_timeout", a.config.WorkerShutdownTimeout)
+
+	// 等待正在执行的后台任务完成
+	workersDone := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.config.WorkerShutdownTimeout)
+		defer cancel()
+		workersDone <- a.workers.Shutdown(ctx)
+	}()
+
+	var errs []error
 	ctx, cancel := context.WithTimeout(context.Background(), a.config.ServerShutdownTimeout)
 	defer cancel()
-
-	slog.Info("shutting down server", "timeout", a.config.ServerShutdownTimeout)
+	// 先拒绝新请求，Shutdown 只需要等待处理中的请求完成
+	a.draining.Store(true)
+	// 再结束实时推送的长连接，否则 Shutdown 会一直等到超时
+	a.events.Close()
 	if err := a.httpServer.Shutdown(ctx); err != nil {
-		return fmt.Errorf("server shutdown: %w", err)
+		errs = append(errs, fmt.Errorf("server shutdown: %w", err))
+	} else {
+		slog.Info("server stopped")
 	}
 
-	slog.Info("server stopped")
-	return nil
+	if err := <-workersDone; err != nil {
+		errs = append(errs, fmt.Errorf("workers shutdown: %w", err))
+	} else {
+		slog.Info("workers stopped")
+	}
+
+	return errors.Join(errs...)
 }
 
 // Close 清理应用程序资源
@@ -181,5 +535,11 @@ func (a *App) Close() error {
 		}
 		slog.Info("database connection closed")
 	}
+	if a.redis != nil {
+		if err := a.redis.Close(); err != nil {
+			return fmt.Errorf("close redis: %w", err)
+		}
+		slog.Info("redis connection closed")
+	}
 	return nil
 }
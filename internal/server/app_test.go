@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+	"github.com/proyuen/simple-bank-v2/internal/event"
+	"github.com/proyuen/simple-bank-v2/internal/worker"
+)
+
+// newTestApp 创建不连接数据库的 App，HTTP 服务对所有请求返回 "ok"
+// 只包含 Run 和 shutdown 需要的依赖
+func newTestApp(addr string) *App {
+	return &App{
+		config: config.Config{
+			ServerAddress:         addr,
+			ServerShutdownTimeout: time.Second,
+			WorkerShutdownTimeout: time.Second,
+		},
+		workers: worker.NewManager(),
+		events:  event.NewBus(),
+		ready:   make(chan struct{}),
+		httpServer: &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				io.WriteString(w, "ok")
+			}),
+		},
+	}
+}
+
+func TestAppAddrBeforeRun(t *testing.T) {
+	app := newTestApp("127.0.0.1:0")
+
+	if got := app.Addr(); got != "127.0.0.1:0" {
+		t.Errorf("Addr() = %q, want the configured address", got)
+	}
+	select {
+	case <-app.Ready():
+		t.Error("Ready() closed before Run")
+	default:
+	}
+}
+
+func TestAppRunReportsAssignedPort(t *testing.T) {
+	app := newTestApp("127.0.0.1:0")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx) }()
+
+	select {
+	case <-app.Ready():
+	case err := <-done:
+		t.Fatalf("Run returned before ready: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not become ready")
+	}
+
+	// 端口 0 被替换为系统分配的端口
+	addr := app.Addr()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("Addr() = %q: %v", addr, err)
+	}
+	if host != "127.0.0.1" || port == "0" {
+		t.Errorf("Addr() = %q, want 127.0.0.1 with an assigned port", addr)
+	}
+
+	// Ready 之后可以立即接受连接
+	resp, err := http.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "ok" {
+		t.Errorf("response = %d %q, want 200 ok", resp.StatusCode, body)
+	}
+
+	// 取消 Context 后正常关闭，端口被释放
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	if conn, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		conn.Close()
+		t.Errorf("%s still accepts connections after shutdown", addr)
+	}
+}
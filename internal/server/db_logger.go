@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/proyuen/simple-bank-v2/internal/config"
+)
+
+// newGormLogger 根据配置创建 GORM 日志器
+//
+// DB_LOG_LEVEL 为空时生产环境只记录错误，其他环境记录全部 SQL；
+// 超过 DB_SLOW_THRESHOLD 的查询以慢查询警告记录
+func newGormLogger(cfg config.Config) logger.Interface {
+	level, _ := parseGormLogLevel(cfg.DBLogLevel)
+	if cfg.DBLogLevel == "" {
+		level = logger.Info
+		if cfg.IsProduction() {
+			level = logger.Error
+		}
+	}
+
+	return &slogGormLogger{
+		logger:        slog.Default().With("component", "gorm"),
+		level:         level,
+		slowThreshold: cfg.DBSlowThreshold,
+	}
+}
+
+// parseGormLogLevel 解析 DB_LOG_LEVEL (silent, error, warn, info)
+func parseGormLogLevel(s string) (logger.LogLevel, error) {
+	switch strings.ToLower(s) {
+	case "silent":
+		return logger.Silent, nil
+	case "error":
+		return logger.Error, nil
+	case "warn":
+		return logger.Warn, nil
+	case "info":
+		return logger.Info, nil
+	default:
+		return 0, fmt.Errorf("unknown db log level %q", s)
+	}
+}
+
+// slogGormLogger 把 GORM 日志转发到 slog 的 logger.Interface 实现
+//
+// SQL 语句、影响行数、耗时和错误作为结构化字段输出:
+//   - 执行出错: Error 级别 (记录不存在除外，属于正常业务流程)
+//   - 慢查询:   Warn 级别
+//   - 其他查询: Info 级别 (LogLevel 为 info 时才记录)
+type slogGormLogger struct {
+	logger        *slog.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// LogMode 实现 logger.Interface 接口，返回使用新级别的副本
+func (l *slogGormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info 实现 logger.Interface 接口
+func (l *slogGormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn 实现 logger.Interface 接口
+func (l *slogGormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error 实现 logger.Interface 接口
+func (l *slogGormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace 实现 logger.Interface 接口，每条 SQL 执行后调用
+func (l *slogGormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{"sql", sql, "rows", rows, "duration", elapsed}
+	}
+
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		l.logger.ErrorContext(ctx, "sql error", append(attrs(), "error", err)...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		l.logger.WarnContext(ctx, "slow sql", append(attrs(), "threshold", l.slowThreshold)...)
+	case l.level >= logger.Info:
+		l.logger.InfoContext(ctx, "sql", attrs()...)
+	}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newCapturingGormLogger 创建输出 JSON 到 buf 的 slogGormLogger
+func newCapturingGormLogger(buf *bytes.Buffer, level logger.LogLevel) *slogGormLogger {
+	return &slogGormLogger{
+		logger:        slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		level:         level,
+		slowThreshold: 100 * time.Millisecond,
+	}
+}
+
+func TestGormLoggerLevels(t *testing.T) {
+	fc := func() (string, int64) { return "SELECT * FROM `accounts`", 0 }
+
+	tests := []struct {
+		name      string
+		level     logger.LogLevel
+		begin     time.Time
+		err       error
+		wantLevel string // 为空表示不应输出
+	}{
+		{"error", logger.Info, time.Now(), errors.New("connection refused"), "ERROR"},
+		{"record not found is not an error", logger.Info, time.Now(), gorm.ErrRecordNotFound, "INFO"},
+		{"slow query", logger.Info, time.Now().Add(-time.Second), nil, "WARN"},
+		{"normal query", logger.Info, time.Now(), nil, "INFO"},
+		{"normal query at error level", logger.Error, time.Now(), nil, ""},
+		{"silent", logger.Silent, time.Now(), errors.New("connection refused"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			newCapturingGormLogger(&buf, tt.level).Trace(context.Background(), tt.begin, fc, tt.err)
+
+			if tt.wantLevel == "" {
+				if buf.Len() != 0 {
+					t.Errorf("logged %s, want nothing", buf.String())
+				}
+				return
+			}
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decode log %q: %v", buf.String(), err)
+			}
+			if record["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %s", record["level"], tt.wantLevel)
+			}
+			if record["sql"] != "SELECT * FROM `accounts`" {
+				t.Errorf("sql = %v", record["sql"])
+			}
+			if tt.wantLevel == "ERROR" && record["error"] != "connection refused" {
+				t.Errorf("error = %v, want connection refused", record["error"])
+			}
+		})
+	}
+}
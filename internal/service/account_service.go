@@ -1,12 +1,17 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"strings"
+
+	"github.com/google/uuid"
 
 	"github.com/proyuen/simple-bank-v2/internal/dto/request"
 	"github.com/proyuen/simple-bank-v2/internal/dto/response"
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
 	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
 )
 
 // ==================== 接口定义 (由使用方定义) ====================
@@ -16,52 +21,133 @@ import (
 type AccountRepository interface {
 	Create(ctx context.Context, account *model.Account) error
 	GetByID(ctx context.Context, id uint) (*model.Account, error)
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Account, error)
 	GetByOwnerAndCurrency(ctx context.Context, owner, currency string) (*model.Account, error)
-	ListByOwner(ctx context.Context, owner string, limit, offset int) ([]model.Account, int64, error)
+	CountByOwner(ctx context.Context, owner string) (int64, error)
+	ListByOwner(ctx context.Context, owner, currency, sort string, limit, offset int) ([]model.Account, int64, error)
+	SetOverdraftLimit(ctx context.Context, id uint, limit int64) (*model.Account, error)
+	SetMinBalance(ctx context.Context, id uint, minBalance int64) (*model.Account, error)
+	SetFrozen(ctx context.Context, id uint, frozen bool) (*model.Account, error)
+	SetName(ctx context.Context, id uint, name string) (*model.Account, error)
+	SumByOwnerGroupedByCurrency(ctx context.Context, owner string) ([]model.CurrencyBalance, error)
+	Restore(ctx context.Context, publicID uuid.UUID) (*model.Account, error)
 }
 
 // ==================== Service 实现 ====================
 
 // AccountService 账户业务逻辑
 type AccountService struct {
+	db          TransactionManager
 	accountRepo AccountRepository
+	auditor     AuditRecorder // 审计日志记录 (管理员操作)
+	maxAccounts int           // 每个用户最多拥有的未关闭账户数，0 表示不限制
+
+	defaultCurrency string // 创建账户时省略货币使用的默认货币，为空表示必须指定
 }
 
 // NewAccountService 创建 AccountService 实例
-func NewAccountService(accountRepo AccountRepository) *AccountService {
+func NewAccountService(db TransactionManager, accountRepo AccountRepository, auditor AuditRecorder) *AccountService {
 	return &AccountService{
+		db:          db,
 		accountRepo: accountRepo,
+		auditor:     auditor,
 	}
 }
 
+// WithMaxAccounts 设置每个用户最多拥有的未关闭账户数，0 表示不限制
+// 已关闭 (软删除) 的账户不计入；管理员恢复账户不受此限制
+func (s *AccountService) WithMaxAccounts(n int) *AccountService {
+	s.maxAccounts = n
+	return s
+}
+
+// WithDefaultCurrency 设置创建账户时省略货币使用的默认货币 (单一货币部署)
+// 调用方需保证 code 是支持的货币代码 (配置加载时已校验)，为空表示必须指定
+func (s *AccountService) WithDefaultCurrency(code string) *AccountService {
+	s.defaultCurrency = code
+	return s
+}
+
 // CreateAccount 创建新账户
 func (s *AccountService) CreateAccount(ctx context.Context, owner string, req *request.CreateAccountRequest) (*response.AccountResponse, error) {
+	// 0. 省略货币时使用默认货币
+	accountCurrency := cmp.Or(req.Currency, s.defaultCurrency)
+	if accountCurrency == "" {
+		return nil, apperrors.ErrInvalidParams("currency is required")
+	}
+
 	// 1. 检查是否已存在相同货币类型的账户
-	existingAccount, err := s.accountRepo.GetByOwnerAndCurrency(ctx, owner, req.Currency)
+	existingAccount, err := s.accountRepo.GetByOwnerAndCurrency(ctx, owner, accountCurrency)
 	if err == nil && existingAccount != nil {
 		return nil, apperrors.NewWithMessage(apperrors.CodeAlreadyExists, "account with this currency already exists")
 	}
 
-	// 2. 创建账户模型
+	// 2. 检查账户数上限
+	if err := s.checkAccountLimit(ctx, owner, s.maxAccounts-1); err != nil {
+		return nil, err
+	}
+
+	// 3. 创建账户模型
 	account := &model.Account{
 		Owner:    owner,
+		Name:     strings.TrimSpace(req.Name),
 		Balance:  0,
-		Currency: req.Currency,
+		Currency: accountCurrency,
 	}
 
-	// 3. 保存到数据库
+	// 4. 保存到数据库
 	if err := s.accountRepo.Create(ctx, account); err != nil {
 		return nil, err
 	}
 
-	// 4. 返回响应
-	return s.toAccountResponse(account), nil
+	// 5. 返回响应
+	return toAccountResponse(account), nil
+}
+
+// CreateAccountsBatch 为当前用户一次创建多个货币的账户
+// 在同一事务中依次创建，已有的货币跳过而不是失败；其他错误时全部回滚
+// 创建后超出账户数上限时返回 CodeAccountLimitReached，整批都不会创建
+func (s *AccountService) CreateAccountsBatch(ctx context.Context, owner string, req *request.CreateAccountsBatchRequest) (*response.CreateAccountsBatchResponse, error) {
+	resp := &response.CreateAccountsBatchResponse{
+		Created: []response.AccountResponse{},
+		Skipped: []string{},
+		Results: make([]response.AccountBatchResult, 0, len(req.Currencies)),
+	}
+
+	err := s.db.Transaction(ctx, func(txCtx context.Context) error {
+		for _, currency := range req.Currencies {
+			// 依赖 Create 的唯一性检查 (owner + currency)，已存在时返回 409
+			account := &model.Account{
+				Owner:    owner,
+				Currency: currency,
+			}
+			err := s.accountRepo.Create(txCtx, account)
+			switch {
+			case err == nil:
+				if err := s.checkAccountLimit(txCtx, owner, s.maxAccounts); err != nil {
+					return err
+				}
+				resp.Created = append(resp.Created, *toAccountResponse(account))
+				resp.Results = append(resp.Results, response.AccountBatchResult{Currency: currency, Status: response.AccountBatchCreated})
+			case apperrors.AsAppError(err).Code == apperrors.CodeAlreadyExists:
+				resp.Skipped = append(resp.Skipped, currency)
+				resp.Results = append(resp.Results, response.AccountBatchResult{Currency: currency, Status: response.AccountBatchSkipped})
+			default:
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 // GetAccount 获取账户详情
-func (s *AccountService) GetAccount(ctx context.Context, owner string, accountID uint) (*response.AccountResponse, error) {
+func (s *AccountService) GetAccount(ctx context.Context, owner string, accountID uuid.UUID) (*response.AccountResponse, error) {
 	// 1. 查询账户
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,17 +158,18 @@ func (s *AccountService) GetAccount(ctx context.Context, owner string, accountID
 	}
 
 	// 3. 返回响应
-	return s.toAccountResponse(account), nil
+	return toAccountResponse(account), nil
 }
 
 // ListAccounts 获取用户的账户列表
-func (s *AccountService) ListAccounts(ctx context.Context, owner string, req *request.PaginationRequest) (*response.ListResponse[response.AccountResponse], error) {
+// currency 不为空时只返回该货币的账户
+func (s *AccountService) ListAccounts(ctx context.Context, owner, currency string, req *request.PaginationRequest) (*response.ListResponse[response.AccountResponse], error) {
 	// 1. 计算分页参数
 	limit := req.Limit()
 	offset := req.Offset()
 
 	// 2. 查询账户列表
-	accounts, total, err := s.accountRepo.ListByOwner(ctx, owner, limit, offset)
+	accounts, total, err := s.accountRepo.ListByOwner(ctx, owner, currency, req.Sort, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +177,7 @@ func (s *AccountService) ListAccounts(ctx context.Context, owner string, req *re
 	// 3. 转换为响应格式
 	items := make([]response.AccountResponse, len(accounts))
 	for i, account := range accounts {
-		items[i] = *s.toAccountResponse(&account)
+		items[i] = *toAccountResponse(&account)
 	}
 
 	// 4. 返回分页响应
@@ -98,13 +185,171 @@ func (s *AccountService) ListAccounts(ctx context.Context, owner string, req *re
 	return &result, nil
 }
 
+// GetSummary 按货币汇总当前用户的账户余额
+//
+// 不同货币的余额不能直接相加，因此每种货币单独汇总
+func (s *AccountService) GetSummary(ctx context.Context, owner string) (*response.AccountSummaryResponse, error) {
+	sums, err := s.accountRepo.SumByOwnerGroupedByCurrency(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.CurrencyBalanceResponse, len(sums))
+	for i, sum := range sums {
+		items[i] = response.CurrencyBalanceResponse{
+			Currency:     sum.Currency,
+			TotalBalance: money.Amount(sum.TotalBalance),
+			AccountCount: sum.AccountCount,
+		}
+	}
+	return &response.AccountSummaryResponse{Currencies: items}, nil
+}
+
+// RenameAccount 修改账户名称
+//
+// 名称仅用于展示，不要求唯一；传入空字符串表示清除名称
+func (s *AccountService) RenameAccount(ctx context.Context, owner string, accountID uuid.UUID, name string) (*response.AccountResponse, error) {
+	// 1. 查询账户
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证账户所有权
+	if account.Owner != owner {
+		return nil, apperrors.ErrForbidden()
+	}
+
+	// 3. 更新名称
+	account, err = s.accountRepo.SetName(ctx, account.ID, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return toAccountResponse(account), nil
+}
+
+// SetOverdraftLimit 设置账户透支额度 (管理员操作，不校验所有权，actor 为管理员用户名)
+//
+// 降低额度不会影响已经透支的余额，只会阻止后续扣款
+// 透支额度与最低余额互斥，账户设置了最低余额时不能再设置透支额度
+func (s *AccountService) SetOverdraftLimit(ctx context.Context, actor string, accountID uuid.UUID, limit int64) (*response.AccountResponse, error) {
+	if limit < 0 {
+		return nil, apperrors.ErrInvalidParams("overdraft limit must not be negative")
+	}
+
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && account.MinBalance > 0 {
+		return nil, apperrors.ErrInvalidParams("account has a minimum balance; clear it before setting an overdraft limit")
+	}
+
+	account, err = s.accountRepo.SetOverdraftLimit(ctx, account.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, actor, model.AuditActionAccountOverdraftLimit, account.PublicID.String())
+
+	return toAccountResponse(account), nil
+}
+
+// SetMinBalance 设置账户最低余额 (管理员操作，不校验所有权，actor 为管理员用户名)
+//
+// 提高最低余额不会影响当前余额，只会阻止后续使余额低于最低余额的扣款
+// 最低余额与透支额度互斥，账户设置了透支额度时不能再设置最低余额
+func (s *AccountService) SetMinBalance(ctx context.Context, actor string, accountID uuid.UUID, minBalance int64) (*response.AccountResponse, error) {
+	if minBalance < 0 {
+		return nil, apperrors.ErrInvalidParams("minimum balance must not be negative")
+	}
+
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if minBalance > 0 && account.OverdraftLimit > 0 {
+		return nil, apperrors.ErrInvalidParams("account has an overdraft limit; clear it before setting a minimum balance")
+	}
+
+	account, err = s.accountRepo.SetMinBalance(ctx, account.ID, minBalance)
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, actor, model.AuditActionAccountMinBalance, account.PublicID.String())
+
+	return toAccountResponse(account), nil
+}
+
+// SetFrozen 冻结或解冻账户 (管理员操作，不校验所有权，actor 为管理员用户名)
+//
+// 冻结的账户仍可以查询，但不能作为转账的任何一方 (包括撤销转账和定时转账的执行)；
+// 冻结不影响余额，解冻后恢复正常
+func (s *AccountService) SetFrozen(ctx context.Context, actor string, accountID uuid.UUID, frozen bool) (*response.AccountResponse, error) {
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err = s.accountRepo.SetFrozen(ctx, account.ID, frozen)
+	if err != nil {
+		return nil, err
+	}
+	action := model.AuditActionAccountUnfreeze
+	if frozen {
+		action = model.AuditActionAccountFreeze
+	}
+	s.auditor.Record(ctx, actor, action, account.PublicID.String())
+
+	return toAccountResponse(account), nil
+}
+
+// RestoreAccount 恢复已关闭 (软删除) 的账户 (管理员操作，不校验所有权，actor 为管理员用户名)
+//
+// 账户未关闭时返回 409；关闭期间用户已开立同币种的新账户时同样返回 409，
+// 需要先关闭新账户才能恢复
+func (s *AccountService) RestoreAccount(ctx context.Context, actor string, accountID uuid.UUID) (*response.AccountResponse, error) {
+	account, err := s.accountRepo.Restore(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, actor, model.AuditActionAccountRestore, account.PublicID.String())
+
+	return toAccountResponse(account), nil
+}
+
+// checkAccountLimit 检查用户的未关闭账户数不超过 allowed，未设置上限时总是通过
+// 创建前检查时 allowed 为上限 - 1，在事务中创建后检查时为上限
+//
+// 统计和创建之间没有加锁，并发创建时可能略微超出上限；
+// 每个用户每种货币最多一个账户，超出的数量不会多于货币种数
+func (s *AccountService) checkAccountLimit(ctx context.Context, owner string, allowed int) error {
+	if s.maxAccounts <= 0 {
+		return nil
+	}
+	count, err := s.accountRepo.CountByOwner(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if count > int64(allowed) {
+		return apperrors.ErrAccountLimitReached(s.maxAccounts)
+	}
+	return nil
+}
+
 // toAccountResponse 转换为账户响应
-func (s *AccountService) toAccountResponse(account *model.Account) *response.AccountResponse {
+func toAccountResponse(account *model.Account) *response.AccountResponse {
 	return &response.AccountResponse{
-		ID:        account.ID,
-		Owner:     account.Owner,
-		Balance:   account.Balance,
-		Currency:  account.Currency,
-		CreatedAt: account.CreatedAt,
+		PublicID:       account.PublicID,
+		Number:         account.Number,
+		Owner:          account.Owner,
+		Name:           account.Name,
+		Balance:        money.Amount(account.Balance),
+		OverdraftLimit: money.Amount(account.OverdraftLimit),
+		MinBalance:     money.Amount(account.MinBalance),
+		IsFrozen:       account.IsFrozen,
+		Currency:       account.Currency,
+		CreatedAt:      account.CreatedAt,
+		UpdatedAt:      account.UpdatedAt,
 	}
 }
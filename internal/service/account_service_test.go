@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+)
+
+// newTestAccountService 创建使用内存 Repository 的 AccountService
+func newTestAccountService(repos *memory.Repositories) *AccountService {
+	return NewAccountService(repos.TxManager, repos.Accounts, NewAuditLogger(repos.AuditLogs))
+}
+
+func TestAccountAdminActionsAreAudited(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos)
+	account := mustCreateAccount(t, repos, "alice", "USD", 0)
+
+	if _, err := s.SetOverdraftLimit(ctx, "admin", account.PublicID, 500); err != nil {
+		t.Fatalf("SetOverdraftLimit: %v", err)
+	}
+	if _, err := s.SetOverdraftLimit(ctx, "admin", account.PublicID, 0); err != nil {
+		t.Fatalf("SetOverdraftLimit: %v", err)
+	}
+	if _, err := s.SetMinBalance(ctx, "admin", account.PublicID, 100); err != nil {
+		t.Fatalf("SetMinBalance: %v", err)
+	}
+	if _, err := s.SetFrozen(ctx, "admin", account.PublicID, true); err != nil {
+		t.Fatalf("SetFrozen: %v", err)
+	}
+	if _, err := s.SetFrozen(ctx, "admin", account.PublicID, false); err != nil {
+		t.Fatalf("SetFrozen: %v", err)
+	}
+
+	want := []string{
+		model.AuditActionAccountOverdraftLimit,
+		model.AuditActionAccountOverdraftLimit,
+		model.AuditActionAccountMinBalance,
+		model.AuditActionAccountFreeze,
+		model.AuditActionAccountUnfreeze,
+	}
+	if got := auditActions(t, repos); !slices.Equal(got, want) {
+		t.Errorf("audit actions = %v, want %v", got, want)
+	}
+
+	logs, _, err := repos.AuditLogs.List(ctx, "admin", "", 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, log := range logs {
+		if log.Target != account.PublicID.String() {
+			t.Errorf("audit target = %q, want %q", log.Target, account.PublicID)
+		}
+	}
+}
+
+func TestAccountAdminActionNotAuditedOnFailure(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos)
+	account := mustCreateAccount(t, repos, "alice", "USD", 0)
+
+	// 未关闭的账户不能恢复
+	if _, err := s.RestoreAccount(ctx, "admin", account.PublicID); err == nil {
+		t.Fatal("RestoreAccount on an open account should fail")
+	}
+	if got := auditActions(t, repos); len(got) != 0 {
+		t.Errorf("audit actions = %v, want none", got)
+	}
+}
+
+func TestAccountNameRoundTrips(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos)
+
+	created, err := s.CreateAccount(ctx, "alice", &request.CreateAccountRequest{Currency: "USD", Name: "Savings"})
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	if created.Name != "Savings" {
+		t.Errorf("created name = %q, want Savings", created.Name)
+	}
+
+	renamed, err := s.RenameAccount(ctx, "alice", created.PublicID, "Travel")
+	if err != nil {
+		t.Fatalf("RenameAccount: %v", err)
+	}
+	if renamed.Name != "Travel" {
+		t.Errorf("renamed name = %q, want Travel", renamed.Name)
+	}
+
+	got, err := s.GetAccount(ctx, "alice", created.PublicID)
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if got.Name != "Travel" {
+		t.Errorf("stored name = %q, want Travel", got.Name)
+	}
+}
+
+func TestRenameOthersAccountForbidden(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos)
+	account := mustCreateAccount(t, repos, "alice", "USD", 0)
+
+	_, err := s.RenameAccount(ctx, "bob", account.PublicID, "Mine now")
+	assertCode(t, err, apperrors.CodeForbidden)
+	if got := mustGetAccount(t, repos, account.ID).Name; got != account.Name {
+		t.Errorf("name = %q, want unchanged %q", got, account.Name)
+	}
+}
+
+func TestCreateAccountDefaultCurrency(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+
+	// 未配置默认货币时必须指定
+	_, err := newTestAccountService(repos).CreateAccount(ctx, "alice", &request.CreateAccountRequest{})
+	assertCode(t, err, apperrors.CodeInvalidParams)
+
+	s := newTestAccountService(repos).WithDefaultCurrency("EUR")
+	inferred, err := s.CreateAccount(ctx, "alice", &request.CreateAccountRequest{})
+	if err != nil {
+		t.Fatalf("CreateAccount without currency: %v", err)
+	}
+	explicit, err := s.CreateAccount(ctx, "alice", &request.CreateAccountRequest{Currency: "USD"})
+	if err != nil {
+		t.Fatalf("CreateAccount with currency: %v", err)
+	}
+	if inferred.Currency != "EUR" || explicit.Currency != "USD" {
+		t.Errorf("currencies = %s, %s; want EUR, USD", inferred.Currency, explicit.Currency)
+	}
+}
+
+func TestCreateAccountLimit(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos).WithMaxAccounts(2)
+
+	// 上限以内可以创建
+	for _, currency := range []string{"USD", "EUR"} {
+		if _, err := s.CreateAccount(ctx, "alice", &request.CreateAccountRequest{Currency: currency}); err != nil {
+			t.Fatalf("CreateAccount(%s): %v", currency, err)
+		}
+	}
+	_, err := s.CreateAccount(ctx, "alice", &request.CreateAccountRequest{Currency: "CNY"})
+	assertCode(t, err, apperrors.CodeAccountLimitReached)
+
+	// 上限按用户计算
+	if _, err := s.CreateAccount(ctx, "bob", &request.CreateAccountRequest{Currency: "CNY"}); err != nil {
+		t.Errorf("CreateAccount for another user: %v", err)
+	}
+
+	// 已关闭的账户不计入上限
+	usd, err := repos.Accounts.GetByOwnerAndCurrency(ctx, "alice", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Accounts.Close(ctx, usd.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateAccount(ctx, "alice", &request.CreateAccountRequest{Currency: "CNY"}); err != nil {
+		t.Errorf("CreateAccount after closing an account: %v", err)
+	}
+}
+
+func TestCreateAccountsBatchSkipsExisting(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos)
+	existing := mustCreateAccount(t, repos, "alice", "EUR", 0)
+
+	resp, err := s.CreateAccountsBatch(ctx, "alice", &request.CreateAccountsBatchRequest{
+		Currencies: []string{"USD", "EUR", "CNY"},
+	})
+	if err != nil {
+		t.Fatalf("CreateAccountsBatch: %v", err)
+	}
+
+	var created []string
+	for _, account := range resp.Created {
+		created = append(created, account.Currency)
+	}
+	if !slices.Equal(created, []string{"USD", "CNY"}) || !slices.Equal(resp.Skipped, []string{"EUR"}) {
+		t.Errorf("created %v, skipped %v; want [USD CNY], [EUR]", created, resp.Skipped)
+	}
+	want := []response.AccountBatchResult{
+		{Currency: "USD", Status: response.AccountBatchCreated},
+		{Currency: "EUR", Status: response.AccountBatchSkipped},
+		{Currency: "CNY", Status: response.AccountBatchCreated},
+	}
+	if !slices.Equal(resp.Results, want) {
+		t.Errorf("results = %v, want %v", resp.Results, want)
+	}
+
+	// 已有账户不受影响
+	got, err := repos.Accounts.GetByOwnerAndCurrency(ctx, "alice", "EUR")
+	if err != nil || got.ID != existing.ID {
+		t.Errorf("EUR account = %v, %v, want the existing account %d", got, err, existing.ID)
+	}
+}
+
+func TestCreateAccountsBatchRollsBackOverLimit(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestAccountService(repos).WithMaxAccounts(2)
+	mustCreateAccount(t, repos, "alice", "EUR", 0)
+
+	// 第二个新账户超出上限，整批都不创建
+	_, err := s.CreateAccountsBatch(ctx, "alice", &request.CreateAccountsBatchRequest{
+		Currencies: []string{"USD", "EUR", "CNY"},
+	})
+	assertCode(t, err, apperrors.CodeAccountLimitReached)
+	if count, _ := repos.Accounts.CountByOwner(ctx, "alice"); count != 1 {
+		t.Errorf("accounts = %d, want 1", count)
+	}
+}
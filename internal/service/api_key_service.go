@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+// ==================== 接口定义 (由使用方定义) ====================
+
+// APIKeyRepository API Key 数据访问接口
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.APIKey, error)
+	GetBySecretHash(ctx context.Context, secretHash string) (*model.APIKey, error)
+	ListByOwner(ctx context.Context, owner string, limit, offset int) ([]model.APIKey, int64, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	Touch(ctx context.Context, id uint, at time.Time) error
+}
+
+// ==================== Service 实现 ====================
+
+const (
+	// apiKeyPrefix 所有 API Key 的固定前缀，便于在日志和代码仓库中识别泄露的 Key
+	apiKeyPrefix = "sbk_"
+
+	// apiKeySecretBytes Key 中随机部分的字节数 (十六进制编码后为 64 个字符)
+	apiKeySecretBytes = 32
+
+	// apiKeyDisplayPrefixLen 保存并展示的 Key 前缀长度 (含 apiKeyPrefix)
+	apiKeyDisplayPrefixLen = 12
+
+	// apiKeyTouchInterval 最后使用时间的更新间隔，避免每个请求都写数据库
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyService API Key 业务逻辑
+//
+// API Key 供无法走交互式登录的服务端集成使用:
+//   - Key 只在创建时返回一次，数据库只保存 SHA-256，丢失后只能撤销重建
+//   - 认证成功后生成一个不过期的 payload，权限由创建时授予的 scope 决定
+//   - API Key 永远只有普通用户权限，即使所有者是管理员
+//   - 不受修改密码影响，撤销是使 Key 失效的唯一方式
+type APIKeyService struct {
+	apiKeyRepo APIKeyRepository
+	auditor    AuditRecorder
+	now        func() time.Time // 时钟，测试时可替换
+}
+
+// NewAPIKeyService 创建 APIKeyService 实例
+func NewAPIKeyService(apiKeyRepo APIKeyRepository, auditor AuditRecorder) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		auditor:    auditor,
+		now:        time.Now,
+	}
+}
+
+// WithClock 替换 Service 使用的时钟
+func (s *APIKeyService) WithClock(now func() time.Time) *APIKeyService {
+	s.now = now
+	return s
+}
+
+// CreateAPIKey 为当前用户创建 API Key
+// 未指定 scope 时授予全部 scope；返回的 Key 明文之后无法再次获取
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, owner string, req *request.CreateAPIKeyRequest) (*response.CreateAPIKeyResponse, error) {
+	// 1. 生成 Key
+	rawKey, err := newAPIKey()
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInternalError, err)
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = token.AllScopes()
+	}
+
+	// 2. 保存哈希
+	key := &model.APIKey{
+		Owner:      owner,
+		Name:       req.Name,
+		Prefix:     rawKey[:apiKeyDisplayPrefixLen],
+		SecretHash: hashAPIKey(rawKey),
+		Scopes:     strings.Join(dedupe(scopes), ","),
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, owner, model.AuditActionAPIKeyCreate, key.PublicID.String())
+
+	// 3. 返回响应 (只有这一次包含 Key 明文)
+	return &response.CreateAPIKeyResponse{
+		APIKeyResponse: *toAPIKeyResponse(key),
+		Key:            rawKey,
+	}, nil
+}
+
+// ListAPIKeys 获取当前用户的 API Key (包括已撤销的)
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, owner string, req *request.PaginationRequest) (*response.ListResponse[response.APIKeyResponse], error) {
+	keys, total, err := s.apiKeyRepo.ListByOwner(ctx, owner, req.Limit(), req.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.APIKeyResponse, len(keys))
+	for i := range keys {
+		items[i] = *toAPIKeyResponse(&keys[i])
+	}
+	result := response.NewListResponse(items, req.PageID, req.PageSize, total)
+	return &result, nil
+}
+
+// RevokeAPIKey 撤销当前用户的 API Key，立即生效
+// 不属于当前用户的 Key 返回 404，不暴露其是否存在
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, owner string, id uuid.UUID) (*response.APIKeyResponse, error) {
+	// 1. 查询并验证所有权
+	key, err := s.apiKeyRepo.GetByPublicID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.Owner != owner {
+		return nil, apperrors.ErrNotFound("api key")
+	}
+
+	// 2. 撤销 (已撤销时返回 409)
+	now := s.now()
+	if err := s.apiKeyRepo.Revoke(ctx, key.ID, now); err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, owner, model.AuditActionAPIKeyRevoke, key.PublicID.String())
+
+	key.Revoked = true
+	key.RevokedAt = &now
+	return toAPIKeyResponse(key), nil
+}
+
+// AuthenticateAPIKey 验证 API Key，返回代表该 Key 的 payload
+//
+// 不存在或已撤销的 Key 统一返回 401 (invalid api key)
+// payload 的 ID 为 Key 的公开ID，Role 固定为普通用户，Scopes 为授予的 scope，没有过期时间
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*token.Payload, error) {
+	// 1. 按哈希查询
+	key, err := s.apiKeyRepo.GetBySecretHash(ctx, hashAPIKey(rawKey))
+	if err != nil {
+		if apperrors.AsAppError(err).Code == apperrors.CodeNotFound {
+			return nil, apperrors.NewWithMessage(apperrors.CodeUnauthorized, "invalid api key")
+		}
+		return nil, err
+	}
+	if key.Revoked {
+		return nil, apperrors.NewWithMessage(apperrors.CodeUnauthorized, "invalid api key")
+	}
+
+	// 2. 更新最后使用时间 (尽力而为，失败不影响认证)
+	now := s.now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.Touch(context.WithoutCancel(ctx), key.ID, now); err != nil {
+			logging.FromContext(ctx).Warn("update api key last used", "api_key", key.PublicID, "error", err)
+		}
+	}
+
+	// 3. 生成 payload
+	return &token.Payload{
+		ID:       key.PublicID,
+		Username: key.Owner,
+		Role:     model.RoleUser,
+		Scopes:   key.ScopeList(),
+		IssuedAt: now,
+	}, nil
+}
+
+// newAPIKey 生成一个新的 API Key 明文: sbk_ + 64 个十六进制字符
+func newAPIKey() (string, error) {
+	b := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// hashAPIKey 计算 Key 的 SHA-256 (十六进制)
+// Key 本身有 256 位随机熵，不需要加盐或慢哈希
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// dedupe 去除重复元素，保留首次出现的顺序
+func dedupe(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
+// toAPIKeyResponse 转换为 API Key 响应 (不包含 Key 明文)
+func toAPIKeyResponse(key *model.APIKey) *response.APIKeyResponse {
+	scopes := key.ScopeList()
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &response.APIKeyResponse{
+		PublicID:   key.PublicID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		Revoked:    key.Revoked,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// ==================== 接口定义 (由使用方定义) ====================
+
+// AuditLogRepository 审计日志数据访问接口
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *model.AuditLog) error
+	List(ctx context.Context, actor, action string, limit, offset int) ([]model.AuditLog, int64, error)
+}
+
+// AuditRecorder 审计记录接口
+// 需要记录敏感操作的 Service 依赖此接口，而不是具体的 AuditLogger
+type AuditRecorder interface {
+	Record(ctx context.Context, actor, action, target string)
+}
+
+// ==================== 客户端信息 ====================
+
+// clientIPKey 是 Context 中存储客户端IP的键
+type clientIPKey struct{}
+
+// ContextWithClientIP 将客户端IP存入 Context
+// Handler 在调用 Service 前设置，供审计日志使用
+func ContextWithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// ClientIPFromContext 从 Context 中获取客户端IP，不存在时返回空字符串
+func ClientIPFromContext(ctx context.Context) string {
+	clientIP, _ := ctx.Value(clientIPKey{}).(string)
+	return clientIP
+}
+
+// ==================== Service 实现 ====================
+
+// AuditLogger 审计日志业务逻辑
+//
+// 写入是尽力而为的: 失败只记录错误日志，不影响主流程
+type AuditLogger struct {
+	auditRepo AuditLogRepository
+}
+
+// NewAuditLogger 创建 AuditLogger 实例
+func NewAuditLogger(auditRepo AuditLogRepository) *AuditLogger {
+	return &AuditLogger{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record 记录一条审计日志
+// 客户端IP 从 Context 中读取 (见 ContextWithClientIP)
+func (l *AuditLogger) Record(ctx context.Context, actor, action, target string) {
+	log := &model.AuditLog{
+		Actor:    actor,
+		Action:   action,
+		Target:   target,
+		ClientIP: ClientIPFromContext(ctx),
+	}
+
+	// 使用不可取消的 Context: 客户端断开不应导致审计记录丢失
+	if err := l.auditRepo.Create(context.WithoutCancel(ctx), log); err != nil {
+		logging.FromContext(ctx).Error("write audit log",
+			"actor", actor,
+			"action", action,
+			"target", target,
+			"error", err,
+		)
+	}
+}
+
+// ListAuditLogs 查询审计日志 (管理员)
+func (l *AuditLogger) ListAuditLogs(ctx context.Context, req *request.ListAuditLogsRequest) (*response.ListResponse[response.AuditLogResponse], error) {
+	// 1. 计算分页参数
+	pagination := request.PaginationRequest{
+		PageID:   req.PageID,
+		PageSize: req.PageSize,
+	}
+
+	// 2. 查询审计日志
+	logs, total, err := l.auditRepo.List(ctx, req.Actor, req.Action, pagination.Limit(), pagination.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 转换为响应格式
+	items := make([]response.AuditLogResponse, len(logs))
+	for i, log := range logs {
+		items[i] = response.AuditLogResponse{
+			ID:        log.ID,
+			Actor:     log.Actor,
+			Action:    log.Action,
+			Target:    log.Target,
+			ClientIP:  log.ClientIP,
+			CreatedAt: log.CreatedAt,
+		}
+	}
+
+	// 4. 返回分页响应
+	result := response.NewListResponse(items, req.PageID, req.PageSize, total)
+	return &result, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/pkg/token"
+)
+
+const testSecretKey = "01234567890123456789012345678901"
+
+// newTestTokenMaker 创建测试用的 JWTMaker
+func newTestTokenMaker(t *testing.T) token.Maker {
+	t.Helper()
+	maker, err := token.NewJWTMaker(testSecretKey)
+	if err != nil {
+		t.Fatalf("NewJWTMaker: %v", err)
+	}
+	return maker
+}
+
+// newTestUserService 创建使用内存 Repository 的 UserService
+func newTestUserService(t *testing.T, repos *memory.Repositories) *UserService {
+	t.Helper()
+	auditor := NewAuditLogger(repos.AuditLogs)
+	return NewUserService(repos.Users, repos.Sessions, newTestTokenMaker(t), 15*time.Minute, 24*time.Hour, 0, auditor)
+}
+
+// mustCreateUser 通过 UserService 注册用户，密码为 "secret123"
+func mustCreateUser(t *testing.T, s *UserService, username string) {
+	t.Helper()
+	_, err := s.CreateUser(context.Background(), &request.CreateUserRequest{
+		Username: username,
+		Password: "secret123",
+		FullName: "Test " + username,
+		Email:    username + "@example.com",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", username, err)
+	}
+}
+
+// mustLogin 以 "secret123" 登录
+func mustLogin(t *testing.T, s *UserService, username string) *response.LoginResponse {
+	t.Helper()
+	resp, err := s.LoginUser(context.Background(), &request.LoginUserRequest{
+		Username: username,
+		Password: "secret123",
+	}, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("LoginUser(%s): %v", username, err)
+	}
+	return resp
+}
+
+// mustCreateAccount 直接在 Repository 中创建账户 (不经过 AccountService 的限制)
+func mustCreateAccount(t *testing.T, repos *memory.Repositories, owner, currency string, balance int64) *model.Account {
+	t.Helper()
+	account := &model.Account{Owner: owner, Currency: currency, Balance: balance}
+	if err := repos.Accounts.Create(context.Background(), account); err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	return account
+}
+
+// mustGetAccount 读取账户的最新状态
+func mustGetAccount(t *testing.T, repos *memory.Repositories, id uint) *model.Account {
+	t.Helper()
+	account, err := repos.Accounts.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get account %d: %v", id, err)
+	}
+	return account
+}
+
+// assertCode 断言 err 是指定错误码的 AppError
+func assertCode(t *testing.T, err error, code int) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected error code %d, got nil", code)
+	}
+	if got := apperrors.AsAppError(err).Code; got != code {
+		t.Fatalf("expected error code %d, got %d (%v)", code, got, err)
+	}
+}
+
+// auditActions 返回内存中记录的审计操作 (按写入顺序)
+func auditActions(t *testing.T, repos *memory.Repositories) []string {
+	t.Helper()
+	logs, _, err := repos.AuditLogs.List(context.Background(), "", "", 1000, 0)
+	if err != nil {
+		t.Fatalf("list audit logs: %v", err)
+	}
+	actions := make([]string, len(logs))
+	for i, log := range logs {
+		actions[len(logs)-1-i] = log.Action
+	}
+	return actions
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/pkg/fx"
+)
+
+// ==================== 接口定义 (由使用方定义) ====================
+
+// RateQuoter 汇率查询接口 (由 fx.CachedProvider 实现)
+type RateQuoter interface {
+	Quote(ctx context.Context, from, to string) (fx.Quote, error)
+}
+
+// ==================== Service 实现 ====================
+
+// RateService 汇率相关业务逻辑
+type RateService struct {
+	quoter RateQuoter
+}
+
+// NewRateService 创建 RateService 实例
+func NewRateService(quoter RateQuoter) *RateService {
+	return &RateService{
+		quoter: quoter,
+	}
+}
+
+// GetRate 查询当前汇率
+//
+// 错误:
+//   - CodeNotFound: 不支持该货币对
+//   - CodeServiceUnavailable: 汇率来源不可用且没有缓存
+func (s *RateService) GetRate(ctx context.Context, req *request.GetRateRequest) (*response.RateResponse, error) {
+	quote, err := s.quoter.Quote(ctx, req.From, req.To)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return nil, apperrors.ErrNotFound("exchange rate")
+		}
+		logging.FromContext(ctx).Error("get exchange rate", "from", req.From, "to", req.To, "error", err)
+		return nil, apperrors.NewWithMessage(apperrors.CodeServiceUnavailable, "exchange rate unavailable")
+	}
+
+	return &response.RateResponse{
+		From:      quote.From,
+		To:        quote.To,
+		Rate:      quote.Rate.String(),
+		Timestamp: quote.Timestamp,
+		Stale:     quote.Stale,
+	}, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+)
+
+// ==================== 接口定义 (由使用方定义) ====================
+
+// ReconcileAccountRepository 对账需要的账户数据访问接口
+type ReconcileAccountRepository interface {
+	ListAfterID(ctx context.Context, afterID uint, limit int) ([]model.Account, error)
+	GetForUpdate(ctx context.Context, id uint) (*model.Account, error)
+	SetBalance(ctx context.Context, id uint, balance int64) (*model.Account, error)
+}
+
+// ReconcileEntryRepository 对账需要的账目数据访问接口
+type ReconcileEntryRepository interface {
+	SumByAccountIDs(ctx context.Context, accountIDs []uint) (map[uint]int64, error)
+}
+
+// ==================== Service 实现 ====================
+
+// defaultReconcileBatchSize 每批读取的账户数
+const defaultReconcileBatchSize = 500
+
+// Discrepancy 一个账户的余额与账目之和不一致
+type Discrepancy struct {
+	AccountID uint
+	PublicID  uuid.UUID
+	Owner     string
+	Currency  string
+	Stored    int64 // accounts.balance 中保存的余额
+	Computed  int64 // 根据账目重新计算的余额
+	Fixed     bool  // 是否已把保存的余额修正为 Computed
+}
+
+// ReconcileReport 一次对账的结果汇总
+type ReconcileReport struct {
+	Checked       int // 检查的账户数
+	Discrepancies int // 不一致的账户数
+	Fixed         int // 已修正的账户数
+}
+
+// ReconcileService 账户余额对账
+//
+// 账户余额应始终等于该账户全部账目的金额之和，对账按 ID 分批遍历所有未关闭账户，
+// 每批用一次 GROUP BY 查询重新计算余额并与保存的余额比较
+//
+// 批量比较时并发的转账可能造成误报，因此每个疑似不一致的账户
+// 都会在事务中锁定 (FOR UPDATE) 后重新计算确认；开启修复时在同一事务中修正余额
+type ReconcileService struct {
+	db          TransactionManager
+	accountRepo ReconcileAccountRepository
+	entryRepo   ReconcileEntryRepository
+	batchSize   int
+}
+
+// NewReconcileService 创建 ReconcileService 实例
+func NewReconcileService(db TransactionManager, accountRepo ReconcileAccountRepository, entryRepo ReconcileEntryRepository) *ReconcileService {
+	return &ReconcileService{
+		db:          db,
+		accountRepo: accountRepo,
+		entryRepo:   entryRepo,
+		batchSize:   defaultReconcileBatchSize,
+	}
+}
+
+// WithBatchSize 设置每批读取的账户数
+func (s *ReconcileService) WithBatchSize(n int) *ReconcileService {
+	if n > 0 {
+		s.batchSize = n
+	}
+	return s
+}
+
+// ReconcileAll 检查所有未关闭账户的余额
+//
+// fix 为 false 时只读，不修改任何数据；为 true 时把不一致的余额修正为账目之和
+// 每发现一个 (确认后的) 不一致账户调用一次 report，由调用方记录
+func (s *ReconcileService) ReconcileAll(ctx context.Context, fix bool, report func(d *Discrepancy)) (*ReconcileReport, error) {
+	result := &ReconcileReport{}
+
+	var afterID uint
+	for {
+		// 1. 按 ID 读取下一批账户
+		accounts, err := s.accountRepo.ListAfterID(ctx, afterID, s.batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(accounts) == 0 {
+			return result, nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+
+		// 2. 一次查询计算这批账户的账目之和
+		ids := make([]uint, len(accounts))
+		for i := range accounts {
+			ids[i] = accounts[i].ID
+		}
+		totals, err := s.entryRepo.SumByAccountIDs(ctx, ids)
+		if err != nil {
+			return result, err
+		}
+
+		// 3. 对疑似不一致的账户加锁确认，需要时修正
+		for i := range accounts {
+			result.Checked++
+			if accounts[i].Balance == totals[accounts[i].ID] {
+				continue
+			}
+
+			d, err := s.reconcileAccount(ctx, accounts[i].ID, fix)
+			if err != nil {
+				return result, err
+			}
+			if d == nil {
+				continue
+			}
+			result.Discrepancies++
+			if d.Fixed {
+				result.Fixed++
+			}
+			if report != nil {
+				report(d)
+			}
+		}
+	}
+}
+
+// reconcileAccount 在事务中锁定账户并重新比较余额，一致 (或账户已关闭) 时返回 nil
+func (s *ReconcileService) reconcileAccount(ctx context.Context, accountID uint, fix bool) (*Discrepancy, error) {
+	var d *Discrepancy
+	err := s.db.Transaction(ctx, func(ctx context.Context) error {
+		account, err := s.accountRepo.GetForUpdate(ctx, accountID)
+		if err != nil {
+			// 对账期间被关闭的账户跳过
+			if apperrors.AsAppError(err).Code == apperrors.CodeAccountNotFound {
+				return nil
+			}
+			return err
+		}
+
+		totals, err := s.entryRepo.SumByAccountIDs(ctx, []uint{accountID})
+		if err != nil {
+			return err
+		}
+		computed := totals[accountID]
+		if account.Balance == computed {
+			return nil
+		}
+
+		d = &Discrepancy{
+			AccountID: account.ID,
+			PublicID:  account.PublicID,
+			Owner:     account.Owner,
+			Currency:  account.Currency,
+			Stored:    account.Balance,
+			Computed:  computed,
+		}
+		if !fix {
+			return nil
+		}
+		if _, err := s.accountRepo.SetBalance(ctx, account.ID, computed); err != nil {
+			return err
+		}
+		d.Fixed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+)
+
+// mustCreateEntry 直接写入一条账目，不改变账户余额
+func mustCreateEntry(t *testing.T, repos *memory.Repositories, accountID uint, amount int64) {
+	t.Helper()
+	if err := repos.Entries.Create(context.Background(), &model.Entry{AccountID: accountID, Amount: amount}); err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+}
+
+func TestReconcileAll(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	// 每批 2 个账户，3 个账户需要翻页
+	s := NewReconcileService(repos.TxManager, repos.Accounts, repos.Entries).WithBatchSize(2)
+
+	balanced := mustCreateAccount(t, repos, "alice", "USD", 150)
+	mustCreateEntry(t, repos, balanced.ID, 200)
+	mustCreateEntry(t, repos, balanced.ID, -50)
+	empty := mustCreateAccount(t, repos, "alice", "EUR", 0)
+	drifted := mustCreateAccount(t, repos, "bob", "USD", 70)
+	mustCreateEntry(t, repos, drifted.ID, 100)
+
+	// 默认只读: 报告不一致但不修改余额
+	var found []*Discrepancy
+	report, err := s.ReconcileAll(ctx, false, func(d *Discrepancy) { found = append(found, d) })
+	if err != nil {
+		t.Fatalf("ReconcileAll: %v", err)
+	}
+	if *report != (ReconcileReport{Checked: 3, Discrepancies: 1}) {
+		t.Errorf("report = %+v, want 3 checked, 1 discrepancy", *report)
+	}
+	if len(found) != 1 || found[0].AccountID != drifted.ID || found[0].Stored != 70 || found[0].Computed != 100 || found[0].Fixed {
+		t.Fatalf("discrepancies = %+v, want account %d stored 70 computed 100", found, drifted.ID)
+	}
+	if got := mustGetAccount(t, repos, drifted.ID).Balance; got != 70 {
+		t.Errorf("read-only run changed balance to %d", got)
+	}
+
+	// 修复后余额等于账目之和，再次对账没有不一致
+	report, err = s.ReconcileAll(ctx, true, nil)
+	if err != nil {
+		t.Fatalf("ReconcileAll fix: %v", err)
+	}
+	if report.Discrepancies != 1 || report.Fixed != 1 {
+		t.Errorf("fix report = %+v, want 1 discrepancy fixed", *report)
+	}
+	for id, want := range map[uint]int64{balanced.ID: 150, empty.ID: 0, drifted.ID: 100} {
+		if got := mustGetAccount(t, repos, id).Balance; got != want {
+			t.Errorf("account %d balance = %d, want %d", id, got, want)
+		}
+	}
+
+	report, err = s.ReconcileAll(ctx, false, nil)
+	if err != nil {
+		t.Fatalf("ReconcileAll: %v", err)
+	}
+	if report.Discrepancies != 0 {
+		t.Errorf("discrepancies after fix = %d, want 0", report.Discrepancies)
+	}
+}
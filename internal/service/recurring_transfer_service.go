@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+// ==================== 接口定义 (由使用方定义) ====================
+
+// RecurringTransferRepository 周期转账规则数据访问接口
+type RecurringTransferRepository interface {
+	Create(ctx context.Context, recurring *model.RecurringTransfer) error
+	GetByID(ctx context.Context, id uint) (*model.RecurringTransfer, error)
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.RecurringTransfer, error)
+	ListByFromAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.RecurringTransfer, int64, error)
+	SetOccurrenceCount(ctx context.Context, id uint, from, to int) error
+	Finish(ctx context.Context, id uint, status string) error
+}
+
+// OccurrenceRepository 周期转账服务需要的定时转账数据访问接口
+type OccurrenceRepository interface {
+	Create(ctx context.Context, scheduled *model.ScheduledTransfer) error
+	CancelPendingByRecurring(ctx context.Context, recurringID uint) error
+}
+
+// RecurringAccountRepository 周期转账服务需要的账户数据访问接口
+type RecurringAccountRepository interface {
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Account, error)
+	PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error)
+}
+
+// ==================== Service 实现 ====================
+
+// RecurringTransferService 周期转账 (定期委托) 业务逻辑
+//
+// 规则的每一次执行都是一条 ScheduledTransfer，由定时转账的后台任务执行；
+// 每次执行结束后 ScheduledTransferService 调用 ScheduleNext 生成下一次
+type RecurringTransferService struct {
+	db            TransactionManager
+	recurringRepo RecurringTransferRepository
+	scheduledRepo OccurrenceRepository
+	accountRepo   RecurringAccountRepository
+	auditor       AuditRecorder
+	now           func() time.Time // 时钟，测试时可替换
+}
+
+// NewRecurringTransferService 创建 RecurringTransferService 实例
+func NewRecurringTransferService(
+	db TransactionManager,
+	recurringRepo RecurringTransferRepository,
+	scheduledRepo OccurrenceRepository,
+	accountRepo RecurringAccountRepository,
+	auditor AuditRecorder,
+) *RecurringTransferService {
+	return &RecurringTransferService{
+		db:            db,
+		recurringRepo: recurringRepo,
+		scheduledRepo: scheduledRepo,
+		accountRepo:   accountRepo,
+		auditor:       auditor,
+		now:           time.Now,
+	}
+}
+
+// WithClock 替换 Service 使用的时钟
+func (s *RecurringTransferService) WithClock(now func() time.Time) *RecurringTransferService {
+	s.now = now
+	return s
+}
+
+// CreateRecurringTransfer 为当前用户的源账户创建周期转账规则
+// 同时生成第一次 (执行时间为 start_at) 的定时转账
+func (s *RecurringTransferService) CreateRecurringTransfer(ctx context.Context, owner string, accountID uuid.UUID, req *request.CreateRecurringTransferRequest) (*response.RecurringTransferResponse, error) {
+	// 1. 验证时间范围
+	if !req.StartAt.After(s.now()) {
+		return nil, apperrors.ErrInvalidParams("start_at must be in the future")
+	}
+	if req.EndAt != nil && req.EndAt.Before(req.StartAt) {
+		return nil, apperrors.ErrInvalidParams("end_at must not be before start_at")
+	}
+
+	// 2. 验证源账户属于当前用户，目标账户存在，货币一致
+	fromAccount, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+	toAccount, err := s.accountRepo.GetByPublicID(ctx, req.ToAccountPublicID())
+	if err != nil {
+		return nil, err
+	}
+	if toAccount.ID == fromAccount.ID {
+		return nil, apperrors.NewWithMessage(apperrors.CodeSameAccount, "cannot transfer to same account")
+	}
+	if fromAccount.Currency != toAccount.Currency || fromAccount.Currency != req.Currency {
+		return nil, apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "currency mismatch")
+	}
+
+	// 3. 在同一事务中保存规则和第一次执行
+	recurring := &model.RecurringTransfer{
+		Owner:           owner,
+		FromAccountID:   fromAccount.ID,
+		ToAccountID:     toAccount.ID,
+		Amount:          req.Amount.Int64(),
+		Currency:        req.Currency,
+		Cadence:         req.Cadence,
+		StartAt:         req.StartAt,
+		EndAt:           req.EndAt,
+		OccurrenceCount: 1,
+		Status:          model.RecurringTransferActive,
+	}
+	err = s.db.Transaction(ctx, func(txCtx context.Context) error {
+		if err := s.recurringRepo.Create(txCtx, recurring); err != nil {
+			return err
+		}
+		return s.scheduledRepo.Create(txCtx, newOccurrence(recurring, recurring.Occurrence(0)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, owner, model.AuditActionRecurringTransferCreate, recurring.PublicID.String())
+
+	// 4. 返回响应
+	accounts := map[uint]uuid.UUID{fromAccount.ID: fromAccount.PublicID, toAccount.ID: toAccount.PublicID}
+	return toRecurringTransferResponse(recurring, accounts), nil
+}
+
+// ListRecurringTransfers 获取源账户的周期转账规则 (只能查看自己的账户)
+func (s *RecurringTransferService) ListRecurringTransfers(ctx context.Context, owner string, accountID uuid.UUID, req *request.PaginationRequest) (*response.ListResponse[response.RecurringTransferResponse], error) {
+	// 1. 验证账户所有权
+	account, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询规则
+	rules, total, err := s.recurringRepo.ListByFromAccountID(ctx, account.ID, req.Limit(), req.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 返回分页响应
+	items, err := s.toRecurringTransferResponses(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	result := response.NewListResponse(items, req.PageID, req.PageSize, total)
+	return &result, nil
+}
+
+// CancelRecurringTransfer 取消周期转账规则
+// 尚未开始执行的下一次同时取消；已被后台任务领取的那一次仍会执行
+func (s *RecurringTransferService) CancelRecurringTransfer(ctx context.Context, owner string, accountID, recurringID uuid.UUID) (*response.RecurringTransferResponse, error) {
+	// 1. 验证账户所有权，规则必须属于该账户
+	account, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+	recurring, err := s.recurringRepo.GetByPublicID(ctx, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	if recurring.FromAccountID != account.ID {
+		return nil, apperrors.ErrNotFound("recurring transfer")
+	}
+
+	// 2. 结束规则并取消等待中的下一次
+	err = s.db.Transaction(ctx, func(txCtx context.Context) error {
+		if err := s.recurringRepo.Finish(txCtx, recurring.ID, model.RecurringTransferCancelled); err != nil {
+			return err
+		}
+		return s.scheduledRepo.CancelPendingByRecurring(txCtx, recurring.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	recurring.Status = model.RecurringTransferCancelled
+	s.auditor.Record(ctx, owner, model.AuditActionRecurringTransferCancel, recurring.PublicID.String())
+
+	// 3. 返回响应
+	items, err := s.toRecurringTransferResponses(ctx, []model.RecurringTransfer{*recurring})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// ScheduleNext 在规则的某一次结束 (执行成功、失败或被单独取消) 后生成下一次
+//
+// 规则已结束时什么也不做；下一次的时间晚于 end_at 时把规则标记为 completed
+// 通过 occurrence_count 的条件更新保证同一次只生成一条
+func (s *RecurringTransferService) ScheduleNext(ctx context.Context, occurrence *model.ScheduledTransfer) error {
+	if occurrence.RecurringTransferID == nil {
+		return nil
+	}
+	recurring, err := s.recurringRepo.GetByID(ctx, *occurrence.RecurringTransferID)
+	if err != nil {
+		return err
+	}
+	if recurring.Status != model.RecurringTransferActive {
+		return nil
+	}
+
+	next := recurring.Occurrence(recurring.OccurrenceCount)
+	if recurring.EndAt != nil && next.After(*recurring.EndAt) {
+		err = s.recurringRepo.Finish(ctx, recurring.ID, model.RecurringTransferCompleted)
+	} else {
+		err = s.db.Transaction(ctx, func(txCtx context.Context) error {
+			if err := s.recurringRepo.SetOccurrenceCount(txCtx, recurring.ID, recurring.OccurrenceCount, recurring.OccurrenceCount+1); err != nil {
+				return err
+			}
+			return s.scheduledRepo.Create(txCtx, newOccurrence(recurring, next))
+		})
+	}
+
+	// 规则在此期间被取消或已由其他实例推进，无需处理
+	if err != nil && apperrors.AsAppError(err).Code == apperrors.CodeStateConflict {
+		return nil
+	}
+	return err
+}
+
+// ownedAccount 查询账户并验证属于 owner
+func (s *RecurringTransferService) ownedAccount(ctx context.Context, owner string, accountID uuid.UUID) (*model.Account, error) {
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Owner != owner {
+		return nil, apperrors.ErrUnauthorized()
+	}
+	return account, nil
+}
+
+// newOccurrence 为规则生成一次在 executeAt 执行的定时转账
+func newOccurrence(recurring *model.RecurringTransfer, executeAt time.Time) *model.ScheduledTransfer {
+	recurringID := recurring.ID
+	return &model.ScheduledTransfer{
+		Owner:               recurring.Owner,
+		FromAccountID:       recurring.FromAccountID,
+		ToAccountID:         recurring.ToAccountID,
+		Amount:              recurring.Amount,
+		Currency:            recurring.Currency,
+		ExecuteAt:           executeAt,
+		Status:              model.ScheduledTransferPending,
+		RecurringTransferID: &recurringID,
+	}
+}
+
+// toRecurringTransferResponses 转换为周期转账规则响应列表，引用的账户公开ID用一次批量查询读取
+func (s *RecurringTransferService) toRecurringTransferResponses(ctx context.Context, rules []model.RecurringTransfer) ([]response.RecurringTransferResponse, error) {
+	ids := make([]uint, 0, 2*len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.FromAccountID, rule.ToAccountID)
+	}
+	accounts, err := s.accountRepo.PublicIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.RecurringTransferResponse, len(rules))
+	for i := range rules {
+		items[i] = *toRecurringTransferResponse(&rules[i], accounts)
+	}
+	return items, nil
+}
+
+// toRecurringTransferResponse 转换为周期转账规则响应，accounts 为 账户ID → 公开ID
+func toRecurringTransferResponse(recurring *model.RecurringTransfer, accounts map[uint]uuid.UUID) *response.RecurringTransferResponse {
+	resp := &response.RecurringTransferResponse{
+		PublicID:      recurring.PublicID,
+		FromAccountID: accounts[recurring.FromAccountID],
+		ToAccountID:   accounts[recurring.ToAccountID],
+		Amount:        money.Amount(recurring.Amount),
+		Currency:      recurring.Currency,
+		Cadence:       recurring.Cadence,
+		StartAt:       recurring.StartAt,
+		EndAt:         recurring.EndAt,
+		Status:        recurring.Status,
+		CreatedAt:     recurring.CreatedAt,
+	}
+	if recurring.Status == model.RecurringTransferActive {
+		next := recurring.Occurrence(recurring.OccurrenceCount - 1)
+		resp.NextRunAt = &next
+	}
+	return resp
+}
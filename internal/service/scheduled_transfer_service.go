@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/logging"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+// ==================== 接口定义 (由使用方定义) ====================
+
+// ScheduledTransferRepository 定时转账数据访问接口
+type ScheduledTransferRepository interface {
+	Create(ctx context.Context, scheduled *model.ScheduledTransfer) error
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.ScheduledTransfer, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]model.ScheduledTransfer, error)
+	Claim(ctx context.Context, id uint) error
+	Cancel(ctx context.Context, id uint) error
+	MarkExecuted(ctx context.Context, id, transferID uint, at time.Time) error
+	MarkFailed(ctx context.Context, id uint, reason string, at time.Time) error
+}
+
+// ScheduledAccountRepository 定时转账服务需要的账户数据访问接口
+type ScheduledAccountRepository interface {
+	GetByPublicIDs(ctx context.Context, publicIDs []uuid.UUID) (map[uuid.UUID]*model.Account, error)
+	GetByNumber(ctx context.Context, number string) (*model.Account, error)
+	PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error)
+}
+
+// ExecutedTransferRepository 定时转账服务需要的转账数据访问接口
+// 用于在响应中以公开ID引用执行生成的转账
+type ExecutedTransferRepository interface {
+	PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error)
+}
+
+// OccurrenceScheduler 周期转账规则的某一次结束后生成下一次
+// 由 RecurringTransferService 实现
+type OccurrenceScheduler interface {
+	ScheduleNext(ctx context.Context, occurrence *model.ScheduledTransfer) error
+}
+
+// TransferExecutor 执行一笔即时转账
+// 由 TransferService 实现，定时转账到期后通过它执行，复用全部校验逻辑
+type TransferExecutor interface {
+	ExecuteTransfer(ctx context.Context, owner string, req *request.CreateTransferRequest) (*TransferResult, error)
+}
+
+// ==================== Service 实现 ====================
+
+// scheduledBatchSize 每轮最多执行的到期定时转账数量
+// 剩余的留到下一轮，避免一轮执行时间过长拖慢关闭
+const scheduledBatchSize = 100
+
+// maxFailureReasonLen 失败原因的最大长度 (与 failure_reason 列一致)
+const maxFailureReasonLen = 255
+
+// ScheduledTransferService 定时转账业务逻辑
+//
+// 创建时只校验账户所有权和货币，余额、限额在执行时由 TransferExecutor 重新校验
+// 到期执行由后台任务周期性调用 ExecuteDue 完成
+type ScheduledTransferService struct {
+	scheduledRepo ScheduledTransferRepository
+	accountRepo   ScheduledAccountRepository
+	transferRepo  ExecutedTransferRepository
+	executor      TransferExecutor
+	auditor       AuditRecorder
+	recurring     OccurrenceScheduler // 为空时不处理周期转账
+	now           func() time.Time    // 时钟，测试时可替换
+}
+
+// NewScheduledTransferService 创建 ScheduledTransferService 实例
+func NewScheduledTransferService(
+	scheduledRepo ScheduledTransferRepository,
+	accountRepo ScheduledAccountRepository,
+	transferRepo ExecutedTransferRepository,
+	executor TransferExecutor,
+	auditor AuditRecorder,
+) *ScheduledTransferService {
+	return &ScheduledTransferService{
+		scheduledRepo: scheduledRepo,
+		accountRepo:   accountRepo,
+		transferRepo:  transferRepo,
+		executor:      executor,
+		auditor:       auditor,
+		now:           time.Now,
+	}
+}
+
+// WithClock 替换 Service 使用的时钟
+func (s *ScheduledTransferService) WithClock(now func() time.Time) *ScheduledTransferService {
+	s.now = now
+	return s
+}
+
+// WithOccurrenceScheduler 设置周期转账规则的下一次生成器
+// 周期转账生成的定时转账结束 (执行、失败或被取消) 后调用它生成下一次
+func (s *ScheduledTransferService) WithOccurrenceScheduler(recurring OccurrenceScheduler) *ScheduledTransferService {
+	s.recurring = recurring
+	return s
+}
+
+// ScheduleTransfer 创建定时转账
+func (s *ScheduledTransferService) ScheduleTransfer(ctx context.Context, owner string, req *request.ScheduleTransferRequest) (*response.ScheduledTransferResponse, error) {
+	// 1. 执行时间必须在未来
+	if !req.ExecuteAt.After(s.now()) {
+		return nil, apperrors.ErrInvalidParams("execute_at must be in the future")
+	}
+
+	// 2. 验证源账户属于当前用户，目标账户 (按ID或账号) 存在，货币一致 (省略时使用源账户的货币)
+	// 余额在执行时校验 (见 TransferService.validateTransfer)
+	fromAccount, toAccount, transferCurrency, err := loadTransferAccounts(ctx, s.accountRepo, owner, &req.CreateTransferRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 保存定时转账
+	scheduled := &model.ScheduledTransfer{
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.Amount.Int64(),
+		Currency:      transferCurrency,
+		ExecuteAt:     req.ExecuteAt,
+		Status:        model.ScheduledTransferPending,
+	}
+	if err := s.scheduledRepo.Create(ctx, scheduled); err != nil {
+		return nil, err
+	}
+	s.auditor.Record(ctx, owner, model.AuditActionTransferSchedule, scheduled.PublicID.String())
+
+	// 4. 返回响应
+	accounts := map[uint]uuid.UUID{fromAccount.ID: fromAccount.PublicID, toAccount.ID: toAccount.PublicID}
+	return toScheduledTransferResponse(scheduled, accounts, nil), nil
+}
+
+// GetScheduledTransfer 查询定时转账 (只能查看自己创建的)
+func (s *ScheduledTransferService) GetScheduledTransfer(ctx context.Context, owner string, id uuid.UUID) (*response.ScheduledTransferResponse, error) {
+	scheduled, err := s.ownedScheduledTransfer(ctx, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.scheduledTransferResponse(ctx, scheduled)
+}
+
+// CancelScheduledTransfer 取消定时转账
+// 只能取消自己创建且仍在等待的定时转账，已开始执行或已结束时返回 409
+// 周期转账生成的定时转账被取消时只跳过这一次，规则继续生成下一次
+func (s *ScheduledTransferService) CancelScheduledTransfer(ctx context.Context, owner string, id uuid.UUID) (*response.ScheduledTransferResponse, error) {
+	// 1. 验证所有权
+	scheduled, err := s.ownedScheduledTransfer(ctx, owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 条件更新为 cancelled，后台任务已领取时失败
+	if err := s.scheduledRepo.Cancel(ctx, scheduled.ID); err != nil {
+		return nil, err
+	}
+	scheduled.Status = model.ScheduledTransferCancelled
+	s.auditor.Record(ctx, owner, model.AuditActionTransferScheduleCancel, scheduled.PublicID.String())
+	if err := s.scheduleNext(ctx, scheduled); err != nil {
+		return nil, err
+	}
+
+	// 3. 返回响应
+	return s.scheduledTransferResponse(ctx, scheduled)
+}
+
+// ExecuteDue 执行所有到期的定时转账 (由后台任务周期性调用)
+//
+// 每条先领取 (pending → processing) 再执行，被取消或被其他实例领取的直接跳过
+// 单条转账失败 (如余额不足) 记录在该行上，不影响其余转账；
+// 只有查询或更新状态失败时返回错误
+//
+// 注意: 领取后进程崩溃会使该行停留在 processing，需要人工确认转账是否已执行
+func (s *ScheduledTransferService) ExecuteDue(ctx context.Context) error {
+	due, err := s.scheduledRepo.ListDue(ctx, s.now(), scheduledBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range due {
+		if err := s.execute(ctx, &due[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// execute 领取并执行一条定时转账，记录执行结果
+func (s *ScheduledTransferService) execute(ctx context.Context, scheduled *model.ScheduledTransfer) error {
+	if err := s.scheduledRepo.Claim(ctx, scheduled.ID); err != nil {
+		if apperrors.AsAppError(err).Code == apperrors.CodeStateConflict {
+			return nil
+		}
+		return err
+	}
+
+	result, err := s.executeTransfer(ctx, scheduled)
+	if err != nil {
+		reason := apperrors.AsAppError(err).Message
+		if len(reason) > maxFailureReasonLen {
+			reason = reason[:maxFailureReasonLen]
+		}
+		logging.FromContext(ctx).Warn("scheduled transfer failed",
+			"scheduled_transfer", scheduled.PublicID,
+			"reason", reason,
+		)
+		err = s.scheduledRepo.MarkFailed(ctx, scheduled.ID, reason, s.now())
+	} else {
+		logging.FromContext(ctx).Info("scheduled transfer executed",
+			"scheduled_transfer", scheduled.PublicID,
+			"reference", result.Transfer.Reference,
+		)
+		err = s.scheduledRepo.MarkExecuted(ctx, scheduled.ID, result.Transfer.ID, s.now())
+	}
+	if err != nil {
+		return err
+	}
+
+	// 周期转账: 无论这一次成功与否，都继续生成下一次
+	return s.scheduleNext(ctx, scheduled)
+}
+
+// executeTransfer 通过 TransferExecutor 执行定时转账
+// 双方账户以公开ID传入，与即时转账经过相同的校验；账户已关闭时返回 CodeAccountNotFound
+func (s *ScheduledTransferService) executeTransfer(ctx context.Context, scheduled *model.ScheduledTransfer) (*TransferResult, error) {
+	accounts, err := s.accountRepo.PublicIDs(ctx, []uint{scheduled.FromAccountID, scheduled.ToAccountID})
+	if err != nil {
+		return nil, err
+	}
+	return s.executor.ExecuteTransfer(ctx, scheduled.Owner, &request.CreateTransferRequest{
+		FromAccountID: accounts[scheduled.FromAccountID].String(),
+		ToAccountID:   accounts[scheduled.ToAccountID].String(),
+		Amount:        money.Amount(scheduled.Amount),
+		Currency:      scheduled.Currency,
+	})
+}
+
+// scheduleNext 周期转账生成的定时转账结束后生成下一次
+func (s *ScheduledTransferService) scheduleNext(ctx context.Context, scheduled *model.ScheduledTransfer) error {
+	if scheduled.RecurringTransferID == nil || s.recurring == nil {
+		return nil
+	}
+	return s.recurring.ScheduleNext(ctx, scheduled)
+}
+
+// ownedScheduledTransfer 查询定时转账并验证属于 owner，否则返回 403
+func (s *ScheduledTransferService) ownedScheduledTransfer(ctx context.Context, owner string, id uuid.UUID) (*model.ScheduledTransfer, error) {
+	scheduled, err := s.scheduledRepo.GetByPublicID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if scheduled.Owner != owner {
+		return nil, apperrors.ErrForbidden()
+	}
+	return scheduled, nil
+}
+
+// scheduledTransferResponse 查询引用的账户和转账的公开ID，转换为定时转账响应
+func (s *ScheduledTransferService) scheduledTransferResponse(ctx context.Context, scheduled *model.ScheduledTransfer) (*response.ScheduledTransferResponse, error) {
+	accounts, err := s.accountRepo.PublicIDs(ctx, []uint{scheduled.FromAccountID, scheduled.ToAccountID})
+	if err != nil {
+		return nil, err
+	}
+
+	var transferID *uuid.UUID
+	if scheduled.TransferID != nil {
+		transfers, err := s.transferRepo.PublicIDs(ctx, []uint{*scheduled.TransferID})
+		if err != nil {
+			return nil, err
+		}
+		publicID := transfers[*scheduled.TransferID]
+		transferID = &publicID
+	}
+	return toScheduledTransferResponse(scheduled, accounts, transferID), nil
+}
+
+// toScheduledTransferResponse 转换为定时转账响应
+// accounts 为 账户ID → 公开ID，transferID 为执行生成的转账的公开ID (未执行时为 nil)
+func toScheduledTransferResponse(scheduled *model.ScheduledTransfer, accounts map[uint]uuid.UUID, transferID *uuid.UUID) *response.ScheduledTransferResponse {
+	return &response.ScheduledTransferResponse{
+		PublicID:      scheduled.PublicID,
+		FromAccountID: accounts[scheduled.FromAccountID],
+		ToAccountID:   accounts[scheduled.ToAccountID],
+		Amount:        money.Amount(scheduled.Amount),
+		Currency:      scheduled.Currency,
+		ExecuteAt:     scheduled.ExecuteAt,
+		Status:        scheduled.Status,
+		TransferID:    transferID,
+		FailureReason: scheduled.FailureReason,
+		ExecutedAt:    scheduled.ExecutedAt,
+		CreatedAt:     scheduled.CreatedAt,
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+)
+
+// newTestScheduledTransferService 创建使用内存 Repository 的 ScheduledTransferService，时钟由 now 控制
+func newTestScheduledTransferService(repos *memory.Repositories, now *time.Time) *ScheduledTransferService {
+	transfers := newTestTransferService(repos, TransferLimits{})
+	return NewScheduledTransferService(repos.ScheduledTransfers, repos.Accounts, repos.Transfers, transfers,
+		NewAuditLogger(repos.AuditLogs)).WithClock(func() time.Time { return *now })
+}
+
+func TestScheduledTransferUsesPublicIDs(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	now := time.Now()
+	s := newTestScheduledTransferService(repos, &now)
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	scheduled, err := s.ScheduleTransfer(ctx, "alice", &request.ScheduleTransferRequest{
+		CreateTransferRequest: *transferRequest(from, to, 1000),
+		ExecuteAt:             now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("ScheduleTransfer: %v", err)
+	}
+	if scheduled.FromAccountID != from.PublicID || scheduled.ToAccountID != to.PublicID {
+		t.Errorf("scheduled accounts = %s → %s, want %s → %s",
+			scheduled.FromAccountID, scheduled.ToAccountID, from.PublicID, to.PublicID)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := s.ExecuteDue(ctx); err != nil {
+		t.Fatalf("ExecuteDue: %v", err)
+	}
+
+	executed, err := s.GetScheduledTransfer(ctx, "alice", scheduled.PublicID)
+	if err != nil {
+		t.Fatalf("GetScheduledTransfer: %v", err)
+	}
+	if executed.Status != model.ScheduledTransferExecuted || executed.TransferID == nil {
+		t.Fatalf("scheduled transfer = %+v, want executed with transfer_id", executed)
+	}
+	transfer, err := repos.Transfers.GetByPublicID(ctx, *executed.TransferID)
+	if err != nil {
+		t.Fatalf("transfer_id %s is not a transfer public id: %v", *executed.TransferID, err)
+	}
+	if transfer.Amount != 1000 {
+		t.Errorf("transfer amount = %d, want 1000", transfer.Amount)
+	}
+}
@@ -1,14 +1,21 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
-	"gorm.io/gorm"
+	"github.com/google/uuid"
 
 	"github.com/proyuen/simple-bank-v2/internal/dto/request"
 	"github.com/proyuen/simple-bank-v2/internal/dto/response"
 	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/event"
 	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/pkg/currency"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
 )
 
 // ==================== 接口定义 (由使用方定义) ====================
@@ -16,37 +23,83 @@ import (
 // TransferAccountRepository 转账服务需要的账户数据访问接口
 type TransferAccountRepository interface {
 	GetByID(ctx context.Context, id uint) (*model.Account, error)
+	GetByIDs(ctx context.Context, ids []uint) (map[uint]*model.Account, error)
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Account, error)
+	GetByPublicIDs(ctx context.Context, publicIDs []uuid.UUID) (map[uuid.UUID]*model.Account, error)
+	GetByNumber(ctx context.Context, number string) (*model.Account, error)
+	PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error)
+	ExistsOwnedBy(ctx context.Context, id uint, owner string) (bool, error)
 	GetForUpdate(ctx context.Context, id uint) (*model.Account, error)
-	UpdateBalance(ctx context.Context, id uint, amount int64) (*model.Account, error)
+	UpdateBalances(ctx context.Context, deltas map[uint]int64) (map[uint]*model.Account, error)
 }
 
 // TransferRepository 转账数据访问接口
 type TransferRepository interface {
 	Create(ctx context.Context, transfer *model.Transfer) error
 	GetByID(ctx context.Context, id uint) (*model.Transfer, error)
-	ListByAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.Transfer, int64, error)
+	GetByReference(ctx context.Context, reference string) (*model.Transfer, error)
+	GetByPublicID(ctx context.Context, publicID uuid.UUID) (*model.Transfer, error)
+	GetReversal(ctx context.Context, transferID uint) (*model.Transfer, error)
+	PublicIDs(ctx context.Context, ids []uint) (map[uint]uuid.UUID, error)
+	ListByAccountID(ctx context.Context, accountID uint, sort string, limit, offset int) ([]model.Transfer, int64, error)
+	ListBetween(ctx context.Context, fromAccountID, toAccountID uint, sort string, limit, offset int) ([]model.Transfer, int64, error)
+	StatsByAccountID(ctx context.Context, accountID uint, period model.TimeRange) (*model.TransferStats, error)
 }
 
 // EntryRepository 账目数据访问接口
 type EntryRepository interface {
 	Create(ctx context.Context, entry *model.Entry) error
 	GetByID(ctx context.Context, id uint) (*model.Entry, error)
-	ListByAccountID(ctx context.Context, accountID uint, limit, offset int) ([]model.Entry, int64, error)
+	ListByAccountID(ctx context.Context, accountID uint, period model.TimeRange, sort string, limit, offset int) ([]model.Entry, int64, error)
+	ListByOwner(ctx context.Context, owner string, period model.TimeRange, sort string, limit, offset int) ([]model.AccountEntry, int64, error)
+	ListActivityByAccountID(ctx context.Context, accountID uint, period model.TimeRange, limit, offset int) ([]model.EntryActivity, int64, error)
+	StreamByAccountID(ctx context.Context, accountID uint, period model.TimeRange, fn func(entry *model.Entry) error) error
+	SumThroughID(ctx context.Context, accountID, id uint) (int64, error)
+	SumBeforeDate(ctx context.Context, accountID uint, before time.Time) (int64, error)
+	SumInRange(ctx context.Context, accountID uint, period model.TimeRange) (credits, debits int64, err error)
+	SumDebitsSince(ctx context.Context, accountID uint, since time.Time) (int64, error)
 }
 
 // TransactionManager 事务管理接口
+// fc 收到的 Context 携带事务，用它调用 Repository 的操作会在同一事务中执行
 type TransactionManager interface {
-	Transaction(fc func(tx *gorm.DB) error) error
+	Transaction(ctx context.Context, fc func(ctx context.Context) error) error
+}
+
+// EventPublisher 发布账户余额变动事件
+// 由 event.Bus 实现
+type EventPublisher interface {
+	Publish(ev event.BalanceChanged)
 }
 
 // ==================== Service 实现 ====================
 
+// dailyLimitWindow 每日累计限额的滚动窗口
+const dailyLimitWindow = 24 * time.Hour
+
+// TransferLimits 转账限额 (单位: 分，0 表示不限制)
+type TransferLimits struct {
+	// MinAmount 单笔转账下限 (以转账货币的最小单位计)
+	MinAmount int64
+
+	// MaxAmount 单笔转账上限
+	MaxAmount int64
+
+	// DailyLimit 单个账户滚动 24 小时内的累计转出上限
+	DailyLimit int64
+}
+
 // TransferService 转账业务逻辑
 type TransferService struct {
 	db           TransactionManager
 	accountRepo  TransferAccountRepository
 	transferRepo TransferRepository
 	entryRepo    EntryRepository
+	auditor      AuditRecorder
+	limits       TransferLimits
+	reversal     time.Duration    // 转账创建后允许撤销的时间窗口，0 表示不允许撤销
+	events       EventPublisher   // 为空时不发布余额变动事件
+	now          func() time.Time // 时钟，测试时可替换
 }
 
 // NewTransferService 创建 TransferService 实例
@@ -55,15 +108,40 @@ func NewTransferService(
 	accountRepo TransferAccountRepository,
 	transferRepo TransferRepository,
 	entryRepo EntryRepository,
+	auditor AuditRecorder,
+	limits TransferLimits,
 ) *TransferService {
 	return &TransferService{
 		db:           db,
 		accountRepo:  accountRepo,
 		transferRepo: transferRepo,
 		entryRepo:    entryRepo,
+		auditor:      auditor,
+		limits:       limits,
+		now:          time.Now,
 	}
 }
 
+// WithClock 替换 Service 使用的时钟
+// 主要用于测试每日限额窗口等依赖当前时间的逻辑
+func (s *TransferService) WithClock(now func() time.Time) *TransferService {
+	s.now = now
+	return s
+}
+
+// WithReversalWindow 设置转账创建后允许转出方撤销的时间窗口
+func (s *TransferService) WithReversalWindow(window time.Duration) *TransferService {
+	s.reversal = window
+	return s
+}
+
+// WithEventPublisher 设置余额变动事件的发布方
+// 转账事务提交后为双方账户各发布一个事件
+func (s *TransferService) WithEventPublisher(events EventPublisher) *TransferService {
+	s.events = events
+	return s
+}
+
 // TransferResult 转账结果
 type TransferResult struct {
 	Transfer    *model.Transfer
@@ -75,110 +153,637 @@ type TransferResult struct {
 
 // CreateTransfer 创建转账
 func (s *TransferService) CreateTransfer(ctx context.Context, owner string, req *request.CreateTransferRequest) (*response.TransferResponse, error) {
-	// 1. 验证源账户存在且属于当前用户
-	fromAccount, err := s.accountRepo.GetByID(ctx, req.FromAccountID)
+	result, err := s.ExecuteTransfer(ctx, owner, req)
 	if err != nil {
 		return nil, err
 	}
-	if fromAccount.Owner != owner {
-		return nil, apperrors.ErrUnauthorized()
+	return toTransferResponse(result.Transfer, accountRefs(result.FromAccount, result.ToAccount)), nil
+}
+
+// ExecuteTransfer 校验并执行转账，返回转账及双方账户的最新状态
+// 定时转账到期后通过它执行 (见 TransferExecutor)，需要内部ID记录执行结果
+func (s *TransferService) ExecuteTransfer(ctx context.Context, owner string, req *request.CreateTransferRequest) (*TransferResult, error) {
+	// 1. 事务外校验 (所有权、货币、余额、限额)
+	plan, err := s.validateTransfer(ctx, owner, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. 验证目标账户存在
-	toAccount, err := s.accountRepo.GetByID(ctx, req.ToAccountID)
+	// 2. 执行转账事务
+	var result TransferResult
+	err = s.db.Transaction(ctx, func(txCtx context.Context) error {
+		return s.execTransfer(txCtx, plan.fromAccount.ID, plan.toAccount.ID, plan.amount, &result)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.auditor.Record(ctx, owner, model.AuditActionTransfer, result.Transfer.Reference)
+	s.publishBalanceChanges(&result)
 
-	// 3. 验证货币类型一致
-	if fromAccount.Currency != toAccount.Currency {
-		return nil, apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "currency mismatch")
+	return &result, nil
+}
+
+// PreviewTransfer 预览转账 (dry-run)
+// 执行与 CreateTransfer 相同的校验，返回转账后的双方账户和账目，但不写入任何数据
+//
+// 预览结果中的转账和账目尚未创建，ID、公开ID 和参考号为空；
+// 收款账户属于其他用户时只返回基本信息 (见 partyAccountResponse)，不暴露对方余额；
+// 预览之后余额或限额可能发生变化，实际转账时会重新校验
+func (s *TransferService) PreviewTransfer(ctx context.Context, owner string, req *request.CreateTransferRequest) (*response.TransferResultResponse, error) {
+	// 1. 事务外校验 (所有权、货币、余额、限额)
+	plan, err := s.validateTransfer(ctx, owner, req)
+	if err != nil {
+		return nil, err
 	}
 
-	// 4. 验证余额充足
-	if fromAccount.Balance < req.Amount {
-		return nil, apperrors.NewWithMessage(apperrors.CodeInsufficientBalance, "insufficient balance")
+	// 2. 在账户副本上计算转账后的余额
+	now := s.now()
+	fromAccount, toAccount := *plan.fromAccount, *plan.toAccount
+	fromAccount.Balance -= plan.amount
+	toAccount.Balance += plan.amount
+
+	// 3. 返回响应
+	transfer := &model.Transfer{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        plan.amount,
+		CreatedAt:     now,
 	}
+	fromEntry := &model.Entry{AccountID: fromAccount.ID, Amount: -plan.amount, CreatedAt: now}
+	toEntry := &model.Entry{AccountID: toAccount.ID, Amount: plan.amount, CreatedAt: now}
+	return &response.TransferResultResponse{
+		Transfer:    *toTransferResponse(transfer, accountRefs(&fromAccount, &toAccount)),
+		FromAccount: *toAccountResponse(&fromAccount),
+		ToAccount:   *partyAccountResponse(&toAccount, owner),
+		FromEntry:   *toEntryResponse(fromEntry, fromAccount.PublicID),
+		ToEntry:     *toEntryResponse(toEntry, toAccount.PublicID),
+	}, nil
+}
 
-	// 5. 执行转账事务
-	var result TransferResult
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		return s.execTransfer(ctx, req.FromAccountID, req.ToAccountID, req.Amount, &result)
-	})
+// transferPlan 通过事务外校验的转账
+type transferPlan struct {
+	fromAccount *model.Account
+	toAccount   *model.Account
+	amount      int64
+}
+
+// validateTransfer 执行转账前的事务外校验: 所有权、账户存在、货币、冻结、余额和限额
+// CreateTransfer 和 PreviewTransfer 共用，定时转账到期后通过 CreateTransfer 执行，同样经过这里
+// 只读取数据，事务中锁定账户后还会重新校验 (见 execTransfer)
+func (s *TransferService) validateTransfer(ctx context.Context, owner string, req *request.CreateTransferRequest) (*transferPlan, error) {
+	amount := req.Amount.Int64()
+
+	// 0. 验证货币受支持 (省略时在第 1 步取源账户的货币)、金额为正 (定时转账等内部调用不经过请求参数校验)
+	if req.Currency != "" && !currency.IsSupported(req.Currency) {
+		return nil, apperrors.ErrInvalidParams(fmt.Sprintf("unsupported currency %q", req.Currency))
+	}
+	if amount <= 0 {
+		return nil, apperrors.ErrInvalidParams("amount must be greater than 0")
+	}
+
+	// 1. 读取双方账户，验证源账户属于当前用户、目标账户存在、货币一致
+	fromAccount, toAccount, transferCurrency, err := loadTransferAccounts(ctx, s.accountRepo, owner, req)
 	if err != nil {
 		return nil, err
 	}
 
-	// 6. 返回响应
-	return s.toTransferResponse(result.Transfer), nil
+	// 2. 验证不低于单笔下限
+	if s.limits.MinAmount > 0 && amount < s.limits.MinAmount {
+		return nil, apperrors.ErrInvalidParams(fmt.Sprintf("amount must be at least %s %s",
+			money.FormatAmount(s.limits.MinAmount, transferCurrency), transferCurrency))
+	}
+
+	// 3. 验证双方账户均未冻结
+	if err := checkNotFrozen(fromAccount, toAccount); err != nil {
+		return nil, err
+	}
+
+	// 4. 验证可用余额充足 (余额 + 透支额度 - 最低余额)
+	// 这里只是提前拦截，事务中锁定账户后会重新校验，
+	// 最终由 UpdateBalances 的条件更新保证不超出透支额度、不低于最低余额
+	if fromAccount.AvailableBalance() < amount {
+		return nil, insufficientBalance(fromAccount)
+	}
+
+	// 5. 验证转账限额 (每日累计限额在事务中锁定账户后会重新校验)
+	if err := s.checkLimits(ctx, fromAccount, amount); err != nil {
+		return nil, err
+	}
+
+	return &transferPlan{fromAccount: fromAccount, toAccount: toAccount, amount: amount}, nil
+}
+
+// transferAccountLoader 读取转账双方账户
+// TransferAccountRepository 和 ScheduledAccountRepository 都满足该接口
+type transferAccountLoader interface {
+	GetByPublicIDs(ctx context.Context, publicIDs []uuid.UUID) (map[uuid.UUID]*model.Account, error)
+	GetByNumber(ctx context.Context, number string) (*model.Account, error)
+}
+
+// loadTransferAccounts 一次查询读取转账双方账户 (收款账户按公开ID或账号)，
+// 验证源账户存在且属于 owner、目标账户存在且不是源账户、双方货币与请求一致
+// 请求省略货币时使用源账户的货币，返回实际使用的货币
+//
+// 立即转账 (validateTransfer) 和定时转账 (ScheduleTransfer) 共用；
+// 这里只是提前拦截，账户在此之后被关闭时，事务中锁定账户会返回 CodeAccountNotFound
+func loadTransferAccounts(ctx context.Context, accounts transferAccountLoader, owner string, req *request.CreateTransferRequest) (*model.Account, *model.Account, string, error) {
+	// 收款账户按账号指定时单独查询，否则与源账户一起按公开ID查询
+	var toAccount *model.Account
+	publicIDs := []uuid.UUID{req.FromAccountPublicID()}
+	if req.ToAccountNumber != "" {
+		account, err := accounts.GetByNumber(ctx, req.ToAccountNumber)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		toAccount = account
+	} else {
+		publicIDs = append(publicIDs, req.ToAccountPublicID())
+	}
+
+	loaded, err := accounts.GetByPublicIDs(ctx, publicIDs)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	fromAccount, ok := loaded[req.FromAccountPublicID()]
+	if !ok {
+		return nil, nil, "", apperrors.ErrAccountNotFound()
+	}
+	if fromAccount.Owner != owner {
+		return nil, nil, "", apperrors.ErrUnauthorized()
+	}
+	if toAccount == nil {
+		if toAccount, ok = loaded[req.ToAccountPublicID()]; !ok {
+			return nil, nil, "", apperrors.ErrAccountNotFound()
+		}
+	}
+	if toAccount.ID == fromAccount.ID {
+		return nil, nil, "", apperrors.NewWithMessage(apperrors.CodeSameAccount, "cannot transfer to same account")
+	}
+
+	transferCurrency := cmp.Or(req.Currency, fromAccount.Currency)
+	if fromAccount.Currency != toAccount.Currency || fromAccount.Currency != transferCurrency {
+		return nil, nil, "", apperrors.NewWithMessage(apperrors.CodeInvalidRequest, "currency mismatch")
+	}
+	return fromAccount, toAccount, transferCurrency, nil
+}
+
+// checkLimits 检查单笔限额和滚动 24 小时累计限额
+// 错误消息中的限额按源账户的货币格式化 (如 "1000.00 USD")
+func (s *TransferService) checkLimits(ctx context.Context, fromAccount *model.Account, amount int64) error {
+	// 1. 单笔限额
+	if s.limits.MaxAmount > 0 && amount > s.limits.MaxAmount {
+		return apperrors.NewWithMessage(apperrors.CodeTransferLimitExceeded,
+			fmt.Sprintf("amount exceeds single transfer limit of %s %s",
+				money.FormatAmount(s.limits.MaxAmount, fromAccount.Currency), fromAccount.Currency))
+	}
+
+	// 2. 每日累计限额
+	return s.checkDailyLimit(ctx, fromAccount, amount)
+}
+
+// checkDailyLimit 检查源账户滚动 24 小时内的累计转出加上 amount 不超过每日限额
+//
+// 事务外检查只是提前拦截: 并发转账可能都读到转账前的累计值，
+// execTransfer 锁定源账户后会再检查一次，同一账户的转账在那里排队，累计值不会被绕过
+func (s *TransferService) checkDailyLimit(ctx context.Context, fromAccount *model.Account, amount int64) error {
+	if s.limits.DailyLimit <= 0 {
+		return nil
+	}
+
+	since := s.now().Add(-dailyLimitWindow)
+	spent, err := s.entryRepo.SumDebitsSince(ctx, fromAccount.ID, since)
+	if err != nil {
+		return err
+	}
+	if spent+amount > s.limits.DailyLimit {
+		return apperrors.NewWithMessage(apperrors.CodeTransferLimitExceeded,
+			fmt.Sprintf("amount exceeds daily transfer limit of %s %s",
+				money.FormatAmount(s.limits.DailyLimit, fromAccount.Currency), fromAccount.Currency))
+	}
+	return nil
 }
 
 // execTransfer 执行转账事务
+// ctx 必须是 TransactionManager.Transaction 传入的事务 Context，
+// 任一步骤失败时之前的写入会全部回滚
 func (s *TransferService) execTransfer(ctx context.Context, fromAccountID, toAccountID uint, amount int64, result *TransferResult) error {
-	var err error
+	// 1. 锁定双方账户，并基于锁定后的状态重新校验
+	// 事务外的校验可能已经过期，锁定后并发转账会在这里排队；
+	// 任一方账户在事务外的校验之后被关闭时，GetForUpdate 返回 CodeAccountNotFound，
+	// 锁定期间账户不会再被关闭，之后的写入不会因账户不存在而失败
+	locked, err := s.lockAccounts(ctx, fromAccountID, toAccountID)
+	if err != nil {
+		return err
+	}
+	if err := checkNotFrozen(locked[fromAccountID], locked[toAccountID]); err != nil {
+		return err
+	}
+	if locked[fromAccountID].AvailableBalance() < amount {
+		return insufficientBalance(locked[fromAccountID])
+	}
 
-	// 1. 创建转账记录
-	result.Transfer = &model.Transfer{
+	// 2. 锁定后重新检查每日累计限额
+	// 持有源账户的行锁时，同一账户的其他转账已提交或在等待锁，累计值是最新的
+	// (锁定读之后的第一次普通读建立事务快照，能看到锁定前已提交的转账)
+	if err := s.checkDailyLimit(ctx, locked[fromAccountID], amount); err != nil {
+		return err
+	}
+
+	// 3. 记账
+	return s.postTransfer(ctx, &model.Transfer{
 		FromAccountID: fromAccountID,
 		ToAccountID:   toAccountID,
 		Amount:        amount,
-	}
-	if err = s.transferRepo.Create(ctx, result.Transfer); err != nil {
+	}, result)
+}
+
+// postTransfer 创建转账记录和双方账目，并更新双方余额
+// 调用方必须已在同一事务中锁定双方账户并完成余额校验
+func (s *TransferService) postTransfer(ctx context.Context, transfer *model.Transfer, result *TransferResult) error {
+	fromAccountID, toAccountID, amount := transfer.FromAccountID, transfer.ToAccountID, transfer.Amount
+
+	// 1. 创建转账记录
+	result.Transfer = transfer
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
 		return err
 	}
 
 	// 2. 创建源账户账目 (负数表示支出)
+	transferID := transfer.ID
 	result.FromEntry = &model.Entry{
-		AccountID: fromAccountID,
-		Amount:    -amount,
+		AccountID:  fromAccountID,
+		Amount:     -amount,
+		TransferID: &transferID,
 	}
-	if err = s.entryRepo.Create(ctx, result.FromEntry); err != nil {
+	if err := s.entryRepo.Create(ctx, result.FromEntry); err != nil {
 		return err
 	}
 
 	// 3. 创建目标账户账目 (正数表示收入)
 	result.ToEntry = &model.Entry{
-		AccountID: toAccountID,
-		Amount:    amount,
+		AccountID:  toAccountID,
+		Amount:     amount,
+		TransferID: &transferID,
 	}
-	if err = s.entryRepo.Create(ctx, result.ToEntry); err != nil {
+	if err := s.entryRepo.Create(ctx, result.ToEntry); err != nil {
 		return err
 	}
 
-	// 4. 更新账户余额 (按 ID 顺序更新以避免死锁)
-	if fromAccountID < toAccountID {
-		result.FromAccount, result.ToAccount, err = s.addMoney(ctx, fromAccountID, -amount, toAccountID, amount)
-	} else {
-		result.ToAccount, result.FromAccount, err = s.addMoney(ctx, toAccountID, amount, fromAccountID, -amount)
+	// 4. 更新账户余额 (UpdateBalances 内部按 ID 顺序更新以避免死锁)
+	accounts, err := s.accountRepo.UpdateBalances(ctx, map[uint]int64{
+		fromAccountID: -amount,
+		toAccountID:   amount,
+	})
+	if err != nil {
+		return err
 	}
+	result.FromAccount = accounts[fromAccountID]
+	result.ToAccount = accounts[toAccountID]
 
-	return err
+	return nil
 }
 
-// addMoney 按顺序更新两个账户余额
-func (s *TransferService) addMoney(ctx context.Context, accountID1 uint, amount1 int64, accountID2 uint, amount2 int64) (*model.Account, *model.Account, error) {
-	account1, err := s.accountRepo.UpdateBalance(ctx, accountID1, amount1)
+// ReverseTransfer 撤销转账: 创建一笔方向相反、金额相同的转账，恢复双方余额
+//
+// 业务规则:
+//   - 只有原转账的转出方可以撤销，且必须在创建后的撤销窗口内
+//   - 撤销转账本身不能再被撤销，每笔转账最多撤销一次
+//   - 收款账户的余额必须仍足以退回 (不动用透支额度、不低于最低余额)，否则返回余额不足
+//   - 任一方账户被冻结时不能撤销
+//   - 撤销是对错误转账的更正，不计入转账限额
+func (s *TransferService) ReverseTransfer(ctx context.Context, owner string, transferID uuid.UUID) (*response.TransferResponse, error) {
+	// 1. 查询原转账并验证当前用户是转账的一方，再验证是转出方
+	original, err := s.transferRepo.GetByPublicID(ctx, transferID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	if err := s.checkTransferParty(ctx, owner, original); err != nil {
+		return nil, err
+	}
+	fromAccount, err := s.accountRepo.GetByID(ctx, original.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if fromAccount.Owner != owner {
+		return nil, apperrors.NewWithMessage(apperrors.CodeForbidden, "only the sender can reverse a transfer")
+	}
+
+	// 2. 验证可以撤销
+	if original.ReversalOf != nil {
+		return nil, apperrors.NewWithMessage(apperrors.CodeStateConflict, "cannot reverse a reversal")
+	}
+	if s.reversal <= 0 || s.now().Sub(original.CreatedAt) > s.reversal {
+		return nil, apperrors.New(apperrors.CodeReversalWindowExpired)
+	}
+
+	// 3. 执行撤销事务
+	var result TransferResult
+	err = s.db.Transaction(ctx, func(txCtx context.Context) error {
+		// 锁定双方账户后再检查是否已撤销，并发撤销同一笔转账会在这里排队
+		locked, err := s.lockAccounts(txCtx, original.FromAccountID, original.ToAccountID)
+		if err != nil {
+			return err
+		}
+		if err := checkNotFrozen(locked[original.FromAccountID], locked[original.ToAccountID]); err != nil {
+			return err
+		}
+		if _, err := s.transferRepo.GetReversal(txCtx, original.ID); err == nil {
+			return apperrors.NewWithMessage(apperrors.CodeStateConflict, "transfer already reversed")
+		} else if apperrors.AsAppError(err).Code != apperrors.CodeNotFound {
+			return err
+		}
+
+		// 收款方的资金可能已经花掉
+		counterparty := locked[original.ToAccountID]
+		if counterparty.Balance-original.Amount < counterparty.MinBalance {
+			return apperrors.ErrInsufficientBalance()
+		}
 
-	account2, err := s.accountRepo.UpdateBalance(ctx, accountID2, amount2)
+		originalID := original.ID
+		return s.postTransfer(txCtx, &model.Transfer{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        original.Amount,
+			ReversalOf:    &originalID,
+		}, &result)
+	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	s.auditor.Record(ctx, owner, model.AuditActionTransferReverse, original.Reference)
+	s.publishBalanceChanges(&result)
 
-	return account1, account2, nil
+	// 4. 返回撤销转账
+	refs := accountRefs(result.FromAccount, result.ToAccount)
+	refs.transfers[original.ID] = original.PublicID
+	return toTransferResponse(result.Transfer, refs), nil
+}
+
+// publishBalanceChanges 为转账双方账户发布余额变动事件
+// 必须在事务提交后调用，避免推送最终被回滚的变动
+func (s *TransferService) publishBalanceChanges(result *TransferResult) {
+	if s.events == nil {
+		return
+	}
+
+	transfer := result.Transfer
+	for _, change := range []struct {
+		account *model.Account
+		kind    string
+		amount  int64
+	}{
+		{result.FromAccount, event.KindTransferOut, -transfer.Amount},
+		{result.ToAccount, event.KindTransferIn, transfer.Amount},
+	} {
+		s.events.Publish(event.BalanceChanged{
+			AccountID:       change.account.ID,
+			AccountPublicID: change.account.PublicID,
+			Owner:           change.account.Owner,
+			Kind:            change.kind,
+			Amount:          change.amount,
+			Balance:         change.account.Balance,
+			Currency:        change.account.Currency,
+			TransferID:      transfer.PublicID,
+			Reference:       transfer.Reference,
+			OccurredAt:      transfer.CreatedAt,
+		})
+	}
+}
+
+// insufficientBalance 返回转出账户余额不足的错误
+// 账户要求最低余额时提示转账会低于最低余额
+func insufficientBalance(account *model.Account) error {
+	if account.MinBalance > 0 {
+		return apperrors.ErrMinBalanceBreach()
+	}
+	return apperrors.ErrInsufficientBalance()
+}
+
+// checkNotFrozen 任一账户被冻结时返回 CodeAccountFrozen
+func checkNotFrozen(accounts ...*model.Account) error {
+	for _, account := range accounts {
+		if account.IsFrozen {
+			return apperrors.ErrAccountFrozen()
+		}
+	}
+	return nil
+}
+
+// lockAccounts 按 ID 升序对账户加行锁 (SELECT ... FOR UPDATE)
+// 所有转账使用相同的加锁顺序，避免互相转账时死锁
+func (s *TransferService) lockAccounts(ctx context.Context, ids ...uint) (map[uint]*model.Account, error) {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	accounts := make(map[uint]*model.Account, len(ids))
+	for _, id := range ids {
+		account, err := s.accountRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = account
+	}
+	return accounts, nil
+}
+
+// GetTransferByReference 根据参考号获取转账详情
+// 只有转账的一方 (转出或转入账户的所有者) 可以查看
+func (s *TransferService) GetTransferByReference(ctx context.Context, owner, reference string) (*response.TransferResponse, error) {
+	// 1. 查询转账
+	transfer, err := s.transferRepo.GetByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证当前用户是转账的一方
+	if err := s.checkTransferParty(ctx, owner, transfer); err != nil {
+		return nil, err
+	}
+
+	// 3. 返回响应
+	return s.transferResponse(ctx, transfer)
+}
+
+// GetTransfer 根据公开ID获取转账详情
+// 只有转账的一方 (转出或转入账户的所有者) 可以查看
+//
+// expandAccounts 为 true 时响应中包含两个账户的信息 (一次查询读取)，
+// 对方账户只返回公开信息，owner 经过掩码 (见 partyAccountResponse)
+func (s *TransferService) GetTransfer(ctx context.Context, owner string, transferID uuid.UUID, expandAccounts bool) (*response.TransferResponse, error) {
+	// 1. 查询转账
+	transfer, err := s.transferRepo.GetByPublicID(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 不展开账户时只验证当前用户是转账的一方
+	if !expandAccounts {
+		if err := s.checkTransferParty(ctx, owner, transfer); err != nil {
+			return nil, err
+		}
+		return s.transferResponse(ctx, transfer)
+	}
+
+	// 3. 一次查询读取两个账户，验证当前用户至少拥有其中一个
+	accounts, err := s.accountRepo.GetByIDs(ctx, []uint{transfer.FromAccountID, transfer.ToAccountID})
+	if err != nil {
+		return nil, err
+	}
+	fromAccount, toAccount := accounts[transfer.FromAccountID], accounts[transfer.ToAccountID]
+	if !ownedBy(fromAccount, owner) && !ownedBy(toAccount, owner) {
+		return nil, apperrors.ErrForbidden()
+	}
+
+	// 4. 返回包含账户信息的响应 (已关闭的账户不返回)
+	resp, err := s.transferResponse(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+	resp.FromAccount = partyAccountResponse(fromAccount, owner)
+	resp.ToAccount = partyAccountResponse(toAccount, owner)
+	return resp, nil
+}
+
+// ownedBy 返回账户是否存在且属于 owner
+func ownedBy(account *model.Account, owner string) bool {
+	return account != nil && account.Owner == owner
+}
+
+// partyAccountResponse 转换为转账详情中的账户信息，account 为 nil 时返回 nil
+// 当前用户自己的账户返回完整信息；对方账户只返回 public_id、currency 和掩码后的 owner，
+// 不暴露余额、额度、账户名称和账号
+func partyAccountResponse(account *model.Account, owner string) *response.AccountResponse {
+	if account == nil {
+		return nil
+	}
+	if account.Owner == owner {
+		return toAccountResponse(account)
+	}
+	return &response.AccountResponse{
+		PublicID: account.PublicID,
+		Owner:    maskOwner(account.Owner),
+		Currency: account.Currency,
+	}
+}
+
+// toHistoryItemResponse 转换为交易历史记录，balanceAfter 为该笔变动后的余额
+func toHistoryItemResponse(activity *model.EntryActivity, balanceAfter int64) *response.HistoryItemResponse {
+	item := &response.HistoryItemResponse{
+		EntryID:      activity.ID,
+		Amount:       money.Amount(activity.Amount),
+		BalanceAfter: money.Amount(balanceAfter),
+		CreatedAt:    activity.CreatedAt,
+	}
+
+	if activity.TransferPublicID == nil {
+		item.Type = response.HistoryTypeDeposit
+		if activity.IsDebit() {
+			item.Type = response.HistoryTypeWithdrawal
+		}
+		return item
+	}
+
+	item.Type = response.HistoryTypeTransferIn
+	if activity.IsDebit() {
+		item.Type = response.HistoryTypeTransferOut
+	}
+	item.TransferID = activity.TransferPublicID
+	if activity.TransferReference != nil {
+		item.Reference = *activity.TransferReference
+	}
+	if activity.CounterpartyPublicID != nil && activity.CounterpartyOwner != nil {
+		item.Counterparty = &response.CounterpartyResponse{
+			AccountID: *activity.CounterpartyPublicID,
+			Owner:     maskOwner(*activity.CounterpartyOwner),
+		}
+	}
+	return item
+}
+
+// maskOwner 掩码用户名，只保留首尾字符，如 "alice" → "a***e"
+// 不超过两个字符的用户名全部掩码
+func maskOwner(owner string) string {
+	r := []rune(owner)
+	if len(r) <= 2 {
+		return "***"
+	}
+	return string(r[0]) + "***" + string(r[len(r)-1])
+}
+
+// checkTransferParty 验证 owner 是转出或转入账户的所有者，否则返回 403
+// 只检查所有权，不读取账户数据
+func (s *TransferService) checkTransferParty(ctx context.Context, owner string, transfer *model.Transfer) error {
+	for _, accountID := range []uint{transfer.FromAccountID, transfer.ToAccountID} {
+		owned, err := s.accountRepo.ExistsOwnedBy(ctx, accountID, owner)
+		if err != nil {
+			return err
+		}
+		if owned {
+			return nil
+		}
+	}
+	return apperrors.ErrForbidden()
 }
 
 // ListTransfers 获取账户的转账记录
-func (s *TransferService) ListTransfers(ctx context.Context, owner string, accountID uint, req *request.PaginationRequest) (*response.ListResponse[response.TransferResponse], error) {
+func (s *TransferService) ListTransfers(ctx context.Context, owner string, accountID uuid.UUID, req *request.PaginationRequest) (*response.ListResponse[response.TransferResponse], error) {
 	// 1. 验证账户属于当前用户
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+	account, err := s.ownedAccount(ctx, owner, accountID)
 	if err != nil {
 		return nil, err
 	}
-	if account.Owner != owner {
-		return nil, apperrors.ErrUnauthorized()
+
+	// 2. 计算分页参数
+	limit := req.Limit()
+	offset := req.Offset()
+
+	// 3. 查询转账记录
+	transfers, total, err := s.transferRepo.ListByAccountID(ctx, account.ID, req.Sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 转换为响应格式
+	items, err := s.toTransferResponses(ctx, transfers)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 返回分页响应
+	result := response.NewListResponse(items, req.PageID, req.PageSize, total)
+	return &result, nil
+}
+
+// ListTransfersBetween 按方向获取转账记录，用于核查两个账户之间的往来 (如处理争议)
+//
+// fromAccountID、toAccountID 至少传一个；只传一个时只按该方向过滤
+// 当前用户需要拥有其中至少一个账户，管理员 (isAdmin) 可以查看任意账户
+func (s *TransferService) ListTransfersBetween(ctx context.Context, owner string, isAdmin bool, fromAccountID, toAccountID *uuid.UUID, req *request.PaginationRequest) (*response.ListResponse[response.TransferResponse], error) {
+	// 1. 查询指定的账户，验证当前用户拥有其中至少一个
+	var fromID, toID uint
+	authorized := isAdmin
+	for _, side := range []struct {
+		publicID *uuid.UUID
+		id       *uint
+	}{{fromAccountID, &fromID}, {toAccountID, &toID}} {
+		if side.publicID == nil {
+			continue
+		}
+		account, err := s.accountRepo.GetByPublicID(ctx, *side.publicID)
+		if err != nil {
+			return nil, err
+		}
+		*side.id = account.ID
+		if account.Owner == owner {
+			authorized = true
+		}
+	}
+	if fromID == 0 && toID == 0 {
+		return nil, apperrors.ErrInvalidParams("from_account_id or to_account_id is required")
+	}
+	if !authorized {
+		return nil, apperrors.ErrForbidden()
 	}
 
 	// 2. 计算分页参数
@@ -186,15 +791,15 @@ func (s *TransferService) ListTransfers(ctx context.Context, owner string, accou
 	offset := req.Offset()
 
 	// 3. 查询转账记录
-	transfers, total, err := s.transferRepo.ListByAccountID(ctx, accountID, limit, offset)
+	transfers, total, err := s.transferRepo.ListBetween(ctx, fromID, toID, req.Sort, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
 	// 4. 转换为响应格式
-	items := make([]response.TransferResponse, len(transfers))
-	for i, transfer := range transfers {
-		items[i] = *s.toTransferResponse(&transfer)
+	items, err := s.toTransferResponses(ctx, transfers)
+	if err != nil {
+		return nil, err
 	}
 
 	// 5. 返回分页响应
@@ -203,22 +808,19 @@ func (s *TransferService) ListTransfers(ctx context.Context, owner string, accou
 }
 
 // ListEntries 获取账户的账目记录
-func (s *TransferService) ListEntries(ctx context.Context, owner string, accountID uint, req *request.PaginationRequest) (*response.ListResponse[response.EntryResponse], error) {
+func (s *TransferService) ListEntries(ctx context.Context, owner string, accountID uuid.UUID, period model.TimeRange, req *request.PaginationRequest) (*response.ListResponse[response.EntryResponse], error) {
 	// 1. 验证账户属于当前用户
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+	account, err := s.ownedAccount(ctx, owner, accountID)
 	if err != nil {
 		return nil, err
 	}
-	if account.Owner != owner {
-		return nil, apperrors.ErrUnauthorized()
-	}
 
 	// 2. 计算分页参数
 	limit := req.Limit()
 	offset := req.Offset()
 
 	// 3. 查询账目记录
-	entries, total, err := s.entryRepo.ListByAccountID(ctx, accountID, limit, offset)
+	entries, total, err := s.entryRepo.ListByAccountID(ctx, account.ID, period, req.Sort, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -226,7 +828,7 @@ func (s *TransferService) ListEntries(ctx context.Context, owner string, account
 	// 4. 转换为响应格式
 	items := make([]response.EntryResponse, len(entries))
 	for i, entry := range entries {
-		items[i] = *s.toEntryResponse(&entry)
+		items[i] = *toEntryResponse(&entry, account.PublicID)
 	}
 
 	// 5. 返回分页响应
@@ -234,23 +836,270 @@ func (s *TransferService) ListEntries(ctx context.Context, owner string, account
 	return &result, nil
 }
 
-// toTransferResponse 转换为转账响应
-func (s *TransferService) toTransferResponse(transfer *model.Transfer) *response.TransferResponse {
-	return &response.TransferResponse{
-		ID:            transfer.ID,
-		FromAccountID: transfer.FromAccountID,
-		ToAccountID:   transfer.ToAccountID,
-		Amount:        transfer.Amount,
+// GetAccountHistory 获取账户的交易历史 (带分页，最新的在前)
+//
+// 把账目和产生它的转账合并为一个列表，每条记录标明类型、转账另一方和变动后的余额
+// 变动后的余额按账目累计计算: 先查询本页最早一条账目之前 (含) 的账目之和，再逐条向后推算
+func (s *TransferService) GetAccountHistory(ctx context.Context, owner string, accountID uuid.UUID, period model.TimeRange, req *request.PaginationRequest) (*response.ListResponse[response.HistoryItemResponse], error) {
+	// 1. 验证账户属于当前用户
+	account, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询账目及其来源转账
+	activities, total, err := s.entryRepo.ListActivityByAccountID(ctx, account.ID, period, req.Limit(), req.Offset())
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 从本页最早的一条开始计算每条记录变动后的余额
+	items := make([]response.HistoryItemResponse, len(activities))
+	if len(activities) > 0 {
+		balance, err := s.entryRepo.SumThroughID(ctx, account.ID, activities[len(activities)-1].ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(activities) - 1; i >= 0; i-- {
+			if i < len(activities)-1 {
+				balance += activities[i].Amount
+			}
+			items[i] = *toHistoryItemResponse(&activities[i], balance)
+		}
+	}
+
+	// 4. 返回分页响应
+	result := response.NewListResponse(items, req.PageID, req.PageSize, total)
+	return &result, nil
+}
+
+// ListOwnerEntries 获取用户所有账户的账目记录，每条附带所属账户的货币
+// 已关闭账户的账目不包含在内
+func (s *TransferService) ListOwnerEntries(ctx context.Context, owner string, period model.TimeRange, req *request.PaginationRequest) (*response.ListResponse[response.EntryResponse], error) {
+	// 1. 计算分页参数
+	limit := req.Limit()
+	offset := req.Offset()
+
+	// 2. 查询用户所有账户的账目记录
+	entries, total, err := s.entryRepo.ListByOwner(ctx, owner, period, req.Sort, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 转换为响应格式
+	items := make([]response.EntryResponse, len(entries))
+	for i := range entries {
+		item := toEntryResponse(&entries[i].Entry, entries[i].AccountPublicID)
+		item.Currency = entries[i].Currency
+		items[i] = *item
+	}
+
+	// 4. 返回分页响应
+	result := response.NewListResponse(items, req.PageID, req.PageSize, total)
+	return &result, nil
+}
+
+// ExportEntries 导出账户在时间范围内的全部账目 (不分页)
+//
+// 先验证账户所有权，再按 ID 升序逐条回调 fn，由调用方负责写出 (CSV/JSON 等)
+// 所有权验证失败时不会调用 fn
+func (s *TransferService) ExportEntries(ctx context.Context, owner string, accountID uuid.UUID, period model.TimeRange, fn func(entry *response.EntryResponse) error) error {
+	// 1. 验证账户属于当前用户
+	account, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return err
+	}
+
+	// 2. 逐条读取并回调
+	return s.entryRepo.StreamByAccountID(ctx, account.ID, period, func(entry *model.Entry) error {
+		return fn(toEntryResponse(entry, account.PublicID))
+	})
+}
+
+// GetAccountStats 获取账户最近 days 天的转账统计
+// 统计区间为 [当前时间 - days 天, 当前时间)，由数据库聚合计算
+func (s *TransferService) GetAccountStats(ctx context.Context, owner string, accountID uuid.UUID, days int) (*response.AccountStatsResponse, error) {
+	// 1. 验证账户属于当前用户
+	account, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 计算统计区间
+	now := s.now()
+	period := model.TimeRange{From: now.AddDate(0, 0, -days), To: now}
+
+	// 3. 聚合统计
+	stats, err := s.transferRepo.StatsByAccountID(ctx, account.ID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 返回响应
+	return &response.AccountStatsResponse{
+		AccountID:       account.PublicID,
+		Currency:        account.Currency,
+		Period:          fmt.Sprintf("%dd", days),
+		PeriodStart:     period.From,
+		PeriodEnd:       period.To,
+		TotalSent:       money.Amount(stats.TotalSent),
+		TotalReceived:   money.Amount(stats.TotalReceived),
+		SentCount:       stats.SentCount,
+		ReceivedCount:   stats.ReceivedCount,
+		TransferCount:   stats.Count(),
+		LargestTransfer: money.Amount(stats.Largest),
+		AverageTransfer: money.Amount(stats.Average()),
+	}, nil
+}
+
+// GetStatement 获取账户的月度对账单
+//
+// 期初余额为月初之前所有账目之和，期末余额 = 期初 + 入账 - 出账
+// 月份按 UTC 划分: [当月 1 日 00:00, 次月 1 日 00:00)
+func (s *TransferService) GetStatement(ctx context.Context, owner string, accountID uuid.UUID, year, month int) (*response.StatementResponse, error) {
+	// 1. 验证账户属于当前用户
+	account, err := s.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 计算统计区间
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	period := model.TimeRange{From: start, To: start.AddDate(0, 1, 0)}
+
+	// 3. 统计期初余额和区间内的收支
+	opening, err := s.entryRepo.SumBeforeDate(ctx, account.ID, period.From)
+	if err != nil {
+		return nil, err
+	}
+	credits, debits, err := s.entryRepo.SumInRange(ctx, account.ID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 读取区间内的账目
+	entries := make([]response.EntryResponse, 0)
+	err = s.entryRepo.StreamByAccountID(ctx, account.ID, period, func(entry *model.Entry) error {
+		entries = append(entries, *toEntryResponse(entry, account.PublicID))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 返回响应
+	return &response.StatementResponse{
+		AccountID:      account.PublicID,
+		Currency:       account.Currency,
+		Year:           year,
+		Month:          month,
+		PeriodStart:    period.From,
+		PeriodEnd:      period.To,
+		OpeningBalance: money.Amount(opening),
+		ClosingBalance: money.Amount(opening + credits - debits),
+		TotalCredits:   money.Amount(credits),
+		TotalDebits:    money.Amount(debits),
+		Entries:        entries,
+	}, nil
+}
+
+// ownedAccount 根据公开ID查询账户并验证属于 owner
+func (s *TransferService) ownedAccount(ctx context.Context, owner string, accountID uuid.UUID) (*model.Account, error) {
+	account, err := s.accountRepo.GetByPublicID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Owner != owner {
+		return nil, apperrors.ErrUnauthorized()
+	}
+	return account, nil
+}
+
+// transferRefs 转账响应中引用的账户和原转账的公开ID (内部ID → 公开ID)
+type transferRefs struct {
+	accounts  map[uint]uuid.UUID
+	transfers map[uint]uuid.UUID
+}
+
+// accountRefs 由已读取的账户构造 transferRefs
+func accountRefs(accounts ...*model.Account) *transferRefs {
+	refs := &transferRefs{
+		accounts:  make(map[uint]uuid.UUID, len(accounts)),
+		transfers: make(map[uint]uuid.UUID),
+	}
+	for _, account := range accounts {
+		refs.accounts[account.ID] = account.PublicID
+	}
+	return refs
+}
+
+// loadTransferRefs 批量查询 transfers 引用的账户 (包括已关闭的) 和原转账的公开ID
+func (s *TransferService) loadTransferRefs(ctx context.Context, transfers []model.Transfer) (*transferRefs, error) {
+	accountIDs := make([]uint, 0, 2*len(transfers))
+	var transferIDs []uint
+	for _, transfer := range transfers {
+		accountIDs = append(accountIDs, transfer.FromAccountID, transfer.ToAccountID)
+		if transfer.ReversalOf != nil {
+			transferIDs = append(transferIDs, *transfer.ReversalOf)
+		}
+	}
+
+	accounts, err := s.accountRepo.PublicIDs(ctx, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+	originals, err := s.transferRepo.PublicIDs(ctx, transferIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &transferRefs{accounts: accounts, transfers: originals}, nil
+}
+
+// toTransferResponses 转换为转账响应列表，引用的公开ID用一次批量查询读取
+func (s *TransferService) toTransferResponses(ctx context.Context, transfers []model.Transfer) ([]response.TransferResponse, error) {
+	refs, err := s.loadTransferRefs(ctx, transfers)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]response.TransferResponse, len(transfers))
+	for i := range transfers {
+		items[i] = *toTransferResponse(&transfers[i], refs)
+	}
+	return items, nil
+}
+
+// transferResponse 转换单笔转账为响应
+func (s *TransferService) transferResponse(ctx context.Context, transfer *model.Transfer) (*response.TransferResponse, error) {
+	items, err := s.toTransferResponses(ctx, []model.Transfer{*transfer})
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// toTransferResponse 转换为转账响应，账户和原转账以 refs 中的公开ID表示
+func toTransferResponse(transfer *model.Transfer, refs *transferRefs) *response.TransferResponse {
+	resp := &response.TransferResponse{
+		PublicID:      transfer.PublicID,
+		Reference:     transfer.Reference,
+		FromAccountID: refs.accounts[transfer.FromAccountID],
+		ToAccountID:   refs.accounts[transfer.ToAccountID],
+		Amount:        money.Amount(transfer.Amount),
 		CreatedAt:     transfer.CreatedAt,
 	}
+	if transfer.ReversalOf != nil {
+		original := refs.transfers[*transfer.ReversalOf]
+		resp.ReversalOf = &original
+	}
+	return resp
 }
 
-// toEntryResponse 转换为账目响应
-func (s *TransferService) toEntryResponse(entry *model.Entry) *response.EntryResponse {
+// toEntryResponse 转换为账目响应，accountID 为账目所属账户的公开ID
+func toEntryResponse(entry *model.Entry, accountID uuid.UUID) *response.EntryResponse {
 	return &response.EntryResponse{
 		ID:        entry.ID,
-		AccountID: entry.AccountID,
-		Amount:    entry.Amount,
+		AccountID: accountID,
+		Amount:    money.Amount(entry.Amount),
 		CreatedAt: entry.CreatedAt,
 	}
 }
@@ -0,0 +1,1050 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	"github.com/proyuen/simple-bank-v2/internal/dto/response"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+	"github.com/proyuen/simple-bank-v2/pkg/money"
+)
+
+// newTestTransferService 创建使用内存 Repository 的 TransferService
+func newTestTransferService(repos *memory.Repositories, limits TransferLimits) *TransferService {
+	return NewTransferService(repos.TxManager, repos.Accounts, repos.Transfers, repos.Entries,
+		NewAuditLogger(repos.AuditLogs), limits)
+}
+
+// transferRequest 构造从 from 到 to 的转账请求
+func transferRequest(from, to *model.Account, amount int64) *request.CreateTransferRequest {
+	return &request.CreateTransferRequest{
+		FromAccountID: from.PublicID.String(),
+		ToAccountID:   to.PublicID.String(),
+		Amount:        money.Amount(amount),
+	}
+}
+
+func TestDailyLimitRecheckedUnderLock(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{DailyLimit: 10000})
+	from := mustCreateAccount(t, repos, "alice", "USD", 100000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	// 两笔转账都在对方提交前通过了事务外的校验
+	req := transferRequest(from, to, 6000)
+	first, err := s.validateTransfer(ctx, "alice", req)
+	if err != nil {
+		t.Fatalf("validate first: %v", err)
+	}
+	second, err := s.validateTransfer(ctx, "alice", req)
+	if err != nil {
+		t.Fatalf("validate second: %v", err)
+	}
+
+	exec := func(plan *transferPlan) error {
+		var result TransferResult
+		return repos.TxManager.Transaction(ctx, func(txCtx context.Context) error {
+			return s.execTransfer(txCtx, plan.fromAccount.ID, plan.toAccount.ID, plan.amount, &result)
+		})
+	}
+	if err := exec(first); err != nil {
+		t.Fatalf("exec first: %v", err)
+	}
+	err = exec(second)
+	assertCode(t, err, apperrors.CodeTransferLimitExceeded)
+
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 94000 {
+		t.Errorf("balance = %d, want 94000", got)
+	}
+}
+
+func TestLimitMessagesUseFormattedAmounts(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{MaxAmount: 50000, DailyLimit: 100000})
+	from := mustCreateAccount(t, repos, "alice", "USD", 1000000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	_, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 50001))
+	assertCode(t, err, apperrors.CodeTransferLimitExceeded)
+	if msg := apperrors.AsAppError(err).Message; !strings.Contains(msg, "500.00 USD") {
+		t.Errorf("message = %q, want formatted single limit", msg)
+	}
+
+	for range 2 {
+		if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 50000)); err != nil {
+			t.Fatalf("CreateTransfer: %v", err)
+		}
+	}
+	_, err = s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1))
+	assertCode(t, err, apperrors.CodeTransferLimitExceeded)
+	if msg := apperrors.AsAppError(err).Message; !strings.Contains(msg, "1000.00 USD") {
+		t.Errorf("message = %q, want formatted daily limit", msg)
+	}
+}
+
+func TestTransferResponsesUsePublicIDs(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{}).WithReversalWindow(time.Hour)
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	transfer, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	if transfer.FromAccountID != from.PublicID || transfer.ToAccountID != to.PublicID {
+		t.Errorf("transfer accounts = %s → %s, want %s → %s",
+			transfer.FromAccountID, transfer.ToAccountID, from.PublicID, to.PublicID)
+	}
+
+	reversal, err := s.ReverseTransfer(ctx, "alice", transfer.PublicID)
+	if err != nil {
+		t.Fatalf("ReverseTransfer: %v", err)
+	}
+	if reversal.ReversalOf == nil || *reversal.ReversalOf != transfer.PublicID {
+		t.Errorf("reversal_of = %v, want %s", reversal.ReversalOf, transfer.PublicID)
+	}
+
+	// 列表中的引用通过批量查询得到相同的公开ID
+	list, err := s.ListTransfers(ctx, "alice", from.PublicID, &request.PaginationRequest{PageID: 1})
+	if err != nil {
+		t.Fatalf("ListTransfers: %v", err)
+	}
+	for _, item := range list.Data {
+		if item.FromAccountID == uuid.Nil || item.ToAccountID == uuid.Nil {
+			t.Errorf("list item %s has unresolved account ids", item.PublicID)
+		}
+		if item.PublicID == reversal.PublicID && *item.ReversalOf != transfer.PublicID {
+			t.Errorf("list reversal_of = %s, want %s", *item.ReversalOf, transfer.PublicID)
+		}
+	}
+
+	now := time.Now().UTC()
+	statement, err := s.GetStatement(ctx, "alice", from.PublicID, now.Year(), int(now.Month()))
+	if err != nil {
+		t.Fatalf("GetStatement: %v", err)
+	}
+	if statement.AccountID != from.PublicID {
+		t.Errorf("statement account_id = %s, want %s", statement.AccountID, from.PublicID)
+	}
+	for _, entry := range statement.Entries {
+		if entry.AccountID != from.PublicID {
+			t.Errorf("entry account_id = %s, want %s", entry.AccountID, from.PublicID)
+		}
+	}
+}
+
+func TestTransferByAccountNumberOrID(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	tests := []struct {
+		name     string
+		req      *request.CreateTransferRequest
+		wantCode int
+	}{
+		{name: "by id", req: transferRequest(from, to, 100)},
+		{name: "by number", req: &request.CreateTransferRequest{
+			FromAccountID:   from.PublicID.String(),
+			ToAccountNumber: to.Number,
+			Amount:          200,
+		}},
+		{name: "unknown number", req: &request.CreateTransferRequest{
+			FromAccountID:   from.PublicID.String(),
+			ToAccountNumber: "000000000000",
+			Amount:          300,
+		}, wantCode: apperrors.CodeAccountNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer, err := s.CreateTransfer(ctx, "alice", tt.req)
+			if tt.wantCode != 0 {
+				assertCode(t, err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateTransfer: %v", err)
+			}
+			if transfer.ToAccountID != to.PublicID {
+				t.Errorf("to_account_id = %s, want %s", transfer.ToAccountID, to.PublicID)
+			}
+		})
+	}
+
+	if got := mustGetAccount(t, repos, to.ID).Balance; got != 300 {
+		t.Errorf("recipient balance = %d, want 300", got)
+	}
+}
+
+func TestTransferInfersCurrency(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{MinAmount: 100})
+	from := mustCreateAccount(t, repos, "alice", "EUR", 10000)
+	to := mustCreateAccount(t, repos, "bob", "EUR", 0)
+
+	withCurrency := func(code string, amount int64) *request.CreateTransferRequest {
+		req := transferRequest(from, to, amount)
+		req.Currency = code
+		return req
+	}
+
+	// 省略货币时使用源账户的货币
+	if _, err := s.CreateTransfer(ctx, "alice", withCurrency("", 100)); err != nil {
+		t.Fatalf("CreateTransfer without currency: %v", err)
+	}
+	if _, err := s.CreateTransfer(ctx, "alice", withCurrency("EUR", 100)); err != nil {
+		t.Fatalf("CreateTransfer with currency: %v", err)
+	}
+	_, err := s.CreateTransfer(ctx, "alice", withCurrency("USD", 100))
+	assertCode(t, err, apperrors.CodeInvalidRequest)
+
+	// 单笔下限的提示也使用推断出的货币
+	_, err = s.CreateTransfer(ctx, "alice", withCurrency("", 50))
+	assertCode(t, err, apperrors.CodeInvalidParams)
+	if msg := apperrors.AsAppError(err).Message; !strings.Contains(msg, "1.00 EUR") {
+		t.Errorf("message = %q, want the minimum in EUR", msg)
+	}
+
+	if got := mustGetAccount(t, repos, to.ID).Balance; got != 200 {
+		t.Errorf("recipient balance = %d, want 200", got)
+	}
+}
+
+func TestValidateTransfer(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{MinAmount: 100, MaxAmount: 5000})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	poor := mustCreateAccount(t, repos, "alice", "EUR", 50)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+	toEUR := mustCreateAccount(t, repos, "bob", "EUR", 0)
+	frozen := mustCreateAccount(t, repos, "carol", "USD", 0)
+	if _, err := repos.Accounts.SetFrozen(ctx, frozen.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	missing := &model.Account{PublicID: uuid.New()}
+
+	tests := []struct {
+		name     string
+		owner    string
+		req      *request.CreateTransferRequest
+		wantCode int
+	}{
+		{name: "valid", owner: "alice", req: transferRequest(from, to, 1000)},
+		{name: "unsupported currency", owner: "alice", req: func() *request.CreateTransferRequest {
+			req := transferRequest(from, to, 1000)
+			req.Currency = "JPY"
+			return req
+		}(), wantCode: apperrors.CodeInvalidParams},
+		{name: "non-positive amount", owner: "alice", req: transferRequest(from, to, 0), wantCode: apperrors.CodeInvalidParams},
+		{name: "source not found", owner: "alice", req: transferRequest(missing, to, 1000), wantCode: apperrors.CodeAccountNotFound},
+		{name: "source not owned", owner: "bob", req: transferRequest(from, to, 1000), wantCode: apperrors.CodeUnauthorized},
+		{name: "target not found", owner: "alice", req: transferRequest(from, missing, 1000), wantCode: apperrors.CodeAccountNotFound},
+		{name: "same account", owner: "alice", req: transferRequest(from, from, 1000), wantCode: apperrors.CodeSameAccount},
+		{name: "currency mismatch", owner: "alice", req: transferRequest(from, toEUR, 1000), wantCode: apperrors.CodeInvalidRequest},
+		{name: "below minimum", owner: "alice", req: transferRequest(from, to, 99), wantCode: apperrors.CodeInvalidParams},
+		{name: "frozen target", owner: "alice", req: transferRequest(from, frozen, 1000), wantCode: apperrors.CodeAccountFrozen},
+		{name: "insufficient balance", owner: "alice", req: transferRequest(poor, toEUR, 100), wantCode: apperrors.CodeInsufficientBalance},
+		{name: "over maximum", owner: "alice", req: transferRequest(from, to, 5001), wantCode: apperrors.CodeTransferLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := s.validateTransfer(ctx, tt.owner, tt.req)
+			if tt.wantCode != 0 {
+				assertCode(t, err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateTransfer: %v", err)
+			}
+			if plan.fromAccount.ID != from.ID || plan.toAccount.ID != to.ID || plan.amount != 1000 {
+				t.Errorf("plan = %d → %d amount %d, want %d → %d amount 1000",
+					plan.fromAccount.ID, plan.toAccount.ID, plan.amount, from.ID, to.ID)
+			}
+		})
+	}
+}
+
+func TestPreviewTransferDoesNotWrite(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{MaxAmount: 5000})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 500)
+
+	preview, err := s.PreviewTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	if err != nil {
+		t.Fatalf("PreviewTransfer: %v", err)
+	}
+	if preview.FromAccount.Balance != 9000 || preview.FromEntry.Amount != -1000 || preview.ToEntry.Amount != 1000 {
+		t.Errorf("preview = from balance %d, entries %d/%d; want 9000, -1000/1000",
+			preview.FromAccount.Balance, preview.FromEntry.Amount, preview.ToEntry.Amount)
+	}
+	// 对方账户不暴露余额
+	if preview.ToAccount.Balance != 0 || preview.ToAccount.Owner != "b***b" {
+		t.Errorf("preview counterparty = %+v, want masked", preview.ToAccount)
+	}
+
+	// 与 CreateTransfer 相同的校验: 余额不足、超出限额
+	_, err = s.PreviewTransfer(ctx, "bob", transferRequest(to, from, 1000))
+	assertCode(t, err, apperrors.CodeInsufficientBalance)
+	_, err = s.PreviewTransfer(ctx, "alice", transferRequest(from, to, 6000))
+	assertCode(t, err, apperrors.CodeTransferLimitExceeded)
+
+	// 预览不写入任何数据
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 10000 {
+		t.Errorf("from balance = %d, want 10000", got)
+	}
+	if got := mustGetAccount(t, repos, to.ID).Balance; got != 500 {
+		t.Errorf("to balance = %d, want 500", got)
+	}
+	if _, total, err := repos.Transfers.ListByAccountID(ctx, from.ID, "", 10, 0); err != nil || total != 0 {
+		t.Errorf("transfers = %d, %v, want none", total, err)
+	}
+	if _, total, err := repos.Entries.ListByAccountID(ctx, from.ID, model.TimeRange{}, "", 10, 0); err != nil || total != 0 {
+		t.Errorf("entries = %d, %v, want none", total, err)
+	}
+	if got := auditActions(t, repos); len(got) != 0 {
+		t.Errorf("audit actions = %v, want none", got)
+	}
+}
+
+func TestTransferRejectsSameAccountByNumber(t *testing.T) {
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+
+	_, err := s.CreateTransfer(context.Background(), "alice", &request.CreateTransferRequest{
+		FromAccountID:   from.PublicID.String(),
+		ToAccountNumber: from.Number,
+		Amount:          100,
+	})
+	assertCode(t, err, apperrors.CodeSameAccount)
+}
+
+func TestPublicIDsAreStableAndUnique(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	seen := make(map[uuid.UUID]bool)
+	for _, owner := range []string{"alice", "bob", "carol"} {
+		account := mustCreateAccount(t, repos, owner, "USD", 0)
+		if account.PublicID == uuid.Nil || seen[account.PublicID] {
+			t.Fatalf("account public id %s is nil or duplicated", account.PublicID)
+		}
+		seen[account.PublicID] = true
+
+		// 余额变动后公开ID不变
+		if _, err := repos.Accounts.UpdateBalance(ctx, account.ID, 100); err != nil {
+			t.Fatal(err)
+		}
+		reloaded, err := repos.Accounts.GetByPublicID(ctx, account.PublicID)
+		if err != nil || reloaded.ID != account.ID {
+			t.Fatalf("GetByPublicID(%s) = %v, %v", account.PublicID, reloaded, err)
+		}
+	}
+}
+
+func TestGetTransferExpandAccounts(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	transfer, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+
+	// 不展开时不返回账户信息
+	plain, err := s.GetTransfer(ctx, "alice", transfer.PublicID, false)
+	if err != nil {
+		t.Fatalf("GetTransfer: %v", err)
+	}
+	if plain.FromAccount != nil || plain.ToAccount != nil {
+		t.Errorf("unexpanded transfer includes accounts: %+v, %+v", plain.FromAccount, plain.ToAccount)
+	}
+
+	tests := []struct {
+		name          string
+		owner         string
+		wantOwnNumber string
+		wantMasked    string
+	}{
+		{name: "sender", owner: "alice", wantOwnNumber: from.Number, wantMasked: "b***b"},
+		{name: "recipient", owner: "bob", wantOwnNumber: to.Number, wantMasked: "a***e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetTransfer(ctx, tt.owner, transfer.PublicID, true)
+			if err != nil {
+				t.Fatalf("GetTransfer: %v", err)
+			}
+			if resp.FromAccount == nil || resp.ToAccount == nil {
+				t.Fatalf("expanded accounts = %+v, %+v", resp.FromAccount, resp.ToAccount)
+			}
+			own, other := resp.FromAccount, resp.ToAccount
+			if tt.owner == "bob" {
+				own, other = other, own
+			}
+
+			// 自己的账户返回完整信息
+			if own.Owner != tt.owner || own.Number != tt.wantOwnNumber {
+				t.Errorf("own account = %+v, want full details", own)
+			}
+			// 对方账户只返回公开ID、货币和掩码后的所有者
+			want := response.AccountResponse{PublicID: other.PublicID, Owner: tt.wantMasked, Currency: "USD"}
+			if *other != want {
+				t.Errorf("counterparty = %+v, want %+v", *other, want)
+			}
+		})
+	}
+
+	_, err = s.GetTransfer(ctx, "carol", transfer.PublicID, true)
+	assertCode(t, err, apperrors.CodeForbidden)
+}
+
+func TestGetTransferByReference(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+	mustCreateAccount(t, repos, "carol", "USD", 0)
+
+	transfer, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+
+	// 转出方和收款方都可以查看
+	for _, owner := range []string{"alice", "bob"} {
+		got, err := s.GetTransferByReference(ctx, owner, transfer.Reference)
+		if err != nil {
+			t.Fatalf("GetTransferByReference(%s): %v", owner, err)
+		}
+		if got.PublicID != transfer.PublicID {
+			t.Errorf("%s got transfer %s, want %s", owner, got.PublicID, transfer.PublicID)
+		}
+	}
+
+	_, err = s.GetTransferByReference(ctx, "carol", transfer.Reference)
+	assertCode(t, err, apperrors.CodeForbidden)
+
+	_, err = s.GetTransferByReference(ctx, "alice", "TRF-DOES-NOT-EXIST")
+	assertCode(t, err, apperrors.CodeNotFound)
+}
+
+func TestTransferRollsBackWhenLastStepFails(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	// 转账记录和双方账目都已写入，最后一步更新余额失败
+	repos.Store.FailOn(memory.OpAccountUpdateBalances, 1, errors.New("connection reset"))
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000)); err == nil {
+		t.Fatal("CreateTransfer should fail")
+	}
+
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 10000 {
+		t.Errorf("from balance = %d, want 10000", got)
+	}
+	if got := mustGetAccount(t, repos, to.ID).Balance; got != 0 {
+		t.Errorf("to balance = %d, want 0", got)
+	}
+	if _, total, err := repos.Transfers.ListByAccountID(ctx, from.ID, "", 10, 0); err != nil || total != 0 {
+		t.Errorf("transfers = %d, %v, want none", total, err)
+	}
+	for _, account := range []*model.Account{from, to} {
+		if _, total, err := repos.Entries.ListByAccountID(ctx, account.ID, model.TimeRange{}, "", 10, 0); err != nil || total != 0 {
+			t.Errorf("entries of account %d = %d, %v, want none", account.ID, total, err)
+		}
+	}
+}
+
+func TestTransferTargetClosedBeforeLock(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	// 事务外的校验通过后目标账户被关闭: 按 ID 顺序第二个锁定的是目标账户，此时已查不到
+	repos.Store.FailOn(memory.OpAccountGetForUpdate, 2, apperrors.ErrAccountNotFound())
+	_, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	assertCode(t, err, apperrors.CodeAccountNotFound)
+
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 10000 {
+		t.Errorf("from balance = %d, want 10000", got)
+	}
+	if _, total, err := repos.Transfers.ListByAccountID(ctx, from.ID, "", 10, 0); err != nil || total != 0 {
+		t.Errorf("transfers = %d, %v, want none", total, err)
+	}
+}
+
+func TestTransferWithinOverdraft(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 1000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+	if _, err := newTestAccountService(repos).SetOverdraftLimit(ctx, "admin", from.PublicID, 500); err != nil {
+		t.Fatalf("SetOverdraftLimit: %v", err)
+	}
+
+	// 动用部分透支额度
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1400)); err != nil {
+		t.Fatalf("CreateTransfer into overdraft: %v", err)
+	}
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != -400 {
+		t.Errorf("balance = %d, want -400", got)
+	}
+
+	// 超过透支额度
+	_, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 200))
+	assertCode(t, err, apperrors.CodeInsufficientBalance)
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != -400 {
+		t.Errorf("balance after rejected transfer = %d, want -400", got)
+	}
+
+	// 恰好用完透支额度
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 100)); err != nil {
+		t.Fatalf("CreateTransfer up to the limit: %v", err)
+	}
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != -500 {
+		t.Errorf("balance = %d, want -500", got)
+	}
+}
+
+func TestFrozenAccountRejectsTransfers(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	accounts := newTestAccountService(repos)
+	alice := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	bob := mustCreateAccount(t, repos, "bob", "USD", 10000)
+
+	if _, err := accounts.SetFrozen(ctx, "admin", bob.PublicID, true); err != nil {
+		t.Fatalf("SetFrozen: %v", err)
+	}
+
+	// 冻结账户不能转入也不能转出
+	_, err := s.CreateTransfer(ctx, "alice", transferRequest(alice, bob, 100))
+	assertCode(t, err, apperrors.CodeAccountFrozen)
+	_, err = s.CreateTransfer(ctx, "bob", transferRequest(bob, alice, 100))
+	assertCode(t, err, apperrors.CodeAccountFrozen)
+
+	// 冻结账户仍然可以查询
+	got, err := accounts.GetAccount(ctx, "bob", bob.PublicID)
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if !got.IsFrozen || got.Balance != 10000 {
+		t.Errorf("frozen account = frozen %v balance %d, want frozen with unchanged balance", got.IsFrozen, got.Balance)
+	}
+
+	// 解冻后恢复
+	if _, err := accounts.SetFrozen(ctx, "admin", bob.PublicID, false); err != nil {
+		t.Fatalf("SetFrozen: %v", err)
+	}
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(alice, bob, 100)); err != nil {
+		t.Errorf("CreateTransfer after unfreeze: %v", err)
+	}
+}
+
+func TestListTransfersSort(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	for _, amount := range []int64{300, 100, 200} {
+		if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, amount)); err != nil {
+			t.Fatalf("CreateTransfer: %v", err)
+		}
+	}
+
+	amounts := func(sort string) []int64 {
+		t.Helper()
+		list, err := s.ListTransfers(ctx, "alice", from.PublicID, &request.PaginationRequest{PageID: 1, Sort: sort})
+		if err != nil {
+			t.Fatalf("ListTransfers(%q): %v", sort, err)
+		}
+		var got []int64
+		for _, item := range list.Data {
+			got = append(got, int64(item.Amount))
+		}
+		return got
+	}
+	for sort, want := range map[string][]int64{
+		"":        {200, 100, 300},
+		"amount":  {100, 200, 300},
+		"-amount": {300, 200, 100},
+	} {
+		if got := amounts(sort); !slices.Equal(got, want) {
+			t.Errorf("sort %q amounts = %v, want %v", sort, got, want)
+		}
+	}
+
+	_, err := s.ListTransfers(ctx, "alice", from.PublicID, &request.PaginationRequest{PageID: 1, Sort: "owner"})
+	assertCode(t, err, apperrors.CodeInvalidParams)
+}
+
+func TestListTransfersBetween(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	alice := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	bob := mustCreateAccount(t, repos, "bob", "USD", 10000)
+	carol := mustCreateAccount(t, repos, "carol", "USD", 10000)
+
+	for _, tr := range []struct {
+		owner    string
+		from, to *model.Account
+		amount   int64
+	}{
+		{"alice", alice, bob, 100},
+		{"bob", bob, alice, 200},
+		{"alice", alice, carol, 300},
+		{"carol", carol, bob, 400},
+		{"alice", alice, bob, 500},
+	} {
+		if _, err := s.CreateTransfer(ctx, tr.owner, transferRequest(tr.from, tr.to, tr.amount)); err != nil {
+			t.Fatalf("CreateTransfer: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		owner    string
+		isAdmin  bool
+		from, to *model.Account
+		want     []int64
+		wantCode int
+	}{
+		{name: "pair", owner: "alice", from: alice, to: bob, want: []int64{500, 100}},
+		{name: "reverse pair", owner: "alice", from: bob, to: alice, want: []int64{200}},
+		{name: "owner of the receiving side", owner: "bob", from: alice, to: bob, want: []int64{500, 100}},
+		{name: "only to", owner: "bob", to: bob, want: []int64{500, 400, 100}},
+		{name: "admin", owner: "admin", isAdmin: true, from: carol, to: bob, want: []int64{400}},
+		{name: "owns neither", owner: "carol", from: alice, to: bob, wantCode: apperrors.CodeForbidden},
+		{name: "no filter", owner: "alice", wantCode: apperrors.CodeInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromID, toID *uuid.UUID
+			if tt.from != nil {
+				fromID = &tt.from.PublicID
+			}
+			if tt.to != nil {
+				toID = &tt.to.PublicID
+			}
+			list, err := s.ListTransfersBetween(ctx, tt.owner, tt.isAdmin, fromID, toID, &request.PaginationRequest{PageID: 1})
+			if tt.wantCode != 0 {
+				assertCode(t, err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListTransfersBetween: %v", err)
+			}
+			var got []int64
+			for _, item := range list.Data {
+				got = append(got, int64(item.Amount))
+			}
+			if !slices.Equal(got, tt.want) || list.Pagination.TotalCount != int64(len(tt.want)) {
+				t.Errorf("amounts = %v (total %d), want %v", got, list.Pagination.TotalCount, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcurrentTransfersSerialize(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	alice := mustCreateAccount(t, repos, "alice", "USD", 1000)
+	bob := mustCreateAccount(t, repos, "bob", "USD", 1000)
+
+	// 同一对账户的双向转账并发执行；源账户余额只够其中一部分转账，
+	// 锁定后重新校验余额，不应出现丢失更新或透支
+	const n = 30
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := map[string]int{}
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			owner, from, to := "alice", alice, bob
+			if i%3 == 0 {
+				owner, from, to = "bob", bob, alice
+			}
+			_, err := s.CreateTransfer(ctx, owner, transferRequest(from, to, 100))
+			if err != nil {
+				if appErr := apperrors.AsAppError(err); appErr.Code != apperrors.CodeInsufficientBalance {
+					t.Errorf("CreateTransfer: %v", err)
+				}
+				return
+			}
+			mu.Lock()
+			succeeded[owner]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	wantAlice := int64(1000 - 100*succeeded["alice"] + 100*succeeded["bob"])
+	wantBob := int64(1000 + 100*succeeded["alice"] - 100*succeeded["bob"])
+	gotAlice := mustGetAccount(t, repos, alice.ID).Balance
+	gotBob := mustGetAccount(t, repos, bob.ID).Balance
+	if gotAlice != wantAlice || gotBob != wantBob {
+		t.Errorf("balances = %d/%d, want %d/%d (succeeded %v)", gotAlice, gotBob, wantAlice, wantBob, succeeded)
+	}
+	if gotAlice < 0 || gotBob < 0 {
+		t.Errorf("balances went negative: %d/%d", gotAlice, gotBob)
+	}
+
+	// 每笔成功的转账都有且只有一条转账记录
+	_, total, err := repos.Transfers.ListByAccountID(ctx, alice.ID, "", 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(succeeded["alice"] + succeeded["bob"]); total != want {
+		t.Errorf("transfers = %d, want %d", total, want)
+	}
+}
+
+func TestTransferAtMinBalanceBoundary(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 1000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+	if _, err := newTestAccountService(repos).SetMinBalance(ctx, "admin", from.PublicID, 300); err != nil {
+		t.Fatalf("SetMinBalance: %v", err)
+	}
+
+	// 转账后余额恰好等于最低余额
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 700)); err != nil {
+		t.Fatalf("CreateTransfer down to the minimum: %v", err)
+	}
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 300 {
+		t.Errorf("balance = %d, want 300", got)
+	}
+
+	// 再转 1 分就低于最低余额
+	_, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1))
+	assertCode(t, err, apperrors.CodeInsufficientBalance)
+	if msg := apperrors.AsAppError(err).Message; msg != "would breach minimum balance" {
+		t.Errorf("message = %q, want minimum balance breach", msg)
+	}
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 300 {
+		t.Errorf("balance after rejected transfer = %d, want 300", got)
+	}
+}
+
+func TestReverseTransfer(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{}).WithReversalWindow(time.Hour)
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	transfer, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+
+	// 只有转出方可以撤销
+	_, err = s.ReverseTransfer(ctx, "bob", transfer.PublicID)
+	assertCode(t, err, apperrors.CodeForbidden)
+
+	reversal, err := s.ReverseTransfer(ctx, "alice", transfer.PublicID)
+	if err != nil {
+		t.Fatalf("ReverseTransfer: %v", err)
+	}
+	if reversal.FromAccountID != to.PublicID || reversal.ToAccountID != from.PublicID || reversal.Amount != transfer.Amount {
+		t.Errorf("reversal = %s → %s %d, want %s → %s %d", reversal.FromAccountID, reversal.ToAccountID, reversal.Amount,
+			to.PublicID, from.PublicID, transfer.Amount)
+	}
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 10000 {
+		t.Errorf("from balance = %d, want 10000", got)
+	}
+	if got := mustGetAccount(t, repos, to.ID).Balance; got != 0 {
+		t.Errorf("to balance = %d, want 0", got)
+	}
+
+	// 每笔转账最多撤销一次，撤销转账本身不能再被撤销
+	_, err = s.ReverseTransfer(ctx, "alice", transfer.PublicID)
+	assertCode(t, err, apperrors.CodeStateConflict)
+	_, err = s.ReverseTransfer(ctx, "bob", reversal.PublicID)
+	assertCode(t, err, apperrors.CodeStateConflict)
+}
+
+func TestReverseTransferFundsAlreadySpent(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{}).WithReversalWindow(time.Hour)
+	alice := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	bob := mustCreateAccount(t, repos, "bob", "USD", 0)
+	carol := mustCreateAccount(t, repos, "carol", "USD", 0)
+
+	transfer, err := s.CreateTransfer(ctx, "alice", transferRequest(alice, bob, 1000))
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	// 收款方已经把钱转走
+	if _, err := s.CreateTransfer(ctx, "bob", transferRequest(bob, carol, 600)); err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+
+	_, err = s.ReverseTransfer(ctx, "alice", transfer.PublicID)
+	assertCode(t, err, apperrors.CodeInsufficientBalance)
+	if got := mustGetAccount(t, repos, alice.ID).Balance; got != 9000 {
+		t.Errorf("alice balance = %d, want 9000", got)
+	}
+	if got := mustGetAccount(t, repos, bob.ID).Balance; got != 400 {
+		t.Errorf("bob balance = %d, want 400", got)
+	}
+}
+
+func TestReverseTransferWindowExpired(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{}).WithReversalWindow(time.Hour)
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	transfer, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 1000))
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+
+	s.WithClock(func() time.Time { return time.Now().Add(2 * time.Hour) })
+	_, err = s.ReverseTransfer(ctx, "alice", transfer.PublicID)
+	assertCode(t, err, apperrors.CodeReversalWindowExpired)
+}
+
+func TestTransferMinAmountBoundary(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{MinAmount: 100})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	_, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 99))
+	assertCode(t, err, apperrors.CodeInvalidParams)
+	if msg := apperrors.AsAppError(err).Message; !strings.Contains(msg, "1.00 USD") {
+		t.Errorf("message = %q, want formatted minimum", msg)
+	}
+
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, 100)); err != nil {
+		t.Fatalf("CreateTransfer at the minimum: %v", err)
+	}
+}
+
+func TestTransferRejectsNonPositiveAmount(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	from := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	to := mustCreateAccount(t, repos, "bob", "USD", 0)
+
+	// 定时转账等内部调用不经过请求绑定，由 Service 拒绝
+	for _, amount := range []int64{0, -100} {
+		_, err := s.CreateTransfer(ctx, "alice", transferRequest(from, to, amount))
+		assertCode(t, err, apperrors.CodeInvalidParams)
+	}
+	if got := mustGetAccount(t, repos, from.ID).Balance; got != 10000 {
+		t.Errorf("balance = %d, want unchanged 10000", got)
+	}
+}
+
+func TestAccountHistoryMixedActivity(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	alice := mustCreateAccount(t, repos, "alice", "USD", 1000)
+	bob := mustCreateAccount(t, repos, "bob", "USD", 1000)
+
+	// 按时间顺序: 入账 1000、转出 300、转入 200、出账 100、两笔入账 50
+	mustCreateEntry(t, repos, alice.ID, 1000)
+	if _, err := s.CreateTransfer(ctx, "alice", transferRequest(alice, bob, 300)); err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	if _, err := s.CreateTransfer(ctx, "bob", transferRequest(bob, alice, 200)); err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	mustCreateEntry(t, repos, alice.ID, -100)
+	mustCreateEntry(t, repos, alice.ID, 50)
+	mustCreateEntry(t, repos, alice.ID, 50)
+
+	type item struct {
+		typ          string
+		amount       int64
+		balanceAfter int64
+	}
+	history := func(page int) []item {
+		t.Helper()
+		list, err := s.GetAccountHistory(ctx, "alice", alice.PublicID, model.TimeRange{},
+			&request.PaginationRequest{PageID: page, PageSize: 5})
+		if err != nil {
+			t.Fatalf("GetAccountHistory: %v", err)
+		}
+		if list.Pagination.TotalCount != 6 {
+			t.Errorf("total = %d, want 6", list.Pagination.TotalCount)
+		}
+		var got []item
+		for _, h := range list.Data {
+			got = append(got, item{h.Type, int64(h.Amount), int64(h.BalanceAfter)})
+
+			// 只有转账记录带有对方账户
+			isTransfer := h.Type == response.HistoryTypeTransferIn || h.Type == response.HistoryTypeTransferOut
+			if isTransfer != (h.Counterparty != nil) || isTransfer != (h.TransferID != nil) {
+				t.Errorf("%s item counterparty = %+v, transfer_id = %v", h.Type, h.Counterparty, h.TransferID)
+			}
+			if h.Counterparty != nil && (h.Counterparty.AccountID != bob.PublicID || h.Counterparty.Owner != "b***b") {
+				t.Errorf("counterparty = %+v, want bob's masked account", h.Counterparty)
+			}
+		}
+		return got
+	}
+
+	// 最新的在前，第二页的余额从之前的账目累计
+	want := []item{
+		{response.HistoryTypeDeposit, 50, 900},
+		{response.HistoryTypeDeposit, 50, 850},
+		{response.HistoryTypeWithdrawal, -100, 800},
+		{response.HistoryTypeTransferIn, 200, 900},
+		{response.HistoryTypeTransferOut, -300, 700},
+	}
+	if got := history(1); !slices.Equal(got, want) {
+		t.Errorf("page 1 = %v, want %v", got, want)
+	}
+	if got := history(2); !slices.Equal(got, []item{{response.HistoryTypeDeposit, 1000, 1000}}) {
+		t.Errorf("page 2 = %v, want the first deposit with balance 1000", got)
+	}
+}
+
+func TestGetAccountStats(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	alice := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	bob := mustCreateAccount(t, repos, "bob", "USD", 10000)
+	carol := mustCreateAccount(t, repos, "carol", "USD", 10000)
+
+	for _, tr := range []struct {
+		owner    string
+		from, to *model.Account
+		amount   int64
+	}{
+		{"alice", alice, bob, 300},
+		{"alice", alice, bob, 700},
+		{"bob", bob, alice, 200},
+		{"carol", carol, bob, 1000}, // 与 alice 无关
+	} {
+		if _, err := s.CreateTransfer(ctx, tr.owner, transferRequest(tr.from, tr.to, tr.amount)); err != nil {
+			t.Fatalf("CreateTransfer: %v", err)
+		}
+	}
+
+	stats, err := s.GetAccountStats(ctx, "alice", alice.PublicID, 30)
+	if err != nil {
+		t.Fatalf("GetAccountStats: %v", err)
+	}
+	if stats.Period != "30d" || !stats.PeriodStart.AddDate(0, 0, 30).Equal(stats.PeriodEnd) {
+		t.Errorf("period = %s [%s, %s)", stats.Period, stats.PeriodStart, stats.PeriodEnd)
+	}
+	got := [...]int64{
+		int64(stats.TotalSent), stats.SentCount, int64(stats.TotalReceived), stats.ReceivedCount,
+		stats.TransferCount, int64(stats.LargestTransfer), int64(stats.AverageTransfer),
+	}
+	if want := [...]int64{1000, 2, 200, 1, 3, 700, 400}; got != want {
+		t.Errorf("sent/count, received/count, total, largest, average = %v, want %v", got, want)
+	}
+
+	// 31 天后这些转账都在 30 天的窗口之外
+	s.WithClock(func() time.Time { return time.Now().AddDate(0, 0, 31) })
+	stats, err = s.GetAccountStats(ctx, "alice", alice.PublicID, 30)
+	if err != nil {
+		t.Fatalf("GetAccountStats: %v", err)
+	}
+	if stats.TransferCount != 0 || stats.TotalSent != 0 || stats.AverageTransfer != 0 {
+		t.Errorf("stats outside the window = %+v, want zero", stats)
+	}
+
+	_, err = s.GetAccountStats(ctx, "bob", alice.PublicID, 30)
+	assertCode(t, err, apperrors.CodeUnauthorized)
+}
+
+func TestListOwnerEntriesAcrossAccounts(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestTransferService(repos, TransferLimits{})
+	aliceUSD := mustCreateAccount(t, repos, "alice", "USD", 10000)
+	aliceEUR := mustCreateAccount(t, repos, "alice", "EUR", 10000)
+	bobUSD := mustCreateAccount(t, repos, "bob", "USD", 10000)
+	bobEUR := mustCreateAccount(t, repos, "bob", "EUR", 10000)
+
+	// 两个用户的转账在 alice 的两个账户之间交错发生
+	for _, tr := range []struct {
+		owner    string
+		from, to *model.Account
+		amount   int64
+	}{
+		{"alice", aliceUSD, bobUSD, 100},
+		{"bob", bobEUR, aliceEUR, 200},
+		{"alice", aliceEUR, bobEUR, 300},
+		{"bob", bobUSD, aliceUSD, 400},
+	} {
+		if _, err := s.CreateTransfer(ctx, tr.owner, transferRequest(tr.from, tr.to, tr.amount)); err != nil {
+			t.Fatalf("CreateTransfer: %v", err)
+		}
+	}
+
+	list, err := s.ListOwnerEntries(ctx, "alice", model.TimeRange{}, &request.PaginationRequest{PageID: 1})
+	if err != nil {
+		t.Fatalf("ListOwnerEntries: %v", err)
+	}
+
+	// 最新的在前，只包含 alice 的账目
+	type item struct {
+		account  uuid.UUID
+		currency string
+		amount   int64
+	}
+	want := []item{
+		{aliceUSD.PublicID, "USD", 400},
+		{aliceEUR.PublicID, "EUR", -300},
+		{aliceEUR.PublicID, "EUR", 200},
+		{aliceUSD.PublicID, "USD", -100},
+	}
+	var got []item
+	for _, entry := range list.Data {
+		got = append(got, item{entry.AccountID, entry.Currency, int64(entry.Amount)})
+	}
+	if !slices.Equal(got, want) || list.Pagination.TotalCount != int64(len(want)) {
+		t.Errorf("entries = %v (total %d), want %v", got, list.Pagination.TotalCount, want)
+	}
+}
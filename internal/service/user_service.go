@@ -33,17 +33,22 @@ type SessionRepository interface {
 	GetByID(ctx context.Context, id string) (*model.Session, error)
 	DeleteByUsername(ctx context.Context, username string) error
 	Block(ctx context.Context, id string) error
+	Touch(ctx context.Context, id string, usedAt time.Time) error
 }
 
 // ==================== Service 实现 ====================
 
 // UserService 用户业务逻辑
 type UserService struct {
-	userRepo       UserRepository
-	sessionRepo    SessionRepository
-	tokenMaker     token.Maker
-	accessDuration time.Duration
+	userRepo        UserRepository
+	sessionRepo     SessionRepository
+	tokenMaker      token.Maker
+	accessDuration  time.Duration
 	refreshDuration time.Duration
+	idleTimeout     time.Duration    // 会话空闲超时，0 表示不启用
+	checkPassword   bool             // 刷新时拒绝修改密码前签发的 Refresh Token
+	auditor         AuditRecorder    // 审计日志记录
+	now             func() time.Time // 时钟，测试时可替换
 }
 
 // NewUserService 创建 UserService 实例
@@ -52,6 +57,8 @@ func NewUserService(
 	sessionRepo SessionRepository,
 	tokenMaker token.Maker,
 	accessDuration, refreshDuration time.Duration,
+	idleTimeout time.Duration,
+	auditor AuditRecorder,
 ) *UserService {
 	return &UserService{
 		userRepo:        userRepo,
@@ -59,9 +66,27 @@ func NewUserService(
 		tokenMaker:      tokenMaker,
 		accessDuration:  accessDuration,
 		refreshDuration: refreshDuration,
+		idleTimeout:     idleTimeout,
+		auditor:         auditor,
+		now:             time.Now,
 	}
 }
 
+// WithClock 替换 Service 使用的时钟
+// 主要用于测试空闲超时等依赖当前时间的逻辑
+func (s *UserService) WithClock(now func() time.Time) *UserService {
+	s.now = now
+	return s
+}
+
+// WithPasswordChangeCheck 设置刷新 Token 时是否检查密码修改时间
+// 开启后修改密码前签发的 Refresh Token 不能再换取新的 Access Token，
+// 与 middleware.RejectTokensBeforePasswordChange 配合使用
+func (s *UserService) WithPasswordChangeCheck(enabled bool) *UserService {
+	s.checkPassword = enabled
+	return s
+}
+
 // CreateUser 创建新用户
 func (s *UserService) CreateUser(ctx context.Context, req *request.CreateUserRequest) (*response.UserResponse, error) {
 	// 1. 密码加密
@@ -76,6 +101,7 @@ func (s *UserService) CreateUser(ctx context.Context, req *request.CreateUserReq
 		HashedPassword: hashedPassword,
 		FullName:       req.FullName,
 		Email:          req.Email,
+		Role:           model.RoleUser,
 	}
 
 	// 3. 保存到数据库
@@ -89,25 +115,34 @@ func (s *UserService) CreateUser(ctx context.Context, req *request.CreateUserReq
 
 // LoginUser 用户登录
 func (s *UserService) LoginUser(ctx context.Context, req *request.LoginUserRequest, userAgent, clientIP string) (*response.LoginResponse, error) {
+	ctx = ContextWithClientIP(ctx, clientIP)
+
 	// 1. 查找用户
 	user, err := s.userRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
+		s.auditor.Record(ctx, req.Username, model.AuditActionLoginFailed, "")
 		return nil, apperrors.ErrPasswordWrong() // 不暴露用户是否存在
 	}
 
 	// 2. 验证密码
 	if err := password.CheckPassword(req.Password, user.HashedPassword); err != nil {
+		s.auditor.Record(ctx, req.Username, model.AuditActionLoginFailed, "")
 		return nil, apperrors.ErrPasswordWrong()
 	}
 
 	// 3. 生成 Access Token
-	accessToken, accessPayload, err := s.tokenMaker.CreateToken(user.Username, s.accessDuration)
+	// 只读登录时两个 Token 都只带 *:read，刷新得到的 Access Token 沿用 Refresh Token 的 scope
+	var scopes []string
+	if req.ReadOnly {
+		scopes = token.ReadOnlyScopes()
+	}
+	accessToken, accessPayload, err := s.tokenMaker.CreateScopedToken(user.Username, user.Role, scopes, s.accessDuration)
 	if err != nil {
 		return nil, apperrors.ErrInternalServer()
 	}
 
 	// 4. 生成 Refresh Token
-	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.Username, s.refreshDuration)
+	refreshToken, refreshPayload, err := s.tokenMaker.CreateScopedToken(user.Username, user.Role, scopes, s.refreshDuration)
 	if err != nil {
 		return nil, apperrors.ErrInternalServer()
 	}
@@ -121,11 +156,13 @@ func (s *UserService) LoginUser(ctx context.Context, req *request.LoginUserReque
 		ClientIP:     clientIP,
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		LastUsedAt:   s.now(),
 	}
 
 	if err := s.sessionRepo.Create(ctx, session); err != nil {
 		return nil, err
 	}
+	s.auditor.Record(ctx, user.Username, model.AuditActionLogin, session.ID.String())
 
 	// 6. 返回响应
 	return &response.LoginResponse{
@@ -134,6 +171,7 @@ func (s *UserService) LoginUser(ctx context.Context, req *request.LoginUserReque
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
 		SessionID:             session.ID.String(),
+		Scopes:                scopes,
 		User:                  *s.toUserResponse(user),
 	}, nil
 }
@@ -163,8 +201,39 @@ func (s *UserService) RefreshToken(ctx context.Context, req *request.RefreshToke
 		return nil, apperrors.New(apperrors.CodeInvalidToken)
 	}
 
-	// 4. 生成新的 Access Token
-	accessToken, accessPayload, err := s.tokenMaker.CreateToken(payload.Username, s.accessDuration)
+	// 4. 加载用户的当前状态
+	// 角色以数据库为准，被降级的管理员刷新后不再拿到管理员 Token
+	user, err := s.userRepo.GetByUsername(ctx, payload.Username)
+	if err != nil {
+		if apperrors.AsAppError(err).Code == apperrors.CodeUserNotFound {
+			return nil, apperrors.New(apperrors.CodeInvalidToken)
+		}
+		return nil, err
+	}
+	if s.checkPassword && payload.IssuedBefore(user.PasswordChangedAt) {
+		return nil, apperrors.NewWithMessage(apperrors.CodeTokenExpired, "password changed, please log in again")
+	}
+
+	// 5. 检查空闲超时
+	// 空闲过久的会话直接封禁，强制用户重新登录
+	now := s.now()
+	if session.IsIdle(now, s.idleTimeout) {
+		if err := s.sessionRepo.Block(ctx, session.ID.String()); err != nil {
+			return nil, err
+		}
+		s.auditor.Record(ctx, session.Username, model.AuditActionSessionBlock, session.ID.String())
+		return nil, apperrors.NewWithMessage(apperrors.CodeSessionExpired, "session idle timeout")
+	}
+
+	// 6. 更新会话最后使用时间
+	if err := s.sessionRepo.Touch(ctx, session.ID.String(), now); err != nil {
+		return nil, err
+	}
+
+	// 7. 生成新的 Access Token (与 Refresh Token 的 scope 和登录时间相同，角色为当前角色)
+	renewed := *payload
+	renewed.Role = user.Role
+	accessToken, accessPayload, err := s.tokenMaker.RenewToken(&renewed, s.accessDuration)
 	if err != nil {
 		return nil, apperrors.ErrInternalServer()
 	}
@@ -184,6 +253,19 @@ func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*
 	return s.toUserResponse(user), nil
 }
 
+// PasswordChangedAt 返回用户最近一次修改密码的时间
+// 供认证中间件拒绝修改密码前签发的 Token，用户不存在时返回 Token 无效
+func (s *UserService) PasswordChangedAt(ctx context.Context, username string) (time.Time, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if apperrors.AsAppError(err).Code == apperrors.CodeUserNotFound {
+			return time.Time{}, apperrors.New(apperrors.CodeInvalidToken)
+		}
+		return time.Time{}, err
+	}
+	return user.PasswordChangedAt, nil
+}
+
 // toUserResponse 转换为用户响应
 func (s *UserService) toUserResponse(user *model.User) *response.UserResponse {
 	return &response.UserResponse{
@@ -191,6 +273,7 @@ func (s *UserService) toUserResponse(user *model.User) *response.UserResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/proyuen/simple-bank-v2/internal/dto/request"
+	apperrors "github.com/proyuen/simple-bank-v2/internal/errors"
+	"github.com/proyuen/simple-bank-v2/internal/model"
+	"github.com/proyuen/simple-bank-v2/internal/repository/memory"
+)
+
+func TestRefreshTokenUsesCurrentRole(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s := newTestUserService(t, repos)
+
+	mustCreateUser(t, s, "alice")
+	user, err := repos.Users.GetByUsername(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Role = model.RoleAdmin
+	if err := repos.Users.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	login := mustLogin(t, s, "alice")
+
+	// 登录后被降级
+	user.Role = model.RoleUser
+	if err := repos.Users.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.RefreshToken(ctx, &request.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	payload, err := s.tokenMaker.VerifyToken(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if payload.Role != model.RoleUser {
+		t.Errorf("role = %q, want %q", payload.Role, model.RoleUser)
+	}
+}
+
+// newIdleTimeoutUserService 创建空闲超时为 30 分钟的 UserService，返回用于推进时间的时钟
+func newIdleTimeoutUserService(t *testing.T, repos *memory.Repositories) (*UserService, *time.Time) {
+	t.Helper()
+	now := time.Now()
+	s := NewUserService(repos.Users, repos.Sessions, newTestTokenMaker(t), 15*time.Minute, 24*time.Hour,
+		30*time.Minute, NewAuditLogger(repos.AuditLogs)).
+		WithClock(func() time.Time { return now })
+	return s, &now
+}
+
+func TestRefreshTokenRejectsIdleSession(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s, now := newIdleTimeoutUserService(t, repos)
+	mustCreateUser(t, s, "alice")
+	login := mustLogin(t, s, "alice")
+
+	// 超过空闲超时未使用
+	*now = now.Add(31 * time.Minute)
+	_, err := s.RefreshToken(ctx, &request.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assertCode(t, err, apperrors.CodeSessionExpired)
+
+	session, err := repos.Sessions.GetByID(ctx, login.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !session.IsBlocked {
+		t.Error("idle session should be blocked")
+	}
+	if got := auditActions(t, repos); !slices.Contains(got, model.AuditActionSessionBlock) {
+		t.Errorf("audit actions = %v, want %s", got, model.AuditActionSessionBlock)
+	}
+
+	// 封禁后即使回到超时以内也不能刷新
+	*now = now.Add(-31 * time.Minute)
+	_, err = s.RefreshToken(ctx, &request.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assertCode(t, err, apperrors.CodeAccountBlocked)
+}
+
+func TestRefreshTokenTouchesActiveSession(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.New()
+	s, now := newIdleTimeoutUserService(t, repos)
+	mustCreateUser(t, s, "alice")
+	login := mustLogin(t, s, "alice")
+
+	// 每次刷新都在超时以内，会话一直保持活跃
+	for range 3 {
+		*now = now.Add(20 * time.Minute)
+		if _, err := s.RefreshToken(ctx, &request.RefreshTokenRequest{RefreshToken: login.RefreshToken}); err != nil {
+			t.Fatalf("RefreshToken: %v", err)
+		}
+		session, err := repos.Sessions.GetByID(ctx, login.SessionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !session.LastUsedAt.Equal(*now) {
+			t.Errorf("last used at = %v, want %v", session.LastUsedAt, *now)
+		}
+	}
+}
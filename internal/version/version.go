@@ -0,0 +1,74 @@
+// Package version 记录构建信息 (版本号、Git 提交、构建时间)
+//
+// 这些变量在构建时通过 -ldflags 注入，例如:
+//
+//	go build -ldflags "\
+//	  -X github.com/proyuen/simple-bank-v2/internal/version.Version=v1.2.0 \
+//	  -X github.com/proyuen/simple-bank-v2/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/proyuen/simple-bank-v2/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//	  -o bin/server ./cmd/server
+//
+// 未注入 Commit 时尝试使用 Go 工具链记录的 VCS 信息 (go build 在 Git 仓库中构建时自动记录)
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// 构建时注入的变量，未注入时保持默认值
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info 当前运行的构建信息
+type Info struct {
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string // 编译使用的 Go 版本
+}
+
+// Get 返回当前运行的构建信息
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+	if info.Commit == "unknown" {
+		if revision, ok := vcsRevision(); ok {
+			info.Commit = revision
+		}
+	}
+	return info
+}
+
+// vcsRevision 读取 Go 工具链记录的 Git 提交，本地有未提交的修改时追加 -dirty
+func vcsRevision() (string, bool) {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", false
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "", false
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision, true
+}
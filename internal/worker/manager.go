@@ -0,0 +1,101 @@
+// Package worker 提供后台任务的生命周期管理
+//
+// 后台任务 (会话清理、定时转账、对账等) 与 HTTP 服务共享根 Context，
+// 收到关闭信号后由 Manager 统一停止，并等待正在执行的任务完成，
+// 避免转账等操作执行到一半被中断
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Worker 后台任务接口
+//
+// Run 应阻塞运行，直到 ctx 被取消后返回
+// 正在执行的一轮工作应当完成后再返回，而不是中途放弃
+type Worker interface {
+	// Name 返回任务名称，用于日志
+	Name() string
+
+	// Run 运行任务，ctx 取消时返回
+	Run(ctx context.Context)
+}
+
+// Manager 管理一组后台任务的启动和优雅关闭
+type Manager struct {
+	workers []Worker
+	cancel  context.CancelFunc
+
+	mu      sync.Mutex
+	running map[string]chan struct{} // 任务名称 → 完成信号
+}
+
+// NewManager 创建 Manager 实例
+func NewManager() *Manager {
+	return &Manager{
+		running: make(map[string]chan struct{}),
+	}
+}
+
+// Add 注册一个后台任务，必须在 Start 之前调用
+func (m *Manager) Add(w Worker) {
+	m.workers = append(m.workers, w)
+}
+
+// Start 在独立的 goroutine 中启动所有任务
+// 任务使用 ctx 的子 Context，ctx 取消或调用 Shutdown 时停止
+func (m *Manager) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, w := range m.workers {
+		done := make(chan struct{})
+		m.running[w.Name()] = done
+
+		go func(w Worker) {
+			defer close(done)
+			slog.Info("worker started", "worker", w.Name())
+			w.Run(ctx)
+			slog.Info("worker stopped", "worker", w.Name())
+		}(w)
+	}
+}
+
+// Shutdown 停止所有任务并等待它们退出
+//
+// 等待时间由 ctx 控制 (通常是服务器关闭超时)
+// 超时仍未退出的任务会被记录到日志，并返回错误
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var timedOut []string
+	for name, done := range m.running {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			// 超时后不再阻塞，只检查剩余任务是否已退出
+			select {
+			case <-done:
+			default:
+				timedOut = append(timedOut, name)
+				slog.Warn("worker did not stop before shutdown timeout", "worker", name)
+			}
+		}
+	}
+
+	if len(timedOut) > 0 {
+		return fmt.Errorf("workers did not stop in time: %v", timedOut)
+	}
+	return nil
+}
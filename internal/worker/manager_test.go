@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowWorker 在 ctx 取消后还需要 stopDelay 才能退出
+type slowWorker struct {
+	stopDelay time.Duration
+	stopped   atomic.Bool
+}
+
+func (w *slowWorker) Name() string { return "slow" }
+
+func (w *slowWorker) Run(ctx context.Context) {
+	<-ctx.Done()
+	time.Sleep(w.stopDelay)
+	w.stopped.Store(true)
+}
+
+func TestShutdownWaitsForInFlightIteration(t *testing.T) {
+	started := make(chan struct{})
+	var finished atomic.Bool
+	iteration := func(ctx context.Context) error {
+		if finished.Load() {
+			return nil
+		}
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		// 本轮的 Context 不会因为关闭而取消
+		if ctx.Err() == nil {
+			finished.Store(true)
+		}
+		return nil
+	}
+
+	m := NewManager()
+	m.Add(NewPeriodic("slow", time.Millisecond, iteration))
+	m.Start(context.Background())
+
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Shutdown returned before the in-flight iteration completed")
+	}
+}
+
+func TestShutdownAwaitsSlowWorkerUpToTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		stopDelay   time.Duration
+		timeout     time.Duration
+		wantStopped bool
+	}{
+		{name: "stops within timeout", stopDelay: 50 * time.Millisecond, timeout: time.Second, wantStopped: true},
+		{name: "exceeds timeout", stopDelay: time.Second, timeout: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &slowWorker{stopDelay: tt.stopDelay}
+			m := NewManager()
+			m.Add(w)
+			m.Start(context.Background())
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			start := time.Now()
+			err := m.Shutdown(ctx)
+			elapsed := time.Since(start)
+
+			if tt.wantStopped {
+				if err != nil || !w.stopped.Load() {
+					t.Errorf("Shutdown = %v, stopped = %v; want the worker awaited", err, w.stopped.Load())
+				}
+				return
+			}
+			// 超时后不再等待，返回的错误指明未退出的任务
+			if err == nil || !strings.Contains(err.Error(), "slow") {
+				t.Errorf("Shutdown = %v, want a timeout error naming the worker", err)
+			}
+			if w.stopped.Load() || elapsed >= tt.stopDelay {
+				t.Errorf("Shutdown waited %v, want it to return at the %v timeout", elapsed, tt.timeout)
+			}
+		})
+	}
+}
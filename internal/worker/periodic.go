@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Periodic 按固定间隔执行的后台任务
+//
+// 每一轮执行使用与根 Context 取消信号解耦的 Context，
+// 因此关闭时正在执行的一轮会完整跑完，Run 才会返回
+type Periodic struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// NewPeriodic 创建一个按 interval 间隔执行 fn 的任务
+func NewPeriodic(name string, interval time.Duration, fn func(ctx context.Context) error) *Periodic {
+	return &Periodic{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+	}
+}
+
+// Name 返回任务名称
+func (p *Periodic) Name() string {
+	return p.name
+}
+
+// Run 按间隔执行任务，直到 ctx 取消
+func (p *Periodic) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// 本轮执行不受关闭信号影响，由 Manager 的关闭超时兜底
+			if err := p.fn(context.WithoutCancel(ctx)); err != nil {
+				slog.Error("worker iteration failed", "worker", p.name, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package currency 集中管理货币代码
+//
+// 包括系统支持开户和转账的货币，以及各货币的小数位数 (ISO 4217)
+// 请求参数校验、金额格式化等都以这里为准，新增货币只需修改本文件
+package currency
+
+import (
+	"slices"
+	"strings"
+)
+
+// 支持开户和转账的货币
+const (
+	USD = "USD" // 美元
+	EUR = "EUR" // 欧元
+	CNY = "CNY" // 人民币
+)
+
+// supported 支持的货币，顺序即 Supported 的返回顺序
+var supported = []string{USD, EUR, CNY}
+
+// defaultMinorUnits 未在 minorUnits 中列出的货币使用的小数位数
+const defaultMinorUnits = 2
+
+// minorUnits 各货币的小数位数 (ISO 4217)
+// 大多数货币为 2 位，这里只列出例外
+var minorUnits = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+// Supported 返回支持的货币列表 (副本)
+func Supported() []string {
+	return slices.Clone(supported)
+}
+
+// IsSupported 返回 code 是否为支持的货币，区分大小写 (货币代码均为大写)
+func IsSupported(code string) bool {
+	return slices.Contains(supported, code)
+}
+
+// MinorUnits 返回货币的小数位数，不区分大小写
+// 例如: USD → 2 (1 美元 = 100 分)，JPY → 0
+// 汇率等场景会用到不支持开户的货币，因此不限于 Supported 中的货币
+func MinorUnits(code string) int {
+	if units, ok := minorUnits[strings.ToUpper(code)]; ok {
+		return units
+	}
+	return defaultMinorUnits
+}
@@ -0,0 +1,56 @@
+package currency
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsSupported(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: USD, want: true},
+		{code: EUR, want: true},
+		{code: CNY, want: true},
+		{code: "usd", want: false}, // 区分大小写
+		{code: "JPY", want: false},
+		{code: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := IsSupported(tt.code); got != tt.want {
+				t.Errorf("IsSupported(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportedReturnsCopy(t *testing.T) {
+	list := Supported()
+	list[0] = "XXX"
+	if got := Supported(); !slices.Equal(got, []string{USD, EUR, CNY}) {
+		t.Errorf("Supported() = %v after modifying a copy", got)
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: USD, want: 2},
+		{code: CNY, want: 2},
+		{code: "JPY", want: 0},
+		{code: "jpy", want: 0}, // 不区分大小写
+		{code: "KWD", want: 3},
+		{code: "GBP", want: 2}, // 未列出的货币使用默认值
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := MinorUnits(tt.code); got != tt.want {
+				t.Errorf("MinorUnits(%q) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote 一次汇率查询的结果
+type Quote struct {
+	From string
+	To   string
+	Rate decimal.Decimal
+
+	// Timestamp 汇率从上游获取的时间
+	Timestamp time.Time
+
+	// Stale 上游获取失败，返回的是已过期的缓存值
+	Stale bool
+}
+
+// CachedProvider 为 Provider 增加 TTL 缓存
+//
+// 缓存未过期时直接返回；过期后向上游重新获取。上游失败时:
+//   - 启用 WithStaleOnError 且存在旧值时返回旧值并标记 Stale (ErrRateNotFound 除外)
+//   - 否则设置了 WithFallback 时使用后备 Provider (例如静态汇率表)，结果不缓存
+type CachedProvider struct {
+	upstream     Provider
+	fallback     Provider
+	ttl          time.Duration
+	staleOnError bool
+	now          func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]Quote
+}
+
+// NewCachedProvider 创建 CachedProvider
+func NewCachedProvider(upstream Provider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{
+		upstream: upstream,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]Quote),
+	}
+}
+
+// WithStaleOnError 上游失败时返回过期的缓存值
+func (p *CachedProvider) WithStaleOnError(enabled bool) *CachedProvider {
+	p.staleOnError = enabled
+	return p
+}
+
+// WithFallback 上游失败且没有可用的缓存时使用 fallback
+func (p *CachedProvider) WithFallback(fallback Provider) *CachedProvider {
+	p.fallback = fallback
+	return p
+}
+
+// Rate 实现 Provider 接口
+func (p *CachedProvider) Rate(ctx context.Context, from, to string) (decimal.Decimal, error) {
+	quote, err := p.Quote(ctx, from, to)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return quote.Rate, nil
+}
+
+// Quote 返回带时间戳和过期标记的汇率
+func (p *CachedProvider) Quote(ctx context.Context, from, to string) (Quote, error) {
+	key := pairKey(from, to)
+
+	p.mu.RLock()
+	cached, ok := p.entries[key]
+	p.mu.RUnlock()
+
+	if ok && p.now().Sub(cached.Timestamp) < p.ttl {
+		return cached, nil
+	}
+
+	rate, err := p.upstream.Rate(ctx, from, to)
+	if err != nil {
+		if p.staleOnError && ok && !errors.Is(err, ErrRateNotFound) {
+			cached.Stale = true
+			return cached, nil
+		}
+		if p.fallback != nil {
+			return p.fallbackQuote(ctx, from, to, err)
+		}
+		return Quote{}, err
+	}
+
+	quote := p.newQuote(from, to, rate)
+
+	p.mu.Lock()
+	p.entries[key] = quote
+	p.mu.Unlock()
+
+	return quote, nil
+}
+
+// fallbackQuote 从后备 Provider 获取汇率
+// 后备也不支持该货币对时返回上游的错误
+func (p *CachedProvider) fallbackQuote(ctx context.Context, from, to string, upstreamErr error) (Quote, error) {
+	rate, err := p.fallback.Rate(ctx, from, to)
+	if err != nil {
+		if errors.Is(err, ErrRateNotFound) {
+			return Quote{}, upstreamErr
+		}
+		return Quote{}, err
+	}
+	return p.newQuote(from, to, rate), nil
+}
+
+// newQuote 以当前时间构造 Quote
+func (p *CachedProvider) newQuote(from, to string, rate decimal.Decimal) Quote {
+	return Quote{
+		From:      strings.ToUpper(from),
+		To:        strings.ToUpper(to),
+		Rate:      rate,
+		Timestamp: p.now(),
+	}
+}
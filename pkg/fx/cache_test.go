@@ -0,0 +1,119 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeProvider 返回可控结果的 Provider
+type fakeProvider struct {
+	rate  decimal.Decimal
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Rate(context.Context, string, string) (decimal.Decimal, error) {
+	p.calls++
+	return p.rate, p.err
+}
+
+// newTestCache 创建时间可控的 CachedProvider
+func newTestCache(upstream Provider) (*CachedProvider, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := NewCachedProvider(upstream, time.Minute)
+	p.now = func() time.Time { return now }
+	return p, &now
+}
+
+func TestCachedProviderServesFromCache(t *testing.T) {
+	upstream := &fakeProvider{rate: decimal.RequireFromString("0.92")}
+	p, _ := newTestCache(upstream)
+
+	for range 3 {
+		if _, err := p.Quote(context.Background(), "usd", "eur"); err != nil {
+			t.Fatalf("Quote: %v", err)
+		}
+	}
+	if upstream.calls != 1 {
+		t.Errorf("upstream calls = %d, want 1", upstream.calls)
+	}
+}
+
+func TestCachedProviderStaleOnError(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		upstream := &fakeProvider{rate: decimal.RequireFromString("0.92")}
+		p, now := newTestCache(upstream)
+		p.WithStaleOnError(enabled)
+
+		if _, err := p.Quote(context.Background(), "USD", "EUR"); err != nil {
+			t.Fatalf("Quote: %v", err)
+		}
+		*now = now.Add(2 * time.Minute)
+		upstream.err = errors.New("connection refused")
+
+		quote, err := p.Quote(context.Background(), "USD", "EUR")
+		if enabled {
+			if err != nil || !quote.Stale || !quote.Rate.Equal(upstream.rate) {
+				t.Errorf("stale-on-error: quote = %+v, err = %v, want stale 0.92", quote, err)
+			}
+		} else if err == nil {
+			t.Errorf("stale-on-error disabled: got %+v, want error", quote)
+		}
+	}
+}
+
+func TestCachedProviderFallsBackToStatic(t *testing.T) {
+	static, err := NewStaticProvider([]string{"USD/EUR=0.90"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := &fakeProvider{err: errors.New("connection refused")}
+	p, _ := newTestCache(upstream)
+	p.WithFallback(static)
+
+	quote, err := p.Quote(context.Background(), "EUR", "USD")
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	if quote.Stale || !quote.Rate.Equal(decimal.RequireFromString("1.11111111")) {
+		t.Errorf("quote = %+v, want static inverse rate", quote)
+	}
+
+	// 后备结果不缓存，下一次仍然先请求上游
+	if _, err := p.Quote(context.Background(), "EUR", "USD"); err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+	if upstream.calls != 2 {
+		t.Errorf("upstream calls = %d, want 2", upstream.calls)
+	}
+
+	// 后备也不支持的货币对返回上游错误
+	if _, err := p.Quote(context.Background(), "USD", "CNY"); err == nil || errors.Is(err, ErrRateNotFound) {
+		t.Errorf("err = %v, want upstream error", err)
+	}
+}
+
+func TestCachedProviderStalePreferredOverFallback(t *testing.T) {
+	static, err := NewStaticProvider([]string{"USD/EUR=0.90"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := &fakeProvider{rate: decimal.RequireFromString("0.92")}
+	p, now := newTestCache(upstream)
+	p.WithStaleOnError(true).WithFallback(static)
+
+	if _, err := p.Quote(context.Background(), "USD", "EUR"); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(2 * time.Minute)
+	upstream.err = errors.New("timeout")
+
+	quote, err := p.Quote(context.Background(), "USD", "EUR")
+	if err != nil || !quote.Stale || !quote.Rate.Equal(decimal.RequireFromString("0.92")) {
+		t.Errorf("quote = %+v, err = %v, want stale upstream rate", quote, err)
+	}
+}
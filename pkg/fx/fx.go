@@ -0,0 +1,84 @@
+// Package fx 提供汇率查询
+//
+// Provider 是汇率来源的统一接口，内置两种实现:
+//   - StaticProvider: 由配置给出的固定汇率表
+//   - HTTPProvider: 从外部汇率服务实时获取
+//
+// CachedProvider 在任意 Provider 外层增加 TTL 缓存，
+// 上游失败时可返回过期的缓存值 (标记为 Stale) 或回退到静态汇率表
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateNotFound 不支持该货币对
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// Provider 汇率来源
+type Provider interface {
+	// Rate 返回 1 单位 from 货币可兑换的 to 货币数量
+	Rate(ctx context.Context, from, to string) (decimal.Decimal, error)
+}
+
+// pairKey 返回货币对在汇率表中的 key，例如 "USD/EUR"
+func pairKey(from, to string) string {
+	return strings.ToUpper(from) + "/" + strings.ToUpper(to)
+}
+
+// ==================== 静态汇率表 ====================
+
+// StaticProvider 使用固定汇率表的 Provider
+//
+// 只需配置单向汇率，反向汇率自动取倒数；相同货币返回 1
+type StaticProvider struct {
+	rates map[string]decimal.Decimal
+}
+
+// NewStaticProvider 从 "FROM/TO=RATE" 格式的条目创建 StaticProvider
+//
+// 例如:
+//
+//	NewStaticProvider([]string{"USD/EUR=0.92", "USD/CNY=7.10"})
+func NewStaticProvider(entries []string) (*StaticProvider, error) {
+	rates := make(map[string]decimal.Decimal, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pair, value, ok := strings.Cut(entry, "=")
+		from, to, okPair := strings.Cut(pair, "/")
+		if !ok || !okPair || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid rate entry %q: want FROM/TO=RATE", entry)
+		}
+
+		rate, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("invalid rate entry %q: rate must be a positive number", entry)
+		}
+
+		rates[pairKey(strings.TrimSpace(from), strings.TrimSpace(to))] = rate
+	}
+	return &StaticProvider{rates: rates}, nil
+}
+
+// Rate 实现 Provider 接口
+func (p *StaticProvider) Rate(_ context.Context, from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return decimal.NewFromInt(1), nil
+	}
+	if rate, ok := p.rates[pairKey(from, to)]; ok {
+		return rate, nil
+	}
+	if rate, ok := p.rates[pairKey(to, from)]; ok {
+		return decimal.NewFromInt(1).DivRound(rate, 8), nil
+	}
+	return decimal.Decimal{}, fmt.Errorf("%w: %s", ErrRateNotFound, pairKey(from, to))
+}
@@ -0,0 +1,72 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// HTTPProvider 从外部汇率服务获取汇率
+//
+// 请求: GET {baseURL}?from=USD&to=EUR
+// 响应: {"rate": "0.92"}，找不到货币对时返回 404
+type HTTPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPProvider 创建 HTTPProvider
+// client 为 nil 时使用 http.DefaultClient，超时由调用方的 Context 控制
+func NewHTTPProvider(baseURL string, client *http.Client) *HTTPProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPProvider{
+		baseURL: baseURL,
+		client:  client,
+	}
+}
+
+// rateResponse 外部汇率服务的响应格式
+type rateResponse struct {
+	Rate decimal.Decimal `json:"rate"`
+}
+
+// Rate 实现 Provider 接口
+func (p *HTTPProvider) Rate(ctx context.Context, from, to string) (decimal.Decimal, error) {
+	query := url.Values{}
+	query.Set("from", strings.ToUpper(from))
+	query.Set("to", strings.ToUpper(to))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("build rate request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("fetch rate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s", ErrRateNotFound, pairKey(from, to))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return decimal.Decimal{}, fmt.Errorf("fetch rate: unexpected status %d", resp.StatusCode)
+	}
+
+	var body rateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return decimal.Decimal{}, fmt.Errorf("decode rate response: %w", err)
+	}
+	if !body.Rate.IsPositive() {
+		return decimal.Decimal{}, fmt.Errorf("fetch rate: invalid rate %s", body.Rate)
+	}
+	return body.Rate, nil
+}
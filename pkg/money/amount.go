@@ -0,0 +1,67 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+)
+
+// amountsAsStrings 为 true 时 Amount 序列化为 JSON 字符串
+var amountsAsStrings atomic.Bool
+
+// SetAmountsAsStrings 设置 Amount 的 JSON 输出格式 (启动时调用一次)
+//
+// JavaScript 的 Number 只能精确表示 2^53 以内的整数，
+// 开启后金额输出为 "123456789012345" 这样的字符串，客户端不会丢失精度
+// 默认关闭，输出 JSON 数字，与旧版本兼容
+func SetAmountsAsStrings(enabled bool) {
+	amountsAsStrings.Store(enabled)
+}
+
+// Amount 以最小货币单位 (分) 表示的金额，用于请求和响应 DTO
+//
+// 输出格式由 SetAmountsAsStrings 决定；
+// 输入无论是否开启都同时接受 JSON 数字 (123) 和整数字符串 ("123")
+type Amount int64
+
+// Int64 返回 int64 形式的金额
+func (a Amount) Int64() int64 {
+	return int64(a)
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (a Amount) MarshalJSON() ([]byte, error) {
+	s := strconv.FormatInt(int64(a), 10)
+	if amountsAsStrings.Load() {
+		return []byte(`"` + s + `"`), nil
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+// 字符串必须是整数 (单位: 分)，十进制金额请使用 amount_decimal 等字段
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	raw := data
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		raw = []byte(s)
+	}
+
+	v, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return ErrAmountOutOfRange
+		}
+		return ErrInvalidAmount
+	}
+	*a = Amount(v)
+	return nil
+}
@@ -0,0 +1,64 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestAmountJSONRoundTrip(t *testing.T) {
+	t.Cleanup(func() { SetAmountsAsStrings(false) })
+
+	// 15 位金额，超出 JavaScript 安全整数范围时字符串输出才能保持精度
+	const value Amount = 123456789012345
+
+	type body struct {
+		Amount Amount `json:"amount"`
+	}
+	tests := []struct {
+		name    string
+		strings bool
+		want    string
+	}{
+		{"number", false, `{"amount":123456789012345}`},
+		{"string", true, `{"amount":"123456789012345"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAmountsAsStrings(tt.strings)
+			data, err := json.Marshal(body{Amount: value})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+
+			var got body
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if got.Amount != value {
+				t.Errorf("round trip = %d, want %d", got.Amount, value)
+			}
+		})
+	}
+}
+
+func TestAmountUnmarshalRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{`"12.50"`, ErrInvalidAmount},
+		{`"abc"`, ErrInvalidAmount},
+		{`1.5`, ErrInvalidAmount},
+		{`"99999999999999999999"`, ErrAmountOutOfRange},
+	}
+	for _, tt := range tests {
+		var a Amount
+		if err := json.Unmarshal([]byte(tt.input), &a); !errors.Is(err, tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.input, err, tt.want)
+		}
+	}
+}
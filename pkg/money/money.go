@@ -0,0 +1,103 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/proyuen/simple-bank-v2/pkg/currency"
+)
+
+// 金额解析错误
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrTooManyDecimals  = errors.New("too many decimal places")
+	ErrAmountOutOfRange = errors.New("amount out of range")
+)
+
+// Exponent 返回货币的小数位数 (见 currency.MinorUnits)
+// 例如: USD → 2 (1 美元 = 100 分)，JPY → 0
+func Exponent(code string) int {
+	return currency.MinorUnits(code)
+}
+
+// ParseAmount 将十进制金额字符串转换为最小货币单位
+//
+// 例如:
+//
+//	ParseAmount("10.50", "USD") → 1050
+//	ParseAmount("10", "USD")    → 1000
+//	ParseAmount("500", "JPY")   → 500
+//	ParseAmount("10.5", "JPY")  → ErrTooManyDecimals
+//
+// 不使用浮点数，避免精度误差；小数位超过货币允许的位数时返回错误
+func ParseAmount(s string, currency string) (int64, error) {
+	s = strings.TrimSpace(s)
+	exp := Exponent(currency)
+
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	}
+
+	whole, frac, hasDot := strings.Cut(s, ".")
+	if whole == "" || (hasDot && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if len(frac) > exp {
+		return 0, fmt.Errorf("%w: %s allows %d", ErrTooManyDecimals, currency, exp)
+	}
+
+	// 小数部分右侧补零到货币精度，再与整数部分拼接
+	digits := whole + frac + strings.Repeat("0", exp-len(frac))
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrAmountOutOfRange, s)
+	}
+
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// FormatAmount 将最小货币单位格式化为十进制字符串
+//
+// 例如:
+//
+//	FormatAmount(1050, "USD") → "10.50"
+//	FormatAmount(-5, "USD")   → "-0.05"
+//	FormatAmount(500, "JPY")  → "500"
+func FormatAmount(cents int64, currency string) string {
+	exp := Exponent(currency)
+
+	sign := ""
+	abs := uint64(cents)
+	if cents < 0 {
+		// 先加 1 再取负，避免 math.MinInt64 溢出
+		sign = "-"
+		abs = uint64(-(cents + 1)) + 1
+	}
+
+	if exp == 0 {
+		return sign + strconv.FormatUint(abs, 10)
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// isDigits 返回字符串是否只包含 0-9
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
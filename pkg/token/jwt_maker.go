@@ -12,8 +12,14 @@ const minSecretKeySize = 32
 
 // Maker 是管理 Token 的接口
 type Maker interface {
-	// CreateToken 为指定用户名创建一个新的 Token
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken 为指定用户名和角色创建一个新的 Token
+	CreateToken(username, role string, duration time.Duration) (string, *Payload, error)
+
+	// CreateScopedToken 创建一个只具有指定权限范围的 Token，scopes 为空时等同于 CreateToken
+	CreateScopedToken(username, role string, scopes []string, duration time.Duration) (string, *Payload, error)
+
+	// RenewToken 根据已验证的 Token 签发一个新 Token，用户名、角色、scope 和登录时间保持不变
+	RenewToken(payload *Payload, duration time.Duration) (string, *Payload, error)
 
 	// VerifyToken 检查 Token 是否有效
 	VerifyToken(token string) (*Payload, error)
@@ -21,23 +27,86 @@ type Maker interface {
 
 // JWTMaker 是 JWT 的 Maker 实现
 type JWTMaker struct {
-	secretKey string
+	secretKey    string   // 当前密钥，用于签发和验证
+	previousKeys []string // 轮换前的旧密钥，仅用于验证
+	issuer       string   // 非空时写入 iss 并在验证时校验
+	audience     string   // 非空时写入 aud 并在验证时校验
+}
+
+// JWTOptions 是 JWTMaker 的可选配置
+type JWTOptions struct {
+	PreviousKeys []string // 轮换前的旧密钥
+	Issuer       string   // Token 签发方，为空时不校验
+	Audience     string   // Token 接收方，为空时不校验
 }
 
 // NewJWTMaker 创建一个新的 JWTMaker
 func NewJWTMaker(secretKey string) (Maker, error) {
-	if len(secretKey) < minSecretKeySize {
+	return NewJWTMakerWithKeys(secretKey)
+}
+
+// NewJWTMakerWithKeys 创建支持密钥轮换的 JWTMaker
+// 新 Token 始终使用 primary 签名，验证时依次尝试 primary 和 previous，
+// 旧密钥签发的 Token 在过期前仍然有效，从而实现无停机轮换
+func NewJWTMakerWithKeys(primary string, previous ...string) (Maker, error) {
+	return NewJWTMakerWithOptions(primary, JWTOptions{PreviousKeys: previous})
+}
+
+// NewJWTMakerWithOptions 使用完整配置创建 JWTMaker
+// 设置了 Issuer/Audience 时，签发的 Token 携带 iss/aud，不匹配的 Token 验证失败
+func NewJWTMakerWithOptions(primary string, opts JWTOptions) (Maker, error) {
+	if len(primary) < minSecretKeySize {
 		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
 	}
-	return &JWTMaker{secretKey: secretKey}, nil
+	previousKeys := make([]string, 0, len(opts.PreviousKeys))
+	for _, key := range opts.PreviousKeys {
+		if key == "" || key == primary {
+			continue
+		}
+		if len(key) < minSecretKeySize {
+			return nil, fmt.Errorf("invalid previous key size: must be at least %d characters", minSecretKeySize)
+		}
+		previousKeys = append(previousKeys, key)
+	}
+	return &JWTMaker{
+		secretKey:    primary,
+		previousKeys: previousKeys,
+		issuer:       opts.Issuer,
+		audience:     opts.Audience,
+	}, nil
+}
+
+// CreateToken 为指定用户名和角色创建一个新的 JWT Token
+func (maker *JWTMaker) CreateToken(username, role string, duration time.Duration) (string, *Payload, error) {
+	return maker.CreateScopedToken(username, role, nil, duration)
+}
+
+// CreateScopedToken 创建一个只具有指定权限范围的 JWT Token
+func (maker *JWTMaker) CreateScopedToken(username, role string, scopes []string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, duration)
+	if err != nil {
+		return "", nil, err
+	}
+	payload.Scopes = scopes
+	return maker.sign(payload)
 }
 
-// CreateToken 为指定用户名创建一个新的 JWT Token
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// RenewToken 根据已验证的 Token 签发一个新的 JWT Token (如用 Refresh Token 换取 Access Token)
+// 新 Token 的登录时间沿用原 Token，刷新不会让 Token 变得"新鲜"
+func (maker *JWTMaker) RenewToken(renewed *Payload, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(renewed.Username, renewed.Role, duration)
 	if err != nil {
 		return "", nil, err
 	}
+	payload.Scopes = renewed.Scopes
+	payload.AuthTime = renewed.AuthenticatedAt()
+	return maker.sign(payload)
+}
+
+// sign 设置签发方和接收方并签名
+func (maker *JWTMaker) sign(payload *Payload) (string, *Payload, error) {
+	payload.Issuer = maker.issuer
+	payload.Audience = maker.audience
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{payload})
 	token, err := jwtToken.SignedString([]byte(maker.secretKey))
@@ -49,16 +118,31 @@ func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (str
 }
 
 // VerifyToken 检查 Token 是否有效
+// 当前密钥验签失败时依次尝试旧密钥；配置了 issuer/audience 时一并校验
 func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	keys := make([]jwt.VerificationKey, 0, 1+len(maker.previousKeys))
+	keys = append(keys, []byte(maker.secretKey))
+	for _, key := range maker.previousKeys {
+		keys = append(keys, []byte(key))
+	}
+
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
 			return nil, ErrInvalidToken
 		}
-		return []byte(maker.secretKey), nil
+		return jwt.VerificationKeySet{Keys: keys}, nil
 	}
 
-	jwtToken, err := jwt.ParseWithClaims(token, &jwtClaims{}, keyFunc)
+	var parserOpts []jwt.ParserOption
+	if maker.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(maker.issuer))
+	}
+	if maker.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(maker.audience))
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &jwtClaims{}, keyFunc, parserOpts...)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
@@ -74,6 +158,29 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 	return claims.Payload, nil
 }
 
+// selfCheckUsername 自检 Token 使用的用户名，不对应真实用户
+const selfCheckUsername = "__token_self_check__"
+
+// SelfCheck 用 maker 签发一个短期 Token 并立即验证，确认签发和验证都可用
+// 用于就绪检查: 密钥配置错误时认证不可用，即使数据库正常服务也不应接收流量
+func SelfCheck(maker Maker) error {
+	if maker == nil {
+		return errors.New("token maker is not configured")
+	}
+	token, _, err := maker.CreateToken(selfCheckUsername, "", time.Minute)
+	if err != nil {
+		return fmt.Errorf("create token: %w", err)
+	}
+	payload, err := maker.VerifyToken(token)
+	if err != nil {
+		return fmt.Errorf("verify token: %w", err)
+	}
+	if payload.Username != selfCheckUsername {
+		return errors.New("verified token has unexpected username")
+	}
+	return nil
+}
+
 // jwtClaims 包装 Payload 以实现 jwt.Claims 接口
 type jwtClaims struct {
 	*Payload
@@ -96,7 +203,7 @@ func (c jwtClaims) GetNotBefore() (*jwt.NumericDate, error) {
 
 // GetIssuer 实现 jwt.Claims 接口
 func (c jwtClaims) GetIssuer() (string, error) {
-	return "", nil
+	return c.Issuer, nil
 }
 
 // GetSubject 实现 jwt.Claims 接口
@@ -106,5 +213,8 @@ func (c jwtClaims) GetSubject() (string, error) {
 
 // GetAudience 实现 jwt.Claims 接口
 func (c jwtClaims) GetAudience() (jwt.ClaimStrings, error) {
-	return nil, nil
+	if c.Audience == "" {
+		return nil, nil
+	}
+	return jwt.ClaimStrings{c.Audience}, nil
 }
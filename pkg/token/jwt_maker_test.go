@@ -0,0 +1,101 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	currentKey  = strings.Repeat("c", minSecretKeySize)
+	previousKey = strings.Repeat("p", minSecretKeySize)
+	unknownKey  = strings.Repeat("u", minSecretKeySize)
+)
+
+// mustMaker 创建 JWTMaker，配置错误时终止测试
+func mustMaker(t *testing.T, primary string, opts JWTOptions) Maker {
+	t.Helper()
+	maker, err := NewJWTMakerWithOptions(primary, opts)
+	if err != nil {
+		t.Fatalf("NewJWTMakerWithOptions: %v", err)
+	}
+	return maker
+}
+
+// mustCreateToken 用 maker 为 alice 签发一个 Token
+func mustCreateToken(t *testing.T, maker Maker) string {
+	t.Helper()
+	token, _, err := maker.CreateToken("alice", "user", time.Minute)
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	return token
+}
+
+func TestJWTMakerKeyRotation(t *testing.T) {
+	rotated := mustMaker(t, currentKey, JWTOptions{PreviousKeys: []string{previousKey}})
+
+	// 轮换前用旧密钥签发的 Token 仍然有效
+	oldToken := mustCreateToken(t, mustMaker(t, previousKey, JWTOptions{}))
+	payload, err := rotated.VerifyToken(oldToken)
+	if err != nil {
+		t.Fatalf("verify token signed with previous key: %v", err)
+	}
+	if payload.Username != "alice" {
+		t.Errorf("username = %q, want alice", payload.Username)
+	}
+
+	// 新 Token 只用当前密钥签名: 只认旧密钥的 maker 无法验证
+	newToken := mustCreateToken(t, rotated)
+	if _, err := mustMaker(t, currentKey, JWTOptions{}).VerifyToken(newToken); err != nil {
+		t.Errorf("verify new token with current key: %v", err)
+	}
+	if _, err := mustMaker(t, previousKey, JWTOptions{}).VerifyToken(newToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("verify new token with previous key error = %v, want %v", err, ErrInvalidToken)
+	}
+
+	// 未知密钥签发的 Token 被拒绝
+	foreignToken := mustCreateToken(t, mustMaker(t, unknownKey, JWTOptions{}))
+	if _, err := rotated.VerifyToken(foreignToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("verify token signed with unknown key error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestJWTMakerRejectsShortPreviousKey(t *testing.T) {
+	if _, err := NewJWTMakerWithKeys(currentKey, "too-short"); err == nil {
+		t.Error("expected error for a previous key shorter than the minimum")
+	}
+}
+
+func TestJWTMakerIssuerAndAudience(t *testing.T) {
+	verifier := mustMaker(t, currentKey, JWTOptions{Issuer: "simple-bank", Audience: "simple-bank-api"})
+
+	tests := []struct {
+		name    string
+		signer  JWTOptions
+		wantErr error
+	}{
+		{"matching", JWTOptions{Issuer: "simple-bank", Audience: "simple-bank-api"}, nil},
+		{"wrong issuer", JWTOptions{Issuer: "other-bank", Audience: "simple-bank-api"}, ErrInvalidToken},
+		{"wrong audience", JWTOptions{Issuer: "simple-bank", Audience: "other-api"}, ErrInvalidToken},
+		{"missing claims", JWTOptions{}, ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := mustCreateToken(t, mustMaker(t, currentKey, tt.signer))
+			if _, err := verifier.VerifyToken(token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyToken error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTMakerSkipsEmptyIssuerAndAudience(t *testing.T) {
+	// 未配置 issuer/audience 时不校验，任意 iss/aud 的 Token 都可以通过
+	verifier := mustMaker(t, currentKey, JWTOptions{})
+	token := mustCreateToken(t, mustMaker(t, currentKey, JWTOptions{Issuer: "other-bank", Audience: "other-api"}))
+	if _, err := verifier.VerifyToken(token); err != nil {
+		t.Errorf("VerifyToken: %v", err)
+	}
+}
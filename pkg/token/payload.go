@@ -14,15 +14,27 @@ var (
 )
 
 // Payload 包含 JWT Token 的载荷数据
+//
+// Role 字段在旧版本 Token 中不存在，解码旧 Token 时为空字符串，
+// 调用方应将空角色视为普通用户
+//
+// Scopes 为空表示不限制 (登录签发的 Token)，见 scope.go；
+// AuthTime 是用户输入密码登录的时间，刷新 Token 时保持不变，
+// 用于判断敏感操作是否需要重新登录 (见 AuthenticatedAt)
 type Payload struct {
-	ID        uuid.UUID `json:"id"`         // Token 唯一标识
-	Username  string    `json:"username"`   // 用户名
-	IssuedAt  time.Time `json:"issued_at"`  // 签发时间
-	ExpiredAt time.Time `json:"expired_at"` // 过期时间
+	ID        uuid.UUID `json:"id"`               // Token 唯一标识
+	Username  string    `json:"username"`         // 用户名
+	Role      string    `json:"role,omitempty"`   // 用户角色
+	Scopes    []string  `json:"scopes,omitempty"` // 权限范围
+	Issuer    string    `json:"iss,omitempty"`    // 签发方
+	Audience  string    `json:"aud,omitempty"`    // 接收方
+	IssuedAt  time.Time `json:"issued_at"`        // 签发时间
+	AuthTime  time.Time `json:"auth_time"`        // 登录时间
+	ExpiredAt time.Time `json:"expired_at"`       // 过期时间
 }
 
 // NewPayload 创建一个新的 Token 载荷
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username, role string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -32,13 +44,54 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
 		IssuedAt:  now,
+		AuthTime:  now,
 		ExpiredAt: now.Add(duration),
 	}
 
 	return payload, nil
 }
 
+// HasRole 检查载荷是否具有指定角色
+func (payload *Payload) HasRole(role string) bool {
+	return payload.Role == role
+}
+
+// HasScope 检查载荷是否具有指定权限范围
+// Scopes 为空 (不限制) 时总是返回 true
+func (payload *Payload) HasScope(scope string) bool {
+	if payload.IsUnrestricted() {
+		return true
+	}
+	for _, granted := range payload.Scopes {
+		if scopeMatches(granted, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUnrestricted 检查载荷是否不限制权限范围 (登录签发的普通 Token)
+func (payload *Payload) IsUnrestricted() bool {
+	return len(payload.Scopes) == 0
+}
+
+// AuthenticatedAt 返回用户登录的时间
+// 没有 AuthTime 的旧 Token 退化为签发时间
+func (payload *Payload) AuthenticatedAt() time.Time {
+	if payload.AuthTime.IsZero() {
+		return payload.IssuedAt
+	}
+	return payload.AuthTime
+}
+
+// IssuedBefore 检查 Token 是否在 t 之前签发
+// JWT 的签发时间只精确到秒，比较前将 t 截断到秒，同一秒内签发的 Token 视为不早于 t
+func (payload *Payload) IssuedBefore(t time.Time) bool {
+	return payload.IssuedAt.Before(t.Truncate(time.Second))
+}
+
 // Valid 检查 Token 载荷是否有效
 // 实现 jwt.Claims 接口
 func (payload *Payload) Valid() error {
@@ -0,0 +1,53 @@
+package token
+
+import (
+	"slices"
+	"strings"
+)
+
+// 权限范围 (scope)，格式为 "<资源>:<操作>"
+//
+// 登录签发的 Token 不带 scope，表示不限制；
+// 只读登录的 Token 和 API Key 等受限凭证的 payload 只包含被授予的 scope
+// 资源部分为 "*" 时匹配任意资源，如 "*:read" 满足 "accounts:read"
+const (
+	// ScopeReadAll 读取所有资源
+	ScopeReadAll = "*:read"
+
+	// ScopeTransfersWrite 创建、撤销转账 (包括定时和周期转账)
+	ScopeTransfersWrite = "transfers:write"
+
+	// ScopeAccountsWrite 创建、修改账户
+	ScopeAccountsWrite = "accounts:write"
+)
+
+// allScopes 可以授予的全部 scope
+var allScopes = []string{ScopeReadAll, ScopeTransfersWrite, ScopeAccountsWrite}
+
+// AllScopes 返回可以授予的全部 scope
+func AllScopes() []string {
+	return slices.Clone(allScopes)
+}
+
+// IsValidScope 检查 scope 是否可以授予
+func IsValidScope(scope string) bool {
+	return slices.Contains(allScopes, scope)
+}
+
+// ReadOnlyScopes 返回只读凭证的 scope
+func ReadOnlyScopes() []string {
+	return []string{ScopeReadAll}
+}
+
+// scopeMatches 检查授予的 scope 是否满足要求的 scope
+func scopeMatches(granted, required string) bool {
+	if granted == required {
+		return true
+	}
+	resource, action, ok := strings.Cut(granted, ":")
+	if !ok || resource != "*" {
+		return false
+	}
+	_, requiredAction, ok := strings.Cut(required, ":")
+	return ok && requiredAction == action
+}